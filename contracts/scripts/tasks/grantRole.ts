@@ -7,7 +7,8 @@ import { AccessContextHandler__factory, type IPolicyVerifier } from "../../types
 task("grant-role", "Grant a role")
   .addParam("context", "The role's and the policies access context id")
   .addParam("policyId", "The policy's id")
-  .setAction(async (taskArgs: { context: BytesLike; policyId: BytesLike }, hre) => {
+  .addParam("inputs", "The public inputs of the policy's proof, comma separated")
+  .setAction(async (taskArgs: { context: BytesLike; policyId: BytesLike; inputs: string }, hre) => {
     const { getNamedAccounts, deployments, ethers, getChainId } = hre;
     const { deployer } = await getNamedAccounts();
     const chainId = await getChainId();
@@ -46,6 +47,7 @@ task("grant-role", "Grant a role")
       [taskArgs.context],
       [taskArgs.policyId],
       [zkVP],
+      [taskArgs.inputs.split(",").map((input) => BigInt(input.trim()))],
     );
     await tx.wait();
     console.log("granted role");
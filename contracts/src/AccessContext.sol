@@ -24,14 +24,16 @@ contract AccessContext is IContextInstance, DIDOwnable, AccessControlListExtensi
 
     /**
      *  @notice         Allows only context admin (owner) or role member for `_role`.
+     *  @dev            Caller must control the did of the owner, or `_did` if it is a member of `_role`.
      *
      *  @param _role           Uid of the role within this context.
+     *  @param _did            DID of the role member.
      */
     modifier onlyOwnerOrRole(
         bytes32 _role,
         bytes32 _did
     ){
-        require(_hasRole(_role, _did) || _isOwner(_did));
+        require(_isOwner(owner()) || (_hasRole(_role, _did) && _isDID(_did)), "not allowed");
         _;
     }
 
@@ -43,7 +45,8 @@ contract AccessContext is IContextInstance, DIDOwnable, AccessControlListExtensi
      *  @param _role           Uid of the role within this context.
      *  @param _did            DID of the user.
      *  @param _policyContexts Uid of policy contexts.
-     *  @param _policies       Uids of the policies of context in `policyContexts` at same index.
+     *  @param _policies       Uids of the policies of context in `policyContexts` at same index. Every policy of
+     *                         the role must be given exactly once.
      */
     function grantRole(
         bytes32 _role,
@@ -51,11 +54,19 @@ contract AccessContext is IContextInstance, DIDOwnable, AccessControlListExtensi
         bytes32[] memory _policyContexts,
         bytes32[] memory _policies,
         IPolicyVerifier.Proof[] memory _proofs,
-        uint[20][] memory _inputs
+        uint[][] memory _inputs
     ) external {
         bytes32 thisContext = _thisContext();
         uint256 policyCount = _policies.length;
+        require(
+            _policyContexts.length == policyCount && _proofs.length == policyCount && _inputs.length == policyCount,
+            "policy arguments differ in length"
+        );
+        require(_hasRoleExpectedPolicyCount(thisContext, _role, policyCount), "policy count of role not matched");
         for (uint256 i = 0; i < policyCount; i++) {
+            for (uint256 j = 0; j < i; j++) {
+                require(_policyContexts[i] != _policyContexts[j] || _policies[i] != _policies[j], "policy given twice");
+            }
             Policy memory policy_ = _getPolicy(_policyContexts[i], _policies[i]);
             require(_hasRolePolicy(thisContext, _role, policy_.context, policy_.id), "policy for role not allowed");
             require(_verifyPolicy(policy_, _proofs[i], _inputs[i]), "policy not satisfied");
@@ -90,13 +101,30 @@ contract AccessContext is IContextInstance, DIDOwnable, AccessControlListExtensi
         return _hasRole(_role, _did);
     }
 
+    /**
+     *  @notice         Checks if a role holds a permission that permits an operation on a resource.
+     *
+     *  @param _role           Uid of the role within this context.
+     *  @param _permission     Uid of the permission.
+     *  @param _resource       Uid of the resource.
+     *  @param _operation      Operation to check against.
+     */
+    function hasPermission(
+        bytes32 _role,
+        bytes32 _permission,
+        bytes32 _resource,
+        Operation _operation
+    ) external view returns (bool) {
+        return _hasRolePermission(_thisContext(), _role, _permission) && _hasPermissionToResource(_permission, _resource, _operation);
+    }
+
     /**
      *  @notice         Revokes a role.
      *  @dev            Caller must be owner or role member.
      *                  Emits {RoleRevoke Event}.
      *
      *  @param _role           Uid of the role within this context.
-     *  @param _did            DID of the admin of this context.
+     *  @param _did            DID of the user whose role is revoked.
      */
     function revokeRole(
         bytes32 _role,
@@ -232,6 +260,19 @@ contract AccessContext is IContextInstance, DIDOwnable, AccessControlListExtensi
         return _getPolicies(_contexts, _ids);
     }
 
+    /**
+     *  @notice         Get the number of policies assigned to a role of this context.
+     *
+     *  @param _role            Uid of the role within this context.
+     *
+     * @return count            The number of policies a user must satisfy to be granted `_role`
+     */
+    function getRolePolicyCount(
+        bytes32 _role
+    ) external view returns (uint256 count) {
+        return _getPolicyCount(_thisContext(), _role);
+    }
+
     /**
      *  @notice         Assigns an existing policy from own or cross-context to a role from own context.
      *  @dev            Caller must have owner role.
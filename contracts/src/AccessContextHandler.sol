@@ -39,20 +39,31 @@ contract AccessContextHandler is IContextHandler, SessionRecipient, ContextHandl
         bytes32[] memory _policyContexts,
         bytes32[] memory _policies,
         IPolicyVerifier.Proof[] memory _proofs,
-        uint[20][] memory _inputs,
+        uint[][] memory _inputs,
         bytes32 _tokenId,
-        bytes memory _token
+        bytes memory _token,
+        uint256 _duration
     ) external {
         _forwardGrantRole(_roleContext, _role, _did, _policyContexts, _policies,_proofs,_inputs);
-        _forwardStartSession(_tokenId, _token, _did);
+        _forwardStartSession(_tokenId, _token, _did, _roleContext, _duration);
     }
 
     function startSession(
         bytes32 _did,
+        bytes32 _context,
         bytes32 _tokenId,
-        bytes memory _token
+        bytes memory _token,
+        uint256 _duration
     ) external {
-        _forwardStartSession(_tokenId, _token, _did);
+        _forwardStartSession(_tokenId, _token, _did, _context, _duration);
+    }
+
+    function revokeSession(
+        bytes32 _tokenId,
+        bytes32 _context,
+        bytes32 _did
+    ) onlyContextAdmin(_context, _did) external {
+        _forwardRevokeSession(_tokenId, _context);
     }
 
     function grantRole(
@@ -62,7 +73,7 @@ contract AccessContextHandler is IContextHandler, SessionRecipient, ContextHandl
         bytes32[] memory _policyContexts,
         bytes32[] memory _policies,
         IPolicyVerifier.Proof[] memory _proofs,
-        uint[20][] memory _inputs
+        uint[][] memory _inputs
     ) external {
         _forwardGrantRole(_roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs);
     }
@@ -93,4 +104,16 @@ contract AccessContextHandler is IContextHandler, SessionRecipient, ContextHandl
     function getContextInstance(bytes32 _id) external view returns (IContextInstance) {
         return _getContextInstance(_id);
     }
+
+    function getSessionRegistry() external view returns (address) {
+        return address(_getSessionRegistry());
+    }
+
+    function getDIDRegistry() external view returns (address) {
+        return address(_getRegistry());
+    }
+
+    function getInstanceImpl() external view returns (address) {
+        return _getInstanceImpl();
+    }
 }
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: UNLICENSED
+pragma solidity ^0.8.20;
+
+import "./Policy.sol";
+
+contract PolicyVerifier is Verifier {
+
+    // URI of the presentation definition a holder has to satisfy
+    string public presentationDefinition;
+    // URI of the compiled proof program
+    string public proofProgram;
+    // URI of the proving key for `proofProgram`
+    string public provingKey;
+    // URI of the verification key for `proofProgram`
+    string public verificationKey;
+
+    /**
+     *  @notice         Deploys a policy verifier that carries the URIs of its off-chain artifacts.
+     *
+     *  @param _presentationDefinition  URI of the presentation definition.
+     *  @param _proofProgram            URI of the compiled proof program.
+     *  @param _provingKey              URI of the proving key.
+     *  @param _verificationKey         URI of the verification key.
+     */
+    constructor(
+        string memory _presentationDefinition,
+        string memory _proofProgram,
+        string memory _provingKey,
+        string memory _verificationKey
+    ) {
+        presentationDefinition = _presentationDefinition;
+        proofProgram = _proofProgram;
+        provingKey = _provingKey;
+        verificationKey = _verificationKey;
+    }
+
+    /**
+     *  @notice         Returns the number of public inputs a proof of `proofProgram` has.
+     *
+     *  @return count   The number of public inputs declared by the verifying key.
+     */
+    function inputCount() public pure returns (uint256 count) {
+        return verifyingKey().gamma_abc.length - 1;
+    }
+
+    /**
+     *  @notice         Verifies a proof of `proofProgram` for any number of public inputs, so that programs are not
+     *                  bound to the input array size of the generated `verifyTx`.
+     *
+     *  @param proof    The proof to verify.
+     *  @param input    The public inputs of the proof, `inputCount` in total.
+     *  @return r       Whether the proof is valid.
+     */
+    function verifyTx(Proof memory proof, uint[] memory input) external view returns (bool r) {
+        if (input.length != inputCount()) {
+            return false;
+        }
+        return verify(input, proof) == 0;
+    }
+}
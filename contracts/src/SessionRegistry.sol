@@ -7,6 +7,9 @@ import "./context/ContextHandlerRecipient.sol";
 
 contract SessionRegistry is ISessionRegistry, SessionRegistryBase, ContextHandlerRecipient {
 
+    // upper bound of the lifetime of a session, so a leaked token cannot be used indefinitely
+    uint256 internal constant MAX_SESSION_DURATION = 30 days;
+
     constructor(
         address contextHandler,
         address didRegistry
@@ -14,8 +17,13 @@ contract SessionRegistry is ISessionRegistry, SessionRegistryBase, ContextHandle
         _initContextHandlerRecipient(contextHandler);
     }
 
-    modifier onlyUserOrContextHandler(bytes32 id){
-        require(_checkSessionUser(id) || _checkContextHandler());
+    modifier onlySessionUser(bytes32 id){
+        require(_checkSessionUser(id), "not allowed");
+        _;
+    }
+
+    modifier onlyContextHandler(){
+        require(_checkContextHandler(), "not allowed");
         _;
     }
 
@@ -29,17 +37,27 @@ contract SessionRegistry is ISessionRegistry, SessionRegistryBase, ContextHandle
     function startSession(
         bytes32 _id,
         bytes memory _token,
-        bytes32 _user
-    ) override external {
+        bytes32 _user,
+        bytes32 _context,
+        uint256 _duration
+    ) onlyContextHandler override external {
         require(!_checkSessionExists(_id), "session already exists");
-        _setSession(_id, _token, _user, 1 days);
+        require(_duration > 0, "session duration is zero");
+        require(_duration <= MAX_SESSION_DURATION, "session duration too long");
+        _setSession(_id, _token, _user, _context, _duration);
     }
 
-    function revokeSession(bytes32 _id) onlyUserOrContextHandler(_id) external {
+    function revokeSession(bytes32 _id) onlySessionUser(_id) external {
         require(_checkSessionExists(_id), "session not found");
         _deleteSession(_id);
     }
 
+    function revokeContextSession(bytes32 _id, bytes32 _context) onlyContextHandler override external {
+        require(_checkSessionExists(_id), "session not found");
+        require(_checkSessionForContext(_id, _context), "session of another context");
+        _deleteSession(_id);
+    }
+
     function isSessionValid(bytes32 _id) external view returns (bool) {
         return _checkSessionValid(_id);
     }
@@ -47,4 +65,12 @@ contract SessionRegistry is ISessionRegistry, SessionRegistryBase, ContextHandle
     function isSession(bytes32 _id, bytes32 _user) external view returns (bool) {
         return _checkSessionExists(_id) && _checkSessionValid(_id) && _checkSessionForUser(_id, _user);
     }
+
+    function getContextHandler() external view returns (address) {
+        return address(_contextHandler());
+    }
+
+    function getDIDRegistry() external view returns (address) {
+        return address(_getRegistry());
+    }
 }
@@ -47,7 +47,7 @@ contract ContextHandlerBase is DIDRecipient {
         bytes32[] memory _policyContexts,
         bytes32[] memory _policies,
         IPolicyVerifier.Proof[] memory _proofs,
-        uint[20][] memory _inputs
+        uint[][] memory _inputs
     ) internal {
         _getContextInstance(_roleContext).grantRole(_role, _did, _policyContexts, _policies, _proofs, _inputs);
     }
@@ -51,6 +51,14 @@ contract AccessControlListExtension {
         assignments[_roleContext][_role].permissions[_permission] = false;
     }
 
+    function _hasRolePermission(
+        bytes32 _roleContext,
+        bytes32 _role,
+        bytes32 _permission
+    ) internal view returns (bool) {
+        return assignments[_roleContext][_role].permissions[_permission];
+    }
+
     function _assignPoliciesToRole(
         bytes32 _roleContext,
         bytes32 _role,
@@ -68,6 +76,10 @@ contract AccessControlListExtension {
         bytes32 _policyContext,
         bytes32 policy_
     ) internal {
+        // the count is of distinct policies, which grantRole expects each exactly once
+        if (_hasRolePolicy(_roleContext, _role, _policyContext, policy_)) {
+            return;
+        }
         assignments[_roleContext][_role].policies[_policyContext][policy_] = true;
         assignments[_roleContext][_role].policyCount += 1;
     }
@@ -89,7 +101,7 @@ contract AccessControlListExtension {
         bytes32 _policyContext,
         bytes32 _policy
     ) internal {
-        require(_getPolicyCount(_roleContext, _role) > 0, "no policy found for role");
+        require(_hasRolePolicy(_roleContext, _role, _policyContext, _policy), "policy not assigned to role");
         assignments[_roleContext][_role].policies[_policyContext][_policy] = false;
         assignments[_roleContext][_role].policyCount -= 1;
     }
@@ -65,7 +65,7 @@ contract PolicyExtension is IPolicyExtension, ContextInstance {
     function _verifyPolicy(
         Policy memory _policy,
         IPolicyVerifier.Proof memory _proof,
-        uint[20] memory _input
+        uint[] memory _input
     ) internal view returns (bool) {
         return _policy.verifier.verifyTx(_proof, _input);
     }
@@ -73,7 +73,7 @@ contract PolicyExtension is IPolicyExtension, ContextInstance {
     function _verifyPolicies (
         Policy[] memory _policies,
         IPolicyVerifier.Proof[] memory _proofs,
-        uint[20][] memory _inputs
+        uint[][] memory _inputs
     ) internal view returns (bool) {
         for (uint256 i = 0; i < _policies.length; i++) {
             if(!_verifyPolicy(_policies[i], _proofs[i], _inputs[i])) {
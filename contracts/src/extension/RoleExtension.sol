@@ -5,11 +5,15 @@ contract RoleExtension {
     // role -> did -> bool has role
     mapping(bytes32 => mapping(bytes32 => bool)) private hasRole;
 
+    event RoleGranted(bytes32 indexed role, bytes32 indexed did);
+    event RoleRevoked(bytes32 indexed role, bytes32 indexed did);
+
     function _grantRole(
         bytes32 _role,
         bytes32 _did
     ) internal {
         hasRole[_role][_did] = true;
+        emit RoleGranted(_role, _did);
     }
 
     function _revokeRole(
@@ -17,6 +21,7 @@ contract RoleExtension {
         bytes32 _did
     ) internal {
         hasRole[_role][_did] = false;
+        emit RoleRevoked(_role, _did);
     }
 
     function _hasRole(
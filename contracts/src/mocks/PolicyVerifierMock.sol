@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: UNLICENSED
+pragma solidity ^0.8.20;
+
+import "../policy/IPolicyVerifier.sol";
+
+/**
+ *  @notice         Policy verifier for tests, which accepts any proof with `inputCount` inputs unless
+ *                  constructed to reject all proofs.
+ */
+contract PolicyVerifierMock is IPolicyVerifier {
+
+    uint256 private inputCount_;
+    bool private satisfied_;
+
+    constructor(
+        uint256 _inputCount,
+        bool _satisfied
+    ) {
+        inputCount_ = _inputCount;
+        satisfied_ = _satisfied;
+    }
+
+    function verifyTx(Proof memory, uint[] memory input) external view returns (bool r) {
+        return satisfied_ && input.length == inputCount_;
+    }
+
+    function inputCount() external view returns (uint256 count) {
+        return inputCount_;
+    }
+}
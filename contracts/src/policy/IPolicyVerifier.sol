@@ -17,5 +17,7 @@ interface IPolicyVerifier {
         Pairing.G1Point c;
     }
 
-    function verifyTx(Proof memory proof, uint[20] memory input) external view returns (bool r);
+    function verifyTx(Proof memory proof, uint[] memory input) external view returns (bool r);
+
+    function inputCount() external view returns (uint256 count);
 }
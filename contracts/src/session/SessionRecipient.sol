@@ -34,9 +34,18 @@ contract SessionRecipient is Context {
     function _forwardStartSession(
         bytes32 _tokenId,
         bytes memory _token,
-        bytes32 _did
+        bytes32 _did,
+        bytes32 _context,
+        uint256 _duration
+    ) internal {
+        _getSessionRegistry().startSession(_tokenId, _token, _did, _context, _duration);
+    }
+
+    function _forwardRevokeSession(
+        bytes32 _tokenId,
+        bytes32 _context
     ) internal {
-        _getSessionRegistry().startSession(_tokenId, _token, _did);
+        _getSessionRegistry().revokeContextSession(_tokenId, _context);
     }
 
     function _forwardIsSession(
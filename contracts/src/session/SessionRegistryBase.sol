@@ -11,12 +11,17 @@ contract SessionRegistryBase is DIDRecipient {
         bytes32 id;
         bytes token;
         bytes32 user;
+        // the access context the session was started in, which alone may revoke it besides its user
+        bytes32 context;
         uint256 expiration;
         bool exists;
     }
 
     mapping(bytes32 => SessionInfo) private _sessions;
 
+    event SessionStarted(bytes32 indexed id, bytes32 indexed user, uint256 expiration, bytes token);
+    event SessionRevoked(bytes32 indexed id);
+
     constructor(
         address didRegistry
     ) {
@@ -27,15 +32,18 @@ contract SessionRegistryBase is DIDRecipient {
         bytes32 _id,
         bytes memory _token,
         bytes32 _user,
+        bytes32 _context,
         uint256 duration
     ) internal {
         _sessions[_id] = SessionInfo({
             id: _id,
             token: _token,
             user: _user,
+            context: _context,
             exists: true,
             expiration: block.timestamp + duration
         });
+        emit SessionStarted(_id, _user, block.timestamp + duration, _token);
     }
 
     function _getSession(
@@ -48,6 +56,7 @@ contract SessionRegistryBase is DIDRecipient {
         bytes32 _id
     ) internal {
         delete _sessions[_id];
+        emit SessionRevoked(_id);
     }
 
     function _checkSessionUser(
@@ -63,6 +72,13 @@ contract SessionRegistryBase is DIDRecipient {
         return _user == _sessions[_id].user;
     }
 
+    function _checkSessionForContext(
+        bytes32 _id,
+        bytes32 _context
+    ) internal view returns (bool) {
+        return _context == _sessions[_id].context;
+    }
+
     function _checkSessionExists(
         bytes32 _id
     ) internal view returns (bool) {
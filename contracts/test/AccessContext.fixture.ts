@@ -0,0 +1,26 @@
+import hre from "hardhat";
+
+import { connectAccessContext, connectAccessContextHandler, connectDIDRegistry } from "../utils/connect";
+import { getCreateAccessContextProps, getPropsFromHre } from "../utils/props";
+
+export async function deployAccessContextFixture() {
+  await hre.deployments.fixture();
+  const { user, signer, ethers } = await getPropsFromHre(hre);
+  const AccessContextHandler = await connectAccessContextHandler(hre, signer);
+  const DIDRegistry = await connectDIDRegistry(hre, signer);
+
+  const { id: context, salt, did } = getCreateAccessContextProps(user);
+  await AccessContextHandler.createContextInstance(context, salt, did).then((tx) => tx.wait());
+  const address = await AccessContextHandler.getContextInstance(context);
+  const AccessContext = await connectAccessContext(hre, signer, address);
+
+  // verifiers of two policies with a different number of public inputs, and one that rejects every proof
+  const factory = await ethers.getContractFactory("PolicyVerifierMock", signer);
+  const verifiers = {
+    single: await factory.deploy(1, true).then((c) => c.getAddress()),
+    double: await factory.deploy(2, true).then((c) => c.getAddress()),
+    unsatisfied: await factory.deploy(1, false).then((c) => c.getAddress()),
+  };
+
+  return { user, did, context, verifiers, instances: { AccessContextHandler, AccessContext, DIDRegistry } };
+}
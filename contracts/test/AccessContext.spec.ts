@@ -0,0 +1,165 @@
+import { loadFixture } from "@nomicfoundation/hardhat-network-helpers";
+import { expect } from "chai";
+import { ethers } from "hardhat";
+
+import type { IPolicyVerifier } from "../types";
+import { deployAccessContextFixture } from "./AccessContext.fixture";
+
+const registerPolicy = "registerPolicy(bytes32,address,bytes32,bytes32)";
+const setupRole = "setupRole(bytes32,bytes32,bytes32,bytes32,uint8[],address,bytes32)";
+
+enum Operation {
+  READ,
+  WRITE,
+}
+
+describe("AccessContext Unit Tests", async () => {
+  const role = ethers.id("ROLE_MEMBER");
+  const policies = [ethers.id("POLICY_AGE"), ethers.id("POLICY_RESIDENCE")];
+  const proof: IPolicyVerifier.ProofStruct = {
+    a: { X: 0, Y: 0 },
+    b: { X: [0, 0], Y: [0, 0] },
+    c: { X: 0, Y: 0 },
+  };
+
+  // registers a single-input and a double-input policy and assigns both to `role`
+  async function setupRoleWithTwoPolicies() {
+    const fixture = await loadFixture(deployAccessContextFixture);
+    const { did, verifiers, instances } = fixture;
+    await instances.AccessContext[registerPolicy](policies[0], verifiers.single, role, did).then((tx) => tx.wait());
+    await instances.AccessContext[registerPolicy](policies[1], verifiers.double, role, did).then((tx) => tx.wait());
+    return fixture;
+  }
+
+  it("Owner assigns multiple policies to a role", async () => {
+    const { instances } = await setupRoleWithTwoPolicies();
+
+    expect(await instances.AccessContext.getRolePolicyCount(role)).to.equal(2);
+  });
+
+  it("User is granted a role by satisfying all of its policies", async () => {
+    const { did, context, instances } = await setupRoleWithTwoPolicies();
+    const { AccessContextHandler: handler, AccessContext: instance } = instances;
+
+    const inputs = [[1], [1, 2]];
+    const tx = handler.grantRole(context, role, did, [context, context], policies, [proof, proof], inputs);
+    await expect(tx).not.to.be.reverted;
+    expect(await instance.hasRole(role, did)).to.equal(true);
+  });
+
+  it("User is granted a role regardless of the order of its policies", async () => {
+    const { did, context, instances } = await setupRoleWithTwoPolicies();
+    const { AccessContextHandler: handler, AccessContext: instance } = instances;
+
+    const reversed = [policies[1], policies[0]];
+    const inputs = [[1, 2], [1]];
+    const tx = handler.grantRole(context, role, did, [context, context], reversed, [proof, proof], inputs);
+    await expect(tx).not.to.be.reverted;
+    expect(await instance.hasRole(role, did)).to.equal(true);
+  });
+
+  it("Role is not granted when a policy of the role is missing", async () => {
+    const { did, context, instances } = await setupRoleWithTwoPolicies();
+    const { AccessContextHandler: handler, AccessContext: instance } = instances;
+
+    const tx = handler.grantRole(context, role, did, [context], [policies[0]], [proof], [[1]]);
+    await expect(tx).to.be.revertedWith("policy count of role not matched");
+    expect(await instance.hasRole(role, did)).to.equal(false);
+  });
+
+  it("Role is not granted when a policy is given twice", async () => {
+    const { did, context, instances } = await setupRoleWithTwoPolicies();
+    const { AccessContextHandler: handler, AccessContext: instance } = instances;
+
+    const duplicated = [policies[0], policies[0]];
+    const tx = handler.grantRole(context, role, did, [context, context], duplicated, [proof, proof], [[1], [1]]);
+    await expect(tx).to.be.revertedWith("policy given twice");
+    expect(await instance.hasRole(role, did)).to.equal(false);
+  });
+
+  it("Role is not granted when the policy arguments differ in length", async () => {
+    const { did, context, instances } = await setupRoleWithTwoPolicies();
+    const { AccessContextHandler: handler } = instances;
+
+    const tx = handler.grantRole(context, role, did, [context, context], policies, [proof], [[1], [1, 2]]);
+    await expect(tx).to.be.revertedWith("policy arguments differ in length");
+  });
+
+  it("Role is not granted when a policy is not satisfied", async () => {
+    const { did, context, verifiers, instances } = await loadFixture(deployAccessContextFixture);
+    const { AccessContextHandler: handler, AccessContext: instance } = instances;
+    await instance[registerPolicy](policies[0], verifiers.unsatisfied, role, did).then((tx) => tx.wait());
+
+    const tx = handler.grantRole(context, role, did, [context], [policies[0]], [proof], [[1]]);
+    await expect(tx).to.be.revertedWith("policy not satisfied");
+    expect(await instance.hasRole(role, did)).to.equal(false);
+  });
+
+  describe("revokeRole", async () => {
+    const member = ethers.id("did:example:member");
+
+    // grants `role` to a did controlled by a second account, with a third account controlling neither did
+    async function grantRoleToMember() {
+      const fixture = await loadFixture(deployAccessContextFixture);
+      const { did, context, verifiers, instances } = fixture;
+      const [, memberAccount, stranger] = await ethers.getSigners();
+      const registry = instances.DIDRegistry.connect(memberAccount);
+      await registry.addController(member, memberAccount.address).then((tx) => tx.wait());
+      await instances.AccessContext[registerPolicy](policies[0], verifiers.single, role, did).then((tx) => tx.wait());
+      const handler = instances.AccessContextHandler;
+      await handler.grantRole(context, role, member, [context], [policies[0]], [proof], [[1]]).then((tx) => tx.wait());
+      return { ...fixture, memberAccount, stranger };
+    }
+
+    it("Owner revokes the role of a member", async () => {
+      const { instances } = await grantRoleToMember();
+
+      await expect(instances.AccessContext.revokeRole(role, member)).to.emit(instances.AccessContext, "RoleRevoked");
+      expect(await instances.AccessContext.hasRole(role, member)).to.equal(false);
+    });
+
+    it("Member revokes its own role", async () => {
+      const { memberAccount, instances } = await grantRoleToMember();
+
+      await expect(instances.AccessContext.connect(memberAccount).revokeRole(role, member)).not.to.be.reverted;
+      expect(await instances.AccessContext.hasRole(role, member)).to.equal(false);
+    });
+
+    it("Account controlling neither the owner nor the member cannot revoke a role", async () => {
+      const { stranger, instances } = await grantRoleToMember();
+
+      const tx = instances.AccessContext.connect(stranger).revokeRole(role, member);
+      await expect(tx).to.be.revertedWith("not allowed");
+      expect(await instances.AccessContext.hasRole(role, member)).to.equal(true);
+    });
+  });
+
+  describe("hasPermission", async () => {
+    const permission = ethers.id("PERMISSION_READ");
+    const resource = ethers.id("did:example:owner;data.csv");
+
+    it("Role holds only the operations permitted on its resource", async () => {
+      const { did, verifiers, instances } = await loadFixture(deployAccessContextFixture);
+      const { AccessContext: instance } = instances;
+      const operations = [Operation.READ];
+      await instance[setupRole](role, policies[0], permission, resource, operations, verifiers.single, did);
+
+      expect(await instance.hasPermission(role, permission, resource, Operation.READ)).to.equal(true);
+      expect(await instance.hasPermission(role, permission, resource, Operation.WRITE)).to.equal(false);
+      const otherResource = ethers.id("did:example:owner;other.csv");
+      expect(await instance.hasPermission(role, permission, otherResource, Operation.READ)).to.equal(false);
+      const otherRole = ethers.id("ROLE_OTHER");
+      expect(await instance.hasPermission(otherRole, permission, resource, Operation.READ)).to.equal(false);
+    });
+
+    it("Role permitted to write may also read", async () => {
+      const { did, verifiers, instances } = await loadFixture(deployAccessContextFixture);
+      const { AccessContext: instance } = instances;
+      const operations = [Operation.READ, Operation.WRITE];
+      await instance[setupRole](role, policies[0], permission, resource, operations, verifiers.single, did);
+
+      expect(await instance.hasPermission(role, permission, resource, Operation.READ)).to.equal(true);
+      expect(await instance.hasPermission(role, permission, resource, Operation.WRITE)).to.equal(true);
+    });
+  });
+});
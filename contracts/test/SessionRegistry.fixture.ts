@@ -0,0 +1,20 @@
+import hre from "hardhat";
+
+import { connectAccessContextHandler, connectSessionRegistry } from "../utils/connect";
+import { getCreateAccessContextProps, getPropsFromHre } from "../utils/props";
+
+export async function deploySessionRegistryFixture() {
+  await hre.deployments.fixture();
+  const { user, signer, ethers } = await getPropsFromHre(hre);
+  const AccessContextHandler = await connectAccessContextHandler(hre, signer);
+  const SessionRegistry = await connectSessionRegistry(hre, signer);
+  await AccessContextHandler.setSessionRegistry(await SessionRegistry.getAddress()).then((tx) => tx.wait());
+
+  // two access contexts of the same admin, so that a session of one can be revoked from the other
+  const { id: context, salt, did } = getCreateAccessContextProps(user);
+  const otherContext = ethers.id(`${user};other`);
+  await AccessContextHandler.createContextInstance(context, salt, did).then((tx) => tx.wait());
+  await AccessContextHandler.createContextInstance(otherContext, ethers.randomBytes(20), did).then((tx) => tx.wait());
+
+  return { user, did, contexts: { context, otherContext }, instances: { AccessContextHandler, SessionRegistry } };
+}
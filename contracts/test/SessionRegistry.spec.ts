@@ -0,0 +1,76 @@
+import { loadFixture, time } from "@nomicfoundation/hardhat-network-helpers";
+import { expect } from "chai";
+import { ethers } from "hardhat";
+
+import { deploySessionRegistryFixture } from "./SessionRegistry.fixture";
+
+const startSession = "startSession(bytes32,bytes32,bytes32,bytes,uint256)";
+
+describe("SessionRegistry Unit Tests", async () => {
+  const id = ethers.id("session");
+  const token = ethers.toUtf8Bytes("session");
+  const duration = time.duration.hours(1);
+
+  it("Handler starts a session for a user", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { AccessContextHandler: handler, SessionRegistry: registry } = instances;
+
+    await expect(handler[startSession](did, contexts.context, id, token, duration)).to.emit(registry, "SessionStarted");
+    expect(await registry.isSession(id, did)).to.equal(true);
+  });
+
+  it("Account other than the handler cannot start a session", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { SessionRegistry: registry } = instances;
+
+    const tx = registry.startSession(id, token, did, contexts.context, duration);
+    await expect(tx).to.be.revertedWith("not allowed");
+    expect(await registry.isSession(id, did)).to.equal(false);
+  });
+
+  it("Session longer than the maximum duration is rejected", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { AccessContextHandler: handler } = instances;
+    const maxDuration = time.duration.days(30);
+
+    const tx = handler[startSession](did, contexts.context, id, token, maxDuration + 1);
+    await expect(tx).to.be.revertedWith("session duration too long");
+    await expect(handler[startSession](did, contexts.context, id, token, maxDuration)).not.to.be.reverted;
+  });
+
+  it("Session of zero duration is rejected", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { AccessContextHandler: handler } = instances;
+
+    const tx = handler[startSession](did, contexts.context, id, token, 0);
+    await expect(tx).to.be.revertedWith("session duration is zero");
+  });
+
+  it("Admin revokes a session from the context it was started in", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { AccessContextHandler: handler, SessionRegistry: registry } = instances;
+    await handler[startSession](did, contexts.context, id, token, duration).then((tx) => tx.wait());
+
+    await expect(handler.revokeSession(id, contexts.context, did)).to.emit(registry, "SessionRevoked").withArgs(id);
+    expect(await registry.isSession(id, did)).to.equal(false);
+  });
+
+  it("Session cannot be revoked from a foreign context", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { AccessContextHandler: handler, SessionRegistry: registry } = instances;
+    await handler[startSession](did, contexts.context, id, token, duration).then((tx) => tx.wait());
+
+    const tx = handler.revokeSession(id, contexts.otherContext, did);
+    await expect(tx).to.be.revertedWith("session of another context");
+    expect(await registry.isSession(id, did)).to.equal(true);
+  });
+
+  it("Account other than the handler cannot revoke a context session", async () => {
+    const { did, contexts, instances } = await loadFixture(deploySessionRegistryFixture);
+    const { AccessContextHandler: handler, SessionRegistry: registry } = instances;
+    await handler[startSession](did, contexts.context, id, token, duration).then((tx) => tx.wait());
+
+    await expect(registry.revokeContextSession(id, contexts.context)).to.be.revertedWith("not allowed");
+    expect(await registry.isSession(id, did)).to.equal(true);
+  });
+});
@@ -1,8 +1,21 @@
 import type { ethers } from "ethers";
 import type { HardhatRuntimeEnvironment } from "hardhat/types";
 
+import didRegistryConfig from "../deploy/001_DIDRegistry";
 import contextHandlerConfig from "../deploy/002_AccessContextHandler";
-import { AccessContextHandler__factory, AccessContext__factory, IPolicyVerifier__factory } from "../types";
+import sessionRegistryConfig from "../deploy/003_SessionRegistry";
+import {
+  AccessContextHandler__factory,
+  AccessContext__factory,
+  IPolicyVerifier__factory,
+  SessionRegistry__factory,
+  SimpleDIDRegistry__factory,
+} from "../types";
+
+export async function connectDIDRegistry(hre: HardhatRuntimeEnvironment, signer: ethers.Signer) {
+  const address = await hre.deployments.get(didRegistryConfig.id ?? "").then((d) => d.address);
+  return SimpleDIDRegistry__factory.connect(address, signer);
+}
 
 export async function connectAccessContextHandler(hre: HardhatRuntimeEnvironment, signer: ethers.Signer) {
   const address = await hre.deployments.get(contextHandlerConfig.id ?? "").then((d) => d.address);
@@ -13,6 +26,11 @@ export async function connectAccessContext(_: HardhatRuntimeEnvironment, signer:
   return AccessContext__factory.connect(address, signer);
 }
 
+export async function connectSessionRegistry(hre: HardhatRuntimeEnvironment, signer: ethers.Signer) {
+  const address = await hre.deployments.get(sessionRegistryConfig.id ?? "").then((d) => d.address);
+  return SessionRegistry__factory.connect(address, signer);
+}
+
 export async function connectPolicyVerifier(hre: HardhatRuntimeEnvironment, signer: ethers.Signer, name: string) {
   const address = await hre.deployments.get(name).then((d) => d.address);
   return IPolicyVerifier__factory.connect(address, signer);
@@ -0,0 +1,7 @@
+package main
+
+import "github.com/fapiper/onchain-access-control/core/deploy"
+
+func main() {
+	deploy.Run()
+}
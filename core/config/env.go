@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/viper"
 	"path"
 	"path/filepath"
+	"strings"
 )
 
 const (
@@ -25,7 +26,7 @@ const (
 )
 
 // LoadEnv finds the appropriate env file to use for the service
-// and configures the environment with the configured input file.
+// and configures the environment with the configured input file, which may also be a .toml file.
 func LoadEnv() (string, error) {
 	envVarPath := viper.GetString(EnvPath.String())
 
@@ -34,6 +35,9 @@ func LoadEnv() (string, error) {
 	logrus.Infof("loading config from env path: %s", envFilePath)
 
 	viper.SetConfigType("env")
+	if strings.EqualFold(filepath.Ext(envFilePath), ".toml") {
+		viper.SetConfigType("toml")
+	}
 	viper.SetConfigName(".env")
 	viper.SetConfigFile(envFilePath)
 
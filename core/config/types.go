@@ -57,10 +57,69 @@ type ServicesConfig struct {
 	FileStoreConfig  FileStoreServiceConfig  `toml:"filestore,omitempty"`
 	DIDConfig        DIDServiceConfig        `toml:"did,omitempty"`
 	CredentialConfig CredentialServiceConfig `toml:"credential,omitempty"`
+	IndexerConfig    IndexerServiceConfig    `toml:"indexer,omitempty"`
+	ProverConfig     ProverServiceConfig     `toml:"prover,omitempty"`
+	OperationConfig  OperationServiceConfig  `toml:"operation,omitempty"`
 }
 
 type AuthServiceConfig struct {
 	EncryptionConfig
+
+	// SessionTTL is the lifetime of issued session tokens and of the sessions started for them in the session registry.
+	// Sessions that outlive it are rejected, even if the token itself claims a later expiry.
+	SessionTTL time.Duration `toml:"session_ttl" conf:"default:24h"`
+	// SessionSweepInterval is the interval in which stored sessions are checked for expiry. A value of 0 disables
+	// the sweeper.
+	SessionSweepInterval time.Duration `toml:"session_sweep_interval" conf:"default:1m"`
+	// RevokeExpiredSessions makes the sweeper also revoke expired sessions on-chain.
+	RevokeExpiredSessions bool `toml:"revoke_expired_sessions" conf:"default:false"`
+	// SessionPickupInterval is the interval in which sessions started on-chain are picked up from the indexer,
+	// decrypted and stored. A value of 0 disables the pickup.
+	SessionPickupInterval time.Duration `toml:"session_pickup_interval" conf:"default:15s"`
+	// VerificationKeyDir is a directory of policy verification keys, named after the hex encoded policy identifier,
+	// e.g. `0x<context hash>+0x<policy hash>.key`. Proofs of policies without a local key are verified against the key pinned to ipfs.
+	VerificationKeyDir string `toml:"verification_key_dir"`
+}
+
+// DefaultSessionTTL is the session lifetime used when none is configured
+const DefaultSessionTTL = 24 * time.Hour
+
+// GetSessionTTL returns the configured session lifetime, falling back to DefaultSessionTTL when unset
+func (a AuthServiceConfig) GetSessionTTL() time.Duration {
+	if a.SessionTTL <= 0 {
+		return DefaultSessionTTL
+	}
+	return a.SessionTTL
+}
+
+type IndexerServiceConfig struct {
+	// PollInterval is the interval in which new blocks are indexed. A value of 0 disables the indexer.
+	PollInterval time.Duration `toml:"poll_interval" conf:"default:12s"`
+	// StartBlock is the first block that is indexed, e.g. the deployment block of the contracts.
+	StartBlock uint64 `toml:"start_block" conf:"default:0"`
+	// BatchSize is the maximum number of blocks queried for logs at once.
+	BatchSize uint64 `toml:"batch_size" conf:"default:2000"`
+	// Confirmations is the number of blocks the indexer stays behind the chain head.
+	Confirmations uint64 `toml:"confirmations" conf:"default:0"`
+	// ReorgDepth is the number of blocks kept to detect and roll back chain reorganizations. The logs of older blocks
+	// are folded into a snapshot of the read model and pruned.
+	ReorgDepth uint64 `toml:"reorg_depth" conf:"default:128"`
+}
+
+type ProverServiceConfig struct {
+	// Backend proves policies either with ZoKrates programs, "zokrates", or with the built-in circuits in pure Go,
+	// "native".
+	Backend string `toml:"backend" conf:"default:zokrates"`
+	// ZoKratesBinary is the path of the zokrates executable that proves policies with ZoKrates programs.
+	ZoKratesBinary string `toml:"zokrates_binary" conf:"default:zokrates"`
+}
+
+type OperationServiceConfig struct {
+	// Confirmations is the number of blocks, including the one it was mined in, a transaction needs before the
+	// operation tracking it is done.
+	Confirmations uint64 `toml:"confirmations" conf:"default:1"`
+	// PollInterval is the interval in which the receipts and confirmations of pending transactions are checked.
+	PollInterval time.Duration `toml:"poll_interval" conf:"default:2s"`
 }
 
 type KeyStoreServiceConfig struct {
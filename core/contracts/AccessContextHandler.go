@@ -50,7 +50,7 @@ type PairingG2Point struct {
 
 // AccessContextHandlerMetaData contains all meta data concerning the AccessContextHandler contract.
 var AccessContextHandlerMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"didRegistry\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"ERC1167FailedCreateClone\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"accessContext\",\"type\":\"address\"}],\"name\":\"CreateContextInstance\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes20\",\"name\":\"_salt\",\"type\":\"bytes20\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"createContextInstance\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"deleteContextInstance\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"getContextInstance\",\"outputs\":[{\"internalType\":\"contractIContextInstance\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_roleContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_role\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policyContexts\",\"type\":\"bytes32[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policies\",\"type\":\"bytes32[]\"},{\"components\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"a\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structPairing.G2Point\",\"name\":\"b\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"c\",\"type\":\"tuple\"}],\"internalType\":\"structIPolicyVerifier.Proof[]\",\"name\":\"_proofs\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256[20][]\",\"name\":\"_inputs\",\"type\":\"uint256[20][]\"}],\"name\":\"grantRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"isSession\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_roleContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_role\",\"type\":\"bytes32\"}],\"name\":\"isSession\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"sessionRegistry\",\"type\":\"address\"}],\"name\":\"setSessionRegistry\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_tokenId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_token\",\"type\":\"bytes\"}],\"name\":\"startSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_roleContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_role\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policyContexts\",\"type\":\"bytes32[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policies\",\"type\":\"bytes32[]\"},{\"components\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"a\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structPairing.G2Point\",\"name\":\"b\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"c\",\"type\":\"tuple\"}],\"internalType\":\"structIPolicyVerifier.Proof[]\",\"name\":\"_proofs\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256[20][]\",\"name\":\"_inputs\",\"type\":\"uint256[20][]\"},{\"internalType\":\"bytes32\",\"name\":\"_tokenId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_token\",\"type\":\"bytes\"}],\"name\":\"startSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"didRegistry\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"ERC1167FailedCreateClone\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"accessContext\",\"type\":\"address\"}],\"name\":\"CreateContextInstance\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes20\",\"name\":\"_salt\",\"type\":\"bytes20\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"createContextInstance\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"deleteContextInstance\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"getContextInstance\",\"outputs\":[{\"internalType\":\"contractIContextInstance\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getDIDRegistry\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getInstanceImpl\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getSessionRegistry\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_roleContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_role\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policyContexts\",\"type\":\"bytes32[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policies\",\"type\":\"bytes32[]\"},{\"components\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"a\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structPairing.G2Point\",\"name\":\"b\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"c\",\"type\":\"tuple\"}],\"internalType\":\"structIPolicyVerifier.Proof[]\",\"name\":\"_proofs\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256[][]\",\"name\":\"_inputs\",\"type\":\"uint256[][]\"}],\"name\":\"grantRole\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"isSession\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_roleContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_role\",\"type\":\"bytes32\"}],\"name\":\"isSession\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_tokenId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_context\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"}],\"name\":\"revokeSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"sessionRegistry\",\"type\":\"address\"}],\"name\":\"setSessionRegistry\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_context\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_tokenId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_token\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"_duration\",\"type\":\"uint256\"}],\"name\":\"startSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_roleContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_role\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_did\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policyContexts\",\"type\":\"bytes32[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"_policies\",\"type\":\"bytes32[]\"},{\"components\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"a\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structPairing.G2Point\",\"name\":\"b\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"c\",\"type\":\"tuple\"}],\"internalType\":\"structIPolicyVerifier.Proof[]\",\"name\":\"_proofs\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256[][]\",\"name\":\"_inputs\",\"type\":\"uint256[][]\"},{\"internalType\":\"bytes32\",\"name\":\"_tokenId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_token\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"_duration\",\"type\":\"uint256\"}],\"name\":\"startSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
 }
 
 // AccessContextHandlerABI is the input ABI used to generate the binding from.
@@ -230,6 +230,99 @@ func (_AccessContextHandler *AccessContextHandlerCallerSession) GetContextInstan
 	return _AccessContextHandler.Contract.GetContextInstance(&_AccessContextHandler.CallOpts, _id)
 }
 
+// GetDIDRegistry is a free data retrieval call binding the contract method 0x0cf8d55b.
+//
+// Solidity: function getDIDRegistry() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerCaller) GetDIDRegistry(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AccessContextHandler.contract.Call(opts, &out, "getDIDRegistry")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetDIDRegistry is a free data retrieval call binding the contract method 0x0cf8d55b.
+//
+// Solidity: function getDIDRegistry() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerSession) GetDIDRegistry() (common.Address, error) {
+	return _AccessContextHandler.Contract.GetDIDRegistry(&_AccessContextHandler.CallOpts)
+}
+
+// GetDIDRegistry is a free data retrieval call binding the contract method 0x0cf8d55b.
+//
+// Solidity: function getDIDRegistry() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerCallerSession) GetDIDRegistry() (common.Address, error) {
+	return _AccessContextHandler.Contract.GetDIDRegistry(&_AccessContextHandler.CallOpts)
+}
+
+// GetInstanceImpl is a free data retrieval call binding the contract method 0xc990a76d.
+//
+// Solidity: function getInstanceImpl() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerCaller) GetInstanceImpl(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AccessContextHandler.contract.Call(opts, &out, "getInstanceImpl")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetInstanceImpl is a free data retrieval call binding the contract method 0xc990a76d.
+//
+// Solidity: function getInstanceImpl() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerSession) GetInstanceImpl() (common.Address, error) {
+	return _AccessContextHandler.Contract.GetInstanceImpl(&_AccessContextHandler.CallOpts)
+}
+
+// GetInstanceImpl is a free data retrieval call binding the contract method 0xc990a76d.
+//
+// Solidity: function getInstanceImpl() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerCallerSession) GetInstanceImpl() (common.Address, error) {
+	return _AccessContextHandler.Contract.GetInstanceImpl(&_AccessContextHandler.CallOpts)
+}
+
+// GetSessionRegistry is a free data retrieval call binding the contract method 0xdcbedb89.
+//
+// Solidity: function getSessionRegistry() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerCaller) GetSessionRegistry(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AccessContextHandler.contract.Call(opts, &out, "getSessionRegistry")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetSessionRegistry is a free data retrieval call binding the contract method 0xdcbedb89.
+//
+// Solidity: function getSessionRegistry() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerSession) GetSessionRegistry() (common.Address, error) {
+	return _AccessContextHandler.Contract.GetSessionRegistry(&_AccessContextHandler.CallOpts)
+}
+
+// GetSessionRegistry is a free data retrieval call binding the contract method 0xdcbedb89.
+//
+// Solidity: function getSessionRegistry() view returns(address)
+func (_AccessContextHandler *AccessContextHandlerCallerSession) GetSessionRegistry() (common.Address, error) {
+	return _AccessContextHandler.Contract.GetSessionRegistry(&_AccessContextHandler.CallOpts)
+}
+
 // CreateContextInstance is a paid mutator transaction binding the contract method 0x656c4309.
 //
 // Solidity: function createContextInstance(bytes32 _id, bytes20 _salt, bytes32 _did) returns()
@@ -272,24 +365,24 @@ func (_AccessContextHandler *AccessContextHandlerTransactorSession) DeleteContex
 	return _AccessContextHandler.Contract.DeleteContextInstance(&_AccessContextHandler.TransactOpts, _id, _did)
 }
 
-// GrantRole is a paid mutator transaction binding the contract method 0x86490b3e.
+// GrantRole is a paid mutator transaction binding the contract method 0xc6f55f91.
 //
-// Solidity: function grantRole(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[20][] _inputs) returns()
-func (_AccessContextHandler *AccessContextHandlerTransactor) GrantRole(opts *bind.TransactOpts, _roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][20]*big.Int) (*types.Transaction, error) {
+// Solidity: function grantRole(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[][] _inputs) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactor) GrantRole(opts *bind.TransactOpts, _roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][]*big.Int) (*types.Transaction, error) {
 	return _AccessContextHandler.contract.Transact(opts, "grantRole", _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs)
 }
 
-// GrantRole is a paid mutator transaction binding the contract method 0x86490b3e.
+// GrantRole is a paid mutator transaction binding the contract method 0xc6f55f91.
 //
-// Solidity: function grantRole(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[20][] _inputs) returns()
-func (_AccessContextHandler *AccessContextHandlerSession) GrantRole(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][20]*big.Int) (*types.Transaction, error) {
+// Solidity: function grantRole(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[][] _inputs) returns()
+func (_AccessContextHandler *AccessContextHandlerSession) GrantRole(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][]*big.Int) (*types.Transaction, error) {
 	return _AccessContextHandler.Contract.GrantRole(&_AccessContextHandler.TransactOpts, _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs)
 }
 
-// GrantRole is a paid mutator transaction binding the contract method 0x86490b3e.
+// GrantRole is a paid mutator transaction binding the contract method 0xc6f55f91.
 //
-// Solidity: function grantRole(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[20][] _inputs) returns()
-func (_AccessContextHandler *AccessContextHandlerTransactorSession) GrantRole(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][20]*big.Int) (*types.Transaction, error) {
+// Solidity: function grantRole(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[][] _inputs) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactorSession) GrantRole(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][]*big.Int) (*types.Transaction, error) {
 	return _AccessContextHandler.Contract.GrantRole(&_AccessContextHandler.TransactOpts, _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs)
 }
 
@@ -335,6 +428,27 @@ func (_AccessContextHandler *AccessContextHandlerTransactorSession) IsSession0(_
 	return _AccessContextHandler.Contract.IsSession0(&_AccessContextHandler.TransactOpts, _id, _did, _roleContext, _role)
 }
 
+// RevokeSession is a paid mutator transaction binding the contract method 0x88b290a5.
+//
+// Solidity: function revokeSession(bytes32 _tokenId, bytes32 _context, bytes32 _did) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactor) RevokeSession(opts *bind.TransactOpts, _tokenId [32]byte, _context [32]byte, _did [32]byte) (*types.Transaction, error) {
+	return _AccessContextHandler.contract.Transact(opts, "revokeSession", _tokenId, _context, _did)
+}
+
+// RevokeSession is a paid mutator transaction binding the contract method 0x88b290a5.
+//
+// Solidity: function revokeSession(bytes32 _tokenId, bytes32 _context, bytes32 _did) returns()
+func (_AccessContextHandler *AccessContextHandlerSession) RevokeSession(_tokenId [32]byte, _context [32]byte, _did [32]byte) (*types.Transaction, error) {
+	return _AccessContextHandler.Contract.RevokeSession(&_AccessContextHandler.TransactOpts, _tokenId, _context, _did)
+}
+
+// RevokeSession is a paid mutator transaction binding the contract method 0x88b290a5.
+//
+// Solidity: function revokeSession(bytes32 _tokenId, bytes32 _context, bytes32 _did) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactorSession) RevokeSession(_tokenId [32]byte, _context [32]byte, _did [32]byte) (*types.Transaction, error) {
+	return _AccessContextHandler.Contract.RevokeSession(&_AccessContextHandler.TransactOpts, _tokenId, _context, _did)
+}
+
 // SetSessionRegistry is a paid mutator transaction binding the contract method 0x4fd9d24b.
 //
 // Solidity: function setSessionRegistry(address sessionRegistry) returns()
@@ -356,46 +470,46 @@ func (_AccessContextHandler *AccessContextHandlerTransactorSession) SetSessionRe
 	return _AccessContextHandler.Contract.SetSessionRegistry(&_AccessContextHandler.TransactOpts, sessionRegistry)
 }
 
-// StartSession is a paid mutator transaction binding the contract method 0x9e41f61e.
+// StartSession is a paid mutator transaction binding the contract method 0xb42b4b34.
 //
-// Solidity: function startSession(bytes32 _did, bytes32 _tokenId, bytes _token) returns()
-func (_AccessContextHandler *AccessContextHandlerTransactor) StartSession(opts *bind.TransactOpts, _did [32]byte, _tokenId [32]byte, _token []byte) (*types.Transaction, error) {
-	return _AccessContextHandler.contract.Transact(opts, "startSession", _did, _tokenId, _token)
+// Solidity: function startSession(bytes32 _did, bytes32 _context, bytes32 _tokenId, bytes _token, uint256 _duration) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactor) StartSession(opts *bind.TransactOpts, _did [32]byte, _context [32]byte, _tokenId [32]byte, _token []byte, _duration *big.Int) (*types.Transaction, error) {
+	return _AccessContextHandler.contract.Transact(opts, "startSession", _did, _context, _tokenId, _token, _duration)
 }
 
-// StartSession is a paid mutator transaction binding the contract method 0x9e41f61e.
+// StartSession is a paid mutator transaction binding the contract method 0xb42b4b34.
 //
-// Solidity: function startSession(bytes32 _did, bytes32 _tokenId, bytes _token) returns()
-func (_AccessContextHandler *AccessContextHandlerSession) StartSession(_did [32]byte, _tokenId [32]byte, _token []byte) (*types.Transaction, error) {
-	return _AccessContextHandler.Contract.StartSession(&_AccessContextHandler.TransactOpts, _did, _tokenId, _token)
+// Solidity: function startSession(bytes32 _did, bytes32 _context, bytes32 _tokenId, bytes _token, uint256 _duration) returns()
+func (_AccessContextHandler *AccessContextHandlerSession) StartSession(_did [32]byte, _context [32]byte, _tokenId [32]byte, _token []byte, _duration *big.Int) (*types.Transaction, error) {
+	return _AccessContextHandler.Contract.StartSession(&_AccessContextHandler.TransactOpts, _did, _context, _tokenId, _token, _duration)
 }
 
-// StartSession is a paid mutator transaction binding the contract method 0x9e41f61e.
+// StartSession is a paid mutator transaction binding the contract method 0xb42b4b34.
 //
-// Solidity: function startSession(bytes32 _did, bytes32 _tokenId, bytes _token) returns()
-func (_AccessContextHandler *AccessContextHandlerTransactorSession) StartSession(_did [32]byte, _tokenId [32]byte, _token []byte) (*types.Transaction, error) {
-	return _AccessContextHandler.Contract.StartSession(&_AccessContextHandler.TransactOpts, _did, _tokenId, _token)
+// Solidity: function startSession(bytes32 _did, bytes32 _context, bytes32 _tokenId, bytes _token, uint256 _duration) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactorSession) StartSession(_did [32]byte, _context [32]byte, _tokenId [32]byte, _token []byte, _duration *big.Int) (*types.Transaction, error) {
+	return _AccessContextHandler.Contract.StartSession(&_AccessContextHandler.TransactOpts, _did, _context, _tokenId, _token, _duration)
 }
 
-// StartSession0 is a paid mutator transaction binding the contract method 0xc83992ef.
+// StartSession0 is a paid mutator transaction binding the contract method 0xca08b281.
 //
-// Solidity: function startSession(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[20][] _inputs, bytes32 _tokenId, bytes _token) returns()
-func (_AccessContextHandler *AccessContextHandlerTransactor) StartSession0(opts *bind.TransactOpts, _roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][20]*big.Int, _tokenId [32]byte, _token []byte) (*types.Transaction, error) {
-	return _AccessContextHandler.contract.Transact(opts, "startSession0", _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs, _tokenId, _token)
+// Solidity: function startSession(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[][] _inputs, bytes32 _tokenId, bytes _token, uint256 _duration) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactor) StartSession0(opts *bind.TransactOpts, _roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][]*big.Int, _tokenId [32]byte, _token []byte, _duration *big.Int) (*types.Transaction, error) {
+	return _AccessContextHandler.contract.Transact(opts, "startSession0", _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs, _tokenId, _token, _duration)
 }
 
-// StartSession0 is a paid mutator transaction binding the contract method 0xc83992ef.
+// StartSession0 is a paid mutator transaction binding the contract method 0xca08b281.
 //
-// Solidity: function startSession(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[20][] _inputs, bytes32 _tokenId, bytes _token) returns()
-func (_AccessContextHandler *AccessContextHandlerSession) StartSession0(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][20]*big.Int, _tokenId [32]byte, _token []byte) (*types.Transaction, error) {
-	return _AccessContextHandler.Contract.StartSession0(&_AccessContextHandler.TransactOpts, _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs, _tokenId, _token)
+// Solidity: function startSession(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[][] _inputs, bytes32 _tokenId, bytes _token, uint256 _duration) returns()
+func (_AccessContextHandler *AccessContextHandlerSession) StartSession0(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][]*big.Int, _tokenId [32]byte, _token []byte, _duration *big.Int) (*types.Transaction, error) {
+	return _AccessContextHandler.Contract.StartSession0(&_AccessContextHandler.TransactOpts, _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs, _tokenId, _token, _duration)
 }
 
-// StartSession0 is a paid mutator transaction binding the contract method 0xc83992ef.
+// StartSession0 is a paid mutator transaction binding the contract method 0xca08b281.
 //
-// Solidity: function startSession(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[20][] _inputs, bytes32 _tokenId, bytes _token) returns()
-func (_AccessContextHandler *AccessContextHandlerTransactorSession) StartSession0(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][20]*big.Int, _tokenId [32]byte, _token []byte) (*types.Transaction, error) {
-	return _AccessContextHandler.Contract.StartSession0(&_AccessContextHandler.TransactOpts, _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs, _tokenId, _token)
+// Solidity: function startSession(bytes32 _roleContext, bytes32 _role, bytes32 _did, bytes32[] _policyContexts, bytes32[] _policies, ((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256))[] _proofs, uint256[][] _inputs, bytes32 _tokenId, bytes _token, uint256 _duration) returns()
+func (_AccessContextHandler *AccessContextHandlerTransactorSession) StartSession0(_roleContext [32]byte, _role [32]byte, _did [32]byte, _policyContexts [][32]byte, _policies [][32]byte, _proofs []IPolicyVerifierProof, _inputs [][]*big.Int, _tokenId [32]byte, _token []byte, _duration *big.Int) (*types.Transaction, error) {
+	return _AccessContextHandler.Contract.StartSession0(&_AccessContextHandler.TransactOpts, _roleContext, _role, _did, _policyContexts, _policies, _proofs, _inputs, _tokenId, _token, _duration)
 }
 
 // AccessContextHandlerCreateContextInstanceIterator is returned from FilterCreateContextInstance and is used to iterate over the raw logs and unpacked data for CreateContextInstance events raised by the AccessContextHandler contract.
@@ -0,0 +1,406 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// VerifierProof is an auto generated low-level Go binding around an user-defined struct.
+type VerifierProof struct {
+	A PairingG1Point
+	B PairingG2Point
+	C PairingG1Point
+}
+
+// PolicyVerifierMetaData contains all meta data concerning the PolicyVerifier contract.
+var PolicyVerifierMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_presentationDefinition\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_proofProgram\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_provingKey\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_verificationKey\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"inputCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"count\",\"type\":\"uint256\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"presentationDefinition\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"proofProgram\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"provingKey\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"verificationKey\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"a\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structPairing.G2Point\",\"name\":\"b\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"c\",\"type\":\"tuple\"}],\"internalType\":\"structVerifier.Proof\",\"name\":\"proof\",\"type\":\"tuple\"},{\"internalType\":\"uint256[20]\",\"name\":\"input\",\"type\":\"uint256[20]\"}],\"name\":\"verifyTx\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"r\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"a\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structPairing.G2Point\",\"name\":\"b\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structPairing.G1Point\",\"name\":\"c\",\"type\":\"tuple\"}],\"internalType\":\"structVerifier.Proof\",\"name\":\"proof\",\"type\":\"tuple\"},{\"internalType\":\"uint256[]\",\"name\":\"input\",\"type\":\"uint256[]\"}],\"name\":\"verifyTx\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"r\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// PolicyVerifierABI is the input ABI used to generate the binding from.
+// Deprecated: Use PolicyVerifierMetaData.ABI instead.
+var PolicyVerifierABI = PolicyVerifierMetaData.ABI
+
+// PolicyVerifier is an auto generated Go binding around an Ethereum contract.
+type PolicyVerifier struct {
+	PolicyVerifierCaller     // Read-only binding to the contract
+	PolicyVerifierTransactor // Write-only binding to the contract
+	PolicyVerifierFilterer   // Log filterer for contract events
+}
+
+// PolicyVerifierCaller is an auto generated read-only Go binding around an Ethereum contract.
+type PolicyVerifierCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// PolicyVerifierTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type PolicyVerifierTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// PolicyVerifierFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type PolicyVerifierFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// PolicyVerifierSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type PolicyVerifierSession struct {
+	Contract     *PolicyVerifier   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// PolicyVerifierCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type PolicyVerifierCallerSession struct {
+	Contract *PolicyVerifierCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// PolicyVerifierTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type PolicyVerifierTransactorSession struct {
+	Contract     *PolicyVerifierTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// PolicyVerifierRaw is an auto generated low-level Go binding around an Ethereum contract.
+type PolicyVerifierRaw struct {
+	Contract *PolicyVerifier // Generic contract binding to access the raw methods on
+}
+
+// PolicyVerifierCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type PolicyVerifierCallerRaw struct {
+	Contract *PolicyVerifierCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// PolicyVerifierTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type PolicyVerifierTransactorRaw struct {
+	Contract *PolicyVerifierTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewPolicyVerifier creates a new instance of PolicyVerifier, bound to a specific deployed contract.
+func NewPolicyVerifier(address common.Address, backend bind.ContractBackend) (*PolicyVerifier, error) {
+	contract, err := bindPolicyVerifier(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyVerifier{PolicyVerifierCaller: PolicyVerifierCaller{contract: contract}, PolicyVerifierTransactor: PolicyVerifierTransactor{contract: contract}, PolicyVerifierFilterer: PolicyVerifierFilterer{contract: contract}}, nil
+}
+
+// NewPolicyVerifierCaller creates a new read-only instance of PolicyVerifier, bound to a specific deployed contract.
+func NewPolicyVerifierCaller(address common.Address, caller bind.ContractCaller) (*PolicyVerifierCaller, error) {
+	contract, err := bindPolicyVerifier(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyVerifierCaller{contract: contract}, nil
+}
+
+// NewPolicyVerifierTransactor creates a new write-only instance of PolicyVerifier, bound to a specific deployed contract.
+func NewPolicyVerifierTransactor(address common.Address, transactor bind.ContractTransactor) (*PolicyVerifierTransactor, error) {
+	contract, err := bindPolicyVerifier(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyVerifierTransactor{contract: contract}, nil
+}
+
+// NewPolicyVerifierFilterer creates a new log filterer instance of PolicyVerifier, bound to a specific deployed contract.
+func NewPolicyVerifierFilterer(address common.Address, filterer bind.ContractFilterer) (*PolicyVerifierFilterer, error) {
+	contract, err := bindPolicyVerifier(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyVerifierFilterer{contract: contract}, nil
+}
+
+// bindPolicyVerifier binds a generic wrapper to an already deployed contract.
+func bindPolicyVerifier(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := PolicyVerifierMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_PolicyVerifier *PolicyVerifierRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _PolicyVerifier.Contract.PolicyVerifierCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_PolicyVerifier *PolicyVerifierRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _PolicyVerifier.Contract.PolicyVerifierTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_PolicyVerifier *PolicyVerifierRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _PolicyVerifier.Contract.PolicyVerifierTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_PolicyVerifier *PolicyVerifierCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _PolicyVerifier.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_PolicyVerifier *PolicyVerifierTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _PolicyVerifier.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_PolicyVerifier *PolicyVerifierTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _PolicyVerifier.Contract.contract.Transact(opts, method, params...)
+}
+
+// InputCount is a free data retrieval call binding the contract method 0xe7dc36ad.
+//
+// Solidity: function inputCount() pure returns(uint256 count)
+func (_PolicyVerifier *PolicyVerifierCaller) InputCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "inputCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// InputCount is a free data retrieval call binding the contract method 0xe7dc36ad.
+//
+// Solidity: function inputCount() pure returns(uint256 count)
+func (_PolicyVerifier *PolicyVerifierSession) InputCount() (*big.Int, error) {
+	return _PolicyVerifier.Contract.InputCount(&_PolicyVerifier.CallOpts)
+}
+
+// InputCount is a free data retrieval call binding the contract method 0xe7dc36ad.
+//
+// Solidity: function inputCount() pure returns(uint256 count)
+func (_PolicyVerifier *PolicyVerifierCallerSession) InputCount() (*big.Int, error) {
+	return _PolicyVerifier.Contract.InputCount(&_PolicyVerifier.CallOpts)
+}
+
+// PresentationDefinition is a free data retrieval call binding the contract method 0x4b820683.
+//
+// Solidity: function presentationDefinition() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCaller) PresentationDefinition(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "presentationDefinition")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// PresentationDefinition is a free data retrieval call binding the contract method 0x4b820683.
+//
+// Solidity: function presentationDefinition() view returns(string)
+func (_PolicyVerifier *PolicyVerifierSession) PresentationDefinition() (string, error) {
+	return _PolicyVerifier.Contract.PresentationDefinition(&_PolicyVerifier.CallOpts)
+}
+
+// PresentationDefinition is a free data retrieval call binding the contract method 0x4b820683.
+//
+// Solidity: function presentationDefinition() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCallerSession) PresentationDefinition() (string, error) {
+	return _PolicyVerifier.Contract.PresentationDefinition(&_PolicyVerifier.CallOpts)
+}
+
+// ProofProgram is a free data retrieval call binding the contract method 0xa6eac93b.
+//
+// Solidity: function proofProgram() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCaller) ProofProgram(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "proofProgram")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// ProofProgram is a free data retrieval call binding the contract method 0xa6eac93b.
+//
+// Solidity: function proofProgram() view returns(string)
+func (_PolicyVerifier *PolicyVerifierSession) ProofProgram() (string, error) {
+	return _PolicyVerifier.Contract.ProofProgram(&_PolicyVerifier.CallOpts)
+}
+
+// ProofProgram is a free data retrieval call binding the contract method 0xa6eac93b.
+//
+// Solidity: function proofProgram() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCallerSession) ProofProgram() (string, error) {
+	return _PolicyVerifier.Contract.ProofProgram(&_PolicyVerifier.CallOpts)
+}
+
+// ProvingKey is a free data retrieval call binding the contract method 0x737fe388.
+//
+// Solidity: function provingKey() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCaller) ProvingKey(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "provingKey")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// ProvingKey is a free data retrieval call binding the contract method 0x737fe388.
+//
+// Solidity: function provingKey() view returns(string)
+func (_PolicyVerifier *PolicyVerifierSession) ProvingKey() (string, error) {
+	return _PolicyVerifier.Contract.ProvingKey(&_PolicyVerifier.CallOpts)
+}
+
+// ProvingKey is a free data retrieval call binding the contract method 0x737fe388.
+//
+// Solidity: function provingKey() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCallerSession) ProvingKey() (string, error) {
+	return _PolicyVerifier.Contract.ProvingKey(&_PolicyVerifier.CallOpts)
+}
+
+// VerificationKey is a free data retrieval call binding the contract method 0x7ddc907d.
+//
+// Solidity: function verificationKey() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCaller) VerificationKey(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "verificationKey")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// VerificationKey is a free data retrieval call binding the contract method 0x7ddc907d.
+//
+// Solidity: function verificationKey() view returns(string)
+func (_PolicyVerifier *PolicyVerifierSession) VerificationKey() (string, error) {
+	return _PolicyVerifier.Contract.VerificationKey(&_PolicyVerifier.CallOpts)
+}
+
+// VerificationKey is a free data retrieval call binding the contract method 0x7ddc907d.
+//
+// Solidity: function verificationKey() view returns(string)
+func (_PolicyVerifier *PolicyVerifierCallerSession) VerificationKey() (string, error) {
+	return _PolicyVerifier.Contract.VerificationKey(&_PolicyVerifier.CallOpts)
+}
+
+// VerifyTx is a free data retrieval call binding the contract method 0xdd9d5523.
+//
+// Solidity: function verifyTx(((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256)) proof, uint256[20] input) view returns(bool r)
+func (_PolicyVerifier *PolicyVerifierCaller) VerifyTx(opts *bind.CallOpts, proof VerifierProof, input [20]*big.Int) (bool, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "verifyTx", proof, input)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifyTx is a free data retrieval call binding the contract method 0xdd9d5523.
+//
+// Solidity: function verifyTx(((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256)) proof, uint256[20] input) view returns(bool r)
+func (_PolicyVerifier *PolicyVerifierSession) VerifyTx(proof VerifierProof, input [20]*big.Int) (bool, error) {
+	return _PolicyVerifier.Contract.VerifyTx(&_PolicyVerifier.CallOpts, proof, input)
+}
+
+// VerifyTx is a free data retrieval call binding the contract method 0xdd9d5523.
+//
+// Solidity: function verifyTx(((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256)) proof, uint256[20] input) view returns(bool r)
+func (_PolicyVerifier *PolicyVerifierCallerSession) VerifyTx(proof VerifierProof, input [20]*big.Int) (bool, error) {
+	return _PolicyVerifier.Contract.VerifyTx(&_PolicyVerifier.CallOpts, proof, input)
+}
+
+// VerifyTx0 is a free data retrieval call binding the contract method 0xc9417647.
+//
+// Solidity: function verifyTx(((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256)) proof, uint256[] input) view returns(bool r)
+func (_PolicyVerifier *PolicyVerifierCaller) VerifyTx0(opts *bind.CallOpts, proof VerifierProof, input []*big.Int) (bool, error) {
+	var out []interface{}
+	err := _PolicyVerifier.contract.Call(opts, &out, "verifyTx0", proof, input)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifyTx0 is a free data retrieval call binding the contract method 0xc9417647.
+//
+// Solidity: function verifyTx(((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256)) proof, uint256[] input) view returns(bool r)
+func (_PolicyVerifier *PolicyVerifierSession) VerifyTx0(proof VerifierProof, input []*big.Int) (bool, error) {
+	return _PolicyVerifier.Contract.VerifyTx0(&_PolicyVerifier.CallOpts, proof, input)
+}
+
+// VerifyTx0 is a free data retrieval call binding the contract method 0xc9417647.
+//
+// Solidity: function verifyTx(((uint256,uint256),(uint256[2],uint256[2]),(uint256,uint256)) proof, uint256[] input) view returns(bool r)
+func (_PolicyVerifier *PolicyVerifierCallerSession) VerifyTx0(proof VerifierProof, input []*big.Int) (bool, error) {
+	return _PolicyVerifier.Contract.VerifyTx0(&_PolicyVerifier.CallOpts, proof, input)
+}
+
@@ -31,7 +31,7 @@ var (
 
 // SessionRegistryMetaData contains all meta data concerning the SessionRegistry contract.
 var SessionRegistryMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"contextHandler\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"didRegistry\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_user\",\"type\":\"bytes32\"}],\"name\":\"isSession\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"isSessionValid\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"revokeSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"contextHandler\",\"type\":\"address\"}],\"name\":\"setContextHandler\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_token\",\"type\":\"bytes\"},{\"internalType\":\"bytes32\",\"name\":\"_user\",\"type\":\"bytes32\"}],\"name\":\"startSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"contextHandler\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"didRegistry\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"id\",\"type\":\"bytes32\"}],\"name\":\"SessionRevoked\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"id\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"user\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"expiration\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"token\",\"type\":\"bytes\"}],\"name\":\"SessionStarted\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"getContextHandler\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getDIDRegistry\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_user\",\"type\":\"bytes32\"}],\"name\":\"isSession\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"isSessionValid\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_context\",\"type\":\"bytes32\"}],\"name\":\"revokeContextSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"revokeSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"contextHandler\",\"type\":\"address\"}],\"name\":\"setContextHandler\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_token\",\"type\":\"bytes\"},{\"internalType\":\"bytes32\",\"name\":\"_user\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_context\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"_duration\",\"type\":\"uint256\"}],\"name\":\"startSession\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
 }
 
 // SessionRegistryABI is the input ABI used to generate the binding from.
@@ -180,6 +180,68 @@ func (_SessionRegistry *SessionRegistryTransactorRaw) Transact(opts *bind.Transa
 	return _SessionRegistry.Contract.contract.Transact(opts, method, params...)
 }
 
+// GetContextHandler is a free data retrieval call binding the contract method 0x9202315c.
+//
+// Solidity: function getContextHandler() view returns(address)
+func (_SessionRegistry *SessionRegistryCaller) GetContextHandler(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _SessionRegistry.contract.Call(opts, &out, "getContextHandler")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetContextHandler is a free data retrieval call binding the contract method 0x9202315c.
+//
+// Solidity: function getContextHandler() view returns(address)
+func (_SessionRegistry *SessionRegistrySession) GetContextHandler() (common.Address, error) {
+	return _SessionRegistry.Contract.GetContextHandler(&_SessionRegistry.CallOpts)
+}
+
+// GetContextHandler is a free data retrieval call binding the contract method 0x9202315c.
+//
+// Solidity: function getContextHandler() view returns(address)
+func (_SessionRegistry *SessionRegistryCallerSession) GetContextHandler() (common.Address, error) {
+	return _SessionRegistry.Contract.GetContextHandler(&_SessionRegistry.CallOpts)
+}
+
+// GetDIDRegistry is a free data retrieval call binding the contract method 0x0cf8d55b.
+//
+// Solidity: function getDIDRegistry() view returns(address)
+func (_SessionRegistry *SessionRegistryCaller) GetDIDRegistry(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _SessionRegistry.contract.Call(opts, &out, "getDIDRegistry")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetDIDRegistry is a free data retrieval call binding the contract method 0x0cf8d55b.
+//
+// Solidity: function getDIDRegistry() view returns(address)
+func (_SessionRegistry *SessionRegistrySession) GetDIDRegistry() (common.Address, error) {
+	return _SessionRegistry.Contract.GetDIDRegistry(&_SessionRegistry.CallOpts)
+}
+
+// GetDIDRegistry is a free data retrieval call binding the contract method 0x0cf8d55b.
+//
+// Solidity: function getDIDRegistry() view returns(address)
+func (_SessionRegistry *SessionRegistryCallerSession) GetDIDRegistry() (common.Address, error) {
+	return _SessionRegistry.Contract.GetDIDRegistry(&_SessionRegistry.CallOpts)
+}
+
 // IsSession is a free data retrieval call binding the contract method 0x011255b5.
 //
 // Solidity: function isSession(bytes32 _id, bytes32 _user) view returns(bool)
@@ -242,6 +304,27 @@ func (_SessionRegistry *SessionRegistryCallerSession) IsSessionValid(_id [32]byt
 	return _SessionRegistry.Contract.IsSessionValid(&_SessionRegistry.CallOpts, _id)
 }
 
+// RevokeContextSession is a paid mutator transaction binding the contract method 0x40743562.
+//
+// Solidity: function revokeContextSession(bytes32 _id, bytes32 _context) returns()
+func (_SessionRegistry *SessionRegistryTransactor) RevokeContextSession(opts *bind.TransactOpts, _id [32]byte, _context [32]byte) (*types.Transaction, error) {
+	return _SessionRegistry.contract.Transact(opts, "revokeContextSession", _id, _context)
+}
+
+// RevokeContextSession is a paid mutator transaction binding the contract method 0x40743562.
+//
+// Solidity: function revokeContextSession(bytes32 _id, bytes32 _context) returns()
+func (_SessionRegistry *SessionRegistrySession) RevokeContextSession(_id [32]byte, _context [32]byte) (*types.Transaction, error) {
+	return _SessionRegistry.Contract.RevokeContextSession(&_SessionRegistry.TransactOpts, _id, _context)
+}
+
+// RevokeContextSession is a paid mutator transaction binding the contract method 0x40743562.
+//
+// Solidity: function revokeContextSession(bytes32 _id, bytes32 _context) returns()
+func (_SessionRegistry *SessionRegistryTransactorSession) RevokeContextSession(_id [32]byte, _context [32]byte) (*types.Transaction, error) {
+	return _SessionRegistry.Contract.RevokeContextSession(&_SessionRegistry.TransactOpts, _id, _context)
+}
+
 // RevokeSession is a paid mutator transaction binding the contract method 0xa7fed385.
 //
 // Solidity: function revokeSession(bytes32 _id) returns()
@@ -284,24 +367,323 @@ func (_SessionRegistry *SessionRegistryTransactorSession) SetContextHandler(cont
 	return _SessionRegistry.Contract.SetContextHandler(&_SessionRegistry.TransactOpts, contextHandler)
 }
 
-// StartSession is a paid mutator transaction binding the contract method 0x04187076.
+// StartSession is a paid mutator transaction binding the contract method 0xc95ee122.
 //
-// Solidity: function startSession(bytes32 _id, bytes _token, bytes32 _user) returns()
-func (_SessionRegistry *SessionRegistryTransactor) StartSession(opts *bind.TransactOpts, _id [32]byte, _token []byte, _user [32]byte) (*types.Transaction, error) {
-	return _SessionRegistry.contract.Transact(opts, "startSession", _id, _token, _user)
+// Solidity: function startSession(bytes32 _id, bytes _token, bytes32 _user, bytes32 _context, uint256 _duration) returns()
+func (_SessionRegistry *SessionRegistryTransactor) StartSession(opts *bind.TransactOpts, _id [32]byte, _token []byte, _user [32]byte, _context [32]byte, _duration *big.Int) (*types.Transaction, error) {
+	return _SessionRegistry.contract.Transact(opts, "startSession", _id, _token, _user, _context, _duration)
 }
 
-// StartSession is a paid mutator transaction binding the contract method 0x04187076.
+// StartSession is a paid mutator transaction binding the contract method 0xc95ee122.
 //
-// Solidity: function startSession(bytes32 _id, bytes _token, bytes32 _user) returns()
-func (_SessionRegistry *SessionRegistrySession) StartSession(_id [32]byte, _token []byte, _user [32]byte) (*types.Transaction, error) {
-	return _SessionRegistry.Contract.StartSession(&_SessionRegistry.TransactOpts, _id, _token, _user)
+// Solidity: function startSession(bytes32 _id, bytes _token, bytes32 _user, bytes32 _context, uint256 _duration) returns()
+func (_SessionRegistry *SessionRegistrySession) StartSession(_id [32]byte, _token []byte, _user [32]byte, _context [32]byte, _duration *big.Int) (*types.Transaction, error) {
+	return _SessionRegistry.Contract.StartSession(&_SessionRegistry.TransactOpts, _id, _token, _user, _context, _duration)
 }
 
-// StartSession is a paid mutator transaction binding the contract method 0x04187076.
+// StartSession is a paid mutator transaction binding the contract method 0xc95ee122.
 //
-// Solidity: function startSession(bytes32 _id, bytes _token, bytes32 _user) returns()
-func (_SessionRegistry *SessionRegistryTransactorSession) StartSession(_id [32]byte, _token []byte, _user [32]byte) (*types.Transaction, error) {
-	return _SessionRegistry.Contract.StartSession(&_SessionRegistry.TransactOpts, _id, _token, _user)
+// Solidity: function startSession(bytes32 _id, bytes _token, bytes32 _user, bytes32 _context, uint256 _duration) returns()
+func (_SessionRegistry *SessionRegistryTransactorSession) StartSession(_id [32]byte, _token []byte, _user [32]byte, _context [32]byte, _duration *big.Int) (*types.Transaction, error) {
+	return _SessionRegistry.Contract.StartSession(&_SessionRegistry.TransactOpts, _id, _token, _user, _context, _duration)
+}
+
+// SessionRegistrySessionRevokedIterator is returned from FilterSessionRevoked and is used to iterate over the raw logs and unpacked data for SessionRevoked events raised by the SessionRegistry contract.
+type SessionRegistrySessionRevokedIterator struct {
+	Event *SessionRegistrySessionRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SessionRegistrySessionRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SessionRegistrySessionRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SessionRegistrySessionRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SessionRegistrySessionRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SessionRegistrySessionRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SessionRegistrySessionRevoked represents a SessionRevoked event raised by the SessionRegistry contract.
+type SessionRegistrySessionRevoked struct {
+	Id  [32]byte
+	Raw types.Log // Blockchain specific contextual infos
+}
+
+// FilterSessionRevoked is a free log retrieval operation binding the contract event 0xcc0f7e7f309149dce2db74723f771f7d96cad368d91763e945dc3956c4dbfb04.
+//
+// Solidity: event SessionRevoked(bytes32 indexed id)
+func (_SessionRegistry *SessionRegistryFilterer) FilterSessionRevoked(opts *bind.FilterOpts, id [][32]byte) (*SessionRegistrySessionRevokedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _SessionRegistry.contract.FilterLogs(opts, "SessionRevoked", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionRegistrySessionRevokedIterator{contract: _SessionRegistry.contract, event: "SessionRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchSessionRevoked is a free log subscription operation binding the contract event 0xcc0f7e7f309149dce2db74723f771f7d96cad368d91763e945dc3956c4dbfb04.
+//
+// Solidity: event SessionRevoked(bytes32 indexed id)
+func (_SessionRegistry *SessionRegistryFilterer) WatchSessionRevoked(opts *bind.WatchOpts, sink chan<- *SessionRegistrySessionRevoked, id [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+
+	logs, sub, err := _SessionRegistry.contract.WatchLogs(opts, "SessionRevoked", idRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SessionRegistrySessionRevoked)
+				if err := _SessionRegistry.contract.UnpackLog(event, "SessionRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSessionRevoked is a log parse operation binding the contract event 0xcc0f7e7f309149dce2db74723f771f7d96cad368d91763e945dc3956c4dbfb04.
+//
+// Solidity: event SessionRevoked(bytes32 indexed id)
+func (_SessionRegistry *SessionRegistryFilterer) ParseSessionRevoked(log types.Log) (*SessionRegistrySessionRevoked, error) {
+	event := new(SessionRegistrySessionRevoked)
+	if err := _SessionRegistry.contract.UnpackLog(event, "SessionRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SessionRegistrySessionStartedIterator is returned from FilterSessionStarted and is used to iterate over the raw logs and unpacked data for SessionStarted events raised by the SessionRegistry contract.
+type SessionRegistrySessionStartedIterator struct {
+	Event *SessionRegistrySessionStarted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SessionRegistrySessionStartedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SessionRegistrySessionStarted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SessionRegistrySessionStarted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SessionRegistrySessionStartedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SessionRegistrySessionStartedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SessionRegistrySessionStarted represents a SessionStarted event raised by the SessionRegistry contract.
+type SessionRegistrySessionStarted struct {
+	Id         [32]byte
+	User       [32]byte
+	Expiration *big.Int
+	Token      []byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterSessionStarted is a free log retrieval operation binding the contract event 0x5e6e598324eb456ea07479dd4bf9b00b44c40f9c211fcfd94d5609ad130ea5fa.
+//
+// Solidity: event SessionStarted(bytes32 indexed id, bytes32 indexed user, uint256 expiration, bytes token)
+func (_SessionRegistry *SessionRegistryFilterer) FilterSessionStarted(opts *bind.FilterOpts, id [][32]byte, user [][32]byte) (*SessionRegistrySessionStartedIterator, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userRule []interface{}
+	for _, userItem := range user {
+		userRule = append(userRule, userItem)
+	}
+
+	logs, sub, err := _SessionRegistry.contract.FilterLogs(opts, "SessionStarted", idRule, userRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionRegistrySessionStartedIterator{contract: _SessionRegistry.contract, event: "SessionStarted", logs: logs, sub: sub}, nil
+}
+
+// WatchSessionStarted is a free log subscription operation binding the contract event 0x5e6e598324eb456ea07479dd4bf9b00b44c40f9c211fcfd94d5609ad130ea5fa.
+//
+// Solidity: event SessionStarted(bytes32 indexed id, bytes32 indexed user, uint256 expiration, bytes token)
+func (_SessionRegistry *SessionRegistryFilterer) WatchSessionStarted(opts *bind.WatchOpts, sink chan<- *SessionRegistrySessionStarted, id [][32]byte, user [][32]byte) (event.Subscription, error) {
+
+	var idRule []interface{}
+	for _, idItem := range id {
+		idRule = append(idRule, idItem)
+	}
+	var userRule []interface{}
+	for _, userItem := range user {
+		userRule = append(userRule, userItem)
+	}
+
+	logs, sub, err := _SessionRegistry.contract.WatchLogs(opts, "SessionStarted", idRule, userRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SessionRegistrySessionStarted)
+				if err := _SessionRegistry.contract.UnpackLog(event, "SessionStarted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSessionStarted is a log parse operation binding the contract event 0x5e6e598324eb456ea07479dd4bf9b00b44c40f9c211fcfd94d5609ad130ea5fa.
+//
+// Solidity: event SessionStarted(bytes32 indexed id, bytes32 indexed user, uint256 expiration, bytes token)
+func (_SessionRegistry *SessionRegistryFilterer) ParseSessionStarted(log types.Log) (*SessionRegistrySessionStarted, error) {
+	event := new(SessionRegistrySessionStarted)
+	if err := _SessionRegistry.contract.UnpackLog(event, "SessionStarted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
 }
 
@@ -0,0 +1,136 @@
+package deploy
+
+import (
+	"context"
+	"flag"
+	"github.com/ethereum/go-ethereum/common"
+	configpkg "github.com/fapiper/onchain-access-control/core/config"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Run deploys the contract suite with the wallet configured in the environment and writes the addresses to a file
+func Run() {
+	envPath := flag.String("env", "", "env or .toml file with RPC_URL, CHAIN_ID and the WALLET_ and TX_ settings, the process environment is used otherwise")
+	outPath := flag.String("out", "", "env or .toml file the contract addresses are written to, defaults to the -env file or .env")
+	verifyOnly := flag.Bool("verify", false, "only verify the linkage of the contracts in the -out file")
+	flag.Parse()
+
+	SetDefaults()
+	if *envPath != "" {
+		viper.Set(configpkg.EnvPath.String(), *envPath)
+		if _, err := configpkg.LoadEnv(); err != nil {
+			logrus.WithError(err).Fatal("could not load env")
+		}
+	}
+	if *outPath == "" {
+		*outPath = *envPath
+	}
+	if *outPath == "" {
+		*outPath = ".env"
+	}
+
+	if err := run(context.Background(), *outPath, *verifyOnly); err != nil {
+		logrus.WithError(err).Fatal("could not deploy the contracts")
+	}
+}
+
+func run(ctx context.Context, outPath string, verifyOnly bool) error {
+	if !verifyOnly {
+		if err := CheckBytecode(); err != nil {
+			return err
+		}
+	}
+	existing, err := ReadAddresses(outPath)
+	if err != nil {
+		return err
+	}
+	fromEnv(existing)
+
+	ethClient, err := rpc.NewEthClient()
+	if err != nil {
+		return errors.Wrap(err, "creating the chain client")
+	}
+	config, err := rpc.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	service, err := rpc.NewRPCServiceWithConfig(ethClient, *config, nil)
+	if err != nil {
+		return err
+	}
+
+	if verifyOnly {
+		if existing.ContextHandler == (common.Address{}) {
+			return errors.Errorf("no %s in %s or the environment", ContextHandlerVariable, outPath)
+		}
+		if existing.AccessContext, err = linkedAddress(ctx, service, existing.ContextHandler, handlerAccessContext); err != nil {
+			return err
+		}
+		if err = Verify(ctx, service, *existing); err != nil {
+			return err
+		}
+		logrus.Infof("contracts in %s are deployed and linked", outPath)
+		return nil
+	}
+
+	addresses, err := Deploy(ctx, service, *existing)
+	if err != nil {
+		return err
+	}
+	if err = WriteAddresses(outPath, *addresses); err != nil {
+		return err
+	}
+
+	logrus.Infof("did registry: %s", addresses.DIDRegistry)
+	logrus.Infof("context handler: %s", addresses.ContextHandler)
+	logrus.Infof("access context implementation: %s", addresses.AccessContext)
+	logrus.Infof("session registry: %s", addresses.SessionRegistry)
+	logrus.Infof("wrote addresses to %s", outPath)
+	return nil
+}
+
+// fromEnv fills in the addresses missing from the output file from the environment
+func fromEnv(addresses *Addresses) {
+	for variable, address := range map[string]*common.Address{
+		DIDRegistryVariable:     &addresses.DIDRegistry,
+		ContextHandlerVariable:  &addresses.ContextHandler,
+		SessionRegistryVariable: &addresses.SessionRegistry,
+	} {
+		if value := env.GetString(variable); *address == (common.Address{}) && common.IsHexAddress(value) {
+			*address = common.HexToAddress(value)
+		}
+	}
+}
+
+func SetDefaults() {
+	viper.SetDefault("RPC_URL", "http://localhost:8545")
+	viper.SetDefault("RPC_MAX_ATTEMPTS", 4)
+	viper.SetDefault("RPC_INITIAL_BACKOFF", "250ms")
+	viper.SetDefault("RPC_MAX_BACKOFF", "30s")
+	viper.SetDefault("PRIVATE_KEY", "")
+	viper.SetDefault("WALLET_SIGNER", "key")
+	viper.SetDefault("WALLET_KEY_ID", "")
+	viper.SetDefault("WALLET_KEY_FILE", "")
+	viper.SetDefault("WALLET_KEY_PASSWORD_FILE", "")
+	viper.SetDefault("WALLET_SIGNER_URL", "")
+	viper.SetDefault("WALLET_ADDRESS", "")
+	viper.SetDefault("OWNER_DID", "")
+	viper.SetDefault("CHAIN_ID", 1337)
+	viper.SetDefault("TX_RESUBMIT_AFTER", "1m")
+	viper.SetDefault("TX_GAS_BUMP_PERCENT", 15)
+	viper.SetDefault("TX_FEE_MODE", "dynamic")
+	viper.SetDefault("TX_GAS_LIMIT_MARGIN", 20)
+	viper.SetDefault("TX_MAX_FEE_PER_GAS", "")
+	viper.SetDefault("TX_MAX_PRIORITY_FEE_PER_GAS", "")
+	viper.SetDefault("TX_FEE_HISTORY_BLOCKS", 10)
+	viper.SetDefault("TX_PRIORITY_FEE_PERCENTILE", 50)
+	viper.SetDefault(DIDRegistryVariable, "")
+	viper.SetDefault(ContextHandlerVariable, "")
+	viper.SetDefault(SessionRegistryVariable, "")
+
+	viper.AutomaticEnv()
+}
@@ -0,0 +1,282 @@
+package deploy
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+// link reads the address a contract is linked to through one of the public getters of the contract
+type link func(opts *bind.CallOpts, contract common.Address, client bind.ContractCaller) (common.Address, error)
+
+var (
+	handlerSessionRegistry link = func(opts *bind.CallOpts, contract common.Address, client bind.ContractCaller) (common.Address, error) {
+		handler, err := contracts.NewAccessContextHandlerCaller(contract, client)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return handler.GetSessionRegistry(opts)
+	}
+	handlerDIDRegistry link = func(opts *bind.CallOpts, contract common.Address, client bind.ContractCaller) (common.Address, error) {
+		handler, err := contracts.NewAccessContextHandlerCaller(contract, client)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return handler.GetDIDRegistry(opts)
+	}
+	handlerAccessContext link = func(opts *bind.CallOpts, contract common.Address, client bind.ContractCaller) (common.Address, error) {
+		handler, err := contracts.NewAccessContextHandlerCaller(contract, client)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return handler.GetInstanceImpl(opts)
+	}
+	sessionRegistryDIDRegistry link = func(opts *bind.CallOpts, contract common.Address, client bind.ContractCaller) (common.Address, error) {
+		registry, err := contracts.NewSessionRegistryCaller(contract, client)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return registry.GetDIDRegistry(opts)
+	}
+	sessionRegistryContextHandler link = func(opts *bind.CallOpts, contract common.Address, client bind.ContractCaller) (common.Address, error) {
+		registry, err := contracts.NewSessionRegistryCaller(contract, client)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return registry.GetContextHandler(opts)
+	}
+)
+
+// bindings are the contracts Deploy deploys, by name
+var bindings = []struct {
+	name     string
+	metadata *bind.MetaData
+}{
+	{"SimpleDIDRegistry", contracts.SimpleDIDRegistryMetaData},
+	{"AccessContextHandler", contracts.AccessContextHandlerMetaData},
+	{"SessionRegistry", contracts.SessionRegistryMetaData},
+}
+
+// CheckBytecode fails if the binding of a contract Deploy deploys carries no bytecode, as the suite could not be
+// completed with it
+func CheckBytecode() error {
+	var missing []string
+	for _, binding := range bindings {
+		if len(common.FromHex(binding.metadata.Bin)) == 0 {
+			missing = append(missing, binding.name)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the bindings of %s carry no bytecode, compile the contracts and run make abi", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Addresses of a deployed contract suite. The access context implementation is deployed by the access context handler,
+// which clones it for every access context.
+type Addresses struct {
+	DIDRegistry     common.Address
+	ContextHandler  common.Address
+	AccessContext   common.Address
+	SessionRegistry common.Address
+}
+
+// Deploy deploys the contracts of existing that are missing or not linked to the others, and links them. The did
+// registry is set up with the did of the wallet, controlled by the wallet. Running it again on its result sends no
+// transactions.
+//
+// The session registry learns its context handler through its constructor, as only the current context handler may
+// call setContextHandler. A session registry linked to another context handler is therefore replaced.
+//
+// The creation bytecode is taken from the contract bindings, which make abi generates with the bytecode of the
+// compiled contracts. Without it, Deploy fails before sending any transaction.
+func Deploy(ctx context.Context, s *rpc.Service, existing Addresses) (*Addresses, error) {
+	if err := CheckBytecode(); err != nil {
+		return nil, err
+	}
+	addresses := existing
+
+	didRegistry, err := reusable(ctx, s, addresses.DIDRegistry, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !didRegistry {
+		addresses.DIDRegistry, err = deploy(ctx, s, "SimpleDIDRegistry", contracts.SimpleDIDRegistryMetaData,
+			[][32]byte{s.OwnerDIDHash()}, []common.Address{s.Wallet.Address})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	contextHandler, err := reusable(ctx, s, addresses.ContextHandler, map[string]expectedLink{
+		"did registry": {handlerDIDRegistry, addresses.DIDRegistry},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !contextHandler {
+		addresses.ContextHandler, err = deploy(ctx, s, "AccessContextHandler", contracts.AccessContextHandlerMetaData, addresses.DIDRegistry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if addresses.AccessContext, err = linkedAddress(ctx, s, addresses.ContextHandler, handlerAccessContext); err != nil {
+		return nil, err
+	}
+
+	sessionRegistry, err := reusable(ctx, s, addresses.SessionRegistry, map[string]expectedLink{
+		"did registry":    {sessionRegistryDIDRegistry, addresses.DIDRegistry},
+		"context handler": {sessionRegistryContextHandler, addresses.ContextHandler},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !sessionRegistry {
+		addresses.SessionRegistry, err = deploy(ctx, s, "SessionRegistry", contracts.SessionRegistryMetaData, addresses.ContextHandler, addresses.DIDRegistry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	linked, err := linkedAddress(ctx, s, addresses.ContextHandler, handlerSessionRegistry)
+	if err != nil {
+		return nil, err
+	}
+	if linked != addresses.SessionRegistry {
+		s.ContextHandler = persist.Address(addresses.ContextHandler.Hex())
+		if _, err = s.SetSessionRegistry(ctx, addresses.SessionRegistry); err != nil {
+			return nil, errors.Wrap(err, "setting session registry of the context handler")
+		}
+		logrus.Infof("linked context handler %s to session registry %s", addresses.ContextHandler, addresses.SessionRegistry)
+	}
+
+	if err = Verify(ctx, s, addresses); err != nil {
+		return nil, err
+	}
+	return &addresses, nil
+}
+
+// Verify checks that all contracts of a suite are deployed and linked to each other
+func Verify(ctx context.Context, s *rpc.Service, addresses Addresses) error {
+	for _, contract := range []struct {
+		name    string
+		address common.Address
+	}{
+		{"did registry", addresses.DIDRegistry},
+		{"context handler", addresses.ContextHandler},
+		{"access context implementation", addresses.AccessContext},
+		{"session registry", addresses.SessionRegistry},
+	} {
+		deployed, err := hasCode(ctx, s, contract.address)
+		if err != nil {
+			return err
+		}
+		if !deployed {
+			return errors.Errorf("%s<%s> is not deployed", contract.name, contract.address)
+		}
+	}
+
+	for _, link := range []struct {
+		name     string
+		contract common.Address
+		get      link
+		expected common.Address
+	}{
+		{"session registry of the context handler", addresses.ContextHandler, handlerSessionRegistry, addresses.SessionRegistry},
+		{"did registry of the context handler", addresses.ContextHandler, handlerDIDRegistry, addresses.DIDRegistry},
+		{"access context implementation of the context handler", addresses.ContextHandler, handlerAccessContext, addresses.AccessContext},
+		{"did registry of the session registry", addresses.SessionRegistry, sessionRegistryDIDRegistry, addresses.DIDRegistry},
+		{"context handler of the session registry", addresses.SessionRegistry, sessionRegistryContextHandler, addresses.ContextHandler},
+	} {
+		actual, err := linkedAddress(ctx, s, link.contract, link.get)
+		if err != nil {
+			return err
+		}
+		if actual != link.expected {
+			return errors.Errorf("%s is %s, expected %s", link.name, actual, link.expected)
+		}
+	}
+	return nil
+}
+
+// expectedLink is a link of a contract and the address it is expected to point to
+type expectedLink struct {
+	get      link
+	expected common.Address
+}
+
+// reusable returns whether a contract is deployed at address and its links are as expected
+func reusable(ctx context.Context, s *rpc.Service, address common.Address, links map[string]expectedLink) (bool, error) {
+	if address == (common.Address{}) {
+		return false, nil
+	}
+	deployed, err := hasCode(ctx, s, address)
+	if err != nil || !deployed {
+		return false, err
+	}
+	for name, link := range links {
+		actual, err := linkedAddress(ctx, s, address, link.get)
+		if err != nil {
+			return false, err
+		}
+		if actual != link.expected {
+			logrus.Infof("contract %s is linked to %s %s instead of %s, replacing it", address, name, actual, link.expected)
+			return false, nil
+		}
+	}
+	logrus.Infof("reusing contract %s", address)
+	return true, nil
+}
+
+// deploy deploys a contract with the abi and bytecode of its binding
+func deploy(ctx context.Context, s *rpc.Service, name string, metadata *bind.MetaData, args ...any) (common.Address, error) {
+	parsed, err := metadata.GetAbi()
+	if err != nil {
+		return common.Address{}, err
+	}
+	bytecode := common.FromHex(metadata.Bin)
+	if len(bytecode) == 0 {
+		return common.Address{}, errors.Errorf("the %s binding carries no bytecode, compile the contracts and run make abi", name)
+	}
+
+	address, tx, err := s.DeployContract(ctx, rpc.DeployContractParams{
+		ABI:      *parsed,
+		Bytecode: bytecode,
+		Args:     args,
+	})
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "deploying %s", name)
+	}
+	receipt, err := s.WaitMined(ctx, tx)
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "waiting for the deployment of %s", name)
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return common.Address{}, errors.Errorf("deploying %s failed in transaction %s", name, receipt.TxHash)
+	}
+	logrus.Infof("deployed %s to %s", name, address)
+	return address, nil
+}
+
+func hasCode(ctx context.Context, s *rpc.Service, address common.Address) (bool, error) {
+	code, err := s.Wallet.Client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, errors.Wrapf(err, "getting code of %s", address)
+	}
+	return len(code) > 0, nil
+}
+
+func linkedAddress(ctx context.Context, s *rpc.Service, contract common.Address, get link) (common.Address, error) {
+	address, err := get(&bind.CallOpts{Context: ctx}, contract, s.Wallet.Client)
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "reading the links of %s", contract)
+	}
+	return address, nil
+}
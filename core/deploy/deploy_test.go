@@ -0,0 +1,143 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/testutil"
+)
+
+func newSimulatedService(t *testing.T) *rpc.Service {
+	key, sim := testutil.NewFundedKey(t)
+	service, err := rpc.NewRPCServiceWithConfig(rpc.NewSimulatedBackend(sim), rpc.SimulatedConfig(key), nil)
+	require.NoError(t, err)
+	return service
+}
+
+func TestDeploy(t *testing.T) {
+	if contracts.AccessContextHandlerMetaData.Bin == "" {
+		t.Skip("the contract bindings carry no bytecode, compile the contracts and run make abi")
+	}
+	s := newSimulatedService(t)
+	ctx := context.Background()
+
+	addresses, err := Deploy(ctx, s, Addresses{})
+	require.NoError(t, err)
+	assert.NotEqual(t, common.Address{}, addresses.AccessContext)
+	require.NoError(t, Verify(ctx, s, *addresses))
+
+	t.Run("sends no transactions for a linked suite", func(tt *testing.T) {
+		head, err := s.BlockNumber(ctx)
+		require.NoError(tt, err)
+
+		again, err := Deploy(ctx, s, *addresses)
+		require.NoError(tt, err)
+		assert.Equal(tt, *addresses, *again)
+
+		after, err := s.BlockNumber(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, head, after)
+	})
+
+	t.Run("replaces a missing session registry", func(tt *testing.T) {
+		existing := *addresses
+		existing.SessionRegistry = common.Address{}
+
+		redeployed, err := Deploy(ctx, s, existing)
+		require.NoError(tt, err)
+		assert.Equal(tt, addresses.ContextHandler, redeployed.ContextHandler)
+		assert.NotEqual(tt, addresses.SessionRegistry, redeployed.SessionRegistry)
+		assert.NoError(tt, Verify(ctx, s, *redeployed))
+		assert.Error(tt, Verify(ctx, s, *addresses))
+	})
+
+	t.Run("replaces a context handler of another did registry", func(tt *testing.T) {
+		existing := *addresses
+		existing.DIDRegistry = common.Address{}
+
+		redeployed, err := Deploy(ctx, s, existing)
+		require.NoError(tt, err)
+		assert.NotEqual(tt, addresses.DIDRegistry, redeployed.DIDRegistry)
+		assert.NotEqual(tt, addresses.ContextHandler, redeployed.ContextHandler)
+		assert.NotEqual(tt, addresses.SessionRegistry, redeployed.SessionRegistry)
+		assert.NoError(tt, Verify(ctx, s, *redeployed))
+	})
+}
+
+func TestCheckBytecode(t *testing.T) {
+	if contracts.AccessContextHandlerMetaData.Bin != "" {
+		assert.NoError(t, CheckBytecode())
+		return
+	}
+	assert.EqualError(t, CheckBytecode(), "the bindings of SimpleDIDRegistry, AccessContextHandler, SessionRegistry carry no bytecode, compile the contracts and run make abi")
+
+	// no transaction is sent without bytecode
+	s := newSimulatedService(t)
+	ctx := context.Background()
+	head, err := s.BlockNumber(ctx)
+	require.NoError(t, err)
+	_, err = Deploy(ctx, s, Addresses{})
+	assert.ErrorContains(t, err, "carry no bytecode")
+	after, err := s.BlockNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, head, after)
+
+	assert.ErrorContains(t, run(ctx, filepath.Join(t.TempDir(), ".env"), false), "carry no bytecode")
+}
+
+func TestWriteAddresses(t *testing.T) {
+	addresses := Addresses{
+		DIDRegistry:     common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3"),
+		ContextHandler:  common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"),
+		SessionRegistry: common.HexToAddress("0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0"),
+	}
+
+	t.Run("env file", func(tt *testing.T) {
+		path := filepath.Join(tt.TempDir(), ".env")
+		require.NoError(tt, os.WriteFile(path, []byte("RPC_URL=http://localhost:8545\nCONTEXT_HANDLER_CONTRACT=0x0\n"), 0o600))
+
+		require.NoError(tt, WriteAddresses(path, addresses))
+		data, err := os.ReadFile(path)
+		require.NoError(tt, err)
+		assert.Equal(tt, "RPC_URL=http://localhost:8545\n"+
+			"CONTEXT_HANDLER_CONTRACT=0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512\n"+
+			"DID_REGISTRY_CONTRACT=0x5FbDB2315678afecb367f032d93F642f64180aa3\n"+
+			"SESSION_REGISTRY_CONTRACT=0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0\n", string(data))
+
+		read, err := ReadAddresses(path)
+		require.NoError(tt, err)
+		assert.Equal(tt, addresses, *read)
+	})
+
+	t.Run("toml file", func(tt *testing.T) {
+		path := filepath.Join(tt.TempDir(), "contracts.toml")
+		require.NoError(tt, os.WriteFile(path, []byte("RPC_URL = \"http://localhost:8545\"\n\n[server]\nport = 3000\n"), 0o600))
+
+		require.NoError(tt, WriteAddresses(path, addresses))
+		data, err := os.ReadFile(path)
+		require.NoError(tt, err)
+		assert.Equal(tt, "RPC_URL = \"http://localhost:8545\"\n"+
+			"DID_REGISTRY_CONTRACT = \"0x5FbDB2315678afecb367f032d93F642f64180aa3\"\n"+
+			"CONTEXT_HANDLER_CONTRACT = \"0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512\"\n"+
+			"SESSION_REGISTRY_CONTRACT = \"0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0\"\n"+
+			"\n[server]\nport = 3000\n", string(data))
+
+		read, err := ReadAddresses(path)
+		require.NoError(tt, err)
+		assert.Equal(tt, addresses, *read)
+	})
+
+	t.Run("missing file", func(tt *testing.T) {
+		read, err := ReadAddresses(filepath.Join(tt.TempDir(), ".env"))
+		require.NoError(tt, err)
+		assert.Equal(tt, Addresses{}, *read)
+	})
+}
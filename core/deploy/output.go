@@ -0,0 +1,115 @@
+package deploy
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Variables the services read the contract addresses from
+const (
+	DIDRegistryVariable     = "DID_REGISTRY_CONTRACT"
+	ContextHandlerVariable  = "CONTEXT_HANDLER_CONTRACT"
+	SessionRegistryVariable = "SESSION_REGISTRY_CONTRACT"
+)
+
+// isTOML returns whether the addresses of a file are kept in TOML instead of env format
+func isTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// ReadAddresses reads the contract addresses of an env or TOML file, with the extension .toml. A missing file holds
+// no addresses.
+func ReadAddresses(path string) (*Addresses, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &Addresses{}, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("env")
+	if isTOML(path) {
+		v.SetConfigType("toml")
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, errors.Wrapf(err, "reading addresses from %s", path)
+	}
+
+	var addresses Addresses
+	for variable, address := range map[string]*common.Address{
+		DIDRegistryVariable:     &addresses.DIDRegistry,
+		ContextHandlerVariable:  &addresses.ContextHandler,
+		SessionRegistryVariable: &addresses.SessionRegistry,
+	} {
+		value := v.GetString(variable)
+		if value == "" {
+			continue
+		}
+		if !common.IsHexAddress(value) {
+			return nil, errors.Errorf("invalid %s<%s> in %s", variable, value, path)
+		}
+		*address = common.HexToAddress(value)
+	}
+	return &addresses, nil
+}
+
+// WriteAddresses sets the contract addresses in an env or TOML file, with the extension .toml. Other lines of an
+// existing file are kept, so the file may also hold the remaining environment of a service.
+func WriteAddresses(path string, addresses Addresses) error {
+	var lines []string
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	case !os.IsNotExist(err):
+		return errors.Wrapf(err, "reading %s", path)
+	}
+
+	format := "%s=%s"
+	if isTOML(path) {
+		format = "%s = %q"
+	}
+	for _, variable := range []struct {
+		name    string
+		address common.Address
+	}{
+		{DIDRegistryVariable, addresses.DIDRegistry},
+		{ContextHandlerVariable, addresses.ContextHandler},
+		{SessionRegistryVariable, addresses.SessionRegistry},
+	} {
+		lines = setVariable(lines, variable.name, fmt.Sprintf(format, variable.name, variable.address.Hex()), isTOML(path))
+	}
+
+	if err = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return errors.Wrapf(err, "writing %s", path)
+	}
+	return nil
+}
+
+// setVariable replaces the line of a variable, or adds it. In TOML, variables are kept at the top level, before the
+// first table.
+func setVariable(lines []string, name, line string, toml bool) []string {
+	end := len(lines)
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if toml && strings.HasPrefix(trimmed, "[") {
+			end = i
+			break
+		}
+		key, _, found := strings.Cut(strings.TrimPrefix(trimmed, "export "), "=")
+		if found && strings.TrimSpace(key) == name {
+			lines[i] = line
+			return lines
+		}
+	}
+
+	// keep a blank line between the variables and the first table
+	for end > 0 && toml && end < len(lines) && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return append(lines[:end], append([]string{line}, lines[end:]...)...)
+}
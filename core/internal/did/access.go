@@ -3,10 +3,18 @@ package did
 import (
 	"context"
 	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
 
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/pkh"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	"github.com/TBD54566975/ssi-sdk/util"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/pkg/errors"
 
 	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
@@ -53,3 +61,117 @@ func VerifyTokenFromDID(ctx context.Context, resolver resolution.Resolver, did,
 	}
 	return nil
 }
+
+// ResolveKeyAgreementKey resolves the key agreement key of a DID, which is used to encrypt messages to the controller
+// of the DID. A DID URL selects the key agreement key it references, otherwise the first one of the document is used.
+// The returned kid is the fully qualified id of the verification method.
+func ResolveKeyAgreementKey(ctx context.Context, resolver resolution.Resolver, didURL string) (kid string, pubKey crypto.PublicKey, err error) {
+	did, fragment, _ := strings.Cut(didURL, "#")
+	resolved, err := resolver.Resolve(ctx, did)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "resolving DID: %s", did)
+	}
+	doc := resolved.Document
+
+	for _, set := range doc.KeyAgreement {
+		var id string
+		switch method := set.(type) {
+		case string:
+			id = method
+		default:
+			// key agreement keys may be embedded instead of referencing a verification method
+			var embedded didsdk.VerificationMethod
+			if err = util.Copy(method, &embedded); err != nil {
+				return "", nil, errors.Wrapf(err, "reading key agreement key of DID: %s", did)
+			}
+			id = embedded.ID
+			doc.VerificationMethod = append(doc.VerificationMethod, embedded)
+		}
+
+		id = didsdk.FullyQualifiedVerificationMethodID(did, id)
+		if fragment != "" && id != didURL {
+			continue
+		}
+		pubKey, err = getKeyAgreementKey(doc, id)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "getting key agreement key from the DID document: %s", did)
+		}
+		return id, pubKey, nil
+	}
+
+	if fragment != "" {
+		return "", nil, errors.Errorf("DID<%s> has no key agreement key: %s", did, didURL)
+	}
+	return "", nil, errors.Errorf("DID<%s> has no key agreement key", did)
+}
+
+// getKeyAgreementKey gets the public key of a verification method. secp256k1 JWKs are decoded here, since the sdk
+// relies on jwx to decode JWKs, which only supports them when built with the jwx_es256k tag.
+func getKeyAgreementKey(doc didsdk.Document, kid string) (crypto.PublicKey, error) {
+	for _, method := range doc.VerificationMethod {
+		if method.PublicKeyJWK == nil || method.PublicKeyJWK.CRV != "secp256k1" ||
+			didsdk.FullyQualifiedVerificationMethodID(doc.ID, method.ID) != kid {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(method.PublicKeyJWK.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding x coordinate")
+		}
+		y, err := base64.RawURLEncoding.DecodeString(method.PublicKeyJWK.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding y coordinate")
+		}
+		return ethcrypto.UnmarshalPubkey(append([]byte{0x04}, append(x, y...)...))
+	}
+	return didsdk.GetKeyFromVerificationMethod(doc, kid)
+}
+
+// VerifyTokenFromPKH verifies that a token was signed by the blockchain account of a did:pkh, either with ES256K or as
+// an EIP-191 personal message. Such DIDs carry no public keys, so the signer is recovered from the signature and
+// compared to the account address.
+func VerifyTokenFromPKH(did string, token keyaccess.JWT) error {
+	if !strings.HasPrefix(did, pkh.DIDPKHPrefix+":") {
+		return errors.Errorf("not a did:pkh: %s", did)
+	}
+	account := did[strings.LastIndex(did, ":")+1:]
+	if !ethcommon.IsHexAddress(account) {
+		return errors.Errorf("did<%s> does not identify an ethereum account", did)
+	}
+
+	message, err := jws.Parse([]byte(token))
+	if err != nil {
+		return errors.Wrap(err, "parsing token")
+	}
+	signatures := message.Signatures()
+	if len(signatures) != 1 {
+		return errors.Errorf("expected exactly one signature, got %d", len(signatures))
+	}
+	signature := signatures[0].Signature()
+
+	// the signing input is the compact serialization without the signature
+	input := string(token)[:strings.LastIndex(string(token), ".")]
+	switch alg := signatures[0].ProtectedHeaders().Algorithm(); alg {
+	case jwa.ES256K:
+		if len(signature) != 64 {
+			return errors.Errorf("invalid signature length: %d", len(signature))
+		}
+		digest := sha256.Sum256([]byte(input))
+		for v := byte(0); v < 2; v++ {
+			pubKey, err := ethcrypto.SigToPub(digest[:], append(signature[:64:64], v))
+			if err == nil && ethcrypto.PubkeyToAddress(*pubKey) == ethcommon.HexToAddress(account) {
+				return nil
+			}
+		}
+	case keyaccess.EIP191:
+		signer, err := keyaccess.RecoverEIP191([]byte(input), signature)
+		if err != nil {
+			return err
+		}
+		if signer == ethcommon.HexToAddress(account) {
+			return nil
+		}
+	default:
+		return errors.Errorf("unsupported signature algorithm for did:pkh: %s", alg)
+	}
+	return errors.Errorf("token is not signed by the account of did: %s", did)
+}
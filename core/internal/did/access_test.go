@@ -0,0 +1,115 @@
+package did
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/key"
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+)
+
+func TestResolveKeyAgreementKey(t *testing.T) {
+	resolver, err := BuildMultiMethodResolver([]string{"key"})
+	require.NoError(t, err)
+
+	// the sdk only expands did:key documents of secp256k1 keys when built with the jwx_es256k tag
+	for _, kt := range []crypto.KeyType{crypto.P256, crypto.X25519} {
+		t.Run(string(kt), func(t *testing.T) {
+			_, doc, err := key.GenerateDIDKey(kt)
+			require.NoError(t, err)
+			expanded, err := doc.Expand()
+			require.NoError(t, err)
+
+			kid, pubKey, err := ResolveKeyAgreementKey(context.Background(), resolver, doc.String())
+			assert.NoError(t, err)
+			assert.Equal(t, expanded.VerificationMethod[0].ID, kid)
+			assert.NotNil(t, pubKey)
+
+			// a DID URL selects the referenced key
+			gotKID, _, err := ResolveKeyAgreementKey(context.Background(), resolver, kid)
+			assert.NoError(t, err)
+			assert.Equal(t, kid, gotKID)
+
+			_, _, err = ResolveKeyAgreementKey(context.Background(), resolver, doc.String()+"#unknown")
+			assert.ErrorContains(t, err, "has no key agreement key")
+		})
+	}
+}
+
+func TestGetKeyAgreementKeySECP256k1(t *testing.T) {
+	privKey, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	pub := ethcrypto.FromECDSAPub(&privKey.PublicKey)
+	doc := didsdk.Document{
+		ID: "did:web:example.com",
+		VerificationMethod: []didsdk.VerificationMethod{{
+			ID:         "#key-1",
+			Type:       "JsonWebKey2020",
+			Controller: "did:web:example.com",
+			PublicKeyJWK: &jwx.PublicKeyJWK{
+				KTY: "EC",
+				CRV: "secp256k1",
+				X:   base64.RawURLEncoding.EncodeToString(pub[1:33]),
+				Y:   base64.RawURLEncoding.EncodeToString(pub[33:]),
+			},
+		}},
+	}
+
+	pubKey, err := getKeyAgreementKey(doc, "did:web:example.com#key-1")
+	assert.NoError(t, err)
+	assert.Equal(t, &privKey.PublicKey, pubKey)
+}
+
+func TestVerifyTokenFromPKH(t *testing.T) {
+	privKey, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	did := fmt.Sprintf("did:pkh:eip155:1337:%s", ethcrypto.PubkeyToAddress(privKey.PublicKey))
+
+	token, err := jwt.NewBuilder().Issuer(did).JwtID("session").Build()
+	require.NoError(t, err)
+	headers := jws.NewHeaders()
+	require.NoError(t, headers.Set(jws.KeyIDKey, did+"#blockchainAccountId"))
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256K, privKey, jws.WithProtectedHeaders(headers)))
+	require.NoError(t, err)
+
+	assert.NoError(t, VerifyTokenFromPKH(did, keyaccess.JWT(signed)))
+
+	otherKey, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	other := fmt.Sprintf("did:pkh:eip155:1337:%s", ethcrypto.PubkeyToAddress(otherKey.PublicKey))
+	assert.ErrorContains(t, VerifyTokenFromPKH(other, keyaccess.JWT(signed)), "not signed by the account")
+
+	assert.ErrorContains(t, VerifyTokenFromPKH("did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp", keyaccess.JWT(signed)), "not a did:pkh")
+
+	t.Run("verifies tokens signed as personal message", func(tt *testing.T) {
+		key := keyaccess.EIP191Key{Context: context.Background(), Signer: textSigner{key: privKey}}
+		signed, err := jwt.Sign(token, jwt.WithKey(keyaccess.EIP191, key, jws.WithProtectedHeaders(headers)))
+		require.NoError(tt, err)
+
+		assert.NoError(tt, VerifyTokenFromPKH(did, keyaccess.JWT(signed)))
+		assert.ErrorContains(tt, VerifyTokenFromPKH(other, keyaccess.JWT(signed)), "not signed by the account")
+	})
+}
+
+// textSigner signs personal messages with a key, as a remote signer does
+type textSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s textSigner) SignText(_ context.Context, text []byte) ([]byte, error) {
+	return ethcrypto.Sign(accounts.TextHash(text), s.key)
+}
@@ -0,0 +1,334 @@
+// Package groth16 proves and verifies Groth16 proofs over the BN254 (alt_bn128) curve off-chain. Verification is
+// equivalent to the verifyTx function of the policy verifier contracts exported by ZoKrates, so that a proof accepted
+// here is also accepted on-chain. Keys, proofs and inputs are read and written in the JSON formats of ZoKrates.
+package groth16
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/fapiper/onchain-access-control/core/contracts"
+)
+
+const (
+	zokratesScheme = "g16"
+	zokratesCurve  = "bn128"
+)
+
+var (
+	// ScalarField is the order of the BN254 scalar field all public inputs must be reduced to
+	ScalarField, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+	ErrInputCount         = errors.New("wrong number of public inputs")
+	ErrInputNotInField    = errors.New("public input is not in the scalar field")
+	ErrInvalidPoint       = errors.New("invalid curve point")
+	ErrPairingCheckFailed = errors.New("pairing check failed")
+	ErrUnsatisfied        = errors.New("witness does not satisfy the constraint system")
+)
+
+// VerificationKey is a Groth16 verification key over BN254
+type VerificationKey struct {
+	Alpha    *bn256.G1
+	Beta     *bn256.G2
+	Gamma    *bn256.G2
+	Delta    *bn256.G2
+	GammaABC []*bn256.G1
+}
+
+// NumInputs returns the number of public inputs proofs for this key have
+func (vk VerificationKey) NumInputs() int {
+	return len(vk.GammaABC) - 1
+}
+
+// zokratesVerificationKey is the JSON format of the verification key written by `zokrates setup`. Coordinates of G2
+// points are encoded with the real part first, the same way as in the Pairing library of the verifier contracts.
+type zokratesVerificationKey struct {
+	Scheme   string       `json:"scheme"`
+	Curve    string       `json:"curve"`
+	Alpha    [2]string    `json:"alpha"`
+	Beta     [2][2]string `json:"beta"`
+	Gamma    [2][2]string `json:"gamma"`
+	Delta    [2][2]string `json:"delta"`
+	GammaABC [][2]string  `json:"gamma_abc"`
+}
+
+// ParseVerificationKey parses a Groth16 verification key in the JSON format of ZoKrates
+func ParseVerificationKey(data []byte) (*VerificationKey, error) {
+	var raw zokratesVerificationKey
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling verification key")
+	}
+	if raw.Scheme != "" && raw.Scheme != zokratesScheme {
+		return nil, errors.Errorf("unsupported proving scheme<%s>, expected %s", raw.Scheme, zokratesScheme)
+	}
+	if raw.Curve != "" && raw.Curve != zokratesCurve {
+		return nil, errors.Errorf("unsupported curve<%s>, expected %s", raw.Curve, zokratesCurve)
+	}
+	if len(raw.GammaABC) == 0 {
+		return nil, errors.New("verification key has no gamma_abc points")
+	}
+
+	var (
+		vk  VerificationKey
+		err error
+	)
+	if vk.Alpha, err = parseG1(raw.Alpha); err != nil {
+		return nil, errors.Wrap(err, "alpha")
+	}
+	if vk.Beta, err = parseG2(raw.Beta); err != nil {
+		return nil, errors.Wrap(err, "beta")
+	}
+	if vk.Gamma, err = parseG2(raw.Gamma); err != nil {
+		return nil, errors.Wrap(err, "gamma")
+	}
+	if vk.Delta, err = parseG2(raw.Delta); err != nil {
+		return nil, errors.Wrap(err, "delta")
+	}
+	vk.GammaABC = make([]*bn256.G1, 0, len(raw.GammaABC))
+	for i, p := range raw.GammaABC {
+		point, err := parseG1(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "gamma_abc[%d]", i)
+		}
+		vk.GammaABC = append(vk.GammaABC, point)
+	}
+	return &vk, nil
+}
+
+// MarshalJSON encodes the verification key in the JSON format of ZoKrates
+func (vk VerificationKey) MarshalJSON() ([]byte, error) {
+	raw := zokratesVerificationKey{
+		Scheme: zokratesScheme,
+		Curve:  zokratesCurve,
+		Alpha:  hexG1(vk.Alpha),
+		Beta:   hexG2(vk.Beta),
+		Gamma:  hexG2(vk.Gamma),
+		Delta:  hexG2(vk.Delta),
+	}
+	for _, p := range vk.GammaABC {
+		raw.GammaABC = append(raw.GammaABC, hexG1(p))
+	}
+	return json.Marshal(raw)
+}
+
+// zokratesProof is the JSON format of the proof written by `zokrates generate-proof`
+type zokratesProof struct {
+	Scheme string `json:"scheme"`
+	Curve  string `json:"curve"`
+	Proof  struct {
+		A [2]string    `json:"a"`
+		B [2][2]string `json:"b"`
+		C [2]string    `json:"c"`
+	} `json:"proof"`
+	Inputs []string `json:"inputs"`
+}
+
+// ParseProof parses a Groth16 proof and its public inputs in the JSON format of ZoKrates
+func ParseProof(data []byte) (*contracts.IPolicyVerifierProof, []*big.Int, error) {
+	var raw zokratesProof
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshalling proof")
+	}
+	if raw.Scheme != "" && raw.Scheme != zokratesScheme {
+		return nil, nil, errors.Errorf("unsupported proving scheme<%s>, expected %s", raw.Scheme, zokratesScheme)
+	}
+
+	var (
+		proof contracts.IPolicyVerifierProof
+		err   error
+	)
+	if proof.A.X, proof.A.Y, err = parseBigs(raw.Proof.A[0], raw.Proof.A[1]); err != nil {
+		return nil, nil, errors.Wrap(err, "a")
+	}
+	if proof.B.X, err = parseBigPair(raw.Proof.B[0]); err != nil {
+		return nil, nil, errors.Wrap(err, "b")
+	}
+	if proof.B.Y, err = parseBigPair(raw.Proof.B[1]); err != nil {
+		return nil, nil, errors.Wrap(err, "b")
+	}
+	if proof.C.X, proof.C.Y, err = parseBigs(raw.Proof.C[0], raw.Proof.C[1]); err != nil {
+		return nil, nil, errors.Wrap(err, "c")
+	}
+
+	inputs := make([]*big.Int, 0, len(raw.Inputs))
+	for i, input := range raw.Inputs {
+		n, ok := math.ParseBig256(input)
+		if !ok {
+			return nil, nil, errors.Errorf("invalid input %d<%s>", i, input)
+		}
+		inputs = append(inputs, n)
+	}
+	return &proof, inputs, nil
+}
+
+// MarshalProof encodes a proof and its public inputs in the JSON format of ZoKrates
+func MarshalProof(proof contracts.IPolicyVerifierProof, inputs []*big.Int) ([]byte, error) {
+	raw := zokratesProof{Scheme: zokratesScheme, Curve: zokratesCurve, Inputs: make([]string, 0, len(inputs))}
+	raw.Proof.A = [2]string{hexBig(proof.A.X), hexBig(proof.A.Y)}
+	raw.Proof.B = [2][2]string{{hexBig(proof.B.X[0]), hexBig(proof.B.X[1])}, {hexBig(proof.B.Y[0]), hexBig(proof.B.Y[1])}}
+	raw.Proof.C = [2]string{hexBig(proof.C.X), hexBig(proof.C.Y)}
+	for _, input := range inputs {
+		raw.Inputs = append(raw.Inputs, hexBig(input))
+	}
+	return json.Marshal(raw)
+}
+
+// Verify checks a proof and its public inputs against the verification key. The returned error wraps one of
+// ErrInputCount, ErrInputNotInField, ErrInvalidPoint or ErrPairingCheckFailed.
+func Verify(vk VerificationKey, proof contracts.IPolicyVerifierProof, inputs []*big.Int) error {
+	if len(inputs) != vk.NumInputs() {
+		return errors.Wrapf(ErrInputCount, "expected %d, got %d", vk.NumInputs(), len(inputs))
+	}
+
+	// vk_x = gamma_abc[0] + sum(inputs[i] * gamma_abc[i+1])
+	vkX := new(bn256.G1).Set(vk.GammaABC[0])
+	for i, input := range inputs {
+		if input == nil || input.Sign() < 0 || input.Cmp(ScalarField) >= 0 {
+			return errors.Wrapf(ErrInputNotInField, "input %d", i)
+		}
+		vkX.Add(vkX, new(bn256.G1).ScalarMult(vk.GammaABC[i+1], input))
+	}
+
+	a, err := toG1(proof.A.X, proof.A.Y)
+	if err != nil {
+		return errors.Wrap(err, "proof point a")
+	}
+	b, err := toG2(proof.B.X, proof.B.Y)
+	if err != nil {
+		return errors.Wrap(err, "proof point b")
+	}
+	c, err := toG1(proof.C.X, proof.C.Y)
+	if err != nil {
+		return errors.Wrap(err, "proof point c")
+	}
+
+	// e(a, b) * e(-vk_x, gamma) * e(-c, delta) * e(-alpha, beta) == 1
+	ok := bn256.PairingCheck(
+		[]*bn256.G1{a, new(bn256.G1).Neg(vkX), new(bn256.G1).Neg(c), new(bn256.G1).Neg(vk.Alpha)},
+		[]*bn256.G2{b, vk.Gamma, vk.Delta, vk.Beta},
+	)
+	if !ok {
+		return ErrPairingCheckFailed
+	}
+	return nil
+}
+
+func parseG1(p [2]string) (*bn256.G1, error) {
+	x, y, err := parseBigs(p[0], p[1])
+	if err != nil {
+		return nil, err
+	}
+	return toG1(x, y)
+}
+
+func parseG2(p [2][2]string) (*bn256.G2, error) {
+	x, err := parseBigPair(p[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := parseBigPair(p[1])
+	if err != nil {
+		return nil, err
+	}
+	return toG2(x, y)
+}
+
+func parseBigPair(p [2]string) ([2]*big.Int, error) {
+	a, b, err := parseBigs(p[0], p[1])
+	return [2]*big.Int{a, b}, err
+}
+
+func parseBigs(a, b string) (*big.Int, *big.Int, error) {
+	x, ok := math.ParseBig256(a)
+	if !ok {
+		return nil, nil, errors.Errorf("invalid field element<%s>", a)
+	}
+	y, ok := math.ParseBig256(b)
+	if !ok {
+		return nil, nil, errors.Errorf("invalid field element<%s>", b)
+	}
+	return x, y, nil
+}
+
+func toG1(x, y *big.Int) (*bn256.G1, error) {
+	data, err := marshalCoordinates(x, y)
+	if err != nil {
+		return nil, err
+	}
+	point := new(bn256.G1)
+	if _, err = point.Unmarshal(data); err != nil {
+		return nil, errors.Wrap(ErrInvalidPoint, err.Error())
+	}
+	return point, nil
+}
+
+// toG2 decodes a G2 point whose coordinates are given with the real part first. Like the Pairing library of the
+// verifier contracts, it swaps them into the order of the pairing precompile, which expects the imaginary part first.
+func toG2(x, y [2]*big.Int) (*bn256.G2, error) {
+	data, err := marshalCoordinates(x[1], x[0], y[1], y[0])
+	if err != nil {
+		return nil, err
+	}
+	point := new(bn256.G2)
+	if _, err = point.Unmarshal(data); err != nil {
+		return nil, errors.Wrap(ErrInvalidPoint, err.Error())
+	}
+	return point, nil
+}
+
+// fromG1 encodes a G1 point as a point of the Pairing library of the verifier contracts
+func fromG1(p *bn256.G1) contracts.PairingG1Point {
+	c := unmarshalCoordinates(p.Marshal())
+	return contracts.PairingG1Point{X: c[0], Y: c[1]}
+}
+
+// fromG2 encodes a G2 point as a point of the Pairing library of the verifier contracts, i.e. it swaps the coordinates
+// to the real part first
+func fromG2(p *bn256.G2) contracts.PairingG2Point {
+	c := unmarshalCoordinates(p.Marshal())
+	return contracts.PairingG2Point{X: [2]*big.Int{c[1], c[0]}, Y: [2]*big.Int{c[3], c[2]}}
+}
+
+func hexG1(p *bn256.G1) [2]string {
+	point := fromG1(p)
+	return [2]string{hexBig(point.X), hexBig(point.Y)}
+}
+
+func hexG2(p *bn256.G2) [2][2]string {
+	point := fromG2(p)
+	return [2][2]string{{hexBig(point.X[0]), hexBig(point.X[1])}, {hexBig(point.Y[0]), hexBig(point.Y[1])}}
+}
+
+func hexBig(n *big.Int) string {
+	if n == nil {
+		n = new(big.Int)
+	}
+	return fmt.Sprintf("0x%064x", n)
+}
+
+func unmarshalCoordinates(data []byte) []*big.Int {
+	out := make([]*big.Int, 0, len(data)/32)
+	for i := 0; i < len(data); i += 32 {
+		out = append(out, new(big.Int).SetBytes(data[i:i+32]))
+	}
+	return out
+}
+
+func marshalCoordinates(coordinates ...*big.Int) ([]byte, error) {
+	out := make([]byte, 0, 32*len(coordinates))
+	for _, c := range coordinates {
+		if c == nil {
+			c = new(big.Int)
+		}
+		if c.Sign() < 0 || c.BitLen() > 256 {
+			return nil, errors.Wrapf(ErrInvalidPoint, "coordinate %s out of range", c)
+		}
+		out = append(out, math.U256Bytes(new(big.Int).Set(c))...)
+	}
+	return out, nil
+}
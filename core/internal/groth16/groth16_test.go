@@ -0,0 +1,147 @@
+package groth16
+
+import (
+	"crypto/rand"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/contracts"
+)
+
+func TestVerify(t *testing.T) {
+	inputs := []*big.Int{big.NewInt(20030101), big.NewInt(1), new(big.Int).Sub(ScalarField, big.NewInt(1))}
+	vkJSON, proof := newTestProof(t, inputs)
+
+	vk, err := ParseVerificationKey(vkJSON)
+	require.NoError(t, err)
+	assert.Equal(t, len(inputs), vk.NumInputs())
+
+	t.Run("valid proof", func(tt *testing.T) {
+		assert.NoError(tt, Verify(*vk, proof, inputs))
+	})
+
+	t.Run("wrong number of inputs", func(tt *testing.T) {
+		err := Verify(*vk, proof, inputs[:2])
+		assert.ErrorIs(tt, err, ErrInputCount)
+		assert.ErrorContains(tt, err, "expected 3, got 2")
+	})
+
+	t.Run("input not in field", func(tt *testing.T) {
+		err := Verify(*vk, proof, []*big.Int{inputs[0], inputs[1], ScalarField})
+		assert.ErrorIs(tt, err, ErrInputNotInField)
+		assert.ErrorContains(tt, err, "input 2")
+	})
+
+	t.Run("modified input", func(tt *testing.T) {
+		err := Verify(*vk, proof, []*big.Int{big.NewInt(20030102), inputs[1], inputs[2]})
+		assert.ErrorIs(tt, err, ErrPairingCheckFailed)
+	})
+
+	t.Run("modified proof", func(tt *testing.T) {
+		tampered := proof
+		tampered.A, tampered.C = proof.C, proof.A
+		assert.ErrorIs(tt, Verify(*vk, tampered, inputs), ErrPairingCheckFailed)
+	})
+
+	t.Run("point not on curve", func(tt *testing.T) {
+		tampered := proof
+		tampered.A = contracts.PairingG1Point{X: big.NewInt(1), Y: big.NewInt(1)}
+		err := Verify(*vk, tampered, inputs)
+		assert.ErrorIs(tt, err, ErrInvalidPoint)
+		assert.ErrorContains(tt, err, "proof point a")
+	})
+}
+
+func TestVerifyZoKratesProof(t *testing.T) {
+	// verification key of contracts/src/Policy.sol and the proof of dev/client_cli/assign_role.sh
+	vkJSON, err := os.ReadFile("testdata/verification.key")
+	require.NoError(t, err)
+	vk, err := ParseVerificationKey(vkJSON)
+	require.NoError(t, err)
+
+	proofJSON, err := os.ReadFile("testdata/proof.json")
+	require.NoError(t, err)
+	proof, inputs, err := ParseProof(proofJSON)
+	require.NoError(t, err)
+
+	assert.NoError(t, Verify(*vk, *proof, inputs))
+}
+
+func TestParseVerificationKey(t *testing.T) {
+	vkJSON, _ := newTestProof(t, []*big.Int{big.NewInt(1)})
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(vkJSON, &raw))
+
+	t.Run("unsupported scheme", func(tt *testing.T) {
+		raw["scheme"] = "gm17"
+		data, _ := json.Marshal(raw)
+		_, err := ParseVerificationKey(data)
+		assert.ErrorContains(tt, err, "unsupported proving scheme<gm17>")
+		raw["scheme"] = zokratesScheme
+	})
+
+	t.Run("invalid point", func(tt *testing.T) {
+		raw["alpha"] = []string{"0x1", "0x1"}
+		data, _ := json.Marshal(raw)
+		_, err := ParseVerificationKey(data)
+		assert.ErrorIs(tt, err, ErrInvalidPoint)
+		assert.ErrorContains(tt, err, "alpha")
+	})
+}
+
+// newTestProof sets up a verification key from known toxic waste, which allows to simulate a valid proof for any
+// inputs without a circuit: with a = alpha*beta + x*gamma + c*delta, e(A, B) equals the product of the other pairings.
+func newTestProof(t *testing.T, inputs []*big.Int) ([]byte, contracts.IPolicyVerifierProof) {
+	alpha, beta, gamma, delta := randomScalar(t), randomScalar(t), randomScalar(t), randomScalar(t)
+
+	gammaABC := make([]*big.Int, len(inputs)+1)
+	x := new(big.Int)
+	for i := range gammaABC {
+		gammaABC[i] = randomScalar(t)
+		if i == 0 {
+			x.Set(gammaABC[0])
+			continue
+		}
+		x.Add(x, new(big.Int).Mul(inputs[i-1], gammaABC[i]))
+	}
+
+	a, b := randomScalar(t), randomScalar(t)
+	c := new(big.Int).Mul(a, b)
+	c.Sub(c, new(big.Int).Mul(alpha, beta))
+	c.Sub(c, new(big.Int).Mul(x, gamma))
+	c.Mul(c, new(big.Int).ModInverse(delta, ScalarField))
+	c.Mod(c, ScalarField)
+
+	vk := zokratesVerificationKey{
+		Scheme: zokratesScheme,
+		Curve:  zokratesCurve,
+		Alpha:  hexG1(new(bn256.G1).ScalarBaseMult(alpha)),
+		Beta:   hexG2(new(bn256.G2).ScalarBaseMult(beta)),
+		Gamma:  hexG2(new(bn256.G2).ScalarBaseMult(gamma)),
+		Delta:  hexG2(new(bn256.G2).ScalarBaseMult(delta)),
+	}
+	for _, k := range gammaABC {
+		vk.GammaABC = append(vk.GammaABC, hexG1(new(bn256.G1).ScalarBaseMult(k)))
+	}
+	vkJSON, err := json.Marshal(vk)
+	require.NoError(t, err)
+
+	return vkJSON, contracts.IPolicyVerifierProof{
+		A: fromG1(new(bn256.G1).ScalarBaseMult(a)),
+		B: fromG2(new(bn256.G2).ScalarBaseMult(b)),
+		C: fromG1(new(bn256.G1).ScalarBaseMult(c)),
+	}
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	k, err := rand.Int(rand.Reader, ScalarField)
+	require.NoError(t, err)
+	return k
+}
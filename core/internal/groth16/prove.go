@@ -0,0 +1,167 @@
+package groth16
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+	"github.com/pkg/errors"
+
+	"github.com/fapiper/onchain-access-control/core/contracts"
+)
+
+// Prove generates a proof that the witness satisfies the constraint system. The blinding factors are drawn from the
+// given source of randomness, or crypto/rand if nil. It returns the proof together with its public inputs.
+func Prove(pk ProvingKey, r1cs *R1CS, witness []*big.Int, random io.Reader) (*contracts.IPolicyVerifierProof, []*big.Int, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	if len(pk.A) != r1cs.numVariables || len(pk.K) != r1cs.numVariables-r1cs.numPublic-1 || len(pk.H) != len(r1cs.constraints)-1 {
+		return nil, nil, errors.New("proving key does not match the constraint system")
+	}
+	if err := r1cs.IsSatisfied(witness); err != nil {
+		return nil, nil, err
+	}
+
+	h, err := quotient(r1cs, witness)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := randomNonZero(random)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "sampling blinding factors")
+	}
+	s, err := randomNonZero(random)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "sampling blinding factors")
+	}
+
+	// A = alpha + sum(w_i * u_i(tau)) + r * delta
+	a := new(bn256.G1).Set(pk.AlphaG1)
+	a.Add(a, multiExpG1(pk.A, witness))
+	a.Add(a, new(bn256.G1).ScalarMult(pk.DeltaG1, r))
+
+	// B = beta + sum(w_i * v_i(tau)) + s * delta, in G2 for the proof and in G1 for C
+	b := new(bn256.G2).Set(pk.BetaG2)
+	for i, p := range pk.B2 {
+		if witness[i].Sign() != 0 {
+			b.Add(b, new(bn256.G2).ScalarMult(p, witness[i]))
+		}
+	}
+	b.Add(b, new(bn256.G2).ScalarMult(pk.DeltaG2, s))
+	b1 := new(bn256.G1).Set(pk.BetaG1)
+	b1.Add(b1, multiExpG1(pk.B1, witness))
+	b1.Add(b1, new(bn256.G1).ScalarMult(pk.DeltaG1, s))
+
+	// C = sum(w_i * k_i) + h(tau) * t(tau) / delta + s * A + r * B - r * s * delta
+	c := multiExpG1(pk.K, witness[r1cs.numPublic+1:])
+	c.Add(c, multiExpG1(pk.H, h))
+	c.Add(c, new(bn256.G1).ScalarMult(a, s))
+	c.Add(c, new(bn256.G1).ScalarMult(b1, r))
+	rs := mod(new(big.Int).Mul(r, s))
+	c.Add(c, new(bn256.G1).Neg(new(bn256.G1).ScalarMult(pk.DeltaG1, rs)))
+
+	proof := contracts.IPolicyVerifierProof{A: fromG1(a), B: fromG2(b), C: fromG1(c)}
+	inputs := make([]*big.Int, 0, r1cs.numPublic)
+	for _, input := range witness[1 : r1cs.numPublic+1] {
+		inputs = append(inputs, new(big.Int).Set(input))
+	}
+	return &proof, inputs, nil
+}
+
+// quotient returns the coefficients of h(x) = (A(x) * B(x) - C(x)) / t(x), where A, B and C interpolate the
+// evaluations of each constraint for the witness over the domain
+func quotient(r1cs *R1CS, witness []*big.Int) ([]*big.Int, error) {
+	d := newDomain(len(r1cs.constraints))
+
+	// t(x) = prod (x - x_j)
+	t := []*big.Int{big.NewInt(1)}
+	for j := 0; j < d.size; j++ {
+		t = mulPolynomials(t, []*big.Int{mod(new(big.Int).Neg(d.point(j))), big.NewInt(1)})
+	}
+
+	a, b, c := newPolynomial(d.size), newPolynomial(d.size), newPolynomial(d.size)
+	for j, constraint := range r1cs.constraints {
+		av, bv, cv := evaluate(constraint.a, witness), evaluate(constraint.b, witness), evaluate(constraint.c, witness)
+		if av.Sign() == 0 && bv.Sign() == 0 && cv.Sign() == 0 {
+			continue
+		}
+		// L_j(x) = t(x) / (x - x_j) / denominator_j
+		basis := divideByLinear(t, d.point(j))
+		scale := new(big.Int).ModInverse(d.denominators[j], ScalarField)
+		for i, coefficient := range basis {
+			k := mod(new(big.Int).Mul(coefficient, scale))
+			a[i] = mod(a[i].Add(a[i], new(big.Int).Mul(k, av)))
+			b[i] = mod(b[i].Add(b[i], new(big.Int).Mul(k, bv)))
+			c[i] = mod(c[i].Add(c[i], new(big.Int).Mul(k, cv)))
+		}
+	}
+
+	p := mulPolynomials(a, b)
+	for i := range c {
+		p[i] = mod(p[i].Sub(p[i], c[i]))
+	}
+
+	// long division by the monic t(x)
+	h := newPolynomial(d.size - 1)
+	for i := len(p) - 1; i >= d.size; i-- {
+		k := new(big.Int).Set(p[i])
+		h[i-d.size] = k
+		for l, coefficient := range t {
+			p[i-d.size+l] = mod(p[i-d.size+l].Sub(p[i-d.size+l], new(big.Int).Mul(k, coefficient)))
+		}
+	}
+	for _, remainder := range p[:d.size] {
+		if remainder.Sign() != 0 {
+			return nil, errors.Wrap(ErrUnsatisfied, "constraints are not divisible by the vanishing polynomial")
+		}
+	}
+	return h, nil
+}
+
+func newPolynomial(size int) []*big.Int {
+	out := make([]*big.Int, size)
+	for i := range out {
+		out[i] = new(big.Int)
+	}
+	return out
+}
+
+func mulPolynomials(x, y []*big.Int) []*big.Int {
+	out := newPolynomial(len(x) + len(y) - 1)
+	for i, a := range x {
+		if a.Sign() == 0 {
+			continue
+		}
+		for j, b := range y {
+			out[i+j].Add(out[i+j], new(big.Int).Mul(a, b))
+		}
+	}
+	for _, coefficient := range out {
+		mod(coefficient)
+	}
+	return out
+}
+
+// divideByLinear divides a polynomial by (x - root) with synthetic division, discarding the remainder
+func divideByLinear(p []*big.Int, root *big.Int) []*big.Int {
+	out := newPolynomial(len(p) - 1)
+	carry := new(big.Int)
+	for i := len(p) - 1; i > 0; i-- {
+		carry = mod(new(big.Int).Add(p[i], new(big.Int).Mul(carry, root)))
+		out[i-1] = carry
+	}
+	return out
+}
+
+func multiExpG1(points []*bn256.G1, scalars []*big.Int) *bn256.G1 {
+	sum := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	for i, p := range points {
+		if scalars[i].Sign() != 0 {
+			sum.Add(sum, new(bn256.G1).ScalarMult(p, scalars[i]))
+		}
+	}
+	return sum
+}
@@ -0,0 +1,88 @@
+package groth16
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeCircuit proves knowledge of a private value within public bounds
+func rangeCircuit(minimum, value, maximum int64) (*R1CS, []*big.Int) {
+	b := NewBuilder()
+	lower := b.PublicInput(big.NewInt(minimum))
+	upper := b.PublicInput(big.NewInt(maximum))
+	v := b.PrivateInput(big.NewInt(value))
+	b.AssertLessOrEqual(lower, v, 16)
+	b.AssertLessOrEqual(v, upper, 16)
+	// v * v is only there to have a multiplication gate
+	b.Mul(v, v)
+	return b.Build()
+}
+
+func TestProve(t *testing.T) {
+	r1cs, witness := rangeCircuit(18, 42, 130)
+	assert.Equal(t, 2, r1cs.NumPublic())
+	require.NoError(t, r1cs.IsSatisfied(witness))
+
+	pk, vk, err := Setup(r1cs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, vk.NumInputs())
+
+	t.Run("valid witness", func(tt *testing.T) {
+		proof, inputs, err := Prove(*pk, r1cs, witness, nil)
+		require.NoError(tt, err)
+		assert.Equal(tt, []*big.Int{big.NewInt(18), big.NewInt(130)}, inputs)
+		assert.NoError(tt, Verify(*vk, *proof, inputs))
+
+		// the proof must not hold for other bounds
+		assert.ErrorIs(tt, Verify(*vk, *proof, []*big.Int{big.NewInt(50), big.NewInt(130)}), ErrPairingCheckFailed)
+	})
+
+	t.Run("value out of range", func(tt *testing.T) {
+		_, outOfRange := rangeCircuit(18, 17, 130)
+		_, _, err := Prove(*pk, r1cs, outOfRange, nil)
+		assert.ErrorIs(tt, err, ErrUnsatisfied)
+	})
+
+	t.Run("proving key of another circuit", func(tt *testing.T) {
+		b := NewBuilder()
+		b.Mul(b.PublicInput(big.NewInt(2)), b.PrivateInput(big.NewInt(3)))
+		other, _ := b.Build()
+		otherPK, _, err := Setup(other, nil)
+		require.NoError(tt, err)
+
+		_, _, err = Prove(*otherPK, r1cs, witness, nil)
+		assert.ErrorContains(tt, err, "proving key does not match the constraint system")
+	})
+}
+
+func TestKeyEncoding(t *testing.T) {
+	r1cs, witness := rangeCircuit(0, 7, 7)
+	pk, vk, err := Setup(r1cs, nil)
+	require.NoError(t, err)
+
+	pkJSON, err := json.Marshal(pk)
+	require.NoError(t, err)
+	parsedPK, err := ParseProvingKey(pkJSON)
+	require.NoError(t, err)
+
+	vkJSON, err := json.Marshal(vk)
+	require.NoError(t, err)
+	parsedVK, err := ParseVerificationKey(vkJSON)
+	require.NoError(t, err)
+
+	proof, inputs, err := Prove(*parsedPK, r1cs, witness, nil)
+	require.NoError(t, err)
+
+	proofJSON, err := MarshalProof(*proof, inputs)
+	require.NoError(t, err)
+	parsedProof, parsedInputs, err := ParseProof(proofJSON)
+	require.NoError(t, err)
+	assert.NoError(t, Verify(*parsedVK, *parsedProof, parsedInputs))
+
+	_, err = ParseProvingKey(vkJSON)
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package groth16
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Variable is a variable of a constraint system, either a public input or a private variable of the witness
+type Variable struct {
+	public bool
+	index  int
+}
+
+// one is the constant variable every constraint system starts with
+var one = Variable{public: true}
+
+// Term is a variable scaled by a coefficient
+type Term struct {
+	Variable    Variable
+	Coefficient *big.Int
+}
+
+// LinearCombination is a sum of terms
+type LinearCombination []Term
+
+// Add returns the sum of both linear combinations
+func (lc LinearCombination) Add(other LinearCombination) LinearCombination {
+	out := make(LinearCombination, 0, len(lc)+len(other))
+	return append(append(out, lc...), other...)
+}
+
+// Sub returns the difference of both linear combinations
+func (lc LinearCombination) Sub(other LinearCombination) LinearCombination {
+	return lc.Add(other.Scale(big.NewInt(-1)))
+}
+
+// Scale returns the linear combination multiplied by a constant
+func (lc LinearCombination) Scale(c *big.Int) LinearCombination {
+	out := make(LinearCombination, 0, len(lc))
+	for _, t := range lc {
+		out = append(out, Term{Variable: t.Variable, Coefficient: mod(new(big.Int).Mul(t.Coefficient, c))})
+	}
+	return out
+}
+
+// Constant returns the linear combination of a constant value
+func Constant(c *big.Int) LinearCombination {
+	return LinearCombination{{Variable: one, Coefficient: mod(new(big.Int).Set(c))}}
+}
+
+// Builder assembles a rank-1 constraint system together with a witness that assigns a value to each variable. Since
+// the constraints never depend on the assigned values, a circuit built with arbitrary values yields the constraint
+// system to run the setup with.
+type Builder struct {
+	public      []*big.Int
+	private     []*big.Int
+	constraints []constraint
+}
+
+// NewBuilder returns an empty builder
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// PublicInput adds a public input with the given value
+func (b *Builder) PublicInput(value *big.Int) LinearCombination {
+	b.public = append(b.public, mod(new(big.Int).Set(value)))
+	return Variable{public: true, index: len(b.public)}.lc()
+}
+
+// PrivateInput adds a private variable with the given value
+func (b *Builder) PrivateInput(value *big.Int) LinearCombination {
+	b.private = append(b.private, mod(new(big.Int).Set(value)))
+	return Variable{index: len(b.private) - 1}.lc()
+}
+
+// Mul returns a new variable constrained to the product of both linear combinations
+func (b *Builder) Mul(x, y LinearCombination) LinearCombination {
+	product := b.PrivateInput(new(big.Int).Mul(b.value(x), b.value(y)))
+	b.constraints = append(b.constraints, constraint{a: x, b: y, c: product})
+	return product
+}
+
+// AssertEqual constrains both linear combinations to the same value
+func (b *Builder) AssertEqual(x, y LinearCombination) {
+	b.constraints = append(b.constraints, constraint{a: x, b: Constant(big.NewInt(1)), c: y})
+}
+
+// ToBits decomposes a linear combination into n boolean variables, least significant bit first. The constraint
+// system is only satisfied if the value fits into n bits.
+func (b *Builder) ToBits(x LinearCombination, n int) []LinearCombination {
+	value := b.value(x)
+	bits := make([]LinearCombination, n)
+	var sum LinearCombination
+	for i := range bits {
+		bit := b.PrivateInput(big.NewInt(int64(value.Bit(i))))
+		// bit * (bit - 1) == 0
+		b.constraints = append(b.constraints, constraint{a: bit, b: bit.Sub(Constant(big.NewInt(1)))})
+		bits[i] = bit
+		sum = sum.Add(bit.Scale(new(big.Int).Lsh(big.NewInt(1), uint(i))))
+	}
+	b.AssertEqual(sum, x)
+	return bits
+}
+
+// AssertLessOrEqual constrains x <= y for values of at most n bits
+func (b *Builder) AssertLessOrEqual(x, y LinearCombination, n int) {
+	b.ToBits(y.Sub(x), n)
+}
+
+// Build returns the constraint system and the witness assigning a value to each of its variables. Public inputs are
+// the witness values following the constant one.
+func (b *Builder) Build() (*R1CS, []*big.Int) {
+	r1cs := R1CS{numPublic: len(b.public), numVariables: 1 + len(b.public) + len(b.private)}
+	for _, c := range b.constraints {
+		r1cs.constraints = append(r1cs.constraints, compiledConstraint{
+			a: r1cs.compile(c.a),
+			b: r1cs.compile(c.b),
+			c: r1cs.compile(c.c),
+		})
+	}
+	// x_i * 0 == 0 for the constant and each public input keeps their polynomials linearly independent
+	for i := 0; i <= r1cs.numPublic; i++ {
+		r1cs.constraints = append(r1cs.constraints, compiledConstraint{a: []wireTerm{{wire: i, coefficient: big.NewInt(1)}}})
+	}
+
+	witness := make([]*big.Int, 0, r1cs.numVariables)
+	witness = append(witness, big.NewInt(1))
+	witness = append(witness, b.public...)
+	witness = append(witness, b.private...)
+	return &r1cs, witness
+}
+
+// value evaluates a linear combination with the values assigned so far
+func (b *Builder) value(lc LinearCombination) *big.Int {
+	sum := new(big.Int)
+	for _, t := range lc {
+		var v *big.Int
+		switch {
+		case t.Variable == one:
+			v = big.NewInt(1)
+		case t.Variable.public:
+			v = b.public[t.Variable.index-1]
+		default:
+			v = b.private[t.Variable.index]
+		}
+		sum.Add(sum, new(big.Int).Mul(v, t.Coefficient))
+	}
+	return mod(sum)
+}
+
+func (v Variable) lc() LinearCombination {
+	return LinearCombination{{Variable: v, Coefficient: big.NewInt(1)}}
+}
+
+type constraint struct {
+	a, b, c LinearCombination
+}
+
+// R1CS is a rank-1 constraint system a·w * b·w == c·w over the witness w = (1, public inputs, private variables)
+type R1CS struct {
+	numPublic    int
+	numVariables int
+	constraints  []compiledConstraint
+}
+
+type compiledConstraint struct {
+	a, b, c []wireTerm
+}
+
+type wireTerm struct {
+	wire        int
+	coefficient *big.Int
+}
+
+// NumPublic returns the number of public inputs
+func (r *R1CS) NumPublic() int {
+	return r.numPublic
+}
+
+// NumConstraints returns the number of constraints
+func (r *R1CS) NumConstraints() int {
+	return len(r.constraints)
+}
+
+// IsSatisfied checks the witness against each constraint
+func (r *R1CS) IsSatisfied(witness []*big.Int) error {
+	if len(witness) != r.numVariables {
+		return errors.Wrapf(ErrUnsatisfied, "expected %d variables, got %d", r.numVariables, len(witness))
+	}
+	for i, c := range r.constraints {
+		a, b, cv := evaluate(c.a, witness), evaluate(c.b, witness), evaluate(c.c, witness)
+		if mod(a.Mul(a, b)).Cmp(cv) != 0 {
+			return errors.Wrapf(ErrUnsatisfied, "constraint %d", i)
+		}
+	}
+	return nil
+}
+
+func (r *R1CS) compile(lc LinearCombination) []wireTerm {
+	out := make([]wireTerm, 0, len(lc))
+	for _, t := range lc {
+		wire := t.Variable.index
+		if !t.Variable.public {
+			wire += 1 + r.numPublic
+		}
+		out = append(out, wireTerm{wire: wire, coefficient: t.Coefficient})
+	}
+	return out
+}
+
+func evaluate(terms []wireTerm, witness []*big.Int) *big.Int {
+	sum := new(big.Int)
+	for _, t := range terms {
+		sum.Add(sum, new(big.Int).Mul(witness[t.wire], t.coefficient))
+	}
+	return mod(sum)
+}
+
+// mod reduces n into the scalar field in place
+func mod(n *big.Int) *big.Int {
+	return n.Mod(n, ScalarField)
+}
@@ -0,0 +1,309 @@
+package groth16
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// ProvingKey is a Groth16 proving key over BN254 for a constraint system
+type ProvingKey struct {
+	AlphaG1 *bn256.G1
+	BetaG1  *bn256.G1
+	BetaG2  *bn256.G2
+	DeltaG1 *bn256.G1
+	DeltaG2 *bn256.G2
+	// A, B1 and B2 hold u_i(tau) and v_i(tau) of each variable
+	A  []*bn256.G1
+	B1 []*bn256.G1
+	B2 []*bn256.G2
+	// K holds (beta*u_i(tau) + alpha*v_i(tau) + w_i(tau)) / delta of each private variable
+	K []*bn256.G1
+	// H holds tau^i * t(tau) / delta
+	H []*bn256.G1
+}
+
+// Setup runs the trusted setup for a constraint system. The toxic waste is drawn from the given source of randomness,
+// or crypto/rand if nil, and is discarded on return.
+func Setup(r1cs *R1CS, random io.Reader) (*ProvingKey, *VerificationKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	var toxic [5]*big.Int
+	for i := range toxic {
+		k, err := randomNonZero(random)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "sampling toxic waste")
+		}
+		toxic[i] = k
+	}
+	tau, alpha, beta, gamma, delta := toxic[0], toxic[1], toxic[2], toxic[3], toxic[4]
+
+	domain := newDomain(len(r1cs.constraints))
+	lagrange, err := domain.lagrangeAt(tau)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// u_i(tau), v_i(tau) and w_i(tau) of each variable
+	u, v, w := make([]*big.Int, r1cs.numVariables), make([]*big.Int, r1cs.numVariables), make([]*big.Int, r1cs.numVariables)
+	for i := range u {
+		u[i], v[i], w[i] = new(big.Int), new(big.Int), new(big.Int)
+	}
+	for j, c := range r1cs.constraints {
+		accumulate(u, c.a, lagrange[j])
+		accumulate(v, c.b, lagrange[j])
+		accumulate(w, c.c, lagrange[j])
+	}
+
+	gammaInverse := new(big.Int).ModInverse(gamma, ScalarField)
+	deltaInverse := new(big.Int).ModInverse(delta, ScalarField)
+	combined := func(i int) *big.Int {
+		k := new(big.Int).Mul(beta, u[i])
+		k.Add(k, new(big.Int).Mul(alpha, v[i]))
+		return mod(k.Add(k, w[i]))
+	}
+
+	pk := ProvingKey{
+		AlphaG1: new(bn256.G1).ScalarBaseMult(alpha),
+		BetaG1:  new(bn256.G1).ScalarBaseMult(beta),
+		BetaG2:  new(bn256.G2).ScalarBaseMult(beta),
+		DeltaG1: new(bn256.G1).ScalarBaseMult(delta),
+		DeltaG2: new(bn256.G2).ScalarBaseMult(delta),
+	}
+	vk := VerificationKey{
+		Alpha: pk.AlphaG1,
+		Beta:  pk.BetaG2,
+		Gamma: new(bn256.G2).ScalarBaseMult(gamma),
+		Delta: pk.DeltaG2,
+	}
+	for i := 0; i < r1cs.numVariables; i++ {
+		pk.A = append(pk.A, new(bn256.G1).ScalarBaseMult(u[i]))
+		pk.B1 = append(pk.B1, new(bn256.G1).ScalarBaseMult(v[i]))
+		pk.B2 = append(pk.B2, new(bn256.G2).ScalarBaseMult(v[i]))
+		k := combined(i)
+		if i <= r1cs.numPublic {
+			vk.GammaABC = append(vk.GammaABC, new(bn256.G1).ScalarBaseMult(mod(k.Mul(k, gammaInverse))))
+			continue
+		}
+		pk.K = append(pk.K, new(bn256.G1).ScalarBaseMult(mod(k.Mul(k, deltaInverse))))
+	}
+
+	h := mod(new(big.Int).Mul(domain.vanishingAt(tau), deltaInverse))
+	for i := 0; i < domain.size-1; i++ {
+		pk.H = append(pk.H, new(bn256.G1).ScalarBaseMult(h))
+		h = mod(h.Mul(h, tau))
+	}
+	return &pk, &vk, nil
+}
+
+// provingKeyJSON is the JSON format of a proving key, with points in the uncompressed encoding of bn256
+type provingKeyJSON struct {
+	Scheme  string          `json:"scheme"`
+	Curve   string          `json:"curve"`
+	AlphaG1 hexutil.Bytes   `json:"alpha_g1"`
+	BetaG1  hexutil.Bytes   `json:"beta_g1"`
+	BetaG2  hexutil.Bytes   `json:"beta_g2"`
+	DeltaG1 hexutil.Bytes   `json:"delta_g1"`
+	DeltaG2 hexutil.Bytes   `json:"delta_g2"`
+	A       []hexutil.Bytes `json:"a"`
+	B1      []hexutil.Bytes `json:"b1"`
+	B2      []hexutil.Bytes `json:"b2"`
+	K       []hexutil.Bytes `json:"k"`
+	H       []hexutil.Bytes `json:"h"`
+}
+
+// MarshalJSON encodes the proving key as JSON
+func (pk ProvingKey) MarshalJSON() ([]byte, error) {
+	raw := provingKeyJSON{
+		Scheme:  zokratesScheme,
+		Curve:   zokratesCurve,
+		AlphaG1: pk.AlphaG1.Marshal(),
+		BetaG1:  pk.BetaG1.Marshal(),
+		BetaG2:  pk.BetaG2.Marshal(),
+		DeltaG1: pk.DeltaG1.Marshal(),
+		DeltaG2: pk.DeltaG2.Marshal(),
+		A:       marshalG1s(pk.A),
+		B1:      marshalG1s(pk.B1),
+		B2:      make([]hexutil.Bytes, 0, len(pk.B2)),
+		K:       marshalG1s(pk.K),
+		H:       marshalG1s(pk.H),
+	}
+	for _, p := range pk.B2 {
+		raw.B2 = append(raw.B2, p.Marshal())
+	}
+	return json.Marshal(raw)
+}
+
+// ParseProvingKey parses a proving key written by ProvingKey.MarshalJSON
+func ParseProvingKey(data []byte) (*ProvingKey, error) {
+	var raw provingKeyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling proving key")
+	}
+	if raw.Scheme != zokratesScheme || raw.Curve != zokratesCurve {
+		return nil, errors.Errorf("unsupported proving key<%s/%s>, expected %s/%s", raw.Scheme, raw.Curve, zokratesScheme, zokratesCurve)
+	}
+
+	var (
+		pk  ProvingKey
+		err error
+	)
+	g1s := []struct {
+		name  string
+		data  hexutil.Bytes
+		point **bn256.G1
+	}{
+		{name: "alpha_g1", data: raw.AlphaG1, point: &pk.AlphaG1},
+		{name: "beta_g1", data: raw.BetaG1, point: &pk.BetaG1},
+		{name: "delta_g1", data: raw.DeltaG1, point: &pk.DeltaG1},
+	}
+	for _, g1 := range g1s {
+		if *g1.point, err = unmarshalG1(g1.data); err != nil {
+			return nil, errors.Wrap(err, g1.name)
+		}
+	}
+	if pk.BetaG2, err = unmarshalG2(raw.BetaG2); err != nil {
+		return nil, errors.Wrap(err, "beta_g2")
+	}
+	if pk.DeltaG2, err = unmarshalG2(raw.DeltaG2); err != nil {
+		return nil, errors.Wrap(err, "delta_g2")
+	}
+	if pk.A, err = unmarshalG1s(raw.A); err != nil {
+		return nil, errors.Wrap(err, "a")
+	}
+	if pk.B1, err = unmarshalG1s(raw.B1); err != nil {
+		return nil, errors.Wrap(err, "b1")
+	}
+	for i, data := range raw.B2 {
+		point, err := unmarshalG2(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "b2[%d]", i)
+		}
+		pk.B2 = append(pk.B2, point)
+	}
+	if pk.K, err = unmarshalG1s(raw.K); err != nil {
+		return nil, errors.Wrap(err, "k")
+	}
+	if pk.H, err = unmarshalG1s(raw.H); err != nil {
+		return nil, errors.Wrap(err, "h")
+	}
+	if len(pk.A) != len(pk.B1) || len(pk.A) != len(pk.B2) {
+		return nil, errors.New("proving key has an inconsistent number of variables")
+	}
+	return &pk, nil
+}
+
+// domain is the set of evaluation points 1..size of a constraint system, one per constraint
+type domain struct {
+	size int
+	// denominators holds prod_{k != j} (x_j - x_k) of each point x_j
+	denominators []*big.Int
+}
+
+func newDomain(size int) domain {
+	factorials := make([]*big.Int, size+1)
+	factorials[0] = big.NewInt(1)
+	for i := 1; i <= size; i++ {
+		factorials[i] = mod(new(big.Int).Mul(factorials[i-1], big.NewInt(int64(i))))
+	}
+	d := domain{size: size, denominators: make([]*big.Int, size)}
+	for j := 1; j <= size; j++ {
+		// (j-1)! * (-1)^(size-j) * (size-j)!
+		denominator := new(big.Int).Mul(factorials[j-1], factorials[size-j])
+		if (size-j)%2 == 1 {
+			denominator.Neg(denominator)
+		}
+		d.denominators[j-1] = mod(denominator)
+	}
+	return d
+}
+
+func (d domain) point(j int) *big.Int {
+	return big.NewInt(int64(j + 1))
+}
+
+// vanishingAt evaluates t(x) = prod (x - x_j) at x
+func (d domain) vanishingAt(x *big.Int) *big.Int {
+	t := big.NewInt(1)
+	for j := 0; j < d.size; j++ {
+		t = mod(t.Mul(t, new(big.Int).Sub(x, d.point(j))))
+	}
+	return t
+}
+
+// lagrangeAt evaluates each lagrange basis polynomial L_j(x) = t(x) / ((x - x_j) * denominator_j) at x
+func (d domain) lagrangeAt(x *big.Int) ([]*big.Int, error) {
+	t := d.vanishingAt(x)
+	if t.Sign() == 0 {
+		return nil, errors.New("evaluation point is in the domain")
+	}
+	out := make([]*big.Int, d.size)
+	for j := range out {
+		denominator := new(big.Int).Sub(x, d.point(j))
+		denominator = mod(denominator.Mul(denominator, d.denominators[j]))
+		out[j] = mod(new(big.Int).Mul(t, new(big.Int).ModInverse(denominator, ScalarField)))
+	}
+	return out, nil
+}
+
+// accumulate adds the terms of a constraint scaled by the lagrange basis of the constraint to the polynomial
+// evaluations of each variable
+func accumulate(evaluations []*big.Int, terms []wireTerm, basis *big.Int) {
+	for _, t := range terms {
+		evaluations[t.wire] = mod(evaluations[t.wire].Add(evaluations[t.wire], new(big.Int).Mul(t.coefficient, basis)))
+	}
+}
+
+func randomNonZero(random io.Reader) (*big.Int, error) {
+	for {
+		k, err := rand.Int(random, ScalarField)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+func marshalG1s(points []*bn256.G1) []hexutil.Bytes {
+	out := make([]hexutil.Bytes, 0, len(points))
+	for _, p := range points {
+		out = append(out, p.Marshal())
+	}
+	return out
+}
+
+func unmarshalG1s(data []hexutil.Bytes) ([]*bn256.G1, error) {
+	out := make([]*bn256.G1, 0, len(data))
+	for i, d := range data {
+		point, err := unmarshalG1(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[%d]", i)
+		}
+		out = append(out, point)
+	}
+	return out, nil
+}
+
+func unmarshalG1(data []byte) (*bn256.G1, error) {
+	point := new(bn256.G1)
+	if _, err := point.Unmarshal(data); err != nil {
+		return nil, errors.Wrap(ErrInvalidPoint, err.Error())
+	}
+	return point, nil
+}
+
+func unmarshalG2(data []byte) (*bn256.G2, error) {
+	point := new(bn256.G2)
+	if _, err := point.Unmarshal(data); err != nil {
+		return nil, errors.Wrap(ErrInvalidPoint, err.Error())
+	}
+	return point, nil
+}
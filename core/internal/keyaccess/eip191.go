@@ -0,0 +1,100 @@
+package keyaccess
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/pkg/errors"
+)
+
+// EIP191 is the algorithm of tokens signed by a blockchain account as an EIP-191 personal message, which remote signers
+// such as clef sign besides transactions. The message is the signing input of the token and the signature is the 65
+// byte r || s || v of the account, so the account is recovered from the signature.
+const EIP191 jwa.SignatureAlgorithm = "ES256K-EIP191"
+
+// TextSigner signs EIP-191 personal messages with the key of a blockchain account
+type TextSigner interface {
+	SignText(ctx context.Context, text []byte) ([]byte, error)
+}
+
+// EIP191Key is the key tokens are signed with under EIP191. Tokens are verified with the address of the account.
+type EIP191Key struct {
+	// Context of the request the token is signed for, which bounds the call to a remote signer
+	Context context.Context
+	Signer  TextSigner
+}
+
+func init() {
+	jws.RegisterSigner(EIP191, jws.SignerFactoryFn(func() (jws.Signer, error) {
+		return eip191Signer{}, nil
+	}))
+	jws.RegisterVerifier(EIP191, jws.VerifierFactoryFn(func() (jws.Verifier, error) {
+		return eip191Verifier{}, nil
+	}))
+}
+
+type eip191Signer struct{}
+
+func (eip191Signer) Algorithm() jwa.SignatureAlgorithm {
+	return EIP191
+}
+
+func (eip191Signer) Sign(payload []byte, key any) ([]byte, error) {
+	signingKey, ok := key.(EIP191Key)
+	if !ok || signingKey.Signer == nil {
+		return nil, errors.Errorf("%s tokens are signed with an EIP191Key, got %T", EIP191, key)
+	}
+	ctx := signingKey.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	signature, err := signingKey.Signer.SignText(ctx, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing personal message")
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, errors.Errorf("invalid signature length: %d", len(signature))
+	}
+	return signature, nil
+}
+
+type eip191Verifier struct{}
+
+func (eip191Verifier) Verify(payload []byte, signature []byte, key any) error {
+	account, ok := key.(common.Address)
+	if !ok {
+		return errors.Errorf("%s tokens are verified with the address of an account, got %T", EIP191, key)
+	}
+	signer, err := RecoverEIP191(payload, signature)
+	if err != nil {
+		return err
+	}
+	if signer != account {
+		return errors.Errorf("message is not signed by the account %s", account)
+	}
+	return nil
+}
+
+// RecoverEIP191 recovers the account that signed a message as an EIP-191 personal message. The recovery id of the
+// signature may be either 0/1 or 27/28, as signers differ in which they return.
+func RecoverEIP191(message, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, errors.Errorf("invalid signature length: %d", len(signature))
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "recovering signer of personal message")
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
@@ -0,0 +1,60 @@
+package keyaccess
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// textSigner signs personal messages like clef does, with a recovery id of 27 or 28
+type textSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s textSigner) SignText(_ context.Context, text []byte) ([]byte, error) {
+	signature, err := crypto.Sign(accounts.TextHash(text), s.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+func TestEIP191(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	account := crypto.PubkeyToAddress(key.PublicKey)
+
+	signed, err := jws.Sign([]byte("payload"), jws.WithKey(EIP191, EIP191Key{Context: context.Background(), Signer: textSigner{key: key}}))
+	require.NoError(t, err)
+
+	payload, err := jws.Verify(signed, jws.WithKey(EIP191, account))
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(payload))
+
+	t.Run("rejects the signature of another account", func(tt *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(tt, err)
+		_, err = jws.Verify(signed, jws.WithKey(EIP191, crypto.PubkeyToAddress(other.PublicKey)))
+		assert.Error(tt, err)
+	})
+
+	t.Run("recovers signatures with a recovery id of 0 or 1", func(tt *testing.T) {
+		signature, err := crypto.Sign(accounts.TextHash([]byte("message")), key)
+		require.NoError(tt, err)
+		signer, err := RecoverEIP191([]byte("message"), signature)
+		require.NoError(tt, err)
+		assert.Equal(tt, account, signer)
+	})
+
+	t.Run("rejects a key without a signer", func(tt *testing.T) {
+		_, err := jws.Sign([]byte("payload"), jws.WithKey(EIP191, key))
+		assert.ErrorContains(tt, err, "EIP191Key")
+	})
+}
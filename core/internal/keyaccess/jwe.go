@@ -0,0 +1,361 @@
+package keyaccess
+
+import (
+	"bytes"
+	gocrypto "crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/pkg/errors"
+)
+
+// JWEKeyAlgorithm is the key management algorithm used for all JWEs, i.e. an ephemeral-static key agreement whose
+// output wraps the content encryption key.
+const JWEKeyAlgorithm = jwa.ECDH_ES_A256KW
+
+// secp256k1Curve is the jwk curve name of secp256k1, which jwx only defines when built with the jwx_es256k tag
+const secp256k1Curve jwa.EllipticCurveAlgorithm = "secp256k1"
+
+// EncryptJWE encrypts a payload to the key agreement key of a single recipient, which is referenced by the kid header.
+// Supported keys are P-256, X25519 and secp256k1 public keys.
+func EncryptJWE(payload []byte, kid string, key gocrypto.PublicKey) ([]byte, error) {
+	if kid == "" {
+		return nil, errors.New("kid cannot be empty")
+	}
+	headers := jwe.NewHeaders()
+	if err := headers.Set(jwe.KeyIDKey, kid); err != nil {
+		return nil, errors.Wrap(err, "setting kid header")
+	}
+
+	var recipientKey any
+	if secpKey, ok := toSECP256k1PublicKey(key); ok {
+		// jwx only supports secp256k1 keys when built with the jwx_es256k tag, so the key agreement is done here
+		encrypter, err := newSECP256k1KeyEncrypter(secpKey)
+		if err != nil {
+			return nil, err
+		}
+		if err = headers.Set(jwe.EphemeralPublicKeyKey, encrypter.epk); err != nil {
+			return nil, errors.Wrap(err, "setting epk header")
+		}
+		recipientKey = encrypter
+	} else {
+		agreementKey, err := toAgreementPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		recipientKey = agreementKey
+	}
+
+	encrypted, err := jwe.Encrypt(payload, jwe.WithKey(JWEKeyAlgorithm, recipientKey, jwe.WithPerRecipientHeaders(headers)), jwe.WithContentEncryption(jwa.A256GCM))
+	if err != nil {
+		return nil, errors.Wrapf(err, "encrypting jwe for kid: %s", kid)
+	}
+	return encrypted, nil
+}
+
+// DecryptJWE decrypts a JWE with the private key of the recipient. Supported keys are P-256, X25519 and secp256k1
+// private keys.
+func DecryptJWE(message []byte, key gocrypto.PrivateKey) ([]byte, error) {
+	if secpKey, ok := toSECP256k1PrivateKey(key); ok {
+		payload, err := decryptSECP256k1JWE(message, secpKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypting jwe")
+		}
+		return payload, nil
+	}
+
+	agreementKey, err := toAgreementPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := jwe.Decrypt(message, jwe.WithKey(JWEKeyAlgorithm, agreementKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting jwe")
+	}
+	return payload, nil
+}
+
+// GetJWERecipients returns the kid headers of all recipients of a JWE
+func GetJWERecipients(message []byte) ([]string, error) {
+	parsed, err := jwe.Parse(message)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing jwe")
+	}
+
+	var kids []string
+	for _, recipient := range parsed.Recipients() {
+		// compact serializations carry the header of their only recipient in the protected header
+		kid := recipient.Headers().KeyID()
+		if kid == "" {
+			kid = parsed.ProtectedHeaders().KeyID()
+		}
+		if kid != "" {
+			kids = append(kids, kid)
+		}
+	}
+	return kids, nil
+}
+
+func toAgreementPublicKey(key gocrypto.PublicKey) (any, error) {
+	switch k := key.(type) {
+	case ecdsa.PublicKey:
+		return toAgreementPublicKey(&k)
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.Errorf("unsupported curve for key agreement: %s", k.Curve.Params().Name)
+		}
+		return k, nil
+	case x25519.PublicKey:
+		return k, nil
+	case *ecdh.PublicKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, errors.Errorf("unsupported curve for key agreement: %s", k.Curve())
+		}
+		return x25519.PublicKey(k.Bytes()), nil
+	}
+	return nil, errors.Errorf("unsupported key type for key agreement: %T", key)
+}
+
+func toAgreementPrivateKey(key gocrypto.PrivateKey) (any, error) {
+	switch k := key.(type) {
+	case ecdsa.PrivateKey:
+		return toAgreementPrivateKey(&k)
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.Errorf("unsupported curve for key agreement: %s", k.Curve.Params().Name)
+		}
+		return k, nil
+	case x25519.PrivateKey:
+		return k, nil
+	case *ecdh.PrivateKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, errors.Errorf("unsupported curve for key agreement: %s", k.Curve())
+		}
+		return x25519.NewKeyFromSeed(k.Bytes())
+	}
+	return nil, errors.Errorf("unsupported key type for key agreement: %T", key)
+}
+
+func toSECP256k1PublicKey(key gocrypto.PublicKey) (*secp.PublicKey, bool) {
+	switch k := key.(type) {
+	case secp.PublicKey:
+		return &k, true
+	case *secp.PublicKey:
+		return k, true
+	case ecdsa.PublicKey:
+		return toSECP256k1PublicKey(&k)
+	case *ecdsa.PublicKey:
+		if !isSECP256k1(k.Curve) {
+			return nil, false
+		}
+		parsed, err := secp.ParsePubKey(elliptic.Marshal(k.Curve, k.X, k.Y))
+		return parsed, err == nil
+	}
+	return nil, false
+}
+
+func toSECP256k1PrivateKey(key gocrypto.PrivateKey) (*secp.PrivateKey, bool) {
+	switch k := key.(type) {
+	case secp.PrivateKey:
+		return &k, true
+	case *secp.PrivateKey:
+		return k, true
+	case ecdsa.PrivateKey:
+		return toSECP256k1PrivateKey(&k)
+	case *ecdsa.PrivateKey:
+		if !isSECP256k1(k.Curve) {
+			return nil, false
+		}
+		return secp.PrivKeyFromBytes(k.D.FillBytes(make([]byte, 32))), true
+	}
+	return nil, false
+}
+
+// isSECP256k1 matches the curve by its parameters, since the curve has several implementations
+func isSECP256k1(curve elliptic.Curve) bool {
+	params := curve.Params()
+	return params != nil && params.P.Cmp(secp.S256().P) == 0 && params.N.Cmp(secp.S256().N) == 0
+}
+
+// secp256k1KeyEncrypter wraps the content encryption key with a key agreed between an ephemeral and the recipient key
+type secp256k1KeyEncrypter struct {
+	kek []byte
+	epk jwk.Key
+}
+
+func newSECP256k1KeyEncrypter(recipient *secp.PublicKey) (*secp256k1KeyEncrypter, error) {
+	ephemeral, err := secp.GeneratePrivateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "generating ephemeral key")
+	}
+
+	// the jwk is parsed from its fields, since jwx cannot convert secp256k1 keys on its own
+	pub := ephemeral.PubKey().SerializeUncompressed()
+	epk, err := jwk.ParseKey([]byte(fmt.Sprintf(`{"kty":"EC","crv":"%s","x":"%s","y":"%s"}`,
+		secp256k1Curve, base64.RawURLEncoding.EncodeToString(pub[1:33]), base64.RawURLEncoding.EncodeToString(pub[33:]))))
+	if err != nil {
+		return nil, errors.Wrap(err, "building ephemeral jwk")
+	}
+
+	return &secp256k1KeyEncrypter{
+		kek: deriveKEK(secp.GenerateSharedSecret(ephemeral, recipient), nil, nil),
+		epk: epk,
+	}, nil
+}
+
+func (e *secp256k1KeyEncrypter) Algorithm() jwa.KeyEncryptionAlgorithm {
+	return JWEKeyAlgorithm
+}
+
+func (e *secp256k1KeyEncrypter) EncryptKey(cek []byte) ([]byte, error) {
+	return wrapKey(e.kek, cek)
+}
+
+// decryptSECP256k1JWE decrypts a compact JWE with a secp256k1 key. The whole message is decrypted here, since jwx
+// cannot read a secp256k1 ephemeral key without the jwx_es256k tag either.
+func decryptSECP256k1JWE(message []byte, key *secp.PrivateKey) ([]byte, error) {
+	parts := bytes.Split(message, []byte("."))
+	if len(parts) != 5 {
+		return nil, errors.New("only compact serialized jwes are supported for secp256k1 keys")
+	}
+	parsed, err := jwe.Parse(message)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing jwe")
+	}
+
+	headers := parsed.ProtectedHeaders()
+	if alg := headers.Algorithm(); alg != JWEKeyAlgorithm {
+		return nil, errors.Errorf("unsupported key management algorithm: %s", alg)
+	}
+	if enc := headers.ContentEncryption(); enc != jwa.A256GCM {
+		return nil, errors.Errorf("unsupported content encryption algorithm: %s", enc)
+	}
+	if headers.Compression() != jwa.NoCompress {
+		return nil, errors.Errorf("unsupported compression: %s", headers.Compression())
+	}
+	epk, ok := headers.EphemeralPublicKey().(jwk.ECDSAPublicKey)
+	if !ok || epk.Crv() != secp256k1Curve {
+		return nil, errors.New("missing ephemeral secp256k1 key in jwe header")
+	}
+	pub, err := secp.ParsePubKey(append([]byte{0x04}, append(padKey(epk.X()), padKey(epk.Y())...)...))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing ephemeral key")
+	}
+
+	kek := deriveKEK(secp.GenerateSharedSecret(key, pub), headers.AgreementPartyUInfo(), headers.AgreementPartyVInfo())
+	cek, err := unwrapKey(kek, parsed.Recipients()[0].EncryptedKey())
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating content cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating content cipher")
+	}
+	// the additional authenticated data is the encoded protected header
+	payload, err := gcm.Open(nil, parsed.InitializationVector(), append(parsed.CipherText(), parsed.Tag()...), parts[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting content")
+	}
+	return payload, nil
+}
+
+func padKey(coordinate []byte) []byte {
+	if len(coordinate) >= 32 {
+		return coordinate
+	}
+	return append(make([]byte, 32-len(coordinate)), coordinate...)
+}
+
+// deriveKEK derives the key encryption key from a shared secret with the Concat KDF, see RFC 7518 section 4.6.2
+func deriveKEK(z, apu, apv []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0, 0, 0, 1})
+	h.Write(z)
+	for _, info := range [][]byte{[]byte(JWEKeyAlgorithm), apu, apv} {
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(info))))
+		h.Write(info)
+	}
+	// the length of the derived key in bits
+	h.Write(binary.BigEndian.AppendUint32(nil, 256))
+	return h.Sum(nil)
+}
+
+var keyWrapIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// wrapKey wraps a key with AES Key Wrap, see RFC 3394
+func wrapKey(kek, key []byte) ([]byte, error) {
+	if len(key)%8 != 0 || len(key) < 16 {
+		return nil, errors.Errorf("invalid key length to wrap: %d", len(key))
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating key wrap cipher")
+	}
+
+	n := len(key) / 8
+	wrapped := make([]byte, 8+len(key))
+	copy(wrapped, keyWrapIV)
+	copy(wrapped[8:], key)
+
+	buf := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buf, wrapped[:8])
+			copy(buf[8:], wrapped[i*8:i*8+8])
+			block.Encrypt(buf, buf)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(wrapped[:8], binary.BigEndian.Uint64(buf[:8])^t)
+			copy(wrapped[i*8:], buf[8:])
+		}
+	}
+	return wrapped, nil
+}
+
+// unwrapKey unwraps a key wrapped with AES Key Wrap, see RFC 3394
+func unwrapKey(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, errors.Errorf("invalid wrapped key length: %d", len(wrapped))
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating key wrap cipher")
+	}
+
+	n := len(wrapped)/8 - 1
+	key := make([]byte, len(wrapped))
+	copy(key, wrapped)
+
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(key[:8])^t)
+			copy(buf[8:], key[i*8:i*8+8])
+			block.Decrypt(buf, buf)
+			copy(key[:8], buf[:8])
+			copy(key[i*8:], buf[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(key[:8], keyWrapIV) != 1 {
+		return nil, errors.New("failed to unwrap key: integrity check failed")
+	}
+	return key[8:], nil
+}
@@ -0,0 +1,66 @@
+package keyaccess
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWEForEachKeyType(t *testing.T) {
+	testKID := "did:key:test#key-1"
+	testPayload := []byte("test-payload")
+
+	for _, kt := range []crypto.KeyType{crypto.P256, crypto.X25519, crypto.SECP256k1, crypto.SECP256k1ECDSA} {
+		t.Run(string(kt), func(t *testing.T) {
+			pubKey, privKey, err := crypto.GenerateKeyByKeyType(kt)
+			require.NoError(t, err)
+
+			encrypted, err := EncryptJWE(testPayload, testKID, pubKey)
+			require.NoError(t, err)
+
+			recipients, err := GetJWERecipients(encrypted)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{testKID}, recipients)
+
+			decrypted, err := DecryptJWE(encrypted, privKey)
+			assert.NoError(t, err)
+			assert.Equal(t, testPayload, decrypted)
+
+			// a different key of the same type cannot decrypt
+			_, otherKey, err := crypto.GenerateKeyByKeyType(kt)
+			require.NoError(t, err)
+			_, err = DecryptJWE(encrypted, otherKey)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		pubKey, _, err := crypto.GenerateKeyByKeyType(crypto.Ed25519)
+		require.NoError(t, err)
+
+		_, err = EncryptJWE(testPayload, testKID, pubKey)
+		assert.ErrorContains(t, err, "unsupported key type for key agreement")
+	})
+}
+
+func TestKeyWrap(t *testing.T) {
+	// test vector of RFC 3394 section 4.6, wrapping 256 bits of key data with a 256-bit kek
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F")
+	expected, _ := hex.DecodeString("28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21")
+
+	wrapped, err := wrapKey(kek, key)
+	require.NoError(t, err)
+	assert.Equal(t, expected, wrapped)
+
+	unwrapped, err := unwrapKey(kek, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, key, unwrapped)
+
+	wrapped[0] ^= 1
+	_, err = unwrapKey(kek, wrapped)
+	assert.ErrorContains(t, err, "integrity check failed")
+}
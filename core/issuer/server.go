@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/TBD54566975/ssi-sdk/schema"
-	"github.com/ethereum/go-ethereum/ethclient"
 	configpkg "github.com/fapiper/onchain-access-control/core/config"
 	"github.com/fapiper/onchain-access-control/core/env"
 	"github.com/fapiper/onchain-access-control/core/log"
@@ -13,7 +12,6 @@ import (
 	"github.com/fapiper/onchain-access-control/core/service/rpc"
 	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
 	"github.com/gin-gonic/gin"
-	shell "github.com/ipfs/go-ipfs-api"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
@@ -53,16 +51,21 @@ func Init() *gin.Engine {
 
 type Clients struct {
 	HTTPClient *http.Client
-	EthClient  *ethclient.Client
-	IPFSClient *shell.Shell
+	EthClient  rpc.Backend
+	Artifacts  ipfs.Store
 }
 
 func ClientInit(ctx context.Context) *Clients {
-	return &Clients{
+	clients := Clients{
 		HTTPClient: &http.Client{Timeout: 0},
-		EthClient:  rpc.NewEthClient(),
-		IPFSClient: ipfs.NewShell(),
+		EthClient:  rpc.NewBackend(),
 	}
+	if artifacts, err := ipfs.NewStore(); err != nil {
+		logrus.WithError(err).Error("could not create the artifact store")
+	} else {
+		clients.Artifacts = artifacts
+	}
+	return &clients
 }
 
 // CoreInit initializes core server functionality. This is abstracted
@@ -108,11 +111,35 @@ func SetDefaults() {
 	viper.SetDefault("ENV", "dev")
 	viper.SetDefault("PORT", 4002)
 	viper.SetDefault("IPFS_URL", "https://cloudflare-ipfs.com/ipfs")
+	viper.SetDefault("IPFS_STORE_DIR", "")
+	viper.SetDefault("FALLBACK_IPFS_URL", "")
+	viper.SetDefault("IPFS_GATEWAYS", "https://cloudflare-ipfs.com,https://ipfs.io,https://gateway.pinata.cloud,https://nftstorage.link")
+	viper.SetDefault("IPFS_GATEWAY_HEDGE_DELAY", "500ms")
+	viper.SetDefault("IPFS_GATEWAY_TIMEOUT", "1m")
 	viper.SetDefault("REDIS_URL", "localhost:6379")
 	viper.SetDefault("RPC_URL", "https://eth-sepolia.g.alchemy.com/v2/demo")
+	viper.SetDefault("RPC_MAX_ATTEMPTS", 4)
+	viper.SetDefault("RPC_INITIAL_BACKOFF", "250ms")
+	viper.SetDefault("RPC_MAX_BACKOFF", "30s")
 	viper.SetDefault("PRIVATE_KEY", "")
+	viper.SetDefault("WALLET_SIGNER", "key")
+	viper.SetDefault("WALLET_KEY_ID", "")
+	viper.SetDefault("WALLET_KEY_FILE", "")
+	viper.SetDefault("WALLET_KEY_PASSWORD_FILE", "")
+	viper.SetDefault("WALLET_SIGNER_URL", "")
+	viper.SetDefault("WALLET_ADDRESS", "")
+	viper.SetDefault("OWNER_DID", "")
+	viper.SetDefault("TX_RESUBMIT_AFTER", "1m")
+	viper.SetDefault("TX_GAS_BUMP_PERCENT", 15)
+	viper.SetDefault("TX_FEE_MODE", "dynamic")
+	viper.SetDefault("TX_GAS_LIMIT_MARGIN", 20)
+	viper.SetDefault("TX_MAX_FEE_PER_GAS", "")
+	viper.SetDefault("TX_MAX_PRIORITY_FEE_PER_GAS", "")
+	viper.SetDefault("TX_FEE_HISTORY_BLOCKS", 10)
+	viper.SetDefault("TX_PRIORITY_FEE_PERCENTILE", 50)
 	viper.SetDefault("CONTEXT_HANDLER_CONTRACT", "")
 	viper.SetDefault("SESSION_REGISTRY_CONTRACT", "")
+	viper.SetDefault("DID_REGISTRY_CONTRACT", "")
 	viper.SetDefault("INFURA_API_KEY", "")
 	viper.SetDefault("INFURA_API_SECRET", "")
 	viper.SetDefault("KEYSTORE_PASSWORD", "default-keystore-password")
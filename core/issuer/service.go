@@ -92,7 +92,7 @@ func servicesInitUnsafe(c *Clients, config configpkg.ServicesConfig) (*Service,
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate batch DID service")
 	}
 
-	didService, err := did.NewDIDService(config.DIDConfig, storageProvider, keyStoreService, keyStoreServiceFactory)
+	didService, err := did.NewDIDService(config.DIDConfig, storageProvider, keyStoreService, keyStoreServiceFactory, nil, nil)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the DID service")
 	}
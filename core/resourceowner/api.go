@@ -13,8 +13,10 @@ import (
 const (
 	OperationPrefix         = "/operations"
 	AccessPrefix            = "/access"
+	IndexPrefix             = "/index"
 	DIDsPrefix              = "/dids"
 	ResolverPrefix          = "/resolver"
+	ControllersPrefix       = "/controllers"
 	CredentialsPrefix       = "/credentials"
 	StatusPrefix            = "/status"
 	PresentationsPrefix     = "/presentations"
@@ -63,6 +65,10 @@ func DecentralizedIdentityAPI(rg *gin.RouterGroup, service *didsvc.Service, did
 	didAPI.GET("/:method/:id", didRouter.GetDIDByMethod)
 	didAPI.DELETE("/:method/:id", didRouter.SoftDeleteDIDByMethod)
 	didAPI.GET(ResolverPrefix+"/:id", didRouter.ResolveDID)
+	didAPI.GET(ControllersPrefix+"/:id", didRouter.GetDIDControllers)
+	didAPI.PUT(ControllersPrefix+"/:id", didRouter.AddDIDController)
+	didAPI.PUT(ControllersPrefix+"/:id/current", didRouter.ChangeDIDController)
+	didAPI.DELETE(ControllersPrefix+"/:id/:controller", didRouter.RemoveDIDController)
 	return
 }
 
@@ -163,5 +169,34 @@ func AccessControlAPI(rg *gin.RouterGroup, service svcframework.Service) (err er
 	accessAPI := rg.Group(AccessPrefix)
 	accessAPI.PUT("/context", accessRouter.CreateAccessContext)
 	accessAPI.PUT("/resource", accessRouter.RegisterResource)
+	accessAPI.DELETE("/role/:id", accessRouter.RevokeRole)
+	accessAPI.PUT("/role/:id/policy", accessRouter.AssignPolicy)
+	accessAPI.PUT("/role/:id/permission/:permission", accessRouter.AssignPermission)
+	accessAPI.DELETE("/role/:id/permission/:permission", accessRouter.UnassignPermission)
+	accessAPI.PUT("/permission", accessRouter.RegisterPermission)
+	accessAPI.DELETE("/session/:id", accessRouter.RevokeSession)
+	accessAPI.PUT("/policy", accessRouter.CreatePolicy)
+	accessAPI.GET("/policy", accessRouter.ListPolicies)
+	accessAPI.GET("/policy/:id", accessRouter.GetPolicy)
+	accessAPI.PUT("/policy/register", accessRouter.RegisterPolicy)
+	return
+}
+
+// IndexerAPI registers all HTTP handlers for the Indexer Service
+func IndexerAPI(rg *gin.RouterGroup, service svcframework.Service) (err error) {
+	indexerRouter, err := router.NewIndexerRouter(service)
+	if err != nil {
+		return sdkutil.LoggingErrorMsg(err, "creating indexer router")
+	}
+
+	// make sure the indexer service is configured to use the correct path
+	config.SetServicePath(svcframework.Indexer, IndexPrefix)
+	indexAPI := rg.Group(IndexPrefix)
+	indexAPI.GET("/checkpoint", indexerRouter.GetCheckpoint)
+	indexAPI.GET("/contexts", indexerRouter.ListContexts)
+	indexAPI.GET("/contexts/:id", indexerRouter.GetContext)
+	indexAPI.GET("/contexts/:id/roles", indexerRouter.ListRoleHolders)
+	indexAPI.GET("/sessions", indexerRouter.ListSessions)
+	indexAPI.GET("/dids/:id", indexerRouter.GetDIDController)
 	return
 }
@@ -44,6 +44,9 @@ func apiInit(ctx context.Context, config configpkg.OACServiceConfig, instance *S
 	if err := AccessControlAPI(v1, instance.AccessControl); err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate Access API")
 	}
+	if err := IndexerAPI(v1, instance.Indexer); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate Indexer API")
+	}
 	if err := KeyStoreAPI(v1, instance.KeyStore); err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate KeyStore API")
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/fapiper/onchain-access-control/core/service/accesscontrol"
+	"github.com/fapiper/onchain-access-control/core/service/indexer"
 	"github.com/fapiper/onchain-access-control/core/service/rpc"
 	"github.com/pkg/errors"
 
@@ -25,11 +26,14 @@ type Service struct {
 	KeyStore         *keystore.Service
 	AccessControl    *accesscontrol.Service
 	RPC              *rpc.Service
+	Indexer          *indexer.Service
+	Indexers         []*indexer.Service
 	DID              *did.Service
 	Schema           *schema.Service
 	Credential       *credential.Service
 	Presentation     *presentation.Service
 	Operation        *operation.Service
+	Trackers         *operation.Trackers
 	storage          storage.ServiceStorage
 	BatchDID         *did.BatchService
 	DIDConfiguration *wellknown.DIDConfigurationService
@@ -41,7 +45,30 @@ func ServicesInit(ctx context.Context, clients *Clients, config configpkg.Servic
 	if err := validateServiceConfig(config); err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the oac service, invalid config")
 	}
-	return servicesInitUnsafe(clients, config)
+	instance, err := servicesInitUnsafe(clients, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// sessions stored by earlier versions are moved before the sweeper and the pickup look for them
+	if err = instance.AccessControl.MigrateSessions(ctx); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not migrate stored sessions")
+	}
+
+	// expire sessions that outlived their lifetime in the background
+	go instance.AccessControl.RunSessionSweeper(ctx)
+	// follow the contract logs of every chain to keep the read model of contexts, roles and sessions up to date
+	for _, indexerService := range instance.Indexers {
+		go indexerService.Run(ctx)
+	}
+	// decrypt and store the sessions users started for resources of this instance
+	go instance.AccessControl.RunSessionPickup(ctx)
+	// complete the operations of sent transactions once they are confirmed
+	go instance.Trackers.Run(ctx)
+	// follow the chain heads to invalidate cached reads of contexts, roles and sessions
+	go instance.RPC.RunCache(ctx)
+
+	return instance, nil
 }
 
 func validateServiceConfig(config configpkg.ServicesConfig) error {
@@ -92,7 +119,17 @@ func servicesInitUnsafe(c *Clients, config configpkg.ServicesConfig) (*Service,
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate batch DID service")
 	}
 
-	didService, err := did.NewDIDService(config.DIDConfig, storageProvider, keyStoreService, keyStoreServiceFactory)
+	rpcService, err := rpc.NewRPCService(c.EthClient, keyStoreService)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the rpc service")
+	}
+
+	trackers, err := operation.NewTrackers(config.OperationConfig, storageProvider, rpcService)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the transaction trackers")
+	}
+
+	didService, err := did.NewDIDService(config.DIDConfig, storageProvider, keyStoreService, keyStoreServiceFactory, rpcService, trackers)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the DID service")
 	}
@@ -123,12 +160,12 @@ func servicesInitUnsafe(c *Clients, config configpkg.ServicesConfig) (*Service,
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the did configuration service")
 	}
 
-	rpcService, err := rpc.NewRPCService()
+	indexerServices, err := indexer.NewIndexerServices(config.IndexerConfig, storageProvider, rpcService)
 	if err != nil {
-		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the rpc service")
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the indexer services")
 	}
 
-	accessControlServiceFactory := accesscontrol.NewAccessControlServiceFactory(storageProvider, presentationService, didResolver, keyStoreService, keyEncrypter, keyDecrypter, rpcService, c.IPFSClient)
+	accessControlServiceFactory := accesscontrol.NewAccessControlServiceFactory(config.AuthConfig, storageProvider, presentationService, didResolver, keyStoreService, indexerServices, trackers, keyEncrypter, keyDecrypter, rpcService, c.Artifacts)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the access control service factory")
 	}
@@ -137,17 +174,22 @@ func servicesInitUnsafe(c *Clients, config configpkg.ServicesConfig) (*Service,
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate access control service")
 	}
+	accessControlService.RegisterFollowUps()
 
 	return &Service{
-		KeyStore:         keyStoreService,
-		DID:              didService,
-		BatchDID:         batchDIDService,
-		Schema:           schemaService,
-		Credential:       credentialService,
-		Presentation:     presentationService,
-		Operation:        operationService,
-		AccessControl:    accessControlService,
-		RPC:              rpcService,
+		KeyStore:      keyStoreService,
+		DID:           didService,
+		BatchDID:      batchDIDService,
+		Schema:        schemaService,
+		Credential:    credentialService,
+		Presentation:  presentationService,
+		Operation:     operationService,
+		Trackers:      trackers,
+		AccessControl: accessControlService,
+		RPC:           rpcService,
+		// the indexer api serves the indexer of the default chain
+		Indexer:          indexerServices[0],
+		Indexers:         indexerServices,
 		DIDConfiguration: didConfigurationService,
 		storage:          storageProvider,
 	}, nil
@@ -155,7 +197,7 @@ func servicesInitUnsafe(c *Clients, config configpkg.ServicesConfig) (*Service,
 
 // GetServices returns all services
 func (s *Service) GetServices() []frameworksvc.Service {
-	return []frameworksvc.Service{
+	services := []frameworksvc.Service{
 		s.KeyStore,
 		s.DID,
 		s.Schema,
@@ -163,7 +205,13 @@ func (s *Service) GetServices() []frameworksvc.Service {
 		s.Presentation,
 		s.Operation,
 		s.AccessControl,
+		s.Indexer,
+	}
+	// the rpc service reports the readiness of each of its chains
+	for _, chain := range s.RPC.Chains() {
+		services = append(services, chain)
 	}
+	return services
 }
 
 func (s *Service) GetStorage() storage.ServiceStorage {
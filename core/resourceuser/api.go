@@ -17,6 +17,7 @@ const (
 	AccessPrefix            = "/access"
 	DIDsPrefix              = "/dids"
 	ResolverPrefix          = "/resolver"
+	ControllersPrefix       = "/controllers"
 	SchemasPrefix           = "/schemas"
 	CredentialsPrefix       = "/credentials"
 	StatusPrefix            = "/status"
@@ -59,8 +60,9 @@ func AuthAPI(rg *gin.RouterGroup, service svcframework.Service) (err error) {
 	}
 	api := rg.Group(AuthPrefix)
 	api.PUT("/role/:id", r.GrantRole)
-	// api.DELETE("/role/:id", r.RevokeRole)
+	api.DELETE("/role/:id", r.RevokeRole)
 	api.PUT("/session", r.StartSession)
+	api.DELETE("/session/:id", r.RevokeSession)
 
 	return
 }
@@ -84,6 +86,10 @@ func DecentralizedIdentityAPI(rg *gin.RouterGroup, service *didsvc.Service, did
 	didAPI.GET("/:method/:id", didRouter.GetDIDByMethod)
 	didAPI.DELETE("/:method/:id", didRouter.SoftDeleteDIDByMethod)
 	didAPI.GET(ResolverPrefix+"/:id", didRouter.ResolveDID)
+	didAPI.GET(ControllersPrefix+"/:id", didRouter.GetDIDControllers)
+	didAPI.PUT(ControllersPrefix+"/:id", didRouter.AddDIDController)
+	didAPI.PUT(ControllersPrefix+"/:id/current", didRouter.ChangeDIDController)
+	didAPI.DELETE(ControllersPrefix+"/:id/:controller", didRouter.RemoveDIDController)
 	return
 }
 
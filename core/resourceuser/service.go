@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/fapiper/onchain-access-control/core/service/auth"
+	"github.com/fapiper/onchain-access-control/core/service/prover"
 	"github.com/fapiper/onchain-access-control/core/service/rpc"
 
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
@@ -29,10 +30,12 @@ type Service struct {
 	Credential       *credential.Service
 	Presentation     *presentation.Service
 	Operation        *operation.Service
+	Trackers         *operation.Trackers
 	storage          storage.ServiceStorage
 	BatchDID         *did.BatchService
 	RPC              *rpc.Service
 	Auth             *auth.Service
+	Prover           *prover.Service
 	DIDConfiguration *wellknown.DIDConfigurationService
 }
 
@@ -42,7 +45,17 @@ func ServicesInit(ctx context.Context, clients *Clients, config config.ServicesC
 	if err := validateServiceConfig(config); err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the oac service, invalid config")
 	}
-	return servicesInitUnsafe(clients, config)
+	instance, err := servicesInitUnsafe(clients, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// complete the operations of sent transactions once they are confirmed
+	go instance.Trackers.Run(ctx)
+	// follow the chain heads to invalidate cached reads of contexts, roles and sessions
+	go instance.RPC.RunCache(ctx)
+
+	return instance, nil
 }
 
 func validateServiceConfig(config config.ServicesConfig) error {
@@ -93,7 +106,17 @@ func servicesInitUnsafe(c *Clients, config config.ServicesConfig) (*Service, err
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate batch DID service")
 	}
 
-	didService, err := did.NewDIDService(config.DIDConfig, storageProvider, keyStoreService, keyStoreServiceFactory)
+	rpcService, err := rpc.NewRPCService(c.EthClient, keyStoreService)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the rpc service")
+	}
+
+	trackers, err := operation.NewTrackers(config.OperationConfig, storageProvider, rpcService)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the transaction trackers")
+	}
+
+	didService, err := did.NewDIDService(config.DIDConfig, storageProvider, keyStoreService, keyStoreServiceFactory, rpcService, trackers)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the DID service")
 	}
@@ -124,12 +147,12 @@ func servicesInitUnsafe(c *Clients, config config.ServicesConfig) (*Service, err
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the did configuration service")
 	}
 
-	rpcService, err := rpc.NewRPCService()
+	proverService, err := prover.NewProverService(config.ProverConfig, credentialService, rpcService, c.Artifacts)
 	if err != nil {
-		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the rpc service")
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the prover service")
 	}
 
-	authServiceFactory := auth.NewAuthServiceFactory(storageProvider, didResolver, keyStoreService, keyEncrypter, keyDecrypter, rpcService, c.IPFSClient)
+	authServiceFactory := auth.NewAuthServiceFactory(config.AuthConfig, storageProvider, didResolver, keyStoreService, trackers, keyEncrypter, keyDecrypter, rpcService, c.Artifacts, proverService)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the auth service factory")
 	}
@@ -138,6 +161,7 @@ func servicesInitUnsafe(c *Clients, config config.ServicesConfig) (*Service, err
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate Auth service")
 	}
+	authService.RegisterFollowUps()
 
 	return &Service{
 		KeyStore:         keyStoreService,
@@ -147,7 +171,9 @@ func servicesInitUnsafe(c *Clients, config config.ServicesConfig) (*Service, err
 		Credential:       credentialService,
 		Presentation:     presentationService,
 		Operation:        operationService,
+		Trackers:         trackers,
 		Auth:             authService,
+		Prover:           proverService,
 		RPC:              rpcService,
 		DIDConfiguration: didConfigurationService,
 		storage:          storageProvider,
@@ -156,7 +182,7 @@ func servicesInitUnsafe(c *Clients, config config.ServicesConfig) (*Service, err
 
 // GetServices returns all services
 func (s *Service) GetServices() []frameworksvc.Service {
-	return []frameworksvc.Service{
+	services := []frameworksvc.Service{
 		s.KeyStore,
 		s.DID,
 		s.Schema,
@@ -164,7 +190,13 @@ func (s *Service) GetServices() []frameworksvc.Service {
 		s.Presentation,
 		s.Operation,
 		s.Auth,
+		s.Prover,
+	}
+	// the rpc service reports the readiness of each of its chains
+	for _, chain := range s.RPC.Chains() {
+		services = append(services, chain)
 	}
+	return services
 }
 
 func (s *Service) GetStorage() storage.ServiceStorage {
@@ -56,22 +56,18 @@ func AuthMiddleware(accessControlService *accesscontrol.Service) gin.HandlerFunc
 			token = header.Token[7:]
 		}
 
-		result, err := accessControlService.VerifySession(c, accesscontrol.VerifySessionInput{RoleID: "VERIFICATION_BODY", SessionToken: keyaccess.JWT(token)})
-
-		if !result.Verified || err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": result.Reason})
+		result, err := accessControlService.AuthorizeResource(c, accesscontrol.AuthorizeResourceInput{
+			Resource:     fmt.Sprintf("%s%s", config.GetFileStoreBase(), c.Param(fileRefParamKey)),
+			Operation:    operationForMethod(c.Request.Method),
+			SessionToken: keyaccess.JWT(token),
+		})
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
-
-		// TODO Retrieve `fileDidUrl` from token:
-		// fileDidUrl := "did:pkh:0x1234?ref=static/test.csv"
-		fileRefFromToken := "static/data/emission_report.csv"
-		fileRefFromPath := fmt.Sprintf("%s%s", config.GetFileStoreBase(), c.Param(fileRefParamKey))
-
-		// 1. check if `fileRefFromToken` matches requested file
-		if fileRefFromToken != fileRefFromPath {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token invalid"})
+		if !result.Verified {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": result.Reason})
 			c.Abort()
 			return
 		}
@@ -79,3 +75,13 @@ func AuthMiddleware(accessControlService *accesscontrol.Service) gin.HandlerFunc
 		c.Next()
 	}
 }
+
+// operationForMethod maps a http method to the operation it performs on a resource
+func operationForMethod(method string) uint8 {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return accesscontrol.OperationRead
+	default:
+		return accesscontrol.OperationWrite
+	}
+}
@@ -3,7 +3,8 @@ package router
 import (
 	"fmt"
 	"github.com/TBD54566975/ssi-sdk/util"
-	framework "github.com/fapiper/onchain-access-control/core/framework/server"
+	"github.com/fapiper/onchain-access-control/core/server/framework"
+	"github.com/fapiper/onchain-access-control/core/server/pagination"
 	"github.com/fapiper/onchain-access-control/core/service/accesscontrol"
 	svcframework "github.com/fapiper/onchain-access-control/core/service/framework"
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,10 @@ import (
 	"net/http"
 )
 
+const (
+	PermissionParam = "permission"
+)
+
 type AccessControlRouter struct {
 	service *accesscontrol.Service
 }
@@ -71,6 +76,34 @@ func (r AccessControlRouter) CreateSession(c *gin.Context) {
 	framework.Respond(c, resp, http.StatusOK)
 }
 
+// RevokeSession godoc
+//
+//	@Summary		Revokes a Session
+//	@Description	Revokes a session within the access context of this instance
+//	@Tags			Accesscontrol
+//	@Produce		json
+//	@Param			id	path		string		true	"ID"
+//	@Success		201	{object}	Operation	"The operation is done once the transaction is confirmed and the session is marked as revoked."
+//	@Failure		400	{string}	string		"Bad request"
+//	@Failure		500	{string}	string		"Internal server error"
+//	@Router			/access/session/{id} [delete]
+func (r AccessControlRouter) RevokeSession(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "revoke session request missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.RevokeSession(c, accesscontrol.RevokeSessionInput{ID: *id})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
 type RegisterResourceRequest accesscontrol.RegisterResourceInput
 
 type RegisterResourceResponse = accesscontrol.RegisterResourceOutput
@@ -82,7 +115,7 @@ type RegisterResourceResponse = accesscontrol.RegisterResourceOutput
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		RegisterResourceRequest	true	"request body"
-//	@Success		200		{object}	RegisterResourceResponse
+//	@Success		201		{object}	Operation				"The output of the operation is RegisterResourceResponse once the transaction is confirmed."
 //	@Failure		400		{string}	string	"Bad request"
 //	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/access/resource [put]
@@ -98,13 +131,252 @@ func (r AccessControlRouter) RegisterResource(c *gin.Context) {
 		return
 	}
 
-	resp, err := r.service.RegisterResource(c, accesscontrol.RegisterResourceInput(request))
+	op, err := r.service.RegisterResource(c, accesscontrol.RegisterResourceInput(request))
 	if err != nil {
 		framework.LoggingRespondErrWithMsg(c, err, "could not register resource", http.StatusInternalServerError)
 		return
 	}
 
-	framework.Respond(c, resp, http.StatusOK)
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type RegisterPolicyRequest accesscontrol.RegisterPolicyInput
+
+type RegisterPolicyResponse = accesscontrol.RegisterPolicyOutput
+
+// RegisterPolicy godoc
+//
+//	@Summary		Registers a Policy
+//	@Description	Registers a deployed policy verifier contract as policy and optionally assigns it to a role
+//	@Tags			Accesscontrol
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RegisterPolicyRequest	true	"request body"
+//	@Success		201		{object}	Operation	"The output of the operation is RegisterPolicyResponse once the transaction is confirmed."
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/access/policy/register [put]
+func (r AccessControlRouter) RegisterPolicy(c *gin.Context) {
+	var request RegisterPolicyRequest
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid register policy request", http.StatusBadRequest)
+		return
+	}
+
+	if err := util.IsValidStruct(request); err != nil {
+		framework.LoggingRespondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.RegisterPolicy(c, accesscontrol.RegisterPolicyInput(request))
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not register policy", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type AssignPolicyRequest struct {
+	// Identifier of the policy, i.e. `<context did>+<policy id>`. A plain id refers to a policy of this instance.
+	Policy string `json:"policy" validate:"required"`
+}
+
+// AssignPolicy godoc
+//
+//	@Summary		Assigns a Policy to a role
+//	@Description	Assigns an existing policy of any access context to a role within the access context of this instance
+//	@Tags			Accesscontrol
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"ID"
+//	@Param			request	body		AssignPolicyRequest	true	"request body"
+//	@Success		201		{object}	Operation			"The operation is done once the transaction is confirmed."
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/access/role/{id}/policy [put]
+func (r AccessControlRouter) AssignPolicy(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "assign policy request missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var request AssignPolicyRequest
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid assign policy request", http.StatusBadRequest)
+		return
+	}
+
+	if err := util.IsValidStruct(request); err != nil {
+		framework.LoggingRespondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.AssignPolicy(c, accesscontrol.AssignPolicyInput{Policy: request.Policy, Role: *id})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not assign policy", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type RegisterPermissionRequest accesscontrol.RegisterPermissionInput
+
+type RegisterPermissionResponse = accesscontrol.RegisterPermissionOutput
+
+// RegisterPermission godoc
+//
+//	@Summary		Registers a Permission
+//	@Description	Registers a permission for operations on a resource and optionally assigns it to a role
+//	@Tags			Accesscontrol
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RegisterPermissionRequest	true	"request body"
+//	@Success		201		{object}	Operation	"The output of the operation is RegisterPermissionResponse once the transaction is confirmed."
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/access/permission [put]
+func (r AccessControlRouter) RegisterPermission(c *gin.Context) {
+	var request RegisterPermissionRequest
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid register permission request", http.StatusBadRequest)
+		return
+	}
+
+	if err := util.IsValidStruct(request); err != nil {
+		framework.LoggingRespondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.RegisterPermission(c, accesscontrol.RegisterPermissionInput(request))
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not register permission", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+// AssignPermission godoc
+//
+//	@Summary		Assigns a Permission to a role
+//	@Description	Assigns a permission to a role within the access context of this instance
+//	@Tags			Accesscontrol
+//	@Produce		json
+//	@Param			id			path		string	true	"ID"
+//	@Param			permission	path		string	true	"Permission ID"
+//	@Success		201			{object}	Operation	"The operation is done once the transaction is confirmed and the assignment is stored."
+//	@Failure		400			{string}	string	"Bad request"
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/access/role/{id}/permission/{permission} [put]
+func (r AccessControlRouter) AssignPermission(c *gin.Context) {
+	request, ok := getPermissionAssignment(c, "assign permission")
+	if !ok {
+		return
+	}
+
+	op, err := r.service.AssignPermission(c, *request)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not assign permission", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+// UnassignPermission godoc
+//
+//	@Summary		Unassigns a Permission from a role
+//	@Description	Removes the assignment of a permission to a role within the access context of this instance
+//	@Tags			Accesscontrol
+//	@Produce		json
+//	@Param			id			path		string	true	"ID"
+//	@Param			permission	path		string	true	"Permission ID"
+//	@Success		201			{object}	Operation	"The operation is done once the transaction is confirmed and the assignment is removed."
+//	@Failure		400			{string}	string	"Bad request"
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/access/role/{id}/permission/{permission} [delete]
+func (r AccessControlRouter) UnassignPermission(c *gin.Context) {
+	request, ok := getPermissionAssignment(c, "unassign permission")
+	if !ok {
+		return
+	}
+
+	op, err := r.service.UnassignPermission(c, *request)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not unassign permission", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+func getPermissionAssignment(c *gin.Context, name string) (*accesscontrol.PermissionAssignmentInput, bool) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, fmt.Sprintf("%s request missing id parameter", name), http.StatusBadRequest)
+		return nil, false
+	}
+	permission := framework.GetParam(c, PermissionParam)
+	if permission == nil {
+		framework.LoggingRespondErrMsg(c, fmt.Sprintf("%s request missing permission parameter", name), http.StatusBadRequest)
+		return nil, false
+	}
+	return &accesscontrol.PermissionAssignmentInput{Permission: *permission, Role: *id}, true
+}
+
+type RevokeRoleRequest struct {
+	// DID of the user the role is revoked from
+	DID string `json:"did" validate:"required"`
+}
+
+// RevokeRole godoc
+//
+//	@Summary		Revokes a role from a user
+//	@Description	Revokes a role within the access context of this instance from a user
+//	@Tags			Accesscontrol
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"ID"
+//	@Param			request	body		RevokeRoleRequest	true	"request body"
+//	@Success		201		{object}	Operation			"The operation is done once the transaction is confirmed."
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/access/role/{id} [delete]
+func (r AccessControlRouter) RevokeRole(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "revoke role request missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	var request RevokeRoleRequest
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid revoke role request", http.StatusBadRequest)
+		return
+	}
+
+	if err := util.IsValidStruct(request); err != nil {
+		framework.LoggingRespondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.RevokeRole(c, accesscontrol.RevokeRoleInput{Role: *id, DID: request.DID})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not revoke role", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
 }
 
 type CreateAccessContextResponse struct {
@@ -117,38 +389,41 @@ type CreateAccessContextResponse struct {
 //	@Summary		Creates an access context
 //	@Tags			Accesscontrol
 //	@Produce		json
-//	@Success		200		{object}	CreateAccessContextResponse
+//	@Success		200		{object}	CreateAccessContextResponse	"The access context already exists."
+//	@Success		201		{object}	Operation					"The output of the operation is the access context once the transaction is confirmed."
 //	@Failure		400		{string}	string	"Bad request"
 //	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/access/context [put]
 func (r AccessControlRouter) CreateAccessContext(c *gin.Context) {
 
-	stored, err := r.service.CreateAccessContext(c)
+	stored, op, err := r.service.CreateAccessContext(c)
 	if err != nil {
 		framework.LoggingRespondErrWithMsg(c, err, "could not create access context", http.StatusInternalServerError)
 		return
 	}
 
+	if op != nil {
+		framework.Respond(c, Operation{ID: op.ID}, http.StatusCreated)
+		return
+	}
+
 	resp := CreateAccessContextResponse{AccessContext: *stored}
 	framework.Respond(c, resp, http.StatusOK)
 }
 
-type CreatePolicyRequest struct {
-	*accesscontrol.CreatePolicyRequest
-}
+type CreatePolicyRequest accesscontrol.CreatePolicyRequest
 
-type CreatePolicyResponse struct {
-	*accesscontrol.CreatePolicyResponse
-}
+type CreatePolicyResponse = accesscontrol.CreatePolicyResponse
 
 // CreatePolicy godoc
 //
 //	@Summary		Creates an access policy
-//	@Tags			Access
+//	@Description	Pins the policy artifacts to ipfs, deploys a policy verifier carrying their uris and registers it in the access context
+//	@Tags			Accesscontrol
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		CreatePolicyRequest	true	"request body"
-//	@Success		200		{object}	CreatePolicyResponse
+//	@Success		201		{object}	Operation	"The output of the operation is CreatePolicyResponse once the transaction is confirmed."
 //	@Failure		400		{string}	string	"Bad request"
 //	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/access/policy [put]
@@ -164,13 +439,85 @@ func (r AccessControlRouter) CreatePolicy(c *gin.Context) {
 		return
 	}
 
-	storedPolicy, err := r.service.CreatePolicy(c, accesscontrol.CreatePolicyRequest{})
-
+	op, err := r.service.CreatePolicy(c, accesscontrol.CreatePolicyRequest(request))
 	if err != nil {
 		framework.LoggingRespondErrWithMsg(c, err, "could not create policy", http.StatusInternalServerError)
 		return
 	}
 
-	resp := CreatePolicyResponse{storedPolicy}
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type GetPolicyResponse = accesscontrol.GetPolicyResponse
+
+// GetPolicy godoc
+//
+//	@Summary		Get an access policy
+//	@Description	Get an access policy by its ID
+//	@Tags			Accesscontrol
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	GetPolicyResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Router			/access/policy/{id} [get]
+func (r AccessControlRouter) GetPolicy(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "cannot get policy without ID parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	resp, err := r.service.GetPolicy(c, accesscontrol.GetPolicyRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get policy with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type ListPoliciesResponse struct {
+	// Policies is the list of all policies the service holds
+	Policies []accesscontrol.StoredPolicy `json:"policies,omitempty"`
+
+	// Pagination token to retrieve the next page of results. If the value is "", it means no further results for the request.
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// ListPolicies godoc
+//
+//	@Summary		List access policies
+//	@Description	List access policies created by the service
+//	@Tags			Accesscontrol
+//	@Accept			json
+//	@Produce		json
+//	@Param			pageSize	query		number	false	"Hint to the server of the maximum elements to return. More may be returned. When not set, the server will return all elements."
+//	@Param			pageToken	query		string	false	"Used to indicate to the server to return a specific page of the list results. Must match a previous requests' `nextPageToken`."
+//	@Success		200			{object}	ListPoliciesResponse
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/access/policy [get]
+func (r AccessControlRouter) ListPolicies(c *gin.Context) {
+	var pageRequest pagination.PageRequest
+	if pagination.ParsePaginationQueryValues(c, &pageRequest) {
+		return
+	}
+
+	gotPolicies, err := r.service.ListPolicies(c, accesscontrol.ListPoliciesRequest{
+		PageRequest: &pageRequest,
+	})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not list policies", http.StatusInternalServerError)
+		return
+	}
+
+	resp := ListPoliciesResponse{Policies: gotPolicies.Policies}
+
+	if pagination.MaybeSetNextPageToken(c, gotPolicies.NextPageToken, &resp.NextPageToken) {
+		return
+	}
 	framework.Respond(c, resp, http.StatusOK)
 }
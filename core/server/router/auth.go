@@ -3,12 +3,15 @@ package router
 import (
 	"fmt"
 	"github.com/TBD54566975/ssi-sdk/util"
-	framework "github.com/fapiper/onchain-access-control/core/framework/server"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/server/framework"
 	"github.com/fapiper/onchain-access-control/core/service/auth"
 	svcframework "github.com/fapiper/onchain-access-control/core/service/framework"
 	"github.com/gin-gonic/gin"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/pkg/errors"
+	"math/big"
 	"net/http"
 )
 
@@ -32,43 +35,162 @@ func NewAuthRouter(s svcframework.Service) (*AuthRouter, error) {
 	return &AuthRouter{service: service}, nil
 }
 
+type StartSessionRequest struct {
+	// DID URL of the resource the session is started for, e.g. `did:pkh:...?ref=static/data/emission_report.csv`
+	Resource string `json:"resource" validate:"required"`
+	// DID or DID URL of the key agreement key of the resource owner the session token is encrypted to. Defaults to
+	// the DID of the resource.
+	Recipient string `json:"recipient,omitempty"`
+}
+
 type StartSessionResponse struct {
 	// The created session
 	Session jwt.Token `json:"session"`
 	// The created session
 	SignedToken string `json:"signed_token"`
+	// The operation that is done once the session is started on-chain
+	Operation Operation `json:"operation"`
 }
 
 // StartSession godoc
 //
 //	@Summary		Starts a Session
 //	@Tags			Auth
+//	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	StartSessionResponse
+//	@Param			request	body		StartSessionRequest	true	"request body"
+//	@Success		201		{object}	StartSessionResponse
 //	@Failure		400		{string}	string	"Bad request"
 //	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/auth/session [put]
 func (r AuthRouter) StartSession(c *gin.Context) {
+	var request StartSessionRequest
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid start session request", http.StatusBadRequest)
+		return
+	}
 
-	token, signedToken, err := r.service.StartSession(c)
+	if err := util.IsValidStruct(request); err != nil {
+		framework.LoggingRespondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	token, signedToken, op, err := r.service.StartSession(c, auth.StartSessionInput{Resource: request.Resource, Recipient: request.Recipient})
 	if err != nil {
 		framework.LoggingRespondErrWithMsg(c, err, "could not start session", http.StatusInternalServerError)
 		return
 	}
 
-	resp := StartSessionResponse{Session: *token, SignedToken: signedToken}
-	framework.Respond(c, resp, http.StatusOK)
+	resp := StartSessionResponse{Session: *token, SignedToken: signedToken, Operation: Operation{ID: op.ID}}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revokes a Session
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			id	path		string		true	"ID"
+//	@Success		201	{object}	Operation	"The operation is done once the transaction is confirmed."
+//	@Failure		400	{string}	string		"Bad request"
+//	@Failure		500	{string}	string		"Internal server error"
+//	@Router			/auth/session/{id} [delete]
+func (r AuthRouter) RevokeSession(c *gin.Context) {
+	id := framework.GetParam(c, SessionIDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "revoke session request missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.RevokeSession(c, auth.RevokeSessionInput{ID: *id})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: op.ID}
+	framework.Respond(c, resp, http.StatusCreated)
 }
 
 type GrantRoleRequest struct {
-	Proof  any    `json:"proof"`
-	Inputs any    `json:"inputs"`
-	Policy string `json:"policy"`
+	// Policies of the role along with the proofs that satisfy them
+	Policies []GrantRolePolicy `json:"policies,omitempty" validate:"omitempty,dive"`
+
+	// Deprecated: single policy form, use Policies instead
+	GrantRolePolicy
+}
+
+// GrantRolePolicy is a policy of a role to be granted and the zero-knowledge proof satisfying it. Without a proof, the
+// proof is generated from the credentials held by this instance.
+type GrantRolePolicy struct {
+	Policy string `json:"policy,omitempty"`
+	Proof  *Proof `json:"proof,omitempty"`
+	// Inputs are the public inputs of the proof, as many as the policy verifier declares
+	Inputs []*math.HexOrDecimal256 `json:"inputs,omitempty"`
+}
+
+// Proof is a Groth16 proof with hex or decimal encoded coordinates
+type Proof struct {
+	A G1Point `json:"a"`
+	B G2Point `json:"b"`
+	C G1Point `json:"c"`
+}
+
+type G1Point struct {
+	X *math.HexOrDecimal256 `json:"X"`
+	Y *math.HexOrDecimal256 `json:"Y"`
+}
+
+type G2Point struct {
+	X [2]*math.HexOrDecimal256 `json:"X"`
+	Y [2]*math.HexOrDecimal256 `json:"Y"`
 }
 
-type GrantRoleResponse struct {
-	// The created role
-	Role auth.Role `json:"role"`
+func (p G1Point) toPairing() contracts.PairingG1Point {
+	return contracts.PairingG1Point{X: toBig(p.X), Y: toBig(p.Y)}
+}
+
+func (p G2Point) toPairing() contracts.PairingG2Point {
+	return contracts.PairingG2Point{
+		X: [2]*big.Int{toBig(p.X[0]), toBig(p.X[1])},
+		Y: [2]*big.Int{toBig(p.Y[0]), toBig(p.Y[1])},
+	}
+}
+
+func (p GrantRolePolicy) toServiceInput() auth.GrantRolePolicyInput {
+	input := auth.GrantRolePolicyInput{PolicyID: p.Policy}
+	if p.Proof == nil {
+		return input
+	}
+	input.Inputs = make([]*big.Int, len(p.Inputs))
+	for i := range p.Inputs {
+		input.Inputs[i] = toBig(p.Inputs[i])
+	}
+	input.Proof = &contracts.IPolicyVerifierProof{
+		A: p.Proof.A.toPairing(),
+		B: p.Proof.B.toPairing(),
+		C: p.Proof.C.toPairing(),
+	}
+	return input
+}
+
+func (r GrantRoleRequest) toServiceRequest(roleID string) auth.GrantRoleInput {
+	policies := r.Policies
+	if len(policies) == 0 && r.Policy != "" {
+		policies = []GrantRolePolicy{r.GrantRolePolicy}
+	}
+	input := auth.GrantRoleInput{RoleID: roleID, Policies: make([]auth.GrantRolePolicyInput, 0, len(policies))}
+	for _, policy := range policies {
+		input.Policies = append(input.Policies, policy.toServiceInput())
+	}
+	return input
+}
+
+func toBig(n *math.HexOrDecimal256) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return (*big.Int)(n)
 }
 
 // GrantRole godoc
@@ -79,7 +201,7 @@ type GrantRoleResponse struct {
 //	@Produce		json
 //	@Param			id		path		string				true	"ID"
 //	@Param			request	body		GrantRoleRequest	true	"request body"
-//	@Success		200		{object}	GrantRoleResponse
+//	@Success		201		{object}	Operation			"The output of the operation is the granted role once the transaction is confirmed."
 //	@Failure		400		{string}	string	"Bad request"
 //	@Failure		500		{string}	string	"Internal server error"
 //	@Router			/auth/session [put]
@@ -101,13 +223,40 @@ func (r AuthRouter) GrantRole(ctx *gin.Context) {
 		return
 	}
 
-	stored, err := r.service.GrantRole(ctx, auth.GrantRoleInput{RoleID: *roleIdentifierParam, PolicyID: request.Policy, Proof: auth.Proof, Inputs: auth.Inputs})
+	op, err := r.service.GrantRole(ctx, request.toServiceRequest(*roleIdentifierParam))
 
 	if err != nil {
 		framework.LoggingRespondErrWithMsg(ctx, err, "could not grant role", http.StatusInternalServerError)
 		return
 	}
 
-	response := GrantRoleResponse{Role: *stored}
-	framework.Respond(ctx, response, http.StatusOK)
+	response := Operation{ID: op.ID}
+	framework.Respond(ctx, response, http.StatusCreated)
+}
+
+// RevokeRole godoc
+//
+//	@Summary		Renounces a role granted to this resourceuser instance
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			id	path		string		true	"ID"
+//	@Success		201	{object}	Operation	"The operation is done once the transaction is confirmed and the stored role is removed."
+//	@Failure		400	{string}	string		"Bad request"
+//	@Failure		500	{string}	string		"Internal server error"
+//	@Router			/auth/role/{id} [delete]
+func (r AuthRouter) RevokeRole(ctx *gin.Context) {
+	roleIdentifierParam := framework.GetParam(ctx, RoleIdentifierParam)
+	if roleIdentifierParam == nil {
+		framework.LoggingRespondErrMsg(ctx, "revoke role request missing id parameter", http.StatusBadRequest)
+		return
+	}
+
+	op, err := r.service.RevokeRole(ctx, auth.RevokeRoleInput{RoleID: *roleIdentifierParam})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(ctx, err, "could not revoke role", http.StatusInternalServerError)
+		return
+	}
+
+	response := Operation{ID: op.ID}
+	framework.Respond(ctx, response, http.StatusCreated)
 }
@@ -0,0 +1,71 @@
+package router
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantRoleRequest(t *testing.T) {
+	roleID := "did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2+ROLE"
+	proof := `{
+		"a": {"X": "0x01", "Y": "0x0000000000000000000000000000000000000000000000000000000000000002"},
+		"b": {"X": ["0x03", "0x04"], "Y": ["5", "6"]},
+		"c": {"X": "0x07", "Y": "0x08"}
+	}`
+
+	t.Run("multiple policies", func(tt *testing.T) {
+		var request GrantRoleRequest
+		err := json.Unmarshal([]byte(`{"policies": [
+			{"policy": "ctx+POLICY_A", "proof": `+proof+`, "inputs": ["0x0a", "11"]},
+			{"policy": "ctx+POLICY_B", "proof": `+proof+`, "inputs": []}
+		]}`), &request)
+		require.NoError(tt, err)
+
+		input := request.toServiceRequest(roleID)
+		assert.Equal(tt, roleID, input.RoleID)
+		require.Len(tt, input.Policies, 2)
+		assert.Equal(tt, "ctx+POLICY_A", input.Policies[0].PolicyID)
+		assert.Equal(tt, "ctx+POLICY_B", input.Policies[1].PolicyID)
+
+		first := input.Policies[0]
+		assert.Equal(tt, big.NewInt(1), first.Proof.A.X)
+		assert.Equal(tt, big.NewInt(2), first.Proof.A.Y)
+		assert.Equal(tt, [2]*big.Int{big.NewInt(3), big.NewInt(4)}, first.Proof.B.X)
+		assert.Equal(tt, [2]*big.Int{big.NewInt(5), big.NewInt(6)}, first.Proof.B.Y)
+		assert.Equal(tt, big.NewInt(8), first.Proof.C.Y)
+		assert.Equal(tt, []*big.Int{big.NewInt(10), big.NewInt(11)}, first.Inputs)
+		assert.True(tt, input.IsValid())
+	})
+
+	t.Run("single policy", func(tt *testing.T) {
+		var request GrantRoleRequest
+		err := json.Unmarshal([]byte(`{"policy": "ctx+POLICY_A", "proof": `+proof+`, "inputs": ["0x0a"]}`), &request)
+		require.NoError(tt, err)
+
+		input := request.toServiceRequest(roleID)
+		require.Len(tt, input.Policies, 1)
+		assert.Equal(tt, "ctx+POLICY_A", input.Policies[0].PolicyID)
+		assert.Equal(tt, big.NewInt(10), input.Policies[0].Inputs[0])
+	})
+
+	t.Run("policy without proof", func(tt *testing.T) {
+		var request GrantRoleRequest
+		err := json.Unmarshal([]byte(`{"policies": [{"policy": "ctx+POLICY_A"}]}`), &request)
+		require.NoError(tt, err)
+
+		input := request.toServiceRequest(roleID)
+		require.Len(tt, input.Policies, 1)
+		assert.Nil(tt, input.Policies[0].Proof)
+		assert.True(tt, input.IsValid())
+	})
+
+	t.Run("no policy", func(tt *testing.T) {
+		input := GrantRoleRequest{}.toServiceRequest(roleID)
+		assert.Empty(tt, input.Policies)
+		assert.False(tt, input.IsValid())
+	})
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -18,12 +19,14 @@ import (
 	"github.com/fapiper/onchain-access-control/core/server/pagination"
 	"github.com/fapiper/onchain-access-control/core/service/did"
 	svcframework "github.com/fapiper/onchain-access-control/core/service/framework"
+	"github.com/fapiper/onchain-access-control/core/service/operation"
 )
 
 const (
-	MethodParam  = "method"
-	IDParam      = "id"
-	DeletedParam = "deleted"
+	MethodParam     = "method"
+	IDParam         = "id"
+	DeletedParam    = "deleted"
+	ControllerParam = "controller"
 )
 
 // DIDRouter represents the dependencies required to instantiate a DID-HTTP service
@@ -470,6 +473,146 @@ func (dr DIDRouter) ResolveDID(c *gin.Context) {
 	framework.Respond(c, resp, http.StatusOK)
 }
 
+type GetDIDControllersResponse struct {
+	DID string `json:"did"`
+	// The controllers the did registry records for the DID, none for a DID unknown to the registry.
+	Controllers []string `json:"controllers"`
+	// The controller authorized to act for the DID.
+	Current string `json:"current,omitempty"`
+}
+
+// GetDIDControllers godoc
+//
+//	@Summary		Get the controllers of a DID
+//	@Description	Get the controllers the did registry records for a DID, on the chain of the DID if it is a did:pkh
+//	@Tags			DecentralizedIdentifiers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	GetDIDControllersResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Router			/v1/dids/controllers/{id} [get]
+func (dr DIDRouter) GetDIDControllers(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "get DID controllers request missing id parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	controllers, err := dr.service.GetControllers(c, did.GetControllersRequest{DID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get controllers of DID: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	resp := GetDIDControllersResponse{DID: controllers.DID, Controllers: controllers.Controllers, Current: controllers.Current}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type UpdateDIDControllerRequest struct {
+	// The address of the controller.
+	Controller string `json:"controller" validate:"required"`
+}
+
+// AddDIDController godoc
+//
+//	@Summary		Add a controller of a DID
+//	@Description	Adds a controller of a DID to the did registry. The wallet of this service must be the current
+//	@Description	controller of the DID, unless the DID has no controllers yet.
+//	@Tags			DecentralizedIdentifiers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"ID"
+//	@Param			request	body		UpdateDIDControllerRequest	true	"request body"
+//	@Success		201		{object}	Operation	"The operation is done once the transaction is confirmed."
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/dids/controllers/{id} [put]
+func (dr DIDRouter) AddDIDController(c *gin.Context) {
+	dr.updateDIDController(c, "add", dr.service.AddController)
+}
+
+// ChangeDIDController godoc
+//
+//	@Summary		Change the current controller of a DID
+//	@Description	Makes a controller of a DID its current one, which is authorized to act for the DID from then on,
+//	@Description	e.g. to administer the access contexts owned by the DID. The wallet of this service must be the
+//	@Description	current controller of the DID.
+//	@Tags			DecentralizedIdentifiers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"ID"
+//	@Param			request	body		UpdateDIDControllerRequest	true	"request body"
+//	@Success		201		{object}	Operation	"The operation is done once the transaction is confirmed."
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/dids/controllers/{id}/current [put]
+func (dr DIDRouter) ChangeDIDController(c *gin.Context) {
+	dr.updateDIDController(c, "change", dr.service.ChangeController)
+}
+
+func (dr DIDRouter) updateDIDController(c *gin.Context, action string, update func(context.Context, did.UpdateControllerRequest) (*operation.Operation, error)) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := fmt.Sprintf("%s DID controller request missing id parameter", action)
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateDIDControllerRequest
+	invalidRequest := fmt.Sprintf("invalid %s DID controller request", action)
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidRequest, http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidRequest, http.StatusBadRequest)
+		return
+	}
+
+	op, err := update(c, did.UpdateControllerRequest{DID: *id, Controller: request.Controller})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not %s controller<%s> of DID: %s", action, request.Controller, *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, Operation{ID: op.ID}, http.StatusCreated)
+}
+
+// RemoveDIDController godoc
+//
+//	@Summary		Remove a controller of a DID
+//	@Description	Removes a controller other than the current one of a DID from the did registry. The wallet of this
+//	@Description	service must be the current controller of the DID.
+//	@Tags			DecentralizedIdentifiers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"ID"
+//	@Param			controller	path		string	true	"Controller address"
+//	@Success		201			{object}	Operation	"The operation is done once the transaction is confirmed."
+//	@Failure		400			{string}	string	"Bad request"
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/v1/dids/controllers/{id}/{controller} [delete]
+func (dr DIDRouter) RemoveDIDController(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	controller := framework.GetParam(c, ControllerParam)
+	if id == nil || controller == nil {
+		errMsg := "remove DID controller request missing id or controller parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	op, err := dr.service.RemoveController(c, did.UpdateControllerRequest{DID: *id, Controller: *controller})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not remove controller<%s> of DID: %s", *controller, *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, Operation{ID: op.ID}, http.StatusCreated)
+}
+
 type BatchCreateDIDsRequest struct {
 	// Required. The list of create credential requests. Cannot be more than {{.Services.DIDConfig.BatchCreateMaxItems}} items.
 	Requests []CreateDIDByMethodRequest `json:"requests" maxItems:"100" validate:"required,dive"`
@@ -0,0 +1,200 @@
+package router
+
+import (
+	"fmt"
+	"github.com/fapiper/onchain-access-control/core/server/framework"
+	"github.com/fapiper/onchain-access-control/core/server/pagination"
+	svcframework "github.com/fapiper/onchain-access-control/core/service/framework"
+	"github.com/fapiper/onchain-access-control/core/service/indexer"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+const (
+	RoleQuery = "role"
+	UserQuery = "user"
+)
+
+type IndexerRouter struct {
+	service *indexer.Service
+}
+
+func NewIndexerRouter(s svcframework.Service) (*IndexerRouter, error) {
+	if s == nil {
+		return nil, errors.New("service cannot be nil")
+	}
+	service, ok := s.(*indexer.Service)
+	if !ok {
+		return nil, fmt.Errorf("casting service: %s", s.Type())
+	}
+	return &IndexerRouter{service: service}, nil
+}
+
+// GetCheckpoint godoc
+//
+//	@Summary		Get the indexer checkpoint
+//	@Description	Get the last block processed by the indexer
+//	@Tags			Indexer
+//	@Produce		json
+//	@Success		200	{object}	indexer.Checkpoint
+//	@Failure		404	{string}	string	"Not found"
+//	@Router			/index/checkpoint [get]
+func (ir IndexerRouter) GetCheckpoint(c *gin.Context) {
+	checkpoint, err := ir.service.GetCheckpoint(c)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not get checkpoint", http.StatusNotFound)
+		return
+	}
+	framework.Respond(c, checkpoint, http.StatusOK)
+}
+
+type ListIndexedContextsResponse struct {
+	Contexts []indexer.IndexedContext `json:"contexts,omitempty"`
+
+	// Pagination token to retrieve the next page of results. If the value is "", it means no further results for the request.
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// ListContexts godoc
+//
+//	@Summary		List access contexts
+//	@Description	List all access contexts created by the context handler
+//	@Tags			Indexer
+//	@Produce		json
+//	@Param			pageSize	query		number	false	"Hint to the server of the maximum elements to return. More may be returned. When not set, the server will return all elements."
+//	@Param			pageToken	query		string	false	"Used to indicate to the server to return a specific page of the list results. Must match a previous requests' `nextPageToken`."
+//	@Success		200			{object}	ListIndexedContextsResponse
+//	@Failure		500			{string}	string	"Internal server error"
+//	@Router			/index/contexts [get]
+func (ir IndexerRouter) ListContexts(c *gin.Context) {
+	var pageRequest pagination.PageRequest
+	if pagination.ParsePaginationQueryValues(c, &pageRequest) {
+		return
+	}
+
+	gotContexts, err := ir.service.ListContexts(c, indexer.ListContextsRequest{PageRequest: &pageRequest})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not list contexts", http.StatusInternalServerError)
+		return
+	}
+
+	resp := ListIndexedContextsResponse{Contexts: gotContexts.Contexts}
+
+	if pagination.MaybeSetNextPageToken(c, gotContexts.NextPageToken, &resp.NextPageToken) {
+		return
+	}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+// GetContext godoc
+//
+//	@Summary		Get an access context
+//	@Description	Get an access context by its address
+//	@Tags			Indexer
+//	@Produce		json
+//	@Param			id	path		string	true	"Address"
+//	@Success		200	{object}	indexer.GetContextResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		404	{string}	string	"Not found"
+//	@Router			/index/contexts/{id} [get]
+func (ir IndexerRouter) GetContext(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot get context without address parameter", http.StatusBadRequest)
+		return
+	}
+
+	gotContext, err := ir.service.GetContext(c, indexer.GetContextRequest{Address: *id})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, fmt.Sprintf("could not get context with address: %s", *id), http.StatusNotFound)
+		return
+	}
+	framework.Respond(c, gotContext, http.StatusOK)
+}
+
+// ListRoleHolders godoc
+//
+//	@Summary		List role holders
+//	@Description	List the DIDs holding roles within an access context
+//	@Tags			Indexer
+//	@Produce		json
+//	@Param			id		path		string	true	"Address"
+//	@Param			role	query		string	false	"Role id or hash to filter by"
+//	@Success		200		{object}	indexer.ListRoleHoldersResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/index/contexts/{id}/roles [get]
+func (ir IndexerRouter) ListRoleHolders(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot list role holders without address parameter", http.StatusBadRequest)
+		return
+	}
+
+	request := indexer.ListRoleHoldersRequest{Context: *id}
+	if role := framework.GetQueryValue(c, RoleQuery); role != nil {
+		request.Role = *role
+	}
+	if !request.IsValid() {
+		framework.LoggingRespondErrMsg(c, fmt.Sprintf("invalid context address: %s", *id), http.StatusBadRequest)
+		return
+	}
+
+	holders, err := ir.service.ListRoleHolders(c, request)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not list role holders", http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, holders, http.StatusOK)
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	List all sessions that are neither revoked nor expired
+//	@Tags			Indexer
+//	@Produce		json
+//	@Param			user	query		string	false	"User DID or hash to filter by"
+//	@Success		200		{object}	indexer.ListSessionsResponse
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/index/sessions [get]
+func (ir IndexerRouter) ListSessions(c *gin.Context) {
+	var request indexer.ListSessionsRequest
+	if user := framework.GetQueryValue(c, UserQuery); user != nil {
+		request.User = *user
+	}
+
+	sessions, err := ir.service.ListSessions(c, request)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not list sessions", http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, sessions, http.StatusOK)
+}
+
+// GetDIDController godoc
+//
+//	@Summary		Get the controller of a DID
+//	@Description	Get the controller of a DID in the DID registry
+//	@Tags			Indexer
+//	@Produce		json
+//	@Param			id	path		string	true	"DID or its hash"
+//	@Success		200	{object}	indexer.GetDIDControllerResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		404	{string}	string	"Not found"
+//	@Router			/index/dids/{id} [get]
+func (ir IndexerRouter) GetDIDController(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot get did controller without id parameter", http.StatusBadRequest)
+		return
+	}
+
+	controller, err := ir.service.GetDIDController(c, indexer.GetDIDControllerRequest{DID: *id})
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, fmt.Sprintf("could not get controller of did: %s", *id), http.StatusNotFound)
+		return
+	}
+	framework.Respond(c, controller, http.StatusOK)
+}
@@ -48,7 +48,7 @@ func testDIDService(t *testing.T, db storage.ServiceStorage, keyStore *keystore.
 		LocalResolutionMethods: []string{"key"},
 	}
 	// create a did service
-	didService, err := did.NewDIDService(serviceConfig, db, keyStore, nil)
+	didService, err := did.NewDIDService(serviceConfig, db, keyStore, nil, nil, nil)
 	require.NoError(t, err)
 	require.NotEmpty(t, didService)
 	return didService
@@ -0,0 +1,54 @@
+package accesscontrol
+
+import (
+	"context"
+	"github.com/fapiper/onchain-access-control/core/service/operation"
+	"github.com/pkg/errors"
+)
+
+// Kinds of the follow-ups that complete the operations of the access control service once their transaction is
+// confirmed
+const (
+	followUpRegisterResource    = "accesscontrol/register-resource"
+	followUpCreateAccessContext = "accesscontrol/create-access-context"
+	followUpCreatePolicy        = "accesscontrol/create-policy"
+	followUpRegisterPolicy      = "accesscontrol/register-policy"
+	followUpRegisterPermission  = "accesscontrol/register-permission"
+	followUpAssignPermission    = "accesscontrol/assign-permission"
+	followUpUnassignPermission  = "accesscontrol/unassign-permission"
+	followUpRevokeSession       = "accesscontrol/revoke-session"
+)
+
+// permissionAssignment is the follow-up of a permission assigned to or unassigned from a role
+type permissionAssignment struct {
+	Permission StoredPermission `json:"permission"`
+	Role       string           `json:"role"`
+}
+
+// RegisterFollowUps registers the handlers of the follow-ups of the operations of this service with the trackers.
+// Operations resumed after a restart run their follow-up as well, so the handlers must be registered before the
+// trackers run.
+func (s Service) RegisterFollowUps() {
+	s.tracker.Handle(followUpRegisterResource, operation.NewFollowUpHandler(s.storeResource))
+	s.tracker.Handle(followUpCreateAccessContext, operation.NewFollowUpHandler(func(ctx context.Context, _ struct{}) (*StoredAccessContext, error) {
+		return s.storeAccessContext(ctx)
+	}))
+	s.tracker.Handle(followUpCreatePolicy, operation.NewFollowUpHandler(s.storePolicy))
+	s.tracker.Handle(followUpRegisterPolicy, operation.NewFollowUpHandler(func(_ context.Context, out RegisterPolicyOutput) (*RegisterPolicyOutput, error) {
+		return &out, nil
+	}))
+	s.tracker.Handle(followUpRegisterPermission, operation.NewFollowUpHandler(s.storePermission))
+	s.tracker.Handle(followUpAssignPermission, operation.NewFollowUpHandler(func(ctx context.Context, a permissionAssignment) (any, error) {
+		return nil, s.storeResourceAssignment(ctx, a.Permission, a.Role)
+	}))
+	s.tracker.Handle(followUpUnassignPermission, operation.NewFollowUpHandler(func(ctx context.Context, a permissionAssignment) (any, error) {
+		// the other assignments of the resource stay in place
+		if err := s.storageClient.RemoveResourceAssignment(ctx, a.Permission.Resource, a.Role, a.Permission.ID); err != nil {
+			return nil, errors.Wrap(err, "could not remove resource assignment")
+		}
+		return nil, nil
+	}))
+	s.tracker.Handle(followUpRevokeSession, operation.NewFollowUpHandler(func(ctx context.Context, id string) (any, error) {
+		return nil, s.markSessionRevoked(ctx, id)
+	}))
+}
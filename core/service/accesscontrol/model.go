@@ -2,16 +2,18 @@ package accesscontrol
 
 import (
 	"github.com/TBD54566975/ssi-sdk/util"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+	"github.com/fapiper/onchain-access-control/core/server/pagination"
 	"github.com/fapiper/onchain-access-control/core/service/persist"
-	"github.com/fapiper/onchain-access-control/core/service/presentation/model"
 	"github.com/fapiper/onchain-access-control/core/service/rpc"
 )
 
 type CreatePolicyRequest struct {
-	PresentationDefinitionID *model.GetPresentationDefinitionRequest `json:"presentation_definition_id"`
-	Verifier                 PolicyVerifier                          `json:"verifier"`
+	// ID of the presentation definition a holder has to satisfy to be granted the policy
+	PresentationDefinitionID string         `json:"presentation_definition_id" validate:"required"`
+	Verifier                 PolicyVerifier `json:"verifier" validate:"required"`
 }
 
 func (cpr CreatePolicyRequest) IsValid() bool {
@@ -19,9 +21,11 @@ func (cpr CreatePolicyRequest) IsValid() bool {
 }
 
 type PolicyVerifier struct {
-	ContractAddress string `json:"contract_address"`
-	ProvingKey      byte   `json:"proving_key"`
-	VerificationKey byte   `json:"verification_key"`
+	// Hex encoded creation bytecode of the policy verifier contract
+	Bytecode        string `json:"bytecode" validate:"required"`
+	ProofProgram    []byte `json:"proof_program" validate:"required"`
+	ProvingKey      []byte `json:"proving_key" validate:"required"`
+	VerificationKey []byte `json:"verification_key" validate:"required"`
 }
 
 type PolicyURISet struct {
@@ -31,16 +35,56 @@ type PolicyURISet struct {
 	VerificationKey        string `json:"verification_key,omitempty"`
 }
 
+func (u PolicyURISet) toParams(bytecode []byte) rpc.DeployPolicyVerifierParams {
+	return rpc.DeployPolicyVerifierParams{
+		Bytecode:               bytecode,
+		PresentationDefinition: u.PresentationDefinition,
+		ProofProgram:           u.ProofProgram,
+		ProvingKey:             u.ProvingKey,
+		VerificationKey:        u.VerificationKey,
+	}
+}
+
 type CreatePolicyResponse struct {
-	// Address of the created policy contract
-	PolicyContract string       `json:"policy_contract"`
-	URIs           PolicyURISet `json:"uris"`
+	// The created policy
+	Policy StoredPolicy `json:"policy"`
+}
+
+type GetPolicyRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type GetPolicyResponse struct {
+	Policy StoredPolicy `json:"policy"`
 }
 
+type ListPoliciesRequest struct {
+	PageRequest *pagination.PageRequest
+}
+
+type ListPoliciesResponse struct {
+	Policies      []StoredPolicy `json:"policies"`
+	NextPageToken string         `json:"nextPageToken"`
+}
+
+const (
+	// OperationRead mirrors the READ operation of the access context permissions
+	OperationRead uint8 = 0
+	// OperationWrite mirrors the WRITE operation of the access context permissions
+	OperationWrite uint8 = 1
+)
+
 type RegisterResourceInput struct {
-	Role           string `json:"role"`
-	PolicyContract string `json:"policy_contract"`
-	Resource       string `json:"resource"`
+	// Id of the role within the access context of this instance
+	Role string `json:"role" validate:"required"`
+	// Address of a policy verifier contract to register as a new policy of the role. Required if no Policy is set.
+	PolicyContract string `json:"policy_contract,omitempty" validate:"required_without=Policy,excluded_with=Policy"`
+	// Identifier of an existing policy, i.e. `<context did>+<policy id>`, to assign to the role
+	Policy string `json:"policy,omitempty"`
+	// Reference of the resource, e.g. `static/data/emission_report.csv`
+	Resource string `json:"resource" validate:"required"`
+	// Permitted operations on the resource. Defaults to read and write.
+	Operations []uint8 `json:"operations,omitempty" validate:"omitempty,max=2,dive,max=1"`
 }
 
 type RegisterResourceValue struct {
@@ -61,14 +105,16 @@ type RegisterResourceOutput struct {
 	DID        string  `json:"did"`
 }
 
-func (v RegisterResourceValue) toParams(address persist.Address) rpc.RegisterResourceParams {
+func (v RegisterResourceValue) toParams(address persist.Address, verifier *ethcommon.Address) rpc.RegisterResourceParams {
 	return rpc.RegisterResourceParams{
 		AccessContext: address,
 		Role:          crypto.Keccak256Hash([]byte(v.Role.RoleID)),
+		PolicyContext: crypto.Keccak256Hash([]byte(v.Policy.ContextID)),
 		Policy:        crypto.Keccak256Hash([]byte(v.Policy.PolicyID)),
 		Permission:    crypto.Keccak256Hash([]byte(v.Permission)),
 		Resource:      crypto.Keccak256Hash([]byte(v.Resource)),
-		Operations:    v.Operations, // read + write
+		Operations:    v.Operations,
+		Verifier:      verifier,
 		DID:           crypto.Keccak256Hash([]byte(v.DID)),
 	}
 }
@@ -88,6 +134,79 @@ func (in RegisterResourceInput) IsValid() bool {
 	return util.IsValidStruct(in) == nil
 }
 
+type RegisterPolicyInput struct {
+	// Id of the policy within the access context of this instance. A random id is generated if empty.
+	Policy string `json:"policy,omitempty"`
+	// Address of the policy verifier contract
+	Verifier string `json:"verifier" validate:"required"`
+	// Id of a role within the access context of this instance to assign the policy to
+	Role string `json:"role,omitempty"`
+}
+
+func (in RegisterPolicyInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
+type RegisterPolicyOutput struct {
+	// Identifier of the registered policy, i.e. `<context did>+<policy id>`
+	Policy   string `json:"policy"`
+	Verifier string `json:"verifier"`
+	Role     string `json:"role,omitempty"`
+}
+
+type AssignPolicyInput struct {
+	// Identifier of the policy, i.e. `<context did>+<policy id>`. A plain id refers to a policy of this instance.
+	Policy string `json:"policy" validate:"required"`
+	// Id of the role within the access context of this instance
+	Role string `json:"role" validate:"required"`
+}
+
+func (in AssignPolicyInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
+type RegisterPermissionInput struct {
+	// Id of the permission within the access context of this instance. A random id is generated if empty.
+	Permission string `json:"permission,omitempty"`
+	// Reference of the resource, e.g. `static/data/emission_report.csv`
+	Resource string `json:"resource" validate:"required"`
+	// Permitted operations on the resource
+	Operations []uint8 `json:"operations" validate:"required,min=1,max=2,dive,max=1"`
+	// Id of a role within the access context of this instance to assign the permission to
+	Role string `json:"role,omitempty"`
+}
+
+func (in RegisterPermissionInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
+type RegisterPermissionOutput struct {
+	Permission StoredPermission `json:"permission"`
+	Role       string           `json:"role,omitempty"`
+}
+
+type PermissionAssignmentInput struct {
+	// Id of the permission within the access context of this instance
+	Permission string `json:"permission" validate:"required"`
+	// Id of the role within the access context of this instance
+	Role string `json:"role" validate:"required"`
+}
+
+func (in PermissionAssignmentInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
+type RevokeRoleInput struct {
+	// Id of the role within the access context of this instance
+	Role string `json:"role" validate:"required"`
+	// DID of the user the role is revoked from
+	DID string `json:"did" validate:"required"`
+}
+
+func (in RevokeRoleInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
 type CreateSessionInput struct {
 	SessionJWE []byte `json:"jwe,omitempty" validate:"required"`
 }
@@ -96,11 +215,26 @@ func (in CreateSessionInput) IsValid() bool {
 	return util.IsValidStruct(in) == nil
 }
 
+type RevokeSessionInput struct {
+	ID string `json:"id" validate:"required"`
+}
+
+func (in RevokeSessionInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
 type VerifySessionInput struct {
 	RoleID       string        `json:"role"`
 	SessionToken keyaccess.JWT `json:"jwt,omitempty" validate:"required"`
 }
 
+type AuthorizeResourceInput struct {
+	// Reference of the requested resource, e.g. `static/data/emission_report.csv`
+	Resource     string        `json:"resource" validate:"required"`
+	Operation    uint8         `json:"operation"`
+	SessionToken keyaccess.JWT `json:"jwt,omitempty" validate:"required"`
+}
+
 type VerifySessionOutput struct {
 	// Whether the Session was verified.
 	Verified bool `json:"verified"`
@@ -0,0 +1,108 @@
+package accesscontrol
+
+import (
+	"context"
+	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+	"github.com/fapiper/onchain-access-control/core/service/indexer"
+	"github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// RunSessionPickup periodically picks up the sessions started on the chain of every indexer until the context is
+// done. It returns immediately when no pickup interval or no indexer is configured.
+func (s Service) RunSessionPickup(ctx context.Context) {
+	interval := s.config.SessionPickupInterval
+	if interval <= 0 || len(s.indexers) == 0 {
+		logrus.Info("session pickup disabled")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, chainIndexer := range s.indexers {
+		wg.Add(1)
+		go func(chainIndexer *indexer.Service) {
+			defer wg.Done()
+			s.runSessionPickup(ctx, interval, chainIndexer)
+		}(chainIndexer)
+	}
+	wg.Wait()
+}
+
+func (s Service) runSessionPickup(ctx context.Context, interval time.Duration, chainIndexer *indexer.Service) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.pickupSessions(ctx, chainIndexer); err != nil {
+				logrus.WithError(err).Error("could not pick up sessions")
+			}
+		}
+	}
+}
+
+// PickupSessions decrypts and stores all active sessions indexed from the session registries whose token is encrypted
+// to a key of this instance. Sessions that fail to be created are retried with the next pickup until they expire.
+func (s Service) PickupSessions(ctx context.Context) error {
+	for _, chainIndexer := range s.indexers {
+		if err := s.pickupSessions(ctx, chainIndexer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pickupSessions picks up the sessions indexed by the indexer of one chain
+func (s Service) pickupSessions(ctx context.Context, chainIndexer *indexer.Service) error {
+	indexed, err := chainIndexer.ListSessions(ctx, indexer.ListSessionsRequest{})
+	if err != nil {
+		return errors.Wrap(err, "listing indexed sessions")
+	}
+
+	for _, session := range indexed.Sessions {
+		if len(session.Token) == 0 {
+			continue
+		}
+		pickedUp, err := s.storageClient.CheckSessionPickedUp(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		if pickedUp {
+			continue
+		}
+
+		if s.isSessionRecipient(ctx, session.Token) {
+			stored, err := s.CreateSession(ctx, CreateSessionInput{SessionJWE: session.Token})
+			if err != nil {
+				logrus.WithError(err).Warnf("could not create session from on-chain session<%s>", session.ID.Hex())
+				continue
+			}
+			logrus.Debugf("picked up session<%s> of %s", stored.ID, stored.Subject)
+		}
+
+		if err = s.storageClient.InsertPickedUpSession(ctx, session.ID); err != nil {
+			return errors.Wrapf(err, "marking session<%s> as picked up", session.ID.Hex())
+		}
+	}
+	return nil
+}
+
+// isSessionRecipient reports whether a session JWE is encrypted to any key held in the keystore of this instance
+func (s Service) isSessionRecipient(ctx context.Context, token []byte) bool {
+	kids, err := keyaccess.GetJWERecipients(token)
+	if err != nil {
+		return false
+	}
+	for _, kid := range kids {
+		if _, err = s.keystore.GetKeyDetails(ctx, keystore.GetKeyDetailsRequest{ID: kid}); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,15 @@
 package accesscontrol
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/TBD54566975/ssi-sdk/did/pkh"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fapiper/onchain-access-control/core/config"
 	didint "github.com/fapiper/onchain-access-control/core/internal/did"
@@ -12,29 +17,35 @@ import (
 	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
 	"github.com/fapiper/onchain-access-control/core/internal/util"
 	"github.com/fapiper/onchain-access-control/core/service/framework"
+	"github.com/fapiper/onchain-access-control/core/service/indexer"
 	"github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/fapiper/onchain-access-control/core/service/operation"
 	"github.com/fapiper/onchain-access-control/core/service/persist"
 	"github.com/fapiper/onchain-access-control/core/service/presentation"
 	"github.com/fapiper/onchain-access-control/core/service/presentation/model"
 	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
 	"github.com/fapiper/onchain-access-control/core/storage"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/google/uuid"
-	shell "github.com/ipfs/go-ipfs-api"
-	"github.com/lestrrat-go/jwx/v2/jwe"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/pkg/errors"
+	"strings"
+	"time"
 )
 
 type ServiceFactory func(storage.Tx) (*Service, error)
 
 type Service struct {
+	config        config.AuthServiceConfig
 	storageClient *Storage
 	presentation  *presentation.Service
-	ipfsClient    *shell.Shell
+	artifacts     ipfs.Store
 	rpcService    *rpc.Service
 	keystore      *keystore.Service
 	resolver      resolution.Resolver
+	indexers      []*indexer.Service
+	tracker       *operation.Trackers
 }
 
 func (s Service) Type() framework.Type {
@@ -46,6 +57,9 @@ func (s Service) Status() framework.Status {
 	if s.storageClient == nil {
 		ae.AppendString("no storage configured")
 	}
+	if s.tracker == nil {
+		ae.AppendString("no transaction tracker configured")
+	}
 	if !ae.IsEmpty() {
 		return framework.Status{
 			Status:  framework.StatusNotReady,
@@ -55,17 +69,22 @@ func (s Service) Status() framework.Status {
 	return framework.Status{Status: framework.StatusReady}
 }
 
-func NewAccessControlService(config config.AuthServiceConfig, s storage.ServiceStorage, p *presentation.Service, r resolution.Resolver, k *keystore.Service, rpcService *rpc.Service, ipfsClient *shell.Shell) (*Service, error) {
+func NewAccessControlService(config config.AuthServiceConfig, s storage.ServiceStorage, p *presentation.Service, r resolution.Resolver, k *keystore.Service, i []*indexer.Service, t *operation.Trackers, rpcService *rpc.Service, artifacts ipfs.Store) (*Service, error) {
 	encrypter, decrypter, err := keystore.NewServiceEncryption(s, config.EncryptionConfig, keystore.ServiceKeyEncryptionKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating new encryption")
 	}
 
-	factory := NewAccessControlServiceFactory(s, p, r, k, encrypter, decrypter, rpcService, ipfsClient)
-	return factory(s)
+	factory := NewAccessControlServiceFactory(config, s, p, r, k, i, t, encrypter, decrypter, rpcService, artifacts)
+	service, err := factory(s)
+	if err != nil {
+		return nil, err
+	}
+	service.RegisterFollowUps()
+	return service, nil
 }
 
-func NewAccessControlServiceFactory(s storage.ServiceStorage, p *presentation.Service, r resolution.Resolver, k *keystore.Service, encrypter encryption.Encrypter, decrypter encryption.Decrypter, rpcService *rpc.Service, ipfsClient *shell.Shell) ServiceFactory {
+func NewAccessControlServiceFactory(config config.AuthServiceConfig, s storage.ServiceStorage, p *presentation.Service, r resolution.Resolver, k *keystore.Service, i []*indexer.Service, t *operation.Trackers, encrypter encryption.Encrypter, decrypter encryption.Decrypter, rpcService *rpc.Service, artifacts ipfs.Store) ServiceFactory {
 	return func(tx storage.Tx) (*Service, error) {
 		// Next, instantiate the key storage
 		sc, err := NewAccessControlStorage(s, encrypter, decrypter, tx)
@@ -75,11 +94,15 @@ func NewAccessControlServiceFactory(s storage.ServiceStorage, p *presentation.Se
 		}
 
 		service := Service{
+			config:        config,
 			storageClient: sc,
+			presentation:  p,
 			keystore:      k,
 			resolver:      r,
+			indexers:      i,
+			tracker:       t,
 			rpcService:    rpcService,
-			ipfsClient:    ipfsClient,
+			artifacts:     artifacts,
 		}
 		if !service.Status().IsReady() {
 			return nil, errors.New(service.Status().Message)
@@ -88,78 +111,180 @@ func NewAccessControlServiceFactory(s storage.ServiceStorage, p *presentation.Se
 	}
 }
 
-// CreatePolicy uploads required policy artifacts to ipfs and deploys and registers an access policy on-chain.
-func (s Service) CreatePolicy(ctx context.Context, request CreatePolicyRequest) (*CreatePolicyResponse, error) {
+// CreatePolicy uploads required policy artifacts to ipfs and deploys and registers an access policy on-chain. The
+// returned operation is done once the policy is registered and stored.
+func (s Service) CreatePolicy(ctx context.Context, request CreatePolicyRequest) (*operation.Operation, error) {
 	if !request.IsValid() {
-		return nil, errors.Errorf("invalid create session request: %+v", request)
+		return nil, errors.Errorf("invalid create policy request: %+v", request)
 	}
 
-	uris, err := s.uploadPolicyArtifactsToIPFS(ctx, request.PresentationDefinitionID, request.Verifier)
+	address, err := s.rpcService.GetAccessContextAddress(s.rpcService.OwnerDID())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get access context address")
+	}
+	if address == persist.ZeroAddress {
+		return nil, errors.New("access context does not exist, create it before creating a policy")
+	}
 
+	uris, err := s.uploadPolicyArtifactsToIPFS(ctx, request.PresentationDefinitionID, request.Verifier)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not upload policy artifacts to ipfs")
 	}
 
-	contract, err := s.deployAndRegisterPolicyContract(ctx, *uris)
+	policy := persist.Policy{
+		ContextID: s.rpcService.OwnerDID(),
+		PolicyID:  uuid.NewString(),
+	}
+
+	bytecode := ethcommon.FromHex(request.Verifier.Bytecode)
+	if len(bytecode) == 0 {
+		return nil, errors.New("policy verifier bytecode is empty")
+	}
+
+	contract, tx, err := s.deployAndRegisterPolicyContract(ctx, address, policy, bytecode, *uris)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not deploy and register policy contract")
 	}
 
-	return &CreatePolicyResponse{PolicyContract: contract, URIs: *uris}, nil
+	followUp, err := operation.NewFollowUp(followUpCreatePolicy, StoredPolicy{
+		ID:                       policy.PolicyID,
+		Context:                  address,
+		Contract:                 contract,
+		PresentationDefinitionID: request.PresentationDefinitionID,
+		URIs:                     *uris,
+		CreatedAt:                time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.tracker.Track(ctx, tx, followUp)
 }
 
-func (s Service) uploadPolicyArtifactsToIPFS(ctx context.Context, definitionRequest *model.GetPresentationDefinitionRequest, verifier PolicyVerifier) (*PolicyURISet, error) {
+// storePolicy stores a policy once it is registered. The verifier is deployed by the transaction sent right before the
+// registration, which is checked to have succeeded as the registration does not call the verifier.
+func (s Service) storePolicy(ctx context.Context, stored StoredPolicy) (*CreatePolicyResponse, error) {
+	code, err := s.rpcService.Wallet.Client.CodeAt(ctx, ethcommon.HexToAddress(string(stored.Contract)), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get code of policy verifier<%s>", stored.Contract)
+	}
+	if len(code) == 0 {
+		return nil, errors.Errorf("policy verifier<%s> was not deployed", stored.Contract)
+	}
 
-	// get policy definition
-	_, err := s.presentation.GetPresentationDefinition(ctx, *definitionRequest)
+	if err = s.storageClient.InsertPolicy(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "could not store policy")
+	}
+	return &CreatePolicyResponse{Policy: stored}, nil
+}
+
+func (s Service) uploadPolicyArtifactsToIPFS(ctx context.Context, definitionID string, verifier PolicyVerifier) (*PolicyURISet, error) {
+	definition, err := s.presentation.GetPresentationDefinition(ctx, model.GetPresentationDefinitionRequest{ID: definitionID})
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get presentation definition object")
 	}
 
-	// upload presentation definition, verifier keys and proof program to ipfs
-	//
+	definitionBytes, err := json.Marshal(definition.PresentationDefinition)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal presentation definition")
+	}
+
+	var uris PolicyURISet
+	artifacts := []struct {
+		name string
+		data []byte
+		uri  *string
+	}{
+		{name: "presentation definition", data: definitionBytes, uri: &uris.PresentationDefinition},
+		{name: "proof program", data: verifier.ProofProgram, uri: &uris.ProofProgram},
+		{name: "proving key", data: verifier.ProvingKey, uri: &uris.ProvingKey},
+		{name: "verification key", data: verifier.VerificationKey, uri: &uris.VerificationKey},
+	}
+	for _, artifact := range artifacts {
+		cid, err := s.artifacts.Put(ctx, bytes.NewReader(artifact.data))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not pin %s", artifact.name)
+		}
+		*artifact.uri = ipfs.URI(cid)
+	}
 
-	return &PolicyURISet{
-		PresentationDefinition: "",
-		ProofProgram:           "",
-		ProvingKey:             "",
-		VerificationKey:        "",
-	}, nil
+	return &uris, nil
 }
 
-func (s Service) deployAndRegisterPolicyContract(ctx context.Context, uris PolicyURISet) (string, error) {
+// deployAndRegisterPolicyContract sends the transactions deploying a policy verifier and registering it, and returns
+// the transaction registering it. The wallet sends them in order, so the verifier is deployed before it is registered.
+func (s Service) deployAndRegisterPolicyContract(ctx context.Context, address persist.Address, policy persist.Policy, bytecode []byte, uris PolicyURISet) (persist.Address, *types.Transaction, error) {
+	contract, _, err := s.rpcService.DeployPolicyVerifier(ctx, uris.toParams(bytecode))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "deploying policy verifier")
+	}
+
+	tx, err := s.rpcService.RegisterPolicy(ctx, rpc.RegisterPolicyParams{
+		AccessContext: address,
+		Policy:        crypto.Keccak256Hash([]byte(policy.PolicyID)),
+		Verifier:      contract,
+		DID:           s.rpcService.OwnerDIDHash(),
+	})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "registering policy verifier<%s>", contract)
+	}
 
-	// send tx: deploy policy with uris
-	// send tx: register policy contract in registry
+	return persist.Address(contract.String()), tx, nil
+}
 
-	return "", nil
+// GetPolicy returns a stored policy by its id
+func (s Service) GetPolicy(ctx context.Context, request GetPolicyRequest) (*GetPolicyResponse, error) {
+	stored, err := s.storageClient.GetPolicy(ctx, request.ID)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "error getting policy: %s", request.ID)
+	}
+	return &GetPolicyResponse{Policy: *stored}, nil
+}
+
+// ListPolicies returns a page of stored policies
+func (s Service) ListPolicies(ctx context.Context, request ListPoliciesRequest) (*ListPoliciesResponse, error) {
+	stored, err := s.storageClient.ListPolicies(ctx, *request.PageRequest.ToServicePage())
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "error getting policies")
+	}
+	return &ListPoliciesResponse{Policies: stored.Policies, NextPageToken: stored.NextPageToken}, nil
 }
 
 // CreateSession houses the main service logic for session token storage.
-// It accepts only requests from trusted parties that are indexing the blockchain state, validates the input, and
-// stores a session entry.
+// It decrypts a session JWE as published in the session registry, validates the session token, and stores a session
+// entry. Sessions are usually picked up from the indexer, see PickupSessions.
 func (s Service) CreateSession(ctx context.Context, request CreateSessionInput) (*StoredSession, error) {
 	if !request.IsValid() {
 		return nil, errors.Errorf("invalid create session request: %+v", request)
 	}
 
-	token, err := s.decryptJWE(ctx, request.SessionJWE, "kid")
+	token, err := s.decryptJWE(ctx, request.SessionJWE)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not decrypt session JWE")
 	}
 
-	return s.createSession(ctx, token)
+	session, err := s.parseSessionToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.createSession(ctx, token, session)
 }
 
-// RegisterResource registers a resource on-chain
-func (s Service) RegisterResource(ctx context.Context, request RegisterResourceInput) (*RegisterResourceOutput, error) {
+// RegisterResource registers a resource on-chain and sets up a role with a policy and a permission for it. The
+// policy is either registered from a verifier contract or an existing policy of any access context. The returned
+// operation is done once the transaction is confirmed and the resource is stored.
+func (s Service) RegisterResource(ctx context.Context, request RegisterResourceInput) (*operation.Operation, error) {
 	if !request.IsValid() {
 		return nil, errors.Errorf("invalid register resource request: %+v", request)
 	}
-	did := s.rpcService.Wallet.GetDID()
-	address, err := s.rpcService.GetAccessContextAddress(s.rpcService.Wallet.GetDIDHash())
+	did := s.rpcService.OwnerDID()
+	address, err := s.getOwnAccessContextAddress()
 	if err != nil {
-		return nil, errors.Wrap(err, "could not get access context address")
+		return nil, err
+	}
+
+	operations := request.Operations
+	if len(operations) == 0 {
+		operations = []uint8{OperationRead, OperationWrite}
 	}
 
 	val := RegisterResourceValue{
@@ -167,100 +292,434 @@ func (s Service) RegisterResource(ctx context.Context, request RegisterResourceI
 			ContextID: did,
 			RoleID:    request.Role,
 		},
-		Policy: persist.Policy{
-			ContextID: did,
-			PolicyID:  uuid.NewString(),
-		},
 		Permission: uuid.NewString(),
-		Resource:   fmt.Sprintf("%s;%s", did, request.Resource),
-		Operations: []uint8{0, 1},
+		Resource:   persist.Resource{DID: did, Ref: request.Resource}.Identifier(),
+		Operations: operations,
 		DID:        did,
 	}
 
-	_, err = s.rpcService.RegisterResource(ctx, val.toParams(address))
+	var verifier *ethcommon.Address
+	if request.Policy != "" {
+		val.Policy = s.parsePolicy(request.Policy)
+	} else {
+		contract, err := parseContractAddress(request.PolicyContract)
+		if err != nil {
+			return nil, err
+		}
+		verifier = &contract
+		val.Policy = persist.Policy{ContextID: did, PolicyID: uuid.NewString()}
+	}
+
+	followUp, err := operation.NewFollowUp(followUpRegisterResource, val)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.RegisterResource(ctx, val.toParams(address, verifier))
 	if err != nil {
 		return nil, errors.Wrap(err, "could not register resource")
 	}
 
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+// storeResource stores the permission and resource of a registered resource
+func (s Service) storeResource(ctx context.Context, val RegisterResourceValue) (*RegisterResourceOutput, error) {
+	err := s.storageClient.InsertPermission(ctx, StoredPermission{
+		ID:         val.Permission,
+		Resource:   val.Resource,
+		Operations: val.Operations,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not store permission")
+	}
+
+	err = s.storageClient.AddResourceAssignment(ctx, val.Resource, ResourceAssignment{
+		Role:       val.Role.RoleID,
+		Policy:     val.Policy.String(),
+		Permission: val.Permission,
+		Operations: val.Operations,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not store resource")
+	}
+
 	out := val.toOut()
 	return &out, nil
 }
 
-// CreateAccessContext creates an access context
-func (s Service) CreateAccessContext(ctx context.Context) (*StoredAccessContext, error) {
-	did := s.rpcService.Wallet.GetDIDHash()
+// RegisterPolicy registers a policy verifier contract as policy in the access context of this instance and optionally
+// assigns it to a role. The output of the returned operation is the registered policy.
+func (s Service) RegisterPolicy(ctx context.Context, request RegisterPolicyInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid register policy request: %+v", request)
+	}
+
+	address, err := s.getOwnAccessContextAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := parseContractAddress(request.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := persist.Policy{ContextID: s.rpcService.OwnerDID(), PolicyID: request.Policy}
+	if policy.PolicyID == "" {
+		policy.PolicyID = uuid.NewString()
+	}
+
+	params := rpc.RegisterPolicyParams{
+		AccessContext: address,
+		Policy:        crypto.Keccak256Hash([]byte(policy.PolicyID)),
+		Verifier:      verifier,
+		DID:           s.rpcService.OwnerDIDHash(),
+	}
+	if request.Role != "" {
+		role := crypto.Keccak256Hash([]byte(request.Role))
+		params.Role = &role
+	}
+
+	followUp, err := operation.NewFollowUp(followUpRegisterPolicy, RegisterPolicyOutput{
+		Policy:   policy.String(),
+		Verifier: verifier.String(),
+		Role:     request.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.RegisterPolicy(ctx, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not register policy<%s>", policy.String())
+	}
+
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+// AssignPolicy assigns an existing policy of any access context to a role of the access context of this instance
+func (s Service) AssignPolicy(ctx context.Context, request AssignPolicyInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid assign policy request: %+v", request)
+	}
+
+	address, err := s.getOwnAccessContextAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.parsePolicy(request.Policy)
+	tx, err := s.rpcService.AssignPolicy(ctx, rpc.AssignPolicyParams{
+		AccessContext: address,
+		PolicyContext: crypto.Keccak256Hash([]byte(policy.ContextID)),
+		Policy:        crypto.Keccak256Hash([]byte(policy.PolicyID)),
+		Role:          crypto.Keccak256Hash([]byte(request.Role)),
+		DID:           s.rpcService.OwnerDIDHash(),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not assign policy<%s> to role<%s>", policy.String(), request.Role)
+	}
+
+	return s.tracker.Track(ctx, tx, nil)
+}
+
+// RegisterPermission registers a permission for operations on a resource in the access context of this instance and
+// optionally assigns it to a role. The returned operation is done once the permission is stored, its output is the
+// registered permission.
+func (s Service) RegisterPermission(ctx context.Context, request RegisterPermissionInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid register permission request: %+v", request)
+	}
+
+	address, err := s.getOwnAccessContextAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	did := s.rpcService.OwnerDID()
+	permission := StoredPermission{
+		ID:         request.Permission,
+		Resource:   persist.Resource{DID: did, Ref: request.Resource}.Identifier(),
+		Operations: request.Operations,
+	}
+	if permission.ID == "" {
+		permission.ID = uuid.NewString()
+	}
+
+	params := rpc.RegisterPermissionParams{
+		AccessContext: address,
+		Permission:    crypto.Keccak256Hash([]byte(permission.ID)),
+		Resource:      crypto.Keccak256Hash([]byte(permission.Resource)),
+		Operations:    permission.Operations,
+		DID:           s.rpcService.OwnerDIDHash(),
+	}
+	if request.Role != "" {
+		role := crypto.Keccak256Hash([]byte(request.Role))
+		params.RoleContext = s.rpcService.OwnerDIDHash()
+		params.Role = &role
+	}
+
+	followUp, err := operation.NewFollowUp(followUpRegisterPermission, RegisterPermissionOutput{Permission: permission, Role: request.Role})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.RegisterPermission(ctx, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not register permission<%s>", permission.ID)
+	}
+
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+// storePermission stores a registered permission along with its assignment to a role, if any
+func (s Service) storePermission(ctx context.Context, out RegisterPermissionOutput) (*RegisterPermissionOutput, error) {
+	if err := s.storageClient.InsertPermission(ctx, out.Permission); err != nil {
+		return nil, errors.Wrap(err, "could not store permission")
+	}
+
+	if out.Role != "" {
+		if err := s.storeResourceAssignment(ctx, out.Permission, out.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	return &out, nil
+}
+
+// AssignPermission assigns a permission of the access context of this instance to a role. The returned operation is
+// done once the assignment is stored.
+func (s Service) AssignPermission(ctx context.Context, request PermissionAssignmentInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid assign permission request: %+v", request)
+	}
+
+	address, err := s.getOwnAccessContextAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := s.storageClient.GetPermission(ctx, request.Permission)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get permission<%s>", request.Permission)
+	}
+
+	followUp, err := operation.NewFollowUp(followUpAssignPermission, permissionAssignment{Permission: *permission, Role: request.Role})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.AssignPermission(ctx, s.permissionAssignmentParams(address, request))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not assign permission<%s> to role<%s>", request.Permission, request.Role)
+	}
+
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+// UnassignPermission removes the assignment of a permission of the access context of this instance from a role. The
+// returned operation is done once the assignment is removed from storage.
+func (s Service) UnassignPermission(ctx context.Context, request PermissionAssignmentInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid unassign permission request: %+v", request)
+	}
+
+	address, err := s.getOwnAccessContextAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := s.storageClient.GetPermission(ctx, request.Permission)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get permission<%s>", request.Permission)
+	}
+
+	followUp, err := operation.NewFollowUp(followUpUnassignPermission, permissionAssignment{Permission: *permission, Role: request.Role})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.UnassignPermission(ctx, s.permissionAssignmentParams(address, request))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not unassign permission<%s> from role<%s>", request.Permission, request.Role)
+	}
+
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+func (s Service) permissionAssignmentParams(address persist.Address, request PermissionAssignmentInput) rpc.PermissionAssignmentParams {
+	return rpc.PermissionAssignmentParams{
+		AccessContext: address,
+		Permission:    crypto.Keccak256Hash([]byte(request.Permission)),
+		RoleContext:   s.rpcService.OwnerDIDHash(),
+		Role:          crypto.Keccak256Hash([]byte(request.Role)),
+		DID:           s.rpcService.OwnerDIDHash(),
+	}
+}
+
+// storeResourceAssignment adds the role a permission is assigned to to the resource of the permission, so that
+// requests on the resource are authorized against that role as well
+func (s Service) storeResourceAssignment(ctx context.Context, permission StoredPermission, role string) error {
+	err := s.storageClient.AddResourceAssignment(ctx, permission.Resource, ResourceAssignment{
+		Role:       role,
+		Permission: permission.ID,
+		Operations: permission.Operations,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not store resource")
+	}
+	return nil
+}
+
+// getOwnAccessContextAddress returns the address of the access context of this instance
+func (s Service) getOwnAccessContextAddress() (persist.Address, error) {
+	address, err := s.rpcService.GetAccessContextAddress(s.rpcService.OwnerDID())
+	if err != nil {
+		return "", errors.Wrap(err, "could not get access context address")
+	}
+	if address == persist.ZeroAddress {
+		return "", errors.New("access context does not exist")
+	}
+	return address, nil
+}
+
+// parsePolicy parses a policy identifier. Plain ids refer to policies of the access context of this instance.
+func (s Service) parsePolicy(id string) persist.Policy {
+	policy, err := persist.ParsePolicyFromIdentifierString(id)
+	if err != nil {
+		return persist.Policy{ContextID: s.rpcService.OwnerDID(), PolicyID: id}
+	}
+	return *policy
+}
+
+func parseContractAddress(address string) (ethcommon.Address, error) {
+	if !ethcommon.IsHexAddress(address) {
+		return ethcommon.Address{}, errors.Errorf("invalid contract address: %s", address)
+	}
+	return ethcommon.HexToAddress(address), nil
+}
+
+// RevokeRole revokes a role of a user within the access context of this instance
+func (s Service) RevokeRole(ctx context.Context, request RevokeRoleInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid revoke role request: %+v", request)
+	}
+
+	address, err := s.rpcService.GetAccessContextAddress(s.rpcService.OwnerDID())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get access context address")
+	}
+	if address == persist.ZeroAddress {
+		return nil, errors.New("access context does not exist")
+	}
+
+	tx, err := s.rpcService.RevokeRole(ctx, rpc.RevokeRoleParams{
+		AccessContext: address,
+		Role:          crypto.Keccak256Hash([]byte(request.Role)),
+		DID:           crypto.Keccak256Hash([]byte(request.DID)),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not revoke role<%s> from did<%s>", request.Role, request.DID)
+	}
+
+	return s.tracker.Track(ctx, tx, nil)
+}
+
+// CreateAccessContext creates the access context of this instance. An existing access context is returned as is.
+// Otherwise, the returned operation tracks the transaction creating it and is done once the context is stored.
+func (s Service) CreateAccessContext(ctx context.Context) (*StoredAccessContext, *operation.Operation, error) {
+	did := s.rpcService.OwnerDIDHash()
 	id := did
 
 	exists, err := s.storageClient.CheckAccessContextExists(ctx, id.String())
 	if err != nil {
-		return nil, errors.Wrap(err, "could not check access context exists")
+		return nil, nil, errors.Wrap(err, "could not check access context exists")
 	}
 
 	if exists {
-		return s.storageClient.GetAccessContext(ctx, id.String())
+		stored, err := s.storageClient.GetAccessContext(ctx, id.String())
+		return stored, nil, err
 	}
 
-	address, err := s.rpcService.GetAccessContextAddress(id)
+	address, err := s.rpcService.GetAccessContextAddress(s.rpcService.OwnerDID())
 	if err != nil {
-		return nil, errors.Wrap(err, "could not get access context address")
+		return nil, nil, errors.Wrap(err, "could not get access context address")
 	}
 	if address != persist.ZeroAddress {
 		return &StoredAccessContext{
 			ID:      id,
 			Address: address,
-		}, nil
+		}, nil, nil
 	}
 
 	salt := [20]byte(random.GetRandomBytes(20))
 
-	_, err = s.rpcService.CreateAccessContext(ctx, rpc.CreateAccessContextParams{
+	tx, err := s.rpcService.CreateAccessContext(ctx, rpc.CreateAccessContextParams{
 		ID:   id,
 		Salt: salt,
 		DID:  did,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "could not create access context")
+		return nil, nil, errors.Wrap(err, "could not create access context")
 	}
-	address, err = s.rpcService.GetAccessContextAddress(id)
+
+	op, err := s.tracker.Track(ctx, tx, &operation.FollowUp{Kind: followUpCreateAccessContext})
+	return nil, op, err
+}
+
+// storeAccessContext stores the access context of this instance once it is created
+func (s Service) storeAccessContext(ctx context.Context) (*StoredAccessContext, error) {
+	address, err := s.rpcService.GetAccessContextAddress(s.rpcService.OwnerDID())
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get access context address")
 	}
 	stored := StoredAccessContext{
-		ID:      did,
+		ID:      s.rpcService.OwnerDIDHash(),
 		Address: address,
 	}
-	err = s.storageClient.InsertAccessContext(ctx, stored)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get access context address")
+	if err = s.storageClient.InsertAccessContext(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "could not store access context")
 	}
-
 	return &stored, nil
 }
 
-func (s Service) decryptJWE(ctx context.Context, jweBytes []byte, kid string) (keyaccess.JWT, error) {
-
-	key, err := s.keystore.GetKey(ctx, keystore.GetKeyRequest{ID: kid})
+// decryptJWE decrypts a JWE with the key of the first recipient that is held in the keystore of this instance.
+func (s Service) decryptJWE(ctx context.Context, jweBytes []byte) (keyaccess.JWT, error) {
+	kids, err := keyaccess.GetJWERecipients(jweBytes)
 	if err != nil {
-		return "", errors.Wrap(err, "getting key from keystore")
+		return "", err
+	}
+	if len(kids) == 0 {
+		return "", errors.New("jwe does not reference any recipient key by kid")
 	}
 
-	jwtBytes, err := jwe.Decrypt(jweBytes, jwe.WithKey(key.Type, key.Key))
-	if err != nil {
-		return "", errors.Wrap(err, "decrypting jwe")
+	for _, kid := range kids {
+		key, err := s.keystore.GetKey(ctx, keystore.GetKeyRequest{ID: kid})
+		if err != nil {
+			continue
+		}
+		jwtBytes, err := keyaccess.DecryptJWE(jweBytes, key.Key)
+		if err != nil {
+			return "", errors.Wrapf(err, "decrypting jwe with key<%s>", kid)
+		}
+		return keyaccess.JWT(jwtBytes), nil
 	}
 
-	return keyaccess.JWT(jwtBytes), nil
+	return "", errors.Errorf("unknown jwe recipients, no key found in keystore for kids: %s", strings.Join(kids, ", "))
 }
 
 func (s Service) VerifySession(ctx context.Context, request VerifySessionInput) (*VerifySessionOutput, error) {
-
-	_, session, err := util.ParseJWT(request.SessionToken)
-
+	session, err := s.parseSessionToken(ctx, request.SessionToken)
 	if err != nil {
 		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
 	}
 
-	granted, err := s.checkRoleForSession(ctx, persist.NewRoleIdentifier(s.rpcService.Wallet.GetDID(), request.RoleID), session)
+	if err = s.validateSessionLifetime(session); err != nil {
+		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
+	}
+
+	granted, err := s.checkRoleForSession(ctx, persist.Role{ContextID: s.rpcService.OwnerDID(), RoleID: request.RoleID}, session)
 	if err != nil {
 		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
 	}
@@ -268,34 +727,181 @@ func (s Service) VerifySession(ctx context.Context, request VerifySessionInput)
 		return &VerifySessionOutput{Verified: false, Reason: "invalid authorization"}, nil
 	}
 
-	_, err = s.storageClient.GetSession(ctx, session.JwtID())
-
-	if err != nil {
-		return &VerifySessionOutput{Verified: true}, nil
+	stored, err := s.storageClient.GetSession(ctx, session.JwtID())
+	if err == nil && stored.Revoked {
+		return &VerifySessionOutput{Verified: false, Reason: "session revoked"}, nil
+	}
+	if err == nil && stored.Expired {
+		return &VerifySessionOutput{Verified: false, Reason: "session expired"}, nil
 	}
 
 	tid := crypto.Keccak256Hash([]byte(session.JwtID()))
 	exists, err := s.rpcService.CheckSession(ctx, rpc.CheckSessionParams{
 		TokenID: tid,
-		DID:     s.rpcService.Wallet.GetDIDHash(),
+		Subject: session.Subject(),
 	})
 
 	if err != nil {
 		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
 	}
 	if !exists {
+		// the session was revoked on-chain by its user, keep the store in line
+		if stored != nil {
+			if err = s.storageClient.RevokeSession(ctx, stored.ID); err != nil {
+				return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
+			}
+		}
 		return &VerifySessionOutput{Verified: false, Reason: "session id not found"}, nil
 	}
 
-	_, err = s.createSession(ctx, request.SessionToken)
+	if stored == nil {
+		if _, err = s.createSession(ctx, request.SessionToken, session); err != nil {
+			return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
+		}
+	}
+
+	return &VerifySessionOutput{Verified: true}, nil
+}
+
+// RevokeSession revokes a session within the access context of this instance on-chain. The returned operation is
+// done once the stored session is marked as revoked.
+func (s Service) RevokeSession(ctx context.Context, request RevokeSessionInput) (*operation.Operation, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid revoke session request: %+v", request)
+	}
+
+	followUp, err := operation.NewFollowUp(followUpRevokeSession, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.revokeSessionOnChain(ctx, request.ID, followUp)
+}
+
+// markSessionRevoked marks a stored session as revoked, sessions that were never picked up are not stored
+func (s Service) markSessionRevoked(ctx context.Context, id string) error {
+	exists, err := s.storageClient.CheckSessionExists(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "could not check session exists")
+	}
+	if !exists {
+		return nil
+	}
+
+	if err = s.storageClient.RevokeSession(ctx, id); err != nil {
+		return errors.Wrap(err, "could not mark session as revoked")
+	}
+	return nil
+}
+
+func (s Service) revokeSessionOnChain(ctx context.Context, id string, followUp *operation.FollowUp) (*operation.Operation, error) {
+	did := s.rpcService.OwnerDIDHash()
+	tx, err := s.rpcService.RevokeContextSession(ctx, rpc.RevokeContextSessionParams{
+		TokenID: crypto.Keccak256Hash([]byte(id)),
+		Context: did,
+		DID:     did,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not revoke session<%s>", id)
+	}
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+// validateSessionLifetime validates the time claims of a session token and caps its lifetime to the configured ttl.
+func (s Service) validateSessionLifetime(session jwt.Token) error {
+	if session.IssuedAt().IsZero() || session.Expiration().IsZero() {
+		return errors.Errorf("session<%s> is missing iat or exp claim", session.JwtID())
+	}
+	if err := jwt.Validate(session); err != nil {
+		return errors.Wrapf(err, "validating session<%s>", session.JwtID())
+	}
+	if time.Now().After(s.sessionExpiry(session)) {
+		return errors.Errorf("session<%s> expired", session.JwtID())
+	}
+	return nil
+}
+
+// sessionExpiry returns the point in time a session expires, which is never later than its issuance plus the ttl.
+func (s Service) sessionExpiry(session jwt.Token) time.Time {
+	expiry := session.IssuedAt().Add(s.config.GetSessionTTL())
+	if exp := session.Expiration(); !exp.IsZero() && exp.Before(expiry) {
+		return exp
+	}
+	return expiry
+}
+
+// AuthorizeResource verifies that a session token carries the requested resource, that its holder has the role the
+// resource is registered for and that the role is permitted to perform the requested operation on-chain.
+func (s Service) AuthorizeResource(ctx context.Context, request AuthorizeResourceInput) (*VerifySessionOutput, error) {
+	session, err := s.parseSessionToken(ctx, request.SessionToken)
+	if err != nil {
+		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
+	}
+
+	claim, ok := session.Get(persist.SessionResourceClaim)
+	if !ok {
+		return &VerifySessionOutput{Verified: false, Reason: "session token does not carry a resource"}, nil
+	}
+	didURL, ok := claim.(string)
+	if !ok {
+		return &VerifySessionOutput{Verified: false, Reason: "session token carries an invalid resource"}, nil
+	}
+	resource, err := persist.ParseResourceFromDIDURL(didURL)
+	if err != nil {
+		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
+	}
+	if resource.DID != s.rpcService.OwnerDID() || resource.Ref != request.Resource {
+		return &VerifySessionOutput{Verified: false, Reason: "session token not valid for the requested resource"}, nil
+	}
+
+	stored, err := s.storageClient.GetResource(ctx, resource.Identifier())
+	if err != nil {
+		return &VerifySessionOutput{Verified: false, Reason: "resource not registered"}, nil
+	}
+
+	contextDID := s.rpcService.OwnerDID()
+	address, err := s.rpcService.GetAccessContextAddress(contextDID)
+	if err != nil {
+		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
+	}
+
+	// the session is authorized by any role assigned to the resource
+	result := &VerifySessionOutput{Verified: false, Reason: "resource has no role assigned"}
+	for _, assignment := range stored.Assignments {
+		result, err = s.authorizeAssignment(ctx, contextDID, address, stored.ID, assignment, request)
+		if err != nil || result.Verified {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// authorizeAssignment verifies that the holder of a session has the role of an assignment of a resource and that the
+// permission of the assignment permits the requested operation on-chain
+func (s Service) authorizeAssignment(ctx context.Context, contextDID string, address persist.Address, resource string, assignment ResourceAssignment, request AuthorizeResourceInput) (*VerifySessionOutput, error) {
+	verified, err := s.VerifySession(ctx, VerifySessionInput{RoleID: assignment.Role, SessionToken: request.SessionToken})
+	if err != nil || !verified.Verified {
+		return verified, err
+	}
+
+	permitted, err := s.rpcService.HasPermission(ctx, rpc.HasPermissionParams{
+		Context:    contextDID,
+		Address:    address,
+		Role:       crypto.Keccak256Hash([]byte(assignment.Role)),
+		Permission: crypto.Keccak256Hash([]byte(assignment.Permission)),
+		Resource:   crypto.Keccak256Hash([]byte(resource)),
+		Operation:  request.Operation,
+	})
 	if err != nil {
 		return &VerifySessionOutput{Verified: false, Reason: err.Error()}, nil
 	}
+	if !permitted {
+		return &VerifySessionOutput{Verified: false, Reason: "operation not permitted"}, nil
+	}
 
 	return &VerifySessionOutput{Verified: true}, nil
 }
 
-func (s Service) checkRoleForSession(ctx context.Context, role persist.RoleIdentifier, session jwt.Token) (bool, error) {
+func (s Service) checkRoleForSession(ctx context.Context, role persist.Role, session jwt.Token) (bool, error) {
 
 	address, err := s.rpcService.GetAccessContextAddress(role.ContextID)
 	if err != nil {
@@ -305,13 +911,16 @@ func (s Service) checkRoleForSession(ctx context.Context, role persist.RoleIdent
 	did := crypto.Keccak256Hash([]byte(session.Subject()))
 
 	return s.rpcService.HasRole(ctx, rpc.HasRoleParams{
+		Context: role.ContextID,
 		Address: address,
-		RoleID:  role.RoleID,
+		RoleID:  crypto.Keccak256Hash([]byte(role.RoleID)),
 		DID:     did,
 	})
 }
 
-func (s Service) createSession(ctx context.Context, token keyaccess.JWT) (*StoredSession, error) {
+// parseSessionToken parses a session token and verifies its signature against the key of its issuer. Claims of a
+// session are only trusted once its signature is verified, whether the session is stored already or not.
+func (s Service) parseSessionToken(ctx context.Context, token keyaccess.JWT) (jwt.Token, error) {
 	signature, session, err := util.ParseJWT(token)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not parse session token")
@@ -322,11 +931,48 @@ func (s Service) createSession(ctx context.Context, token keyaccess.JWT) (*Store
 		return nil, errors.Errorf("missing kid in header of session<%s>", session.JwtID())
 	}
 
-	// verify the token with the did by first resolving the did and getting the public key and next verifying the token
-	if err = didint.VerifyTokenFromDID(ctx, s.resolver, session.Issuer(), kid, token); err != nil {
+	if strings.HasPrefix(session.Issuer(), pkh.DIDPKHPrefix+":") {
+		// a did:pkh carries no keys, the signer is recovered from the signature instead
+		if err = didint.VerifyTokenFromPKH(session.Issuer(), token); err != nil {
+			return nil, errors.Wrapf(err, "verifying token from did<%s>", session.Issuer())
+		}
+	} else if err = didint.VerifyTokenFromDID(ctx, s.resolver, session.Issuer(), kid, token); err != nil {
+		// verify the token with the did by first resolving the did and getting the public key and next verifying the token
 		return nil, errors.Wrapf(err, "verifying token from did<%s> with kid<%s>", session.Issuer(), kid)
 	}
+	if err = s.checkIssuerControlsSubject(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// checkIssuerControlsSubject checks that a session issued by another DID than the one it belongs to is issued by the
+// current controller of that DID, which is the wallet acting for an owner DID after its rotation
+func (s Service) checkIssuerControlsSubject(ctx context.Context, session jwt.Token) error {
+	if session.Subject() == session.Issuer() {
+		return nil
+	}
+	issuerChainID, pkhIssuer, err := rpc.ChainIDOf(session.Issuer())
+	if err != nil || !pkhIssuer {
+		return errors.Errorf("issuer<%s> of session<%s> is not an account that can control subject<%s>", session.Issuer(), session.JwtID(), session.Subject())
+	}
+	chain, err := s.rpcService.ChainOf(session.Subject())
+	if err != nil {
+		return errors.Wrapf(err, "getting chain of subject<%s>", session.Subject())
+	}
+	_, current, err := s.rpcService.GetControllers(ctx, session.Subject())
+	if err != nil {
+		return errors.Wrapf(err, "getting controllers of subject<%s>", session.Subject())
+	}
+	issuer := ethcommon.HexToAddress(session.Issuer()[strings.LastIndex(session.Issuer(), ":")+1:])
+	if issuerChainID != chain.ChainID() || issuer != current {
+		return errors.Errorf("issuer<%s> of session<%s> is not the current controller of subject<%s>", session.Issuer(), session.JwtID(), session.Subject())
+	}
+	return nil
+}
 
+// createSession stores a session of a verified session token
+func (s Service) createSession(ctx context.Context, token keyaccess.JWT, session jwt.Token) (*StoredSession, error) {
 	storedSession := StoredSession{
 		ID:         session.JwtID(),
 		Audience:   session.Audience(),
@@ -336,9 +982,10 @@ func (s Service) createSession(ctx context.Context, token keyaccess.JWT) (*Store
 		CreatedAt:  session.IssuedAt(),
 		Revoked:    false,
 		Expired:    false,
+		ExpiresAt:  s.sessionExpiry(session),
 	}
 
-	if s.storageClient.InsertSession(ctx, storedSession) != nil {
+	if err := s.storageClient.InsertSession(ctx, storedSession); err != nil {
 		return nil, errors.Wrap(err, "storing session token")
 	}
 
@@ -0,0 +1,564 @@
+package accesscontrol
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/deploy"
+	didint "github.com/fapiper/onchain-access-control/core/internal/did"
+	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+	"github.com/fapiper/onchain-access-control/core/server/pagination"
+	"github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/fapiper/onchain-access-control/core/service/operation"
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/service/presentation"
+	"github.com/fapiper/onchain-access-control/core/service/presentation/model"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
+	"github.com/fapiper/onchain-access-control/core/service/schema"
+	"github.com/fapiper/onchain-access-control/core/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.EnableSchemaCaching()
+	os.Exit(m.Run())
+}
+
+// newTestService returns an access control service with a funded wallet on a simulated chain, whose trackers run
+// until the test ends. The key of the wallet is returned to sign sessions of the owner.
+func newTestService(t *testing.T) (*Service, *ecdsa.PrivateKey) {
+	key, backend := testutil.NewFundedKey(t)
+	rpcService, err := rpc.NewRPCServiceWithConfig(rpc.NewSimulatedBackend(backend), rpc.SimulatedConfig(key), nil)
+	require.NoError(t, err)
+
+	db := testutil.TestDatabases[0].ServiceStorage(t)
+	trackers, err := operation.NewTrackers(config.OperationServiceConfig{Confirmations: 1, PollInterval: 10 * time.Millisecond}, db, rpcService)
+	require.NoError(t, err)
+
+	keyStore, err := keystore.NewKeyStoreService(config.KeyStoreServiceConfig{}, db)
+	require.NoError(t, err)
+	resolver, err := didint.BuildMultiMethodResolver([]string{"key"})
+	require.NoError(t, err)
+	schemaService, err := schema.NewSchemaService(db, keyStore, resolver)
+	require.NoError(t, err)
+	presentationService, err := presentation.NewPresentationService(db, resolver, schemaService, keyStore)
+	require.NoError(t, err)
+	artifacts, err := ipfs.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	s, err := NewAccessControlService(config.AuthServiceConfig{SessionTTL: time.Hour}, db, presentationService, resolver, keyStore, nil, trackers, rpcService, artifacts)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		trackers.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return s, key
+}
+
+// deployTestContracts deploys the contract suite with the wallet of the service as the controller of its did,
+// skipping the test for bindings generated without bytecode
+func deployTestContracts(t *testing.T, s *Service) {
+	if contracts.AccessContextHandlerMetaData.Bin == "" {
+		t.Skip("the contract bindings carry no bytecode, compile the contracts and run make abi")
+	}
+	addresses, err := deploy.Deploy(context.Background(), s.rpcService, deploy.Addresses{})
+	require.NoError(t, err)
+
+	s.rpcService.DIDRegistry = persist.Address(addresses.DIDRegistry.Hex())
+	s.rpcService.ContextHandler = persist.Address(addresses.ContextHandler.Hex())
+	s.rpcService.SessionRegistry = persist.Address(addresses.SessionRegistry.Hex())
+}
+
+// createTestAccessContext creates the access context of the service on the deployed contracts
+func createTestAccessContext(t *testing.T, s *Service) {
+	_, op, err := s.CreateAccessContext(context.Background())
+	awaitOperation(t, s, op, err)
+}
+
+// grantTestRole grants a role of the access context of the service to a did. The role has no policy assigned, so it is
+// granted without proofs.
+func grantTestRole(t *testing.T, s *Service, role, did string) {
+	tx, err := s.rpcService.GrantRole(context.Background(), rpc.GrantRoleParams{
+		RoleIdentifier: persist.NewRoleIdentifier(s.rpcService.OwnerDID(), role),
+		DID:            ethcrypto.Keccak256Hash([]byte(did)),
+	})
+	requireMined(t, s, tx, err)
+}
+
+// hasTestRole returns whether a did has a role of the access context of the service
+func hasTestRole(t *testing.T, s *Service, role, did string) bool {
+	address, err := s.getOwnAccessContextAddress()
+	require.NoError(t, err)
+	has, err := s.rpcService.HasRole(context.Background(), rpc.HasRoleParams{
+		Context: s.rpcService.OwnerDID(),
+		Address: address,
+		RoleID:  ethcrypto.Keccak256Hash([]byte(role)),
+		DID:     ethcrypto.Keccak256Hash([]byte(did)),
+	})
+	require.NoError(t, err)
+	return has
+}
+
+// startTestSession starts a session of the wallet of the service in its access context
+func startTestSession(t *testing.T, s *Service, id string) {
+	tx, err := s.rpcService.StartSession(context.Background(), rpc.StartSessionParams{
+		DID:        s.rpcService.OwnerDIDHash(),
+		Context:    s.rpcService.OwnerDIDHash(),
+		TokenID:    ethcrypto.Keccak256Hash([]byte(id)),
+		SessionJWE: []byte("session"),
+		Duration:   time.Hour,
+	})
+	requireMined(t, s, tx, err)
+}
+
+// hasTestSession returns whether a session of the wallet of the service is valid on-chain
+func hasTestSession(t *testing.T, s *Service, id string) bool {
+	valid, err := s.rpcService.CheckSession(context.Background(), rpc.CheckSessionParams{
+		TokenID: ethcrypto.Keccak256Hash([]byte(id)),
+		Subject: s.rpcService.OwnerDID(),
+	})
+	require.NoError(t, err)
+	return valid
+}
+
+func requireMined(t *testing.T, s *Service, tx *types.Transaction, err error) {
+	require.NoError(t, err)
+	receipt, err := s.rpcService.WaitMined(context.Background(), tx)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+// awaitOperation waits until an operation is done and requires it to have succeeded
+func awaitOperation(t *testing.T, s *Service, op *operation.Operation, err error) *operation.Operation {
+	require.NoError(t, err)
+	require.NotNil(t, op)
+
+	operations, err := operation.NewOperationService(s.storageClient.db)
+	require.NoError(t, err)
+	var done *operation.Operation
+	require.Eventually(t, func() bool {
+		done, err = operations.GetOperation(context.Background(), operation.GetOperationRequest{ID: op.ID})
+		require.NoError(t, err)
+		return done.Done
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Empty(t, done.Result.Error)
+	return done
+}
+
+func TestValidateSessionLifetime(t *testing.T) {
+	s := Service{config: config.AuthServiceConfig{SessionTTL: time.Hour}}
+
+	buildSession := func(t *testing.T, iat, exp time.Time) jwt.Token {
+		builder := jwt.NewBuilder().JwtID("test-session")
+		if !iat.IsZero() {
+			builder = builder.IssuedAt(iat).NotBefore(iat)
+		}
+		if !exp.IsZero() {
+			builder = builder.Expiration(exp)
+		}
+		session, err := builder.Build()
+		require.NoError(t, err)
+		return session
+	}
+
+	t.Run("valid session", func(t *testing.T) {
+		now := time.Now()
+		session := buildSession(t, now, now.Add(30*time.Minute))
+		assert.NoError(t, s.validateSessionLifetime(session))
+		assert.WithinDuration(t, now.Add(30*time.Minute), s.sessionExpiry(session), time.Second)
+	})
+
+	t.Run("missing claims", func(t *testing.T) {
+		session := buildSession(t, time.Time{}, time.Time{})
+		assert.ErrorContains(t, s.validateSessionLifetime(session), "missing iat or exp claim")
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		now := time.Now()
+		session := buildSession(t, now.Add(-2*time.Hour), now.Add(-time.Hour))
+		assert.Error(t, s.validateSessionLifetime(session))
+	})
+
+	t.Run("lifetime is capped to the ttl", func(t *testing.T) {
+		now := time.Now()
+		session := buildSession(t, now.Add(-2*time.Hour), now.Add(24*time.Hour))
+		assert.ErrorContains(t, s.validateSessionLifetime(session), "expired")
+		assert.WithinDuration(t, now.Add(-time.Hour), s.sessionExpiry(session), time.Second)
+	})
+}
+
+func TestRegisterResourceInputIsValid(t *testing.T) {
+	verifier := "0x04756f72242049Eb05A0BAADa41E0F46828122cD"
+
+	t.Run("new policy from verifier", func(t *testing.T) {
+		in := RegisterResourceInput{Role: "role", PolicyContract: verifier, Resource: "data.csv"}
+		assert.True(t, in.IsValid())
+	})
+
+	t.Run("existing policy", func(t *testing.T) {
+		in := RegisterResourceInput{Role: "role", Policy: "did:key:z6Mk+policy", Resource: "data.csv", Operations: []uint8{OperationRead}}
+		assert.True(t, in.IsValid())
+	})
+
+	t.Run("missing policy", func(t *testing.T) {
+		in := RegisterResourceInput{Role: "role", Resource: "data.csv"}
+		assert.False(t, in.IsValid())
+	})
+
+	t.Run("policy and verifier", func(t *testing.T) {
+		in := RegisterResourceInput{Role: "role", PolicyContract: verifier, Policy: "policy", Resource: "data.csv"}
+		assert.False(t, in.IsValid())
+	})
+
+	t.Run("unknown operation", func(t *testing.T) {
+		in := RegisterResourceInput{Role: "role", PolicyContract: verifier, Resource: "data.csv", Operations: []uint8{2}}
+		assert.False(t, in.IsValid())
+	})
+}
+
+// signSessionToken returns a session token issued and signed by a did:pkh, which belongs to the subject or to the
+// issuer if the subject is empty
+func signSessionToken(t *testing.T, resource, subject string) keyaccess.JWT {
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	return signSessionTokenWithKey(t, key, resource, subject)
+}
+
+// signSessionTokenWithKey returns a session token issued and signed by the did:pkh of key
+func signSessionTokenWithKey(t *testing.T, key *ecdsa.PrivateKey, resource, subject string) keyaccess.JWT {
+	did := fmt.Sprintf("did:pkh:eip155:1337:%s", ethcrypto.PubkeyToAddress(key.PublicKey))
+	if subject == "" {
+		subject = did
+	}
+
+	now := time.Now()
+	token, err := jwt.NewBuilder().
+		Issuer(did).
+		Subject(subject).
+		JwtID("session").
+		IssuedAt(now).
+		Expiration(now.Add(time.Hour)).
+		Claim(persist.SessionResourceClaim, resource).
+		Build()
+	require.NoError(t, err)
+	headers := jws.NewHeaders()
+	require.NoError(t, headers.Set(jws.KeyIDKey, did+"#blockchainAccountId"))
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256K, key, jws.WithProtectedHeaders(headers)))
+	require.NoError(t, err)
+	return keyaccess.JWT(signed)
+}
+
+func TestParseSessionToken(t *testing.T) {
+	ctx := context.Background()
+	s := Service{config: config.AuthServiceConfig{SessionTTL: time.Hour}}
+	token := signSessionToken(t, "did:pkh:eip155:1337:0x1/data.csv", "")
+
+	t.Run("verified token", func(tt *testing.T) {
+		session, err := s.parseSessionToken(ctx, token)
+		require.NoError(tt, err)
+		assert.Equal(tt, "session", session.JwtID())
+	})
+
+	// a forged token carries the claims of a known session under a signature not made by its issuer
+	parts := strings.Split(string(token), ".")
+	signature := []byte(parts[2])
+	if signature[0] = 'A'; parts[2][0] == 'A' {
+		signature[0] = 'B'
+	}
+	forged := keyaccess.JWT(strings.Join([]string{parts[0], parts[1], string(signature)}, "."))
+
+	t.Run("tampered signature", func(tt *testing.T) {
+		_, err := s.parseSessionToken(ctx, forged)
+		assert.Error(tt, err)
+	})
+
+	t.Run("sessions with a tampered signature are rejected", func(tt *testing.T) {
+		verified, err := s.VerifySession(ctx, VerifySessionInput{RoleID: "role", SessionToken: forged})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Contains(tt, verified.Reason, "verifying token")
+
+		verified, err = s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "data.csv", SessionToken: forged})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Contains(tt, verified.Reason, "verifying token")
+	})
+
+	t.Run("sessions of another did are only trusted from its current controller", func(tt *testing.T) {
+		key, backend := testutil.NewFundedKey(tt)
+		rpcService, err := rpc.NewRPCServiceWithConfig(rpc.NewSimulatedBackend(backend), rpc.SimulatedConfig(key), nil)
+		require.NoError(tt, err)
+		s := Service{config: s.config, rpcService: rpcService}
+
+		owner := "did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2"
+		_, err = s.parseSessionToken(ctx, signSessionToken(tt, owner+"/data.csv", owner))
+		assert.ErrorIs(tt, err, rpc.ErrNoDIDRegistry)
+	})
+}
+
+func TestCreatePolicy(t *testing.T) {
+	ctx := context.Background()
+	verifier := PolicyVerifier{
+		Bytecode:        "0x00",
+		ProofProgram:    []byte("program"),
+		ProvingKey:      []byte("proving.key"),
+		VerificationKey: []byte("verification.key"),
+	}
+
+	t.Run("invalid request", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		_, err := s.CreatePolicy(ctx, CreatePolicyRequest{Verifier: verifier})
+		assert.ErrorContains(tt, err, "invalid create policy request")
+	})
+
+	t.Run("policies need an access context", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		deployTestContracts(tt, s)
+
+		_, err := s.CreatePolicy(ctx, CreatePolicyRequest{PresentationDefinitionID: "definition", Verifier: verifier})
+		assert.ErrorContains(tt, err, "access context does not exist")
+	})
+
+	t.Run("deploys, registers and stores a policy", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		deployTestContracts(tt, s)
+		createTestAccessContext(tt, s)
+
+		definition, err := s.presentation.CreatePresentationDefinition(ctx, model.CreatePresentationDefinitionRequest{
+			PresentationDefinition: exchange.PresentationDefinition{
+				ID: "definition",
+				InputDescriptors: []exchange.InputDescriptor{{
+					ID:          "descriptor",
+					Constraints: &exchange.Constraints{Fields: []exchange.Field{{Path: []string{"$.credentialSubject.birthdate"}}}},
+				}},
+			},
+		})
+		require.NoError(tt, err)
+
+		verifier.Bytecode = contracts.PolicyVerifierMetaData.Bin
+		op, err := s.CreatePolicy(ctx, CreatePolicyRequest{PresentationDefinitionID: definition.PresentationDefinition.ID, Verifier: verifier})
+		awaitOperation(tt, s, op, err)
+
+		policies, err := s.ListPolicies(ctx, ListPoliciesRequest{})
+		require.NoError(tt, err)
+		require.Len(tt, policies.Policies, 1)
+		policy := policies.Policies[0]
+		assert.Equal(tt, definition.PresentationDefinition.ID, policy.PresentationDefinitionID)
+
+		stored, err := s.GetPolicy(ctx, GetPolicyRequest{ID: policy.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, policy.Contract, stored.Policy.Contract)
+
+		// the artifacts are pinned under the uris the verifier is deployed with
+		provingKey, err := ipfs.ReadURI(ctx, s.artifacts, policy.URIs.ProvingKey)
+		require.NoError(tt, err)
+		assert.Equal(tt, verifier.ProvingKey, provingKey)
+		uris, err := s.rpcService.GetPolicyVerifierURIs(ctx, rpc.PolicyVerifierParams{Context: s.rpcService.OwnerDID(), Verifier: policy.Contract.Address()})
+		require.NoError(tt, err)
+		assert.Equal(tt, policy.URIs.ProvingKey, uris.ProvingKey)
+	})
+}
+
+func TestListPolicies(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t)
+	for _, id := range []string{"policy-a", "policy-b", "policy-c"} {
+		require.NoError(t, s.storageClient.InsertPolicy(ctx, StoredPolicy{ID: id, CreatedAt: time.Now()}))
+	}
+
+	t.Run("lists all policies", func(tt *testing.T) {
+		policies, err := s.ListPolicies(ctx, ListPoliciesRequest{})
+		require.NoError(tt, err)
+		assert.Len(tt, policies.Policies, 3)
+		assert.Empty(tt, policies.NextPageToken)
+	})
+
+	t.Run("pages through policies", func(tt *testing.T) {
+		size := 2
+		first, err := s.ListPolicies(ctx, ListPoliciesRequest{PageRequest: &pagination.PageRequest{PageSize: &size}})
+		require.NoError(tt, err)
+		require.Len(tt, first.Policies, 2)
+		require.NotEmpty(tt, first.NextPageToken)
+
+		second, err := s.ListPolicies(ctx, ListPoliciesRequest{PageRequest: &pagination.PageRequest{PageSize: &size, PageToken: &first.NextPageToken}})
+		require.NoError(tt, err)
+		require.Len(tt, second.Policies, 1)
+		assert.Empty(tt, second.NextPageToken)
+
+		ids := []string{first.Policies[0].ID, first.Policies[1].ID, second.Policies[0].ID}
+		assert.ElementsMatch(tt, []string{"policy-a", "policy-b", "policy-c"}, ids)
+	})
+
+	t.Run("invalid page token", func(tt *testing.T) {
+		token := "not a page token"
+		_, err := s.ListPolicies(ctx, ListPoliciesRequest{PageRequest: &pagination.PageRequest{PageToken: &token}})
+		assert.Error(tt, err)
+	})
+}
+
+func TestRevokeRole(t *testing.T) {
+	ctx := context.Background()
+	user := "did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2"
+
+	t.Run("invalid request", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		_, err := s.RevokeRole(ctx, RevokeRoleInput{Role: "reader"})
+		assert.ErrorContains(tt, err, "invalid revoke role request")
+	})
+
+	t.Run("roles are revoked within an access context", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		deployTestContracts(tt, s)
+
+		_, err := s.RevokeRole(ctx, RevokeRoleInput{Role: "reader", DID: user})
+		assert.ErrorContains(tt, err, "access context does not exist")
+	})
+
+	t.Run("revokes the role of a user", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		deployTestContracts(tt, s)
+		createTestAccessContext(tt, s)
+		grantTestRole(tt, s, "reader", user)
+		grantTestRole(tt, s, "writer", user)
+		require.True(tt, hasTestRole(tt, s, "reader", user))
+
+		op, err := s.RevokeRole(ctx, RevokeRoleInput{Role: "reader", DID: user})
+		awaitOperation(tt, s, op, err)
+
+		assert.False(tt, hasTestRole(tt, s, "reader", user))
+		assert.True(tt, hasTestRole(tt, s, "writer", user))
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid request", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		_, err := s.RevokeSession(ctx, RevokeSessionInput{})
+		assert.ErrorContains(tt, err, "invalid revoke session request")
+	})
+
+	t.Run("stored sessions are marked as revoked", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		require.NoError(tt, s.storageClient.InsertSession(ctx, StoredSession{ID: "session", CreatedAt: time.Now()}))
+
+		require.NoError(tt, s.markSessionRevoked(ctx, "session"))
+		stored, err := s.storageClient.GetSession(ctx, "session")
+		require.NoError(tt, err)
+		assert.True(tt, stored.Revoked)
+
+		// sessions that were never picked up are revoked on-chain only
+		assert.NoError(tt, s.markSessionRevoked(ctx, "unknown"))
+	})
+
+	t.Run("sessions are revoked only if they exist", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		deployTestContracts(tt, s)
+		createTestAccessContext(tt, s)
+
+		_, err := s.RevokeSession(ctx, RevokeSessionInput{ID: "unknown"})
+		assert.ErrorContains(tt, err, "could not revoke session<unknown>")
+	})
+
+	t.Run("revokes a session on-chain and in storage", func(tt *testing.T) {
+		s, _ := newTestService(tt)
+		deployTestContracts(tt, s)
+		createTestAccessContext(tt, s)
+		startTestSession(tt, s, "session")
+		require.NoError(tt, s.storageClient.InsertSession(ctx, StoredSession{ID: "session", CreatedAt: time.Now()}))
+		require.True(tt, hasTestSession(tt, s, "session"))
+
+		op, err := s.RevokeSession(ctx, RevokeSessionInput{ID: "session"})
+		awaitOperation(tt, s, op, err)
+
+		assert.False(tt, hasTestSession(tt, s, "session"))
+		stored, err := s.storageClient.GetSession(ctx, "session")
+		require.NoError(tt, err)
+		assert.True(tt, stored.Revoked)
+	})
+}
+
+func TestAuthorizeResource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sessions are valid for their resource only", func(tt *testing.T) {
+		s, key := newTestService(tt)
+		resource := persist.Resource{DID: s.rpcService.OwnerDID(), Ref: "data.csv"}
+		token := signSessionTokenWithKey(tt, key, resource.String(), "")
+
+		verified, err := s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "other.csv", SessionToken: token})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Equal(tt, "session token not valid for the requested resource", verified.Reason)
+
+		other := persist.Resource{DID: "did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2", Ref: "data.csv"}
+		token = signSessionTokenWithKey(tt, key, other.String(), "")
+		verified, err = s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "data.csv", SessionToken: token})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Equal(tt, "session token not valid for the requested resource", verified.Reason)
+	})
+
+	t.Run("resources must be registered", func(tt *testing.T) {
+		s, key := newTestService(tt)
+		resource := persist.Resource{DID: s.rpcService.OwnerDID(), Ref: "data.csv"}
+		token := signSessionTokenWithKey(tt, key, resource.String(), "")
+
+		verified, err := s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "data.csv", SessionToken: token})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Equal(tt, "resource not registered", verified.Reason)
+	})
+
+	t.Run("authorizes the permitted operations of a role", func(tt *testing.T) {
+		s, key := newTestService(tt)
+		deployTestContracts(tt, s)
+		createTestAccessContext(tt, s)
+		resource := persist.Resource{DID: s.rpcService.OwnerDID(), Ref: "data.csv"}
+		token := signSessionTokenWithKey(tt, key, resource.String(), "")
+
+		op, err := s.RegisterPermission(ctx, RegisterPermissionInput{Resource: "data.csv", Operations: []uint8{OperationRead}, Role: "reader"})
+		awaitOperation(tt, s, op, err)
+		startTestSession(tt, s, "session")
+
+		// the session holder needs the role the resource is assigned to
+		verified, err := s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "data.csv", Operation: OperationRead, SessionToken: token})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Equal(tt, "invalid authorization", verified.Reason)
+
+		grantTestRole(tt, s, "reader", s.rpcService.OwnerDID())
+		verified, err = s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "data.csv", Operation: OperationRead, SessionToken: token})
+		require.NoError(tt, err)
+		assert.True(tt, verified.Verified, verified.Reason)
+
+		verified, err = s.AuthorizeResource(ctx, AuthorizeResourceInput{Resource: "data.csv", Operation: OperationWrite, SessionToken: token})
+		require.NoError(tt, err)
+		assert.False(tt, verified.Verified)
+		assert.Equal(tt, "operation not permitted", verified.Reason)
+	})
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
-	"github.com/ethereum/go-ethereum/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/fapiper/onchain-access-control/core/internal/encryption"
 	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+	"github.com/fapiper/onchain-access-control/core/service/common"
 	"github.com/fapiper/onchain-access-control/core/service/persist"
 	"github.com/fapiper/onchain-access-control/core/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"strings"
 	"time"
 )
 
 type StoredAccessContext struct {
-	ID      common.Hash     `json:"id"`
+	ID      ethcommon.Hash  `json:"id"`
 	Address persist.Address `json:"address,omitempty"`
 }
 
@@ -30,10 +34,65 @@ type StoredSession struct {
 	ExpiresAt  time.Time     `json:"expiresAt"`
 }
 
+type StoredPolicy struct {
+	ID                       string          `json:"id"`
+	Context                  persist.Address `json:"context"`
+	Contract                 persist.Address `json:"contract"`
+	PresentationDefinitionID string          `json:"presentationDefinitionId"`
+	URIs                     PolicyURISet    `json:"uris"`
+	CreatedAt                time.Time       `json:"createdAt"`
+}
+
+type StoredResource struct {
+	// Identifier of the resource within the access context, i.e. `<did>;<ref>`
+	ID string `json:"id"`
+	// Assignments are the roles permitted to operate on the resource, each through one permission
+	Assignments []ResourceAssignment `json:"assignments"`
+}
+
+// ResourceAssignment is a permission on a resource assigned to a role
+type ResourceAssignment struct {
+	Role       string  `json:"role"`
+	Policy     string  `json:"policy,omitempty"`
+	Permission string  `json:"permission"`
+	Operations []uint8 `json:"operations"`
+}
+
+// withoutAssignment returns the assignments of the resource other than the one of role and permission
+func (r StoredResource) withoutAssignment(role, permission string) []ResourceAssignment {
+	assignments := make([]ResourceAssignment, 0, len(r.Assignments))
+	for _, assignment := range r.Assignments {
+		if assignment.Role != role || assignment.Permission != permission {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments
+}
+
+type StoredPermission struct {
+	ID string `json:"id"`
+	// Identifier of the resource within the access context, i.e. `<did>;<ref>`
+	Resource   string  `json:"resource"`
+	Operations []uint8 `json:"operations"`
+}
+
+type StoredPolicies struct {
+	Policies      []StoredPolicy
+	NextPageToken string
+}
+
 const (
 	namespace = "accesscontrol"
 )
 
+var (
+	sessionNamespace    = storage.Join(namespace, "session")
+	policyNamespace     = storage.Join(namespace, "policy")
+	resourceNamespace   = storage.Join(namespace, "resource")
+	permissionNamespace = storage.Join(namespace, "permission")
+	pickupNamespace     = storage.Join(namespace, "pickup")
+)
+
 type Storage struct {
 	db        storage.ServiceStorage
 	tx        storage.Tx
@@ -117,11 +176,11 @@ func (s *Storage) InsertSession(ctx context.Context, session StoredSession) erro
 		return sdkutil.LoggingErrorMsgf(err, "could not encrypt session: %s", session.ID)
 	}
 
-	return s.tx.Write(ctx, namespace, id, encryptedSession)
+	return s.tx.Write(ctx, sessionNamespace, id, encryptedSession)
 }
 
 func (s *Storage) GetSession(ctx context.Context, id string) (*StoredSession, error) {
-	storedSessionBytes, err := s.db.Read(ctx, namespace, id)
+	storedSessionBytes, err := s.db.Read(ctx, sessionNamespace, id)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "getting session details for session <%s>", id)
 	}
@@ -141,3 +200,285 @@ func (s *Storage) GetSession(ctx context.Context, id string) (*StoredSession, er
 	}
 	return &stored, nil
 }
+
+// ListSessions returns all stored sessions. It will return those it can even if it has trouble with some.
+func (s *Storage) ListSessions(ctx context.Context) ([]StoredSession, error) {
+	gotSessions, err := s.db.ReadAll(ctx, sessionNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading all sessions")
+	}
+
+	stored := make([]StoredSession, 0, len(gotSessions))
+	for id, sessionBytes := range gotSessions {
+		decryptedSession, err := s.decrypter.Decrypt(ctx, sessionBytes, nil)
+		if err != nil {
+			logrus.WithError(err).Errorf("could not decrypt session: %s", id)
+			continue
+		}
+		var nextSession StoredSession
+		if err = json.Unmarshal(decryptedSession, &nextSession); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored session: %s", id)
+			continue
+		}
+		stored = append(stored, nextSession)
+	}
+	return stored, nil
+}
+
+func (s *Storage) CheckSessionExists(ctx context.Context, id string) (bool, error) {
+	storedSessionBytes, err := s.db.Read(ctx, sessionNamespace, id)
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "getting session details for session <%s>", id)
+	}
+	return len(storedSessionBytes) > 0, nil
+}
+
+// MigrateSessions moves the sessions that earlier versions stored next to the access contexts into the session
+// namespace, where they are verified and swept. Sessions already in the session namespace are kept. It returns the
+// number of sessions moved.
+func (s *Storage) MigrateSessions(ctx context.Context) (int, error) {
+	stored, err := s.db.ReadAll(ctx, namespace)
+	if err != nil {
+		return 0, errors.Wrap(err, "reading all access contexts")
+	}
+
+	migrated := 0
+	for id, storedBytes := range stored {
+		// some providers list the entries of the nested namespaces as well
+		if strings.Contains(id, ":") || isStoredAccessContext(id, storedBytes) {
+			continue
+		}
+		decryptedSession, err := s.decrypter.Decrypt(ctx, storedBytes, nil)
+		if err != nil {
+			logrus.WithError(err).Warnf("could not decrypt stored entry <%s> of namespace<%s>", id, namespace)
+			continue
+		}
+		var session StoredSession
+		if err = json.Unmarshal(decryptedSession, &session); err != nil || session.ID != id {
+			logrus.Warnf("skipping stored entry <%s> of namespace<%s> which is not a session", id, namespace)
+			continue
+		}
+
+		exists, err := s.db.Exists(ctx, sessionNamespace, id)
+		if err != nil {
+			return migrated, sdkutil.LoggingErrorMsgf(err, "checking session <%s>", id)
+		}
+		if !exists {
+			if err = s.tx.Write(ctx, sessionNamespace, id, storedBytes); err != nil {
+				return migrated, sdkutil.LoggingErrorMsgf(err, "moving session <%s>", id)
+			}
+		}
+		if err = s.tx.Delete(ctx, namespace, id); err != nil {
+			return migrated, sdkutil.LoggingErrorMsgf(err, "deleting moved session <%s>", id)
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// isStoredAccessContext tells whether an entry of the access control namespace is an access context, which is stored
+// under its ID
+func isStoredAccessContext(id string, storedBytes []byte) bool {
+	var access StoredAccessContext
+	return json.Unmarshal(storedBytes, &access) == nil && access.ID.String() == id
+}
+
+// InsertPickedUpSession remembers an on-chain session, so that it is not picked up again.
+func (s *Storage) InsertPickedUpSession(ctx context.Context, id ethcommon.Hash) error {
+	return s.tx.Write(ctx, pickupNamespace, id.Hex(), []byte(time.Now().Format(time.RFC3339)))
+}
+
+func (s *Storage) CheckSessionPickedUp(ctx context.Context, id ethcommon.Hash) (bool, error) {
+	pickedUpBytes, err := s.db.Read(ctx, pickupNamespace, id.Hex())
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "getting pickup of session <%s>", id.Hex())
+	}
+	return len(pickedUpBytes) > 0, nil
+}
+
+// RevokeSession revokes a session by setting the revoked flag to true.
+func (s *Storage) RevokeSession(ctx context.Context, id string) error {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	session.Revoked = true
+	session.RevokedAt = time.Now().Format(time.RFC3339)
+	return s.InsertSession(ctx, *session)
+}
+
+// ExpireSession marks a session as expired.
+func (s *Storage) ExpireSession(ctx context.Context, id string) error {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	session.Expired = true
+	return s.InsertSession(ctx, *session)
+}
+
+func (s *Storage) InsertPolicy(ctx context.Context, policy StoredPolicy) error {
+	id := policy.ID
+	if id == "" {
+		return sdkutil.LoggingNewError("could not store policy without an ID")
+	}
+
+	policyBytes, err := json.Marshal(policy)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not store policy: %s", id)
+	}
+
+	return s.tx.Write(ctx, policyNamespace, id, policyBytes)
+}
+
+func (s *Storage) GetPolicy(ctx context.Context, id string) (*StoredPolicy, error) {
+	storedPolicyBytes, err := s.db.Read(ctx, policyNamespace, id)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "getting policy details for policy <%s>", id)
+	}
+	if len(storedPolicyBytes) == 0 {
+		return nil, sdkutil.LoggingNewErrorf("could not find policy details for policy <%s>", id)
+	}
+
+	var stored StoredPolicy
+	if err = json.Unmarshal(storedPolicyBytes, &stored); err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "unmarshalling stored policy: %s", id)
+	}
+	return &stored, nil
+}
+
+// ListPolicies attempts to get all stored policies. It will return those it can even if it has trouble with some.
+func (s *Storage) ListPolicies(ctx context.Context, page common.Page) (*StoredPolicies, error) {
+	token, size := page.ToStorageArgs()
+	gotPolicies, nextPageToken, err := s.db.ReadPage(ctx, policyNamespace, token, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading page of policies")
+	}
+
+	stored := make([]StoredPolicy, 0, len(gotPolicies))
+	for _, policyBytes := range gotPolicies {
+		var nextPolicy StoredPolicy
+		if err = json.Unmarshal(policyBytes, &nextPolicy); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored policy: %s", string(policyBytes))
+			continue
+		}
+		stored = append(stored, nextPolicy)
+	}
+	return &StoredPolicies{
+		Policies:      stored,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (s *Storage) InsertResource(ctx context.Context, resource StoredResource) error {
+	id := resource.ID
+	if id == "" {
+		return sdkutil.LoggingNewError("could not store resource without an ID")
+	}
+
+	resourceBytes, err := json.Marshal(resource)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not store resource: %s", id)
+	}
+
+	return s.tx.Write(ctx, resourceNamespace, id, resourceBytes)
+}
+
+func (s *Storage) GetResource(ctx context.Context, id string) (*StoredResource, error) {
+	storedResourceBytes, err := s.db.Read(ctx, resourceNamespace, id)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "getting resource details for resource <%s>", id)
+	}
+	if len(storedResourceBytes) == 0 {
+		return nil, sdkutil.LoggingNewErrorf("could not find resource details for resource <%s>", id)
+	}
+
+	// resources stored before they held several assignments carry a single assignment inline
+	var stored struct {
+		StoredResource
+		ResourceAssignment
+	}
+	if err = json.Unmarshal(storedResourceBytes, &stored); err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "unmarshalling stored resource: %s", id)
+	}
+	if len(stored.Assignments) == 0 && stored.Role != "" {
+		stored.Assignments = []ResourceAssignment{stored.ResourceAssignment}
+	}
+	return &stored.StoredResource, nil
+}
+
+// AddResourceAssignment adds the assignment of a role to a resource, replacing an earlier assignment of the role
+// through the same permission. The resource is stored with its first assignment.
+func (s *Storage) AddResourceAssignment(ctx context.Context, id string, assignment ResourceAssignment) error {
+	resource := &StoredResource{ID: id}
+	exists, err := s.db.Exists(ctx, resourceNamespace, id)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "checking resource <%s>", id)
+	}
+	if exists {
+		if resource, err = s.GetResource(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	resource.Assignments = append(resource.withoutAssignment(assignment.Role, assignment.Permission), assignment)
+	return s.InsertResource(ctx, *resource)
+}
+
+// RemoveResourceAssignment removes the assignment of a role through a permission from a resource, keeping its other
+// assignments. The resource is deleted with its last assignment.
+func (s *Storage) RemoveResourceAssignment(ctx context.Context, id, role, permission string) error {
+	resource, err := s.GetResource(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	assignments := resource.withoutAssignment(role, permission)
+	if len(assignments) == len(resource.Assignments) {
+		return nil
+	}
+	if len(assignments) == 0 {
+		return s.DeleteResource(ctx, id)
+	}
+	resource.Assignments = assignments
+	return s.InsertResource(ctx, *resource)
+}
+
+func (s *Storage) DeleteResource(ctx context.Context, id string) error {
+	if err := s.db.Delete(ctx, resourceNamespace, id); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not delete resource: %s", id)
+	}
+	return nil
+}
+
+func (s *Storage) InsertPermission(ctx context.Context, permission StoredPermission) error {
+	id := permission.ID
+	if id == "" {
+		return sdkutil.LoggingNewError("could not store permission without an ID")
+	}
+
+	permissionBytes, err := json.Marshal(permission)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not store permission: %s", id)
+	}
+
+	return s.tx.Write(ctx, permissionNamespace, id, permissionBytes)
+}
+
+func (s *Storage) GetPermission(ctx context.Context, id string) (*StoredPermission, error) {
+	storedPermissionBytes, err := s.db.Read(ctx, permissionNamespace, id)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "getting permission details for permission <%s>", id)
+	}
+	if len(storedPermissionBytes) == 0 {
+		return nil, sdkutil.LoggingNewErrorf("could not find permission details for permission <%s>", id)
+	}
+
+	var stored StoredPermission
+	if err = json.Unmarshal(storedPermissionBytes, &stored); err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "unmarshalling stored permission: %s", id)
+	}
+	return &stored, nil
+}
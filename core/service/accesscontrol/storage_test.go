@@ -0,0 +1,133 @@
+package accesscontrol
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/storage"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	db, err := storage.NewStorage(storage.Bolt, storage.Option{
+		ID:     storage.BoltDBFilePathOption,
+		Option: filepath.Join(t.TempDir(), "bolt"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	s, err := NewAccessControlStorage(db, nil, nil, nil)
+	require.NoError(t, err)
+	return s
+}
+
+func TestResourceAssignments(t *testing.T) {
+	ctx := context.Background()
+	id := "did:pkh:eip155:1337:0x1;data.csv"
+	reader := ResourceAssignment{Role: "reader", Permission: "read", Operations: []uint8{OperationRead}}
+	writer := ResourceAssignment{Role: "writer", Permission: "write", Operations: []uint8{OperationRead, OperationWrite}}
+
+	t.Run("a resource keeps the assignments of several roles", func(tt *testing.T) {
+		s := newTestStorage(tt)
+		require.NoError(tt, s.AddResourceAssignment(ctx, id, reader))
+		require.NoError(tt, s.AddResourceAssignment(ctx, id, writer))
+
+		resource, err := s.GetResource(ctx, id)
+		require.NoError(tt, err)
+		assert.Equal(tt, []ResourceAssignment{reader, writer}, resource.Assignments)
+
+		// assigning the same role and permission again replaces the assignment
+		reader.Operations = []uint8{OperationWrite}
+		require.NoError(tt, s.AddResourceAssignment(ctx, id, reader))
+		resource, err = s.GetResource(ctx, id)
+		require.NoError(tt, err)
+		assert.Equal(tt, []ResourceAssignment{writer, reader}, resource.Assignments)
+	})
+
+	t.Run("unassigning removes only the matching assignment", func(tt *testing.T) {
+		s := newTestStorage(tt)
+		require.NoError(tt, s.AddResourceAssignment(ctx, id, reader))
+		require.NoError(tt, s.AddResourceAssignment(ctx, id, writer))
+
+		require.NoError(tt, s.RemoveResourceAssignment(ctx, id, "reader", "write"))
+		require.NoError(tt, s.RemoveResourceAssignment(ctx, id, "reader", "read"))
+		resource, err := s.GetResource(ctx, id)
+		require.NoError(tt, err)
+		assert.Equal(tt, []ResourceAssignment{writer}, resource.Assignments)
+
+		require.NoError(tt, s.RemoveResourceAssignment(ctx, id, "writer", "write"))
+		_, err = s.GetResource(ctx, id)
+		assert.Error(tt, err)
+	})
+
+	t.Run("reads resources stored with a single assignment", func(tt *testing.T) {
+		s := newTestStorage(tt)
+		legacy := []byte(`{"id":"` + id + `","role":"reader","policy":"policy","permission":"read","operations":[0]}`)
+		require.NoError(tt, s.db.Write(ctx, resourceNamespace, id, legacy))
+
+		resource, err := s.GetResource(ctx, id)
+		require.NoError(tt, err)
+		assert.Equal(tt, []ResourceAssignment{{Role: "reader", Policy: "policy", Permission: "read", Operations: []uint8{0}}}, resource.Assignments)
+
+		require.NoError(tt, s.AddResourceAssignment(ctx, id, writer))
+		resource, err = s.GetResource(ctx, id)
+		require.NoError(tt, err)
+		assert.Len(tt, resource.Assignments, 2)
+	})
+}
+
+func TestMigrateSessions(t *testing.T) {
+	ctx := context.Background()
+	access := StoredAccessContext{ID: ethcommon.HexToHash("0x1"), Address: "0xd231120eea6201b142b4048cf6c86bac2a0655d2"}
+
+	t.Run("moves sessions stored next to the access contexts", func(tt *testing.T) {
+		s := newTestStorage(tt)
+		require.NoError(tt, s.InsertAccessContext(ctx, access))
+		legacy, err := json.Marshal(StoredSession{ID: "legacy", Subject: "did:example:user"})
+		require.NoError(tt, err)
+		require.NoError(tt, s.db.Write(ctx, namespace, "legacy", legacy))
+		require.NoError(tt, s.InsertSession(ctx, StoredSession{ID: "current"}))
+
+		migrated, err := s.MigrateSessions(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, 1, migrated)
+
+		session, err := s.GetSession(ctx, "legacy")
+		require.NoError(tt, err)
+		assert.Equal(tt, "did:example:user", session.Subject)
+		exists, err := s.db.Exists(ctx, namespace, "legacy")
+		require.NoError(tt, err)
+		assert.False(tt, exists)
+		stored, err := s.GetAccessContext(ctx, access.ID.String())
+		require.NoError(tt, err)
+		assert.Equal(tt, access, *stored)
+		sessions, err := s.ListSessions(ctx)
+		require.NoError(tt, err)
+		assert.Len(tt, sessions, 2)
+
+		// nothing is left to move on the next start
+		migrated, err = s.MigrateSessions(ctx)
+		require.NoError(tt, err)
+		assert.Zero(tt, migrated)
+	})
+
+	t.Run("keeps the session already in the session namespace", func(tt *testing.T) {
+		s := newTestStorage(tt)
+		require.NoError(tt, s.InsertSession(ctx, StoredSession{ID: "session", Revoked: true}))
+		legacy, err := json.Marshal(StoredSession{ID: "session"})
+		require.NoError(tt, err)
+		require.NoError(tt, s.db.Write(ctx, namespace, "session", legacy))
+
+		migrated, err := s.MigrateSessions(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, 1, migrated)
+		session, err := s.GetSession(ctx, "session")
+		require.NoError(tt, err)
+		assert.True(tt, session.Revoked)
+	})
+}
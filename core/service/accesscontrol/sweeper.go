@@ -0,0 +1,74 @@
+package accesscontrol
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// RunSessionSweeper periodically sweeps expired sessions until the context is done. It returns immediately when no
+// sweep interval is configured.
+func (s Service) RunSessionSweeper(ctx context.Context) {
+	interval := s.config.SessionSweepInterval
+	if interval <= 0 {
+		logrus.Info("session sweeper disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SweepSessions(ctx); err != nil {
+				logrus.WithError(err).Error("could not sweep sessions")
+			}
+		}
+	}
+}
+
+// MigrateSessions moves the sessions stored by earlier versions next to the access contexts into the session
+// namespace, so that they are verified and swept like the sessions stored since. It runs before the sweeper starts.
+func (s Service) MigrateSessions(ctx context.Context) error {
+	migrated, err := s.storageClient.MigrateSessions(ctx)
+	if err != nil {
+		return errors.Wrap(err, "migrating sessions")
+	}
+	if migrated > 0 {
+		logrus.Infof("moved %d stored sessions into namespace<%s>", migrated, sessionNamespace)
+	}
+	return nil
+}
+
+// SweepSessions marks all stored sessions that outlived their lifetime as expired. When configured, expired sessions
+// are revoked on-chain as well.
+func (s Service) SweepSessions(ctx context.Context) error {
+	sessions, err := s.storageClient.ListSessions(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing sessions")
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if session.Expired || session.Revoked || session.ExpiresAt.IsZero() || now.Before(session.ExpiresAt) {
+			continue
+		}
+
+		if s.config.RevokeExpiredSessions {
+			if _, err = s.revokeSessionOnChain(ctx, session.ID, nil); err != nil {
+				logrus.WithError(err).Warnf("could not revoke expired session<%s> on-chain", session.ID)
+			}
+		}
+
+		if err = s.storageClient.ExpireSession(ctx, session.ID); err != nil {
+			logrus.WithError(err).Errorf("could not mark session<%s> as expired", session.ID)
+			continue
+		}
+		logrus.Debugf("session<%s> expired at %s", session.ID, session.ExpiresAt)
+	}
+	return nil
+}
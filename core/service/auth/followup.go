@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"github.com/fapiper/onchain-access-control/core/service/operation"
+)
+
+// Kinds of the follow-ups that complete the operations of the auth service once their transaction is confirmed
+const (
+	followUpGrantRole  = "auth/grant-role"
+	followUpRevokeRole = "auth/revoke-role"
+)
+
+// RegisterFollowUps registers the handlers of the follow-ups of the operations of this service with the trackers.
+// Operations resumed after a restart run their follow-up as well, so the handlers must be registered before the
+// trackers run.
+func (s Service) RegisterFollowUps() {
+	s.tracker.Handle(followUpGrantRole, operation.NewFollowUpHandler(s.storeRole))
+	s.tracker.Handle(followUpRevokeRole, operation.NewFollowUpHandler(s.deleteRole))
+}
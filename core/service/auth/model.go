@@ -7,12 +7,47 @@ import (
 )
 
 type GrantRoleInput struct {
-	RoleID   string                         `json:"role"`
-	PolicyID string                         `json:"policy"`
-	Proof    contracts.IPolicyVerifierProof `json:"proof"`
-	Inputs   [20]*big.Int                   `json:"inputs"`
+	RoleID string `json:"role" validate:"required"`
+	// Policies assigned to the role, each satisfied by a proof. All policies assigned to the role must be provided.
+	Policies []GrantRolePolicyInput `json:"policies" validate:"required,min=1,dive"`
+}
+
+type GrantRolePolicyInput struct {
+	PolicyID string `json:"policy" validate:"required"`
+	// Proof of the policy. If omitted, the proof and its inputs are generated from the credentials of the holder.
+	Proof *contracts.IPolicyVerifierProof `json:"proof,omitempty"`
+	// Inputs are the public inputs of the proof, as many as the policy verifier declares
+	Inputs []*big.Int `json:"inputs,omitempty"`
 }
 
 func (in GrantRoleInput) IsValid() bool {
 	return util.IsValidStruct(in) == nil
 }
+
+type RevokeRoleInput struct {
+	RoleID string `json:"role" validate:"required"`
+}
+
+func (in RevokeRoleInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
+type StartSessionInput struct {
+	// DID URL of the resource the session is started for, e.g. `did:pkh:...?ref=static/data/emission_report.csv`
+	Resource string `json:"resource" validate:"required"`
+	// DID or DID URL of the key agreement key the session token is encrypted to. Defaults to the DID of the resource,
+	// which is required when the resource owner is identified by a DID without keys, e.g. a did:pkh.
+	Recipient string `json:"recipient,omitempty"`
+}
+
+func (in StartSessionInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
+
+type RevokeSessionInput struct {
+	ID string `json:"id" validate:"required"`
+}
+
+func (in RevokeSessionInput) IsValid() bool {
+	return util.IsValidStruct(in) == nil
+}
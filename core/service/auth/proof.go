@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/internal/groth16"
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/service/prover"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
+	"github.com/pkg/errors"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+const verificationKeyExtension = ".key"
+
+// resolvePolicyVerifiers returns the verifier contract of every policy, in the order of the policies
+func (s Service) resolvePolicyVerifiers(ctx context.Context, contextDID string, address persist.Address, policies []GrantRolePolicyInput, params rpc.GrantRoleParams) ([]common.Address, error) {
+	verifiers := make([]common.Address, 0, len(params.Policies))
+	for i, policy := range params.Policies {
+		verifier, err := s.rpcService.GetPolicyVerifier(ctx, rpc.GetPolicyVerifierParams{
+			Context:          contextDID,
+			AccessContext:    address,
+			PolicyIdentifier: policy.PolicyIdentifier,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get verifier of policy<%s>", policies[i].PolicyID)
+		}
+		verifiers = append(verifiers, verifier)
+	}
+	return verifiers, nil
+}
+
+// validatePolicyInputs checks the number of public inputs of every policy against the input count declared by its
+// policy verifier, since each policy program has its own number of public inputs.
+func (s Service) validatePolicyInputs(ctx context.Context, contextDID string, verifiers []common.Address, policies []GrantRolePolicyInput, params rpc.GrantRoleParams) error {
+	for i, policy := range params.Policies {
+		policyID := policies[i].PolicyID
+		count, err := s.rpcService.GetPolicyInputCount(ctx, rpc.PolicyVerifierParams{Context: contextDID, Verifier: verifiers[i]})
+		if err != nil {
+			return errors.Wrapf(err, "could not get input count of policy<%s>", policyID)
+		}
+		if uint64(len(policy.Inputs)) != count {
+			return errors.Errorf("policy<%s> requires %d public inputs, got %d", policyID, count, len(policy.Inputs))
+		}
+	}
+	return nil
+}
+
+// verifyPolicyProofs checks the proof of every policy against the verification key of the policy, so that an invalid
+// proof is rejected with a precise error instead of reverting the grant role transaction.
+func (s Service) verifyPolicyProofs(ctx context.Context, contextDID string, verifiers []common.Address, policies []GrantRolePolicyInput, params rpc.GrantRoleParams) error {
+	for i, policy := range params.Policies {
+		policyID := policies[i].PolicyID
+		vk, err := s.getVerificationKey(ctx, contextDID, verifiers[i], policy.PolicyIdentifier)
+		if err != nil {
+			return errors.Wrapf(err, "could not get verification key of policy<%s>", policyID)
+		}
+		if err = groth16.Verify(*vk, policy.Proof, policy.Inputs); err != nil {
+			return errors.Wrapf(err, "invalid proof for policy<%s>", policyID)
+		}
+	}
+	return nil
+}
+
+// getVerificationKey loads the verification key of a policy from the configured verification key directory, where
+// keys are named after the hex encoded policy identifier, e.g. `0x<context hash>+0x<policy hash>.key`. Policies
+// without a local key fall back to the verification key uri carried by the policy verifier contract.
+func (s Service) getVerificationKey(ctx context.Context, contextDID string, verifier common.Address, identifier persist.PolicyIdentifier) (*groth16.VerificationKey, error) {
+	if dir := s.config.VerificationKeyDir; dir != "" {
+		path, err := verificationKeyPath(dir, identifier.String())
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return groth16.ParseVerificationKey(data)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, errors.Wrap(err, "reading local verification key")
+		}
+	}
+
+	uri, err := s.rpcService.GetVerificationKeyURI(ctx, rpc.PolicyVerifierParams{Context: contextDID, Verifier: verifier})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting verification key uri of policy verifier<%s>", verifier)
+	}
+	if !ipfs.IsIpfsProtoURL(uri) {
+		return nil, errors.Errorf("policy verifier<%s> carries no ipfs verification key uri: %q", verifier, uri)
+	}
+
+	data, err := ipfs.ReadURI(ctx, s.artifacts, uri)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching verification key")
+	}
+	return groth16.ParseVerificationKey(data)
+}
+
+// verificationKeyPath returns the path of the verification key of a policy identifier within dir. Only hex encoded
+// identifiers are accepted, so that the path cannot leave dir.
+func verificationKeyPath(dir string, identifier string) (string, error) {
+	parsed, err := persist.ParsePolicyIdentifier(identifier)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid verification key name")
+	}
+	return filepath.Join(dir, parsed.String()+verificationKeyExtension), nil
+}
+
+// provePolicy generates the proof of a policy from the credentials held by this instance
+func (s Service) provePolicy(ctx context.Context, contextDID string, address persist.Address, identifier persist.PolicyIdentifier, policyID string) (contracts.IPolicyVerifierProof, []*big.Int, error) {
+	if s.prover == nil {
+		return contracts.IPolicyVerifierProof{}, nil, errors.Errorf("no proof provided for policy<%s> and no prover configured", policyID)
+	}
+
+	response, err := s.prover.ProvePolicy(ctx, prover.ProvePolicyRequest{
+		Context:          contextDID,
+		AccessContext:    address,
+		PolicyIdentifier: identifier,
+	})
+	if err != nil {
+		return contracts.IPolicyVerifierProof{}, nil, errors.Wrapf(err, "could not prove policy<%s>", policyID)
+	}
+	return response.Proof.Proof, response.Inputs, nil
+}
@@ -7,30 +7,35 @@ import (
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fapiper/onchain-access-control/core/config"
-	"github.com/fapiper/onchain-access-control/core/contracts"
+	didint "github.com/fapiper/onchain-access-control/core/internal/did"
 	"github.com/fapiper/onchain-access-control/core/internal/encryption"
+	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
 	"github.com/fapiper/onchain-access-control/core/service/framework"
 	"github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/fapiper/onchain-access-control/core/service/operation"
 	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/service/prover"
 	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
 	"github.com/fapiper/onchain-access-control/core/storage"
 	"github.com/google/uuid"
-	shell "github.com/ipfs/go-ipfs-api"
-	"github.com/lestrrat-go/jwx/v2/jwa"
-	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"math/big"
+	"time"
 )
 
 type ServiceFactory func(storage.Tx) (*Service, error)
 type Service struct {
+	config        config.AuthServiceConfig
 	storageClient *Storage
-	ipfsClient    *shell.Shell
+	artifacts     ipfs.Store
 	rpcService    *rpc.Service
 	keystore      *keystore.Service
 	resolver      resolution.Resolver
+	prover        *prover.Service
+	tracker       *operation.Trackers
 }
 
 func (s Service) Type() framework.Type {
@@ -42,12 +47,15 @@ func (s Service) Status() framework.Status {
 	if s.storageClient == nil {
 		e.AppendString("no storage configured")
 	}
-	if s.ipfsClient == nil {
-		e.AppendString("no ipfs client configured")
+	if s.artifacts == nil {
+		e.AppendString("no artifact store configured")
 	}
 	if s.rpcService == nil {
 		e.AppendString("no rpc service configured")
 	}
+	if s.tracker == nil {
+		e.AppendString("no transaction tracker configured")
+	}
 	if !e.IsEmpty() {
 		return framework.Status{
 			Status:  framework.StatusNotReady,
@@ -57,17 +65,22 @@ func (s Service) Status() framework.Status {
 	return framework.Status{Status: framework.StatusReady}
 }
 
-func NewAuthService(config config.AuthServiceConfig, s storage.ServiceStorage, r resolution.Resolver, k *keystore.Service, rpcService *rpc.Service, ipfsClient *shell.Shell) (*Service, error) {
+func NewAuthService(config config.AuthServiceConfig, s storage.ServiceStorage, r resolution.Resolver, k *keystore.Service, t *operation.Trackers, rpcService *rpc.Service, artifacts ipfs.Store) (*Service, error) {
 	encrypter, decrypter, err := keystore.NewServiceEncryption(s, config.EncryptionConfig, keystore.ServiceKeyEncryptionKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating new encryption")
 	}
 
-	factory := NewAuthServiceFactory(s, r, k, encrypter, decrypter, rpcService, ipfsClient)
-	return factory(s)
+	factory := NewAuthServiceFactory(config, s, r, k, t, encrypter, decrypter, rpcService, artifacts, nil)
+	service, err := factory(s)
+	if err != nil {
+		return nil, err
+	}
+	service.RegisterFollowUps()
+	return service, nil
 }
 
-func NewAuthServiceFactory(s storage.ServiceStorage, r resolution.Resolver, k *keystore.Service, encrypter encryption.Encrypter, decrypter encryption.Decrypter, rpcService *rpc.Service, ipfsClient *shell.Shell) ServiceFactory {
+func NewAuthServiceFactory(config config.AuthServiceConfig, s storage.ServiceStorage, r resolution.Resolver, k *keystore.Service, t *operation.Trackers, encrypter encryption.Encrypter, decrypter encryption.Decrypter, rpcService *rpc.Service, artifacts ipfs.Store, p *prover.Service) ServiceFactory {
 	return func(tx storage.Tx) (*Service, error) {
 		// Next, instantiate the key storage
 		sc, err := NewAuthStorage(s, encrypter, decrypter, tx)
@@ -77,11 +90,14 @@ func NewAuthServiceFactory(s storage.ServiceStorage, r resolution.Resolver, k *k
 		}
 
 		service := Service{
+			config:        config,
 			storageClient: sc,
 			keystore:      k,
 			resolver:      r,
 			rpcService:    rpcService,
-			ipfsClient:    ipfsClient,
+			artifacts:     artifacts,
+			prover:        p,
+			tracker:       t,
 		}
 		if !service.Status().IsReady() {
 			return nil, errors.New(service.Status().Message)
@@ -90,53 +106,110 @@ func NewAuthServiceFactory(s storage.ServiceStorage, r resolution.Resolver, k *k
 	}
 }
 
-// StartSession starts a session by registering the session token on-chain.
-func (s Service) StartSession(ctx context.Context) (*jwt.Token, string, error) {
+// StartSession starts a session for a resource by registering the session token on-chain. The token is returned right
+// away, together with the operation that is done once the session is confirmed on-chain.
+func (s Service) StartSession(ctx context.Context, input StartSessionInput) (*jwt.Token, string, *operation.Operation, error) {
+	if !input.IsValid() {
+		return nil, "", nil, errors.Errorf("invalid start session input: %+v", input)
+	}
+
+	resource, err := persist.ParseResourceFromDIDURL(input.Resource)
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "could not parse resource did url")
+	}
+
 	tid := uuid.NewString()
+	now := time.Now()
 
+	// the session belongs to the owner DID, and is issued by the wallet currently acting for it
 	builder := jwt.NewBuilder().
-		Audience([]string{s.rpcService.Wallet.GetDID()}).
-		Subject(s.rpcService.Wallet.GetDID()).
+		Audience([]string{resource.DID}).
+		Subject(s.rpcService.OwnerDID()).
 		Issuer(s.rpcService.Wallet.GetDID()).
-		JwtID(tid)
+		JwtID(tid).
+		IssuedAt(now).
+		NotBefore(now).
+		Expiration(now.Add(s.config.GetSessionTTL())).
+		Claim(persist.SessionResourceClaim, resource.String())
 
 	token, err := builder.Build()
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "failed to build token")
+	}
 
-	signedToken, err := jwt.Sign(token, jwt.WithKey(jwa.ES256K, s.rpcService.Wallet.PrivateKey))
+	headers := jws.NewHeaders()
+	if err = headers.Set(jws.KeyIDKey, s.rpcService.Wallet.GetKID()); err != nil {
+		return nil, "", nil, errors.Wrap(err, "failed to set kid header")
+	}
+	alg, key, err := s.rpcService.Wallet.TokenKey(ctx)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "failed to sign token")
+		return nil, "", nil, errors.Wrap(err, "failed to get session token key")
+	}
+	signedToken, err := jwt.Sign(token, jwt.WithKey(alg, key, jws.WithProtectedHeaders(headers)))
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "failed to sign token")
+	}
+
+	recipient := input.Recipient
+	if recipient == "" {
+		recipient = resource.DID
+	}
+	sessionJWE, err := s.encryptJWE(ctx, signedToken, recipient)
+	if err != nil {
+		return nil, "", nil, errors.Wrapf(err, "failed to encrypt token to recipient<%s>", recipient)
 	}
 
-	_, err = s.rpcService.StartSession(ctx, rpc.StartSessionParams{
-		DID:        s.rpcService.Wallet.GetDIDHash(),
+	tx, err := s.rpcService.StartSession(ctx, rpc.StartSessionParams{
+		DID:        s.rpcService.OwnerDIDHash(),
+		Context:    crypto.Keccak256Hash([]byte(resource.DID)),
 		TokenID:    crypto.Keccak256Hash([]byte(tid)),
-		SessionJWE: crypto.Keccak256Hash(signedToken).Bytes(),
+		SessionJWE: sessionJWE,
+		Duration:   s.config.GetSessionTTL(),
 	})
 
 	if err != nil {
-		return nil, "", errors.Wrap(err, "unable to start session transaction")
+		return nil, "", nil, errors.Wrap(err, "unable to start session transaction")
 	}
 
-	return &token, string(signedToken), nil
+	op, err := s.tracker.Track(ctx, tx, nil)
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "unable to track start session transaction")
+	}
+
+	return &token, string(signedToken), op, nil
 }
 
-func (s Service) encryptJWE(ctx context.Context, signedToken []byte, audience string) ([]byte, error) {
-	did, err := s.resolver.Resolve(ctx, audience)
-	if err != nil {
-		return nil, errors.Wrap(err, "resolve doc for did")
+// RevokeSession ends a session of this instance by revoking the session token on-chain.
+func (s Service) RevokeSession(ctx context.Context, input RevokeSessionInput) (*operation.Operation, error) {
+	if !input.IsValid() {
+		return nil, errors.Errorf("invalid revoke session input: %+v", input)
 	}
 
-	if len(did.Document.VerificationMethod) < 1 {
-		return nil, errors.Wrap(err, "no verification method for did")
+	tx, err := s.rpcService.RevokeSession(ctx, rpc.RevokeSessionParams{
+		TokenID: crypto.Keccak256Hash([]byte(input.ID)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to execute revoke session transaction")
 	}
-	verificationMethod := did.Document.VerificationMethod[0]
 
-	return jwe.Encrypt(signedToken, jwe.WithKey(verificationMethod.Type, verificationMethod.PublicKeyJWK))
+	return s.tracker.Track(ctx, tx, nil)
+}
 
+// encryptJWE encrypts a signed token to the key agreement key of the recipient, so that only the resource owner is
+// able to read the session token that is published on-chain. The recipient key is referenced by the kid header.
+func (s Service) encryptJWE(ctx context.Context, signedToken []byte, recipient string) ([]byte, error) {
+	kid, pubKey, err := didint.ResolveKeyAgreementKey(ctx, s.resolver, recipient)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving key agreement key")
+	}
+	return keyaccess.EncryptJWE(signedToken, kid, pubKey)
 }
 
-// GrantRole verifies a policy and assign the role.
-func (s Service) GrantRole(ctx context.Context, input GrantRoleInput) (*Role, error) {
+// GrantRole verifies the policies of a role and assigns the role. Every policy assigned to the role must be given,
+// policies without a proof are proven with the credentials held by this instance. Both the number of policies and the
+// proofs are checked before the transaction is sent. The returned operation is done once the role is confirmed
+// on-chain and stored.
+func (s Service) GrantRole(ctx context.Context, input GrantRoleInput) (*operation.Operation, error) {
 	if !input.IsValid() {
 		return nil, errors.Errorf("invalid grant role input: %+v", input)
 	}
@@ -146,37 +219,172 @@ func (s Service) GrantRole(ctx context.Context, input GrantRoleInput) (*Role, er
 		return nil, errors.Wrap(err, "could not parse role from identifier string")
 	}
 
-	policy, err := persist.ParsePolicyFromIdentifierString(input.PolicyID)
+	if err = s.checkContextChain(role.ContextID); err != nil {
+		return nil, err
+	}
+
+	address, err := s.rpcService.GetAccessContextAddress(role.ContextID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get access context address")
+	}
+	if address == persist.ZeroAddress {
+		return nil, errors.Errorf("access context for role<%s> does not exist", input.RoleID)
+	}
+
+	params, err := s.buildGrantRoleParams(ctx, address, role, input.Policies)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not parse policy from identifier string")
+		return nil, err
+	}
+
+	if err = s.validateRolePolicyCount(ctx, role.ContextID, address, params); err != nil {
+		return nil, err
+	}
+
+	verifiers, err := s.resolvePolicyVerifiers(ctx, role.ContextID, address, input.Policies, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.validatePolicyInputs(ctx, role.ContextID, verifiers, input.Policies, params); err != nil {
+		return nil, err
+	}
+
+	if err = s.verifyPolicyProofs(ctx, role.ContextID, verifiers, input.Policies, params); err != nil {
+		return nil, err
 	}
 
-	params := s.buildGrantRoleParams(role, policy, input.Proof, input.Inputs)
 	logrus.Infof("GrantRoleParams: %s", params)
 
-	_, err = s.rpcService.GrantRole(ctx, params)
+	followUp, err := operation.NewFollowUp(followUpGrantRole, Role{
+		Id:         role.RoleID,
+		Context:    role.ContextID,
+		Identifier: params.RoleIdentifier.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.GrantRole(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to execute grant role transaction")
 	}
 
-	stored := Role{Id: role.RoleID, Context: role.ContextID, Identifier: params.RoleIdentifier.String()}
+	return s.tracker.Track(ctx, tx, followUp)
+}
 
-	if err = s.storageClient.InsertRole(ctx, stored); err != nil {
+// storeRole stores a role once it is granted
+func (s Service) storeRole(ctx context.Context, role Role) (*Role, error) {
+	if err := s.storageClient.InsertRole(ctx, role); err != nil {
 		return nil, errors.Wrap(err, "could not store role for user")
 	}
+	return &role, nil
+}
 
-	return &stored, nil
+// checkContextChain checks that an access context is on the default chain, as roles are granted and revoked by the
+// wallet of this instance on its default chain only
+func (s Service) checkContextChain(context string) error {
+	chain, err := s.rpcService.ChainOf(context)
+	if err != nil {
+		return errors.Wrapf(err, "could not get chain of access context<%s>", context)
+	}
+	if chain.ChainID() != s.rpcService.ChainID() {
+		return errors.Errorf("access context<%s> is on chain %d, roles are granted on chain %d", context, chain.ChainID(), s.rpcService.ChainID())
+	}
+	return nil
 }
 
-func (s Service) buildGrantRoleParams(role *persist.Role, policy *persist.Policy, proof contracts.IPolicyVerifierProof, inputs [20]*big.Int) rpc.GrantRoleParams {
-	roleIdentifier := persist.NewRoleIdentifier(role.ContextID, role.RoleID)
-	policyIdentifier := persist.NewPolicyIdentifier(policy.ContextID, policy.PolicyID)
+// validateRolePolicyCount checks that the policies to grant a role with match the number of policies assigned to the
+// role, since the access context only verifies the policies it is given.
+func (s Service) validateRolePolicyCount(ctx context.Context, contextDID string, address persist.Address, params rpc.GrantRoleParams) error {
+	count, err := s.rpcService.GetRolePolicyCount(ctx, rpc.GetRolePolicyCountParams{
+		Context: contextDID,
+		Address: address,
+		RoleID:  params.RoleIdentifier.RoleID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not get policy count of role")
+	}
+	if uint64(len(params.Policies)) != count {
+		return errors.Errorf("role requires %d policies, got %d", count, len(params.Policies))
+	}
+	return nil
+}
 
-	return rpc.GrantRoleParams{
-		RoleIdentifier:   roleIdentifier,                   // input.RoleId
-		DID:              s.rpcService.Wallet.GetDIDHash(), // DID
-		PolicyIdentifier: policyIdentifier,
-		Proof:            proof,
-		Inputs:           inputs,
+// RevokeRole renounces a granted role on-chain. The returned operation is done once the stored role is removed.
+func (s Service) RevokeRole(ctx context.Context, input RevokeRoleInput) (*operation.Operation, error) {
+	if !input.IsValid() {
+		return nil, errors.Errorf("invalid revoke role input: %+v", input)
+	}
+
+	role, err := persist.ParseRoleFromIdentifierString(input.RoleID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse role from identifier string")
+	}
+
+	if err = s.checkContextChain(role.ContextID); err != nil {
+		return nil, err
+	}
+
+	identifier := persist.NewRoleIdentifier(role.ContextID, role.RoleID)
+	address, err := s.rpcService.GetAccessContextAddress(role.ContextID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get access context address")
+	}
+	if address == persist.ZeroAddress {
+		return nil, errors.Errorf("access context for role<%s> does not exist", input.RoleID)
+	}
+
+	followUp, err := operation.NewFollowUp(followUpRevokeRole, role.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.rpcService.RevokeRole(ctx, rpc.RevokeRoleParams{
+		AccessContext: address,
+		Role:          identifier.RoleID,
+		DID:           s.rpcService.OwnerDIDHash(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to execute revoke role transaction")
+	}
+
+	return s.tracker.Track(ctx, tx, followUp)
+}
+
+// deleteRole removes a stored role once it is revoked
+func (s Service) deleteRole(ctx context.Context, id string) (any, error) {
+	if err := s.storageClient.DeleteRole(ctx, id); err != nil {
+		return nil, errors.Wrap(err, "could not delete stored role")
+	}
+	return nil, nil
+}
+
+func (s Service) buildGrantRoleParams(ctx context.Context, address persist.Address, role *persist.Role, policies []GrantRolePolicyInput) (rpc.GrantRoleParams, error) {
+	params := rpc.GrantRoleParams{
+		RoleIdentifier: persist.NewRoleIdentifier(role.ContextID, role.RoleID),
+		DID:            s.rpcService.OwnerDIDHash(),
+		Policies:       make([]rpc.GrantRolePolicy, 0, len(policies)),
+	}
+
+	seen := make(map[persist.PolicyIdentifier]bool, len(policies))
+	for _, p := range policies {
+		policy, err := persist.ParsePolicyFromIdentifierString(p.PolicyID)
+		if err != nil {
+			return params, errors.Wrap(err, "could not parse policy from identifier string")
+		}
+		identifier := persist.NewPolicyIdentifier(policy.ContextID, policy.PolicyID)
+		if seen[identifier] {
+			return params, errors.Errorf("policy<%s> is provided more than once", p.PolicyID)
+		}
+		seen[identifier] = true
+
+		grant := rpc.GrantRolePolicy{PolicyIdentifier: identifier, Inputs: p.Inputs}
+		if p.Proof != nil {
+			grant.Proof = *p.Proof
+		} else if grant.Proof, grant.Inputs, err = s.provePolicy(ctx, role.ContextID, address, identifier, p.PolicyID); err != nil {
+			return params, err
+		}
+		params.Policies = append(params.Policies, grant)
 	}
+	return params, nil
 }
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/deploy"
+	didint "github.com/fapiper/onchain-access-control/core/internal/did"
+	"github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/fapiper/onchain-access-control/core/service/operation"
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
+	"github.com/fapiper/onchain-access-control/core/testutil"
+)
+
+// newTestService returns an auth service with a funded wallet on a simulated chain, whose trackers run until the test
+// ends
+func newTestService(t *testing.T) *Service {
+	key, backend := testutil.NewFundedKey(t)
+	rpcService, err := rpc.NewRPCServiceWithConfig(rpc.NewSimulatedBackend(backend), rpc.SimulatedConfig(key), nil)
+	require.NoError(t, err)
+
+	db := testutil.TestDatabases[0].ServiceStorage(t)
+	trackers, err := operation.NewTrackers(config.OperationServiceConfig{Confirmations: 1, PollInterval: 10 * time.Millisecond}, db, rpcService)
+	require.NoError(t, err)
+
+	keyStore, err := keystore.NewKeyStoreService(config.KeyStoreServiceConfig{}, db)
+	require.NoError(t, err)
+	resolver, err := didint.BuildMultiMethodResolver([]string{"key"})
+	require.NoError(t, err)
+	artifacts, err := ipfs.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	s, err := NewAuthService(config.AuthServiceConfig{SessionTTL: time.Hour}, db, resolver, keyStore, trackers, rpcService, artifacts)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		trackers.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return s
+}
+
+// deployTestContext deploys the contract suite and creates the access context of the wallet of the service, skipping
+// the test for bindings generated without bytecode
+func deployTestContext(t *testing.T, s *Service) {
+	if contracts.AccessContextHandlerMetaData.Bin == "" {
+		t.Skip("the contract bindings carry no bytecode, compile the contracts and run make abi")
+	}
+	ctx := context.Background()
+	addresses, err := deploy.Deploy(ctx, s.rpcService, deploy.Addresses{})
+	require.NoError(t, err)
+
+	s.rpcService.DIDRegistry = persist.Address(addresses.DIDRegistry.Hex())
+	s.rpcService.ContextHandler = persist.Address(addresses.ContextHandler.Hex())
+	s.rpcService.SessionRegistry = persist.Address(addresses.SessionRegistry.Hex())
+
+	did := s.rpcService.OwnerDIDHash()
+	tx, err := s.rpcService.CreateAccessContext(ctx, rpc.CreateAccessContextParams{ID: did, DID: did})
+	requireMined(t, s, tx, err)
+}
+
+func requireMined(t *testing.T, s *Service, tx *types.Transaction, err error) {
+	require.NoError(t, err)
+	receipt, err := s.rpcService.WaitMined(context.Background(), tx)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+// awaitOperation waits until an operation is done and requires it to have succeeded
+func awaitOperation(t *testing.T, s *Service, op *operation.Operation, err error) {
+	require.NoError(t, err)
+	require.NotNil(t, op)
+
+	operations, err := operation.NewOperationService(s.storageClient.db)
+	require.NoError(t, err)
+	var done *operation.Operation
+	require.Eventually(t, func() bool {
+		done, err = operations.GetOperation(context.Background(), operation.GetOperationRequest{ID: op.ID})
+		require.NoError(t, err)
+		return done.Done
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Empty(t, done.Result.Error)
+}
+
+func TestRevokeRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(tt *testing.T) {
+		s := newTestService(tt)
+		_, err := s.RevokeRole(ctx, RevokeRoleInput{})
+		assert.ErrorContains(tt, err, "invalid revoke role input")
+
+		_, err = s.RevokeRole(ctx, RevokeRoleInput{RoleID: "reader"})
+		assert.ErrorContains(tt, err, "invalid role identifier format")
+	})
+
+	t.Run("roles of a context on another chain", func(tt *testing.T) {
+		s := newTestService(tt)
+		_, err := s.RevokeRole(ctx, RevokeRoleInput{RoleID: "did:pkh:eip155:1:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2+reader"})
+		assert.ErrorContains(tt, err, "could not get chain of access context")
+	})
+
+	t.Run("roles of a context that does not exist", func(tt *testing.T) {
+		s := newTestService(tt)
+		deployTestContext(tt, s)
+
+		_, err := s.RevokeRole(ctx, RevokeRoleInput{RoleID: "did:example:missing+reader"})
+		assert.ErrorContains(tt, err, "does not exist")
+	})
+
+	t.Run("revokes a granted role and deletes the stored role", func(tt *testing.T) {
+		s := newTestService(tt)
+		deployTestContext(tt, s)
+		role := persist.Role{ContextID: s.rpcService.OwnerDID(), RoleID: "reader"}
+		identifier := persist.NewRoleIdentifier(role.ContextID, role.RoleID)
+
+		// the role has no policy assigned, so it is granted without proofs
+		tx, err := s.rpcService.GrantRole(ctx, rpc.GrantRoleParams{RoleIdentifier: identifier, DID: s.rpcService.OwnerDIDHash()})
+		requireMined(tt, s, tx, err)
+		require.NoError(tt, s.storageClient.InsertRole(ctx, Role{Id: role.RoleID, Context: role.ContextID, Identifier: identifier.String()}))
+
+		op, err := s.RevokeRole(ctx, RevokeRoleInput{RoleID: role.String()})
+		awaitOperation(tt, s, op, err)
+
+		_, err = s.storageClient.GetRole(ctx, role.RoleID)
+		assert.ErrorContains(tt, err, "role not found")
+		address, err := s.rpcService.GetAccessContextAddress(role.ContextID)
+		require.NoError(tt, err)
+		has, err := s.rpcService.HasRole(ctx, rpc.HasRoleParams{Context: role.ContextID, Address: address, RoleID: identifier.RoleID, DID: s.rpcService.OwnerDIDHash()})
+		require.NoError(tt, err)
+		assert.False(tt, has)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(tt *testing.T) {
+		s := newTestService(tt)
+		_, err := s.RevokeSession(ctx, RevokeSessionInput{})
+		assert.ErrorContains(tt, err, "invalid revoke session input")
+	})
+
+	t.Run("sessions are revoked only if they exist", func(tt *testing.T) {
+		s := newTestService(tt)
+		deployTestContext(tt, s)
+
+		_, err := s.RevokeSession(ctx, RevokeSessionInput{ID: "unknown"})
+		assert.Error(tt, err)
+	})
+
+	t.Run("revokes a session of the user", func(tt *testing.T) {
+		s := newTestService(tt)
+		deployTestContext(tt, s)
+		tokenID := crypto.Keccak256Hash([]byte("session"))
+		params := rpc.CheckSessionParams{TokenID: tokenID, Subject: s.rpcService.OwnerDID()}
+
+		tx, err := s.rpcService.StartSession(ctx, rpc.StartSessionParams{
+			DID:        s.rpcService.OwnerDIDHash(),
+			Context:    s.rpcService.OwnerDIDHash(),
+			TokenID:    tokenID,
+			SessionJWE: []byte("session"),
+			Duration:   time.Hour,
+		})
+		requireMined(tt, s, tx, err)
+		valid, err := s.rpcService.CheckSession(ctx, params)
+		require.NoError(tt, err)
+		require.True(tt, valid)
+
+		op, err := s.RevokeSession(ctx, RevokeSessionInput{ID: "session"})
+		awaitOperation(tt, s, op, err)
+
+		valid, err = s.rpcService.CheckSession(ctx, params)
+		require.NoError(tt, err)
+		assert.False(tt, valid)
+	})
+}
@@ -67,7 +67,7 @@ func (s *Storage) InsertRole(ctx context.Context, role Role) error {
 		return errors.Wrap(err, "marshalling role")
 	}
 
-	return s.db.Write(ctx, namespace, role.Id, data)
+	return s.tx.Write(ctx, namespace, role.Id, data)
 }
 
 func (s *Storage) GetRole(ctx context.Context, id string) (*Role, error) {
@@ -87,3 +87,13 @@ func (s *Storage) GetRole(ctx context.Context, id string) (*Role, error) {
 	}
 	return &r, nil
 }
+
+func (s *Storage) DeleteRole(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("cannot delete role without an ID")
+	}
+	if err := s.tx.Delete(ctx, namespace, id); err != nil {
+		return errors.Wrapf(err, "deleting role: %s", id)
+	}
+	return nil
+}
@@ -35,17 +35,18 @@ type MethodHandler interface {
 }
 
 // NewHandlerResolver creates a new HandlerResolver from a map of MethodHandlers which are used to resolve DIDs
-// stored in our database
-func NewHandlerResolver(handlers map[didsdk.Method]MethodHandler) (*resolution.MultiMethodResolver, error) {
+// stored in our database, and further resolvers of methods without a handler
+func NewHandlerResolver(handlers map[didsdk.Method]MethodHandler, resolvers ...resolution.Resolver) (*resolution.MultiMethodResolver, error) {
 	if len(handlers) == 0 {
 		return nil, util.LoggingNewError("no handlers provided")
 	}
 
-	methodResolvers := make([]resolution.Resolver, 0, len(handlers))
+	methodResolvers := make([]resolution.Resolver, 0, len(handlers)+len(resolvers))
 	for method, handler := range handlers {
 		methodResolver := resolverFromHandler(method, handler)
 		methodResolvers = append(methodResolvers, methodResolver)
 	}
+	methodResolvers = append(methodResolvers, resolvers...)
 
 	multiMethodResolver, err := resolution.NewResolver(methodResolvers...)
 	if err != nil {
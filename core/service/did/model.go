@@ -108,3 +108,21 @@ const (
 	AnchoredStatus    UpdateRequestStatus = "anchored"
 	DoneStatus        UpdateRequestStatus = "done"
 )
+
+type GetControllersRequest struct {
+	DID string `json:"did" validate:"required"`
+}
+
+// GetControllersResponse lists the controllers of a did recorded in the did registry, which are none for a did unknown
+// to the registry
+type GetControllersResponse struct {
+	DID         string   `json:"did"`
+	Controllers []string `json:"controllers"`
+	// Current is the controller authorized to act for the did
+	Current string `json:"current,omitempty"`
+}
+
+type UpdateControllerRequest struct {
+	DID        string `json:"did" validate:"required"`
+	Controller string `json:"controller" validate:"required"`
+}
@@ -0,0 +1,197 @@
+package did
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/cryptosuite"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/pkh"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	"github.com/TBD54566975/ssi-sdk/util"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/fapiper/onchain-access-control/core/service/operation"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+)
+
+const (
+	eip155Prefix           = pkh.DIDPKHPrefix + ":eip155:"
+	blockchainAccountIDKey = "#blockchainAccountId"
+	controllerKeyPrefix    = "#controller-"
+)
+
+// Registry is the did registry recording the controllers of dids, which access contexts owned by a did authorize
+// through. It is implemented by the rpc service.
+type Registry interface {
+	GetControllers(ctx context.Context, did string) ([]ethcommon.Address, ethcommon.Address, error)
+	AddController(ctx context.Context, params rpc.DIDControllerParams) (*types.Transaction, error)
+	RemoveController(ctx context.Context, params rpc.DIDControllerParams) (*types.Transaction, error)
+	ChangeController(ctx context.Context, params rpc.DIDControllerParams) (*types.Transaction, error)
+}
+
+var _ Registry = (*rpc.Service)(nil)
+
+// Tracker follows the transactions changing the controllers of dids. It is implemented by the operation trackers.
+type Tracker interface {
+	Track(ctx context.Context, tx *types.Transaction, followUp *operation.FollowUp) (*operation.Operation, error)
+}
+
+var _ Tracker = (*operation.Trackers)(nil)
+
+// GetControllers returns the controllers of a did recorded in the did registry
+func (s *Service) GetControllers(ctx context.Context, request GetControllersRequest) (*GetControllersResponse, error) {
+	if s.registry == nil {
+		return nil, util.LoggingNewError("no did registry configured")
+	}
+	if err := util.IsValidStruct(request); err != nil {
+		return nil, util.LoggingErrorMsg(err, "invalid get controllers request")
+	}
+
+	controllers, current, err := s.registry.GetControllers(ctx, request.DID)
+	if err != nil {
+		return nil, util.LoggingErrorMsgf(err, "could not get controllers of did<%s>", request.DID)
+	}
+	response := GetControllersResponse{DID: request.DID, Controllers: make([]string, 0, len(controllers))}
+	for _, controller := range controllers {
+		response.Controllers = append(response.Controllers, controller.Hex())
+	}
+	if len(controllers) > 0 {
+		response.Current = current.Hex()
+	}
+	return &response, nil
+}
+
+// AddController adds a controller of a did, sent by the wallet of this instance as the current controller of the did.
+// The returned operation is done once the transaction is confirmed.
+func (s *Service) AddController(ctx context.Context, request UpdateControllerRequest) (*operation.Operation, error) {
+	return s.updateController(ctx, request, "add", Registry.AddController)
+}
+
+// RemoveController removes a controller of a did other than its current one
+func (s *Service) RemoveController(ctx context.Context, request UpdateControllerRequest) (*operation.Operation, error) {
+	return s.updateController(ctx, request, "remove", Registry.RemoveController)
+}
+
+// ChangeController makes a controller of a did its current one. The wallet of this instance can no longer act for the
+// did afterwards, unless it is the new controller.
+func (s *Service) ChangeController(ctx context.Context, request UpdateControllerRequest) (*operation.Operation, error) {
+	return s.updateController(ctx, request, "change", Registry.ChangeController)
+}
+
+func (s *Service) updateController(ctx context.Context, request UpdateControllerRequest, action string, update func(Registry, context.Context, rpc.DIDControllerParams) (*types.Transaction, error)) (*operation.Operation, error) {
+	if s.registry == nil {
+		return nil, util.LoggingNewError("no did registry configured")
+	}
+	if err := util.IsValidStruct(request); err != nil {
+		return nil, util.LoggingErrorMsgf(err, "invalid %s controller request", action)
+	}
+	if !ethcommon.IsHexAddress(request.Controller) {
+		return nil, util.LoggingNewErrorf("invalid controller address: %s", request.Controller)
+	}
+
+	tx, err := update(s.registry, ctx, rpc.DIDControllerParams{DID: request.DID, Controller: ethcommon.HexToAddress(request.Controller)})
+	if err != nil {
+		return nil, util.LoggingErrorMsgf(err, "could not %s controller<%s> of did<%s>", action, request.Controller, request.DID)
+	}
+	op, err := s.tracker.Track(ctx, tx, nil)
+	if err != nil {
+		return nil, util.LoggingErrorMsgf(err, "could not track %s controller<%s> of did<%s> in tx<%s>", action, request.Controller, request.DID, tx.Hash())
+	}
+	return op, nil
+}
+
+// pkhResolver resolves the did:pkh of eip155 accounts to documents listing the controllers the did registry records
+// for the did. The current controller authenticates and invokes capabilities for the did, so that the wallet
+// administering the access contexts of an owner can be rotated without losing them.
+type pkhResolver struct {
+	registry Registry
+}
+
+var _ resolution.Resolver = (*pkhResolver)(nil)
+
+func (r pkhResolver) Resolve(ctx context.Context, did string, _ ...resolution.Option) (*resolution.Result, error) {
+	chainID, account, err := parseEIP155DID(did)
+	if err != nil {
+		return nil, err
+	}
+	controllers, current, err := r.registry.GetControllers(ctx, did)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting controllers of did<%s>", did)
+	}
+	doc, err := pkhDocument(did, chainID, account, controllers, current)
+	if err != nil {
+		return nil, err
+	}
+	return &resolution.Result{Document: *doc}, nil
+}
+
+func (pkhResolver) Methods() []didsdk.Method {
+	return []didsdk.Method{didsdk.PKHMethod}
+}
+
+// parseEIP155DID returns the chain id and the account of a did:pkh of an eip155 chain
+func parseEIP155DID(did string) (string, ethcommon.Address, error) {
+	if !strings.HasPrefix(did, eip155Prefix) {
+		return "", ethcommon.Address{}, errors.Errorf("not a did:pkh of an eip155 chain: %s", did)
+	}
+	chainID, account, found := strings.Cut(strings.TrimPrefix(did, eip155Prefix), ":")
+	if !found || chainID == "" || !ethcommon.IsHexAddress(account) {
+		return "", ethcommon.Address{}, errors.Errorf("invalid did:pkh of an eip155 chain: %s", did)
+	}
+	return chainID, ethcommon.HexToAddress(account), nil
+}
+
+// pkhDocument builds the document of a did:pkh of an eip155 account. The controllers recorded for it other than the
+// account itself are listed as verification methods, and the current one is the controller of the document. Without
+// recorded controllers the account controls the did itself.
+func pkhDocument(did, chainID string, account ethcommon.Address, controllers []ethcommon.Address, current ethcommon.Address) (*didsdk.Document, error) {
+	pkhContext, err := pkh.GetDIDPKHContext()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting did:pkh context")
+	}
+	contextJSON, err := util.ToJSONInterface(pkhContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "converting did:pkh context to json")
+	}
+
+	accountMethod := did + blockchainAccountIDKey
+	doc := didsdk.Document{
+		Context:            contextJSON,
+		ID:                 did,
+		VerificationMethod: []didsdk.VerificationMethod{eip155VerificationMethod(accountMethod, did, chainID, account)},
+		AssertionMethod:    []didsdk.VerificationMethodSet{accountMethod},
+	}
+
+	currentMethod := accountMethod
+	for i, controller := range controllers {
+		if controller == account {
+			continue
+		}
+		id := fmt.Sprintf("%s%s%d", did, controllerKeyPrefix, i)
+		controllerDID := eip155Prefix + chainID + ":" + controller.Hex()
+		doc.VerificationMethod = append(doc.VerificationMethod, eip155VerificationMethod(id, controllerDID, chainID, controller))
+		if controller == current {
+			currentMethod = id
+			doc.Controller = controllerDID
+		}
+	}
+
+	authorized := []didsdk.VerificationMethodSet{currentMethod}
+	doc.Authentication = authorized
+	doc.CapabilityInvocation = authorized
+	doc.CapabilityDelegation = authorized
+	return &doc, nil
+}
+
+func eip155VerificationMethod(id, controller, chainID string, account ethcommon.Address) didsdk.VerificationMethod {
+	return didsdk.VerificationMethod{
+		ID:                  id,
+		Type:                cryptosuite.LDKeyType(pkh.ECDSASECP256k1RecoveryMethod2020),
+		Controller:          controller,
+		BlockchainAccountID: fmt.Sprintf("eip155:%s:%s", chainID, account.Hex()),
+	}
+}
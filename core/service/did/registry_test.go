@@ -0,0 +1,186 @@
+package did
+
+import (
+	"context"
+	"testing"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/service/operation"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+)
+
+// testRegistry is a did registry keeping the controllers of dids in memory, which applies changes once they are sent
+type testRegistry struct {
+	controllers map[string][]ethcommon.Address
+	current     map[string]ethcommon.Address
+	sent        uint64
+	err         error
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{
+		controllers: make(map[string][]ethcommon.Address),
+		current:     make(map[string]ethcommon.Address),
+	}
+}
+
+func (r *testRegistry) send() (*types.Transaction, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.sent++
+	return types.NewTx(&types.LegacyTx{Nonce: r.sent}), nil
+}
+
+// testTracker tracks transactions by their hash
+type testTracker struct {
+	tracked []ethcommon.Hash
+}
+
+func (t *testTracker) Track(_ context.Context, tx *types.Transaction, _ *operation.FollowUp) (*operation.Operation, error) {
+	t.tracked = append(t.tracked, tx.Hash())
+	return &operation.Operation{ID: tx.Hash().Hex()}, nil
+}
+
+func (r *testRegistry) GetControllers(_ context.Context, did string) ([]ethcommon.Address, ethcommon.Address, error) {
+	return r.controllers[did], r.current[did], nil
+}
+
+func (r *testRegistry) AddController(_ context.Context, params rpc.DIDControllerParams) (*types.Transaction, error) {
+	if len(r.controllers[params.DID]) == 0 {
+		r.current[params.DID] = params.Controller
+	}
+	r.controllers[params.DID] = append(r.controllers[params.DID], params.Controller)
+	return r.send()
+}
+
+func (r *testRegistry) RemoveController(_ context.Context, params rpc.DIDControllerParams) (*types.Transaction, error) {
+	var controllers []ethcommon.Address
+	for _, controller := range r.controllers[params.DID] {
+		if controller != params.Controller {
+			controllers = append(controllers, controller)
+		}
+	}
+	r.controllers[params.DID] = controllers
+	return r.send()
+}
+
+func (r *testRegistry) ChangeController(_ context.Context, params rpc.DIDControllerParams) (*types.Transaction, error) {
+	r.current[params.DID] = params.Controller
+	return r.send()
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	owner := "did:pkh:eip155:80001:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2"
+	account := ethcommon.HexToAddress("0xd231120Eea6201B142b4048cf6C86BaC2A0655D2")
+	rotated := ethcommon.HexToAddress("0x7F3b8A4dC1B3a1Ee02d1e3F9B6C2aD8B0A4f8e21")
+
+	t.Run("manages the controllers of a did", func(tt *testing.T) {
+		tracker := new(testTracker)
+		service := Service{registry: newTestRegistry(), tracker: tracker}
+		for _, update := range []struct {
+			update     func(context.Context, UpdateControllerRequest) (*operation.Operation, error)
+			controller ethcommon.Address
+		}{
+			{service.AddController, account},
+			{service.AddController, rotated},
+			{service.ChangeController, rotated},
+			{service.RemoveController, account},
+		} {
+			op, err := update.update(ctx, UpdateControllerRequest{DID: owner, Controller: update.controller.Hex()})
+			require.NoError(tt, err)
+			assert.Equal(tt, tracker.tracked[len(tracker.tracked)-1].Hex(), op.ID)
+		}
+		assert.Len(tt, tracker.tracked, 4)
+
+		controllers, err := service.GetControllers(ctx, GetControllersRequest{DID: owner})
+		require.NoError(tt, err)
+		assert.Equal(tt, &GetControllersResponse{DID: owner, Controllers: []string{rotated.Hex()}, Current: rotated.Hex()}, controllers)
+	})
+
+	t.Run("returns no controllers of an unknown did", func(tt *testing.T) {
+		service := Service{registry: newTestRegistry()}
+		controllers, err := service.GetControllers(ctx, GetControllersRequest{DID: owner})
+		require.NoError(tt, err)
+		assert.Empty(tt, controllers.Controllers)
+		assert.Empty(tt, controllers.Current)
+	})
+
+	t.Run("rejects bad controller requests", func(tt *testing.T) {
+		registry := newTestRegistry()
+		tracker := new(testTracker)
+		service := Service{registry: registry, tracker: tracker}
+		_, err := service.AddController(ctx, UpdateControllerRequest{DID: owner, Controller: "not-an-address"})
+		assert.ErrorContains(tt, err, "invalid controller address")
+		_, err = service.AddController(ctx, UpdateControllerRequest{Controller: account.Hex()})
+		assert.ErrorContains(tt, err, "invalid add controller request")
+
+		registry.err = errors.New("execution reverted")
+		_, err = service.ChangeController(ctx, UpdateControllerRequest{DID: owner, Controller: rotated.Hex()})
+		assert.ErrorContains(tt, err, "could not change controller")
+		assert.Empty(tt, tracker.tracked)
+	})
+
+	t.Run("fails without a did registry", func(tt *testing.T) {
+		service := Service{}
+		_, err := service.GetControllers(ctx, GetControllersRequest{DID: owner})
+		assert.ErrorContains(tt, err, "no did registry configured")
+		_, err = service.RemoveController(ctx, UpdateControllerRequest{DID: owner, Controller: account.Hex()})
+		assert.ErrorContains(tt, err, "no did registry configured")
+	})
+}
+
+func TestPKHResolver(t *testing.T) {
+	ctx := context.Background()
+	owner := "did:pkh:eip155:80001:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2"
+	account := ethcommon.HexToAddress("0xd231120Eea6201B142b4048cf6C86BaC2A0655D2")
+	rotated := ethcommon.HexToAddress("0x7F3b8A4dC1B3a1Ee02d1e3F9B6C2aD8B0A4f8e21")
+
+	t.Run("resolves a did without controllers to its account", func(tt *testing.T) {
+		resolver := pkhResolver{registry: newTestRegistry()}
+		result, err := resolver.Resolve(ctx, owner)
+		require.NoError(tt, err)
+
+		doc := result.Document
+		assert.Equal(tt, owner, doc.ID)
+		assert.Empty(tt, doc.Controller)
+		require.Len(tt, doc.VerificationMethod, 1)
+		assert.Equal(tt, "eip155:80001:"+account.Hex(), doc.VerificationMethod[0].BlockchainAccountID)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{owner + "#blockchainAccountId"}, doc.Authentication)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{owner + "#blockchainAccountId"}, doc.AssertionMethod)
+	})
+
+	t.Run("resolves a did to its current controller", func(tt *testing.T) {
+		registry := newTestRegistry()
+		registry.controllers[owner] = []ethcommon.Address{account, rotated}
+		registry.current[owner] = rotated
+		resolver := pkhResolver{registry: registry}
+		result, err := resolver.Resolve(ctx, owner)
+		require.NoError(tt, err)
+
+		doc := result.Document
+		rotatedDID := "did:pkh:eip155:80001:" + rotated.Hex()
+		assert.Equal(tt, rotatedDID, doc.Controller)
+		require.Len(tt, doc.VerificationMethod, 2)
+		assert.Equal(tt, owner+"#controller-1", doc.VerificationMethod[1].ID)
+		assert.Equal(tt, rotatedDID, doc.VerificationMethod[1].Controller)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{owner + "#controller-1"}, doc.Authentication)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{owner + "#controller-1"}, doc.CapabilityInvocation)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{owner + "#blockchainAccountId"}, doc.AssertionMethod)
+	})
+
+	t.Run("rejects dids of other chains", func(tt *testing.T) {
+		resolver := pkhResolver{registry: newTestRegistry()}
+		_, err := resolver.Resolve(ctx, "did:pkh:bip122:000000000019d6689c085ae165831e93:128Lkh3S7CkDTBZ8W7BbpsN3YYizJMp8p6")
+		assert.ErrorContains(tt, err, "not a did:pkh of an eip155 chain")
+		_, err = resolver.Resolve(ctx, "did:pkh:eip155:80001:not-an-address")
+		assert.ErrorContains(tt, err, "invalid did:pkh")
+	})
+}
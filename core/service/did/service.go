@@ -25,6 +25,11 @@ type Service struct {
 	// resolver for DID methods
 	resolver *resolution.ServiceResolver
 
+	// registry of the controllers of dids, nil if there is none
+	registry Registry
+	// tracker of the transactions sent to the registry
+	tracker Tracker
+
 	// external dependencies
 	keyStore          *keystore.Service
 	keyStoreFactory   keystore.ServiceFactory
@@ -67,7 +72,13 @@ func (s *Service) GetResolver() didresolution.Resolver {
 	return s.resolver
 }
 
-func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage, keyStore *keystore.Service, factory keystore.ServiceFactory) (*Service, error) {
+// NewDIDService returns the did service. With a did registry, it manages the controllers of dids and resolves did:pkh
+// documents listing them. The tracker follows the transactions changing the controllers, and is required along with
+// the registry.
+func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage, keyStore *keystore.Service, factory keystore.ServiceFactory, registry Registry, tracker Tracker) (*Service, error) {
+	if registry != nil && tracker == nil {
+		return nil, errors.New("a did registry requires a tracker")
+	}
 	didStorage, err := NewDIDStorage(s)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not instantiate DID storage for the DID service")
@@ -80,6 +91,8 @@ func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage, key
 		handlers:          make(map[didsdk.Method]MethodHandler),
 		keyStore:          keyStore,
 		keyStoreFactory:   factory,
+		registry:          registry,
+		tracker:           tracker,
 	}
 
 	// instantiate all handlers for DID methods
@@ -89,8 +102,12 @@ func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage, key
 		}
 	}
 
-	// create handler resolver first, which wraps our handlers as a resolver
-	hr, err := NewHandlerResolver(service.handlers)
+	// create handler resolver first, which wraps our handlers as a resolver, along with the did:pkh resolver of the registry
+	var resolvers []didresolution.Resolver
+	if registry != nil {
+		resolvers = append(resolvers, pkhResolver{registry: registry})
+	}
+	hr, err := NewHandlerResolver(service.handlers, resolvers...)
 	if err != nil {
 		return nil, errors.Wrap(err, "instantiating handler resolver")
 	}
@@ -21,6 +21,8 @@ const (
 	Presentation     Type = "presentation"
 	Operation        Type = "operation"
 	DIDConfiguration Type = "did_configuration"
+	Indexer          Type = "indexer"
+	Prover           Type = "prover"
 
 	StatusReady    StatusState = "ready"
 	StatusNotReady StatusState = "not_ready"
@@ -0,0 +1,187 @@
+package indexer
+
+import (
+	"context"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// eventParser decodes the logs of all indexed contracts
+type eventParser struct {
+	handler  *contracts.AccessContextHandlerFilterer
+	context  *contracts.AccessContextFilterer
+	sessions *contracts.SessionRegistryFilterer
+	dids     *contracts.SimpleDIDRegistryFilterer
+
+	createContextInstance ethcommon.Hash
+	ownershipTransferred  ethcommon.Hash
+	roleGranted           ethcommon.Hash
+	roleRevoked           ethcommon.Hash
+	sessionStarted        ethcommon.Hash
+	sessionRevoked        ethcommon.Hash
+	didControllerChanged  ethcommon.Hash
+}
+
+func newEventParser() (*eventParser, error) {
+	var (
+		p   eventParser
+		err error
+	)
+	// filterers are only used to unpack logs, so they are not bound to an address or backend
+	if p.handler, err = contracts.NewAccessContextHandlerFilterer(ethcommon.Address{}, nil); err != nil {
+		return nil, err
+	}
+	if p.context, err = contracts.NewAccessContextFilterer(ethcommon.Address{}, nil); err != nil {
+		return nil, err
+	}
+	if p.sessions, err = contracts.NewSessionRegistryFilterer(ethcommon.Address{}, nil); err != nil {
+		return nil, err
+	}
+	if p.dids, err = contracts.NewSimpleDIDRegistryFilterer(ethcommon.Address{}, nil); err != nil {
+		return nil, err
+	}
+
+	handlerABI, err := contracts.AccessContextHandlerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contextABI, err := contracts.AccessContextMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	sessionABI, err := contracts.SessionRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	didABI, err := contracts.SimpleDIDRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	p.createContextInstance = handlerABI.Events["CreateContextInstance"].ID
+	p.ownershipTransferred = contextABI.Events["OwnershipTransferred"].ID
+	p.roleGranted = contextABI.Events["RoleGranted"].ID
+	p.roleRevoked = contextABI.Events["RoleRevoked"].ID
+	p.sessionStarted = sessionABI.Events["SessionStarted"].ID
+	p.sessionRevoked = sessionABI.Events["SessionRevoked"].ID
+	p.didControllerChanged = didABI.Events["DIDControllerChanged"].ID
+	return &p, nil
+}
+
+// newContexts returns the addresses of all access contexts created within the given logs of the context handler
+func (p *eventParser) newContexts(logs []types.Log) []ethcommon.Address {
+	var addresses []ethcommon.Address
+	for _, log := range logs {
+		if len(log.Topics) == 0 || log.Topics[0] != p.createContextInstance {
+			continue
+		}
+		event, err := p.handler.ParseCreateContextInstance(log)
+		if err != nil {
+			logrus.WithError(err).Warnf("could not parse context instance creation in tx<%s>", log.TxHash)
+			continue
+		}
+		addresses = append(addresses, event.AccessContext)
+	}
+	return addresses
+}
+
+// apply updates the read model with a single log
+func (s Service) apply(ctx context.Context, log types.Log) error {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	p := s.events
+
+	switch {
+	case log.Address == s.contracts.ContextHandler && log.Topics[0] == p.createContextInstance:
+		event, err := p.handler.ParseCreateContextInstance(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing context instance creation")
+		}
+		return s.updateContext(ctx, event.AccessContext, log.BlockNumber, nil)
+
+	case log.Address == s.contracts.SessionRegistry && log.Topics[0] == p.sessionStarted:
+		event, err := p.sessions.ParseSessionStarted(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing session start")
+		}
+		return s.storage.PutSession(ctx, IndexedSession{
+			ID:         event.Id,
+			User:       event.User,
+			Expiration: event.Expiration.Uint64(),
+			Token:      event.Token,
+			Block:      log.BlockNumber,
+		})
+
+	case log.Address == s.contracts.SessionRegistry && log.Topics[0] == p.sessionRevoked:
+		event, err := p.sessions.ParseSessionRevoked(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing session revocation")
+		}
+		return s.storage.DeleteSession(ctx, event.Id)
+
+	case log.Address == s.contracts.DIDRegistry && log.Topics[0] == p.didControllerChanged:
+		event, err := p.dids.ParseDIDControllerChanged(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing did controller change")
+		}
+		return s.storage.PutDIDController(ctx, DIDController{
+			Identity:   event.Identity,
+			Controller: event.Controller,
+			Block:      log.BlockNumber,
+		})
+
+	case s.isContext(log.Address) && log.Topics[0] == p.ownershipTransferred:
+		event, err := p.context.ParseOwnershipTransferred(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing ownership transfer")
+		}
+		owner := ethcommon.Hash(event.NewOwner)
+		return s.updateContext(ctx, log.Address, log.BlockNumber, &owner)
+
+	case s.isContext(log.Address) && log.Topics[0] == p.roleGranted:
+		event, err := p.context.ParseRoleGranted(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing role grant")
+		}
+		return s.storage.PutRoleHolder(ctx, RoleHolder{
+			Context: log.Address,
+			Role:    event.Role,
+			DID:     event.Did,
+			Block:   log.BlockNumber,
+		})
+
+	case s.isContext(log.Address) && log.Topics[0] == p.roleRevoked:
+		event, err := p.context.ParseRoleRevoked(log)
+		if err != nil {
+			return errors.Wrap(err, "parsing role revocation")
+		}
+		return s.storage.DeleteRoleHolder(ctx, log.Address, event.Role, event.Did)
+	}
+	return nil
+}
+
+// isContext reports whether logs of an address belong to an access context instance, i.e. any indexed address other
+// than the singleton contracts
+func (s Service) isContext(address ethcommon.Address) bool {
+	return address != s.contracts.ContextHandler && address != s.contracts.SessionRegistry && address != s.contracts.DIDRegistry
+}
+
+// updateContext creates or updates an indexed context. The ownership of a context is transferred during its
+// initialization, which is logged before the context handler logs its creation.
+func (s Service) updateContext(ctx context.Context, address ethcommon.Address, block uint64, owner *ethcommon.Hash) error {
+	stored, err := s.storage.GetContext(ctx, address)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		stored = &IndexedContext{Address: address}
+	}
+	if owner != nil {
+		stored.Owner = *owner
+	}
+	stored.Block = block
+	return s.storage.PutContext(ctx, *stored)
+}
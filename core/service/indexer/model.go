@@ -0,0 +1,117 @@
+package indexer
+
+import (
+	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fapiper/onchain-access-control/core/server/pagination"
+)
+
+// Checkpoint is the last block processed by the indexer
+type Checkpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// IndexedContext is an access context instance created by the context handler
+type IndexedContext struct {
+	Address common.Address `json:"address"`
+	// Hash of the DID owning the context
+	Owner common.Hash `json:"owner"`
+	// Block in which the context was last updated
+	Block uint64 `json:"block"`
+}
+
+// RoleHolder is a DID holding a role within an access context
+type RoleHolder struct {
+	Context common.Address `json:"context"`
+	Role    common.Hash    `json:"role"`
+	DID     common.Hash    `json:"did"`
+	Block   uint64         `json:"block"`
+}
+
+// IndexedSession is a session started in the session registry
+type IndexedSession struct {
+	ID   common.Hash `json:"id"`
+	User common.Hash `json:"user"`
+	// Unix time in seconds at which the session expires
+	Expiration uint64 `json:"expiration"`
+	// The session token, encrypted to the key agreement key of the resource owner
+	Token []byte `json:"token,omitempty"`
+	Block uint64 `json:"block"`
+}
+
+// DIDController is the controller of a DID in the DID registry
+type DIDController struct {
+	Identity   common.Hash    `json:"identity"`
+	Controller common.Address `json:"controller"`
+	Block      uint64         `json:"block"`
+}
+
+type ListContextsRequest struct {
+	PageRequest *pagination.PageRequest
+}
+
+type ListContextsResponse struct {
+	Contexts      []IndexedContext `json:"contexts"`
+	NextPageToken string           `json:"nextPageToken"`
+}
+
+type GetContextRequest struct {
+	Address string `json:"address" validate:"required"`
+}
+
+func (r GetContextRequest) IsValid() bool {
+	return util.IsValidStruct(r) == nil && common.IsHexAddress(r.Address)
+}
+
+type GetContextResponse struct {
+	Context IndexedContext `json:"context"`
+}
+
+type ListRoleHoldersRequest struct {
+	// Address of the access context
+	Context string `json:"context" validate:"required"`
+	// Role to filter by, either its id or its hash
+	Role string `json:"role,omitempty"`
+}
+
+func (r ListRoleHoldersRequest) IsValid() bool {
+	return util.IsValidStruct(r) == nil && common.IsHexAddress(r.Context)
+}
+
+type ListRoleHoldersResponse struct {
+	RoleHolders []RoleHolder `json:"roleHolders"`
+}
+
+type ListSessionsRequest struct {
+	// User to filter by, either the DID or its hash
+	User string `json:"user,omitempty"`
+}
+
+type ListSessionsResponse struct {
+	Sessions []IndexedSession `json:"sessions"`
+}
+
+type GetDIDControllerRequest struct {
+	// Either the DID or its hash
+	DID string `json:"did" validate:"required"`
+}
+
+func (r GetDIDControllerRequest) IsValid() bool {
+	return util.IsValidStruct(r) == nil
+}
+
+type GetDIDControllerResponse struct {
+	Controller DIDController `json:"controller"`
+}
+
+// ToHash returns the hash of an identifier as used on-chain. Values that already are 32 byte hex strings are
+// returned as is.
+func ToHash(id string) common.Hash {
+	if b, err := hexutil.Decode(id); err == nil && len(b) == common.HashLength {
+		return common.BytesToHash(b)
+	}
+	return crypto.Keccak256Hash([]byte(id))
+}
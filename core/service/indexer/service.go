@@ -0,0 +1,199 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fapiper/onchain-access-control/core/config"
+	"github.com/fapiper/onchain-access-control/core/service/framework"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/storage"
+	"github.com/pkg/errors"
+	"math/big"
+	"time"
+)
+
+// Chain is the part of the rpc service the indexer reads from
+type Chain interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, params rpc.FilterLogsParams) ([]types.Log, error)
+}
+
+// Contracts are the addresses of the contracts whose logs are indexed. Access context instances are discovered from
+// the logs of the context handler.
+type Contracts struct {
+	ContextHandler  ethcommon.Address
+	SessionRegistry ethcommon.Address
+	DIDRegistry     ethcommon.Address
+}
+
+type Service struct {
+	config    config.IndexerServiceConfig
+	chainID   uint64
+	storage   *Storage
+	chain     Chain
+	contracts Contracts
+	events    *eventParser
+}
+
+func (s Service) Type() framework.Type {
+	return framework.Indexer
+}
+
+func (s Service) Status() framework.Status {
+	ae := sdkutil.NewAppendError()
+	if s.storage == nil {
+		ae.AppendString("no storage configured")
+	}
+	if s.chain == nil {
+		ae.AppendString("no chain configured")
+	}
+	if !ae.IsEmpty() {
+		return framework.Status{
+			Status:  framework.StatusNotReady,
+			Message: fmt.Sprintf("indexer service is not ready: %s", ae.Error().Error()),
+		}
+	}
+	return framework.Status{Status: framework.StatusReady}
+}
+
+// NewIndexerServices returns one indexer per chain of the rpc service, starting with the indexer of the default chain.
+// The indexers of the other chains keep their data apart from the data of the default chain.
+func NewIndexerServices(config config.IndexerServiceConfig, s storage.ServiceStorage, rpcService *rpc.Service) ([]*Service, error) {
+	if rpcService == nil {
+		return nil, errors.New("rpc service cannot be nil")
+	}
+	chains := rpcService.Chains()
+	services := make([]*Service, 0, len(chains))
+	for i, chain := range chains {
+		indexerStorage, err := NewIndexerStorage(s)
+		if i > 0 {
+			indexerStorage, err = NewChainIndexerStorage(s, chain.ChainID())
+		}
+		if err != nil {
+			return nil, sdkutil.LoggingErrorMsgf(err, "instantiating storage for the indexer of chain %d", chain.ChainID())
+		}
+		service, err := newIndexerService(config, indexerStorage, chain, Contracts{
+			ContextHandler:  chain.ContextHandler.Address(),
+			SessionRegistry: chain.SessionRegistry.Address(),
+			DIDRegistry:     chain.DIDRegistry.Address(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		service.chainID = chain.ChainID()
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+func newIndexerService(config config.IndexerServiceConfig, indexerStorage *Storage, chain Chain, contracts Contracts) (*Service, error) {
+	events, err := newEventParser()
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "instantiating event parser for the indexer service")
+	}
+	service := Service{
+		config:    config,
+		storage:   indexerStorage,
+		chain:     chain,
+		contracts: contracts,
+		events:    events,
+	}
+	if !service.Status().IsReady() {
+		return nil, errors.New(service.Status().Message)
+	}
+	return &service, nil
+}
+
+// GetCheckpoint returns the last block processed by the indexer
+func (s Service) GetCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	checkpoint, err := s.storage.GetCheckpoint(ctx)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "error getting checkpoint")
+	}
+	if checkpoint == nil {
+		return nil, sdkutil.LoggingNewError("no blocks indexed yet")
+	}
+	return checkpoint, nil
+}
+
+// ListContexts returns a page of indexed access contexts
+func (s Service) ListContexts(ctx context.Context, request ListContextsRequest) (*ListContextsResponse, error) {
+	stored, err := s.storage.ListContexts(ctx, *request.PageRequest.ToServicePage())
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "error getting contexts")
+	}
+	return &ListContextsResponse{Contexts: stored.Contexts, NextPageToken: stored.NextPageToken}, nil
+}
+
+// GetContext returns an indexed access context by its address
+func (s Service) GetContext(ctx context.Context, request GetContextRequest) (*GetContextResponse, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid get context request: %+v", request)
+	}
+	stored, err := s.storage.GetContext(ctx, ethcommon.HexToAddress(request.Address))
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "error getting context: %s", request.Address)
+	}
+	if stored == nil {
+		return nil, sdkutil.LoggingNewErrorf("could not find context: %s", request.Address)
+	}
+	return &GetContextResponse{Context: *stored}, nil
+}
+
+// ListRoleHolders returns the DIDs holding roles within an access context
+func (s Service) ListRoleHolders(ctx context.Context, request ListRoleHoldersRequest) (*ListRoleHoldersResponse, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid list role holders request: %+v", request)
+	}
+	var role *ethcommon.Hash
+	if request.Role != "" {
+		hash := ToHash(request.Role)
+		role = &hash
+	}
+	holders, err := s.storage.ListRoleHolders(ctx, ethcommon.HexToAddress(request.Context), role)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "error getting role holders of context: %s", request.Context)
+	}
+	return &ListRoleHoldersResponse{RoleHolders: holders}, nil
+}
+
+// ListSessions returns all sessions that are not revoked and not yet expired
+func (s Service) ListSessions(ctx context.Context, request ListSessionsRequest) (*ListSessionsResponse, error) {
+	stored, err := s.storage.ListSessions(ctx)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "error getting sessions")
+	}
+
+	var user *ethcommon.Hash
+	if request.User != "" {
+		hash := ToHash(request.User)
+		user = &hash
+	}
+	now := uint64(time.Now().Unix())
+	sessions := make([]IndexedSession, 0, len(stored))
+	for _, session := range stored {
+		if session.Expiration < now || (user != nil && session.User != *user) {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	return &ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// GetDIDController returns the indexed controller of a DID
+func (s Service) GetDIDController(ctx context.Context, request GetDIDControllerRequest) (*GetDIDControllerResponse, error) {
+	if !request.IsValid() {
+		return nil, errors.Errorf("invalid get did controller request: %+v", request)
+	}
+	stored, err := s.storage.GetDIDController(ctx, ToHash(request.DID))
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "error getting controller of did: %s", request.DID)
+	}
+	if stored == nil {
+		return nil, sdkutil.LoggingNewErrorf("could not find controller of did: %s", request.DID)
+	}
+	return &GetDIDControllerResponse{Controller: *stored}, nil
+}
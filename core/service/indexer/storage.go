@@ -0,0 +1,423 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fapiper/onchain-access-control/core/service/common"
+	"github.com/fapiper/onchain-access-control/core/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	namespace     = "indexer"
+	checkpointKey = "latest"
+	snapshotKey   = "snapshot"
+)
+
+// Namespaces of the indexed data, relative to the namespace of the chain of a storage
+var (
+	checkpointNamespace = "checkpoint"
+	blockNamespace      = "block"
+	logNamespace        = "log"
+	contextNamespace    = "context"
+	roleNamespace       = "role"
+	sessionNamespace    = "session"
+	didNamespace        = "did"
+	snapshotNamespace   = "snapshot"
+
+	// readModelNamespaces hold state derived from the stored logs, which is rebuilt after a reorg
+	readModelNamespaces = []string{contextNamespace, roleNamespace, sessionNamespace, didNamespace}
+)
+
+type StoredContexts struct {
+	Contexts      []IndexedContext
+	NextPageToken string
+}
+
+type Storage struct {
+	db storage.ServiceStorage
+	// namespace holds the data indexed from one chain
+	namespace string
+}
+
+// NewIndexerStorage returns the storage of the indexer of the default chain
+func NewIndexerStorage(db storage.ServiceStorage) (*Storage, error) {
+	if db == nil {
+		return nil, errors.New("db reference is nil")
+	}
+	return &Storage{db: db, namespace: namespace}, nil
+}
+
+// NewChainIndexerStorage returns the storage of the indexer of a chain other than the default chain, which keeps its
+// data apart from the data of the default chain
+func NewChainIndexerStorage(db storage.ServiceStorage, chainID uint64) (*Storage, error) {
+	if db == nil {
+		return nil, errors.New("db reference is nil")
+	}
+	return &Storage{db: db, namespace: storage.Join(namespace, strconv.FormatUint(chainID, 10))}, nil
+}
+
+// ns returns the namespace of indexed data within the namespace of the chain
+func (s *Storage) ns(name string) string {
+	return storage.Join(s.namespace, name)
+}
+
+// snapshot returns the storage of the snapshot of the read model, which holds the state as of the snapshot checkpoint
+func (s *Storage) snapshot() *Storage {
+	return &Storage{db: s.db, namespace: s.ns(snapshotNamespace)}
+}
+
+// blockKey pads block numbers so that keys sort in block order
+func blockKey(number uint64) string {
+	return fmt.Sprintf("%020d", number)
+}
+
+func logKey(log types.Log) string {
+	return fmt.Sprintf("%s-%06d", blockKey(log.BlockNumber), log.Index)
+}
+
+func blockFromKey(key string) (uint64, error) {
+	number, _, _ := strings.Cut(key, "-")
+	return strconv.ParseUint(number, 10, 64)
+}
+
+func roleKey(context ethcommon.Address, role, did ethcommon.Hash) string {
+	return storage.Join(context.Hex(), role.Hex(), did.Hex())
+}
+
+func (s *Storage) write(ctx context.Context, namespace, key string, value any) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not marshal %s<%s>", namespace, key)
+	}
+	return s.db.Write(ctx, s.ns(namespace), key, bytes)
+}
+
+func (s *Storage) read(ctx context.Context, namespace, key string, value any) (bool, error) {
+	bytes, err := s.db.Read(ctx, s.ns(namespace), key)
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "reading %s<%s>", namespace, key)
+	}
+	if len(bytes) == 0 {
+		return false, nil
+	}
+	if err = json.Unmarshal(bytes, value); err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "unmarshalling %s<%s>", namespace, key)
+	}
+	return true, nil
+}
+
+func (s *Storage) delete(ctx context.Context, namespace, key string) error {
+	exists, err := s.db.Exists(ctx, s.ns(namespace), key)
+	if err != nil || !exists {
+		return err
+	}
+	return s.db.Delete(ctx, s.ns(namespace), key)
+}
+
+// deleteKeys deletes all keys of a namespace the keep function returns false for
+func (s *Storage) deleteKeys(ctx context.Context, namespace string, keep func(key string) bool) error {
+	keys, err := s.db.ReadAllKeys(ctx, s.ns(namespace))
+	if err != nil {
+		return errors.Wrapf(err, "reading keys of %s", namespace)
+	}
+	for _, key := range keys {
+		if keep != nil && keep(key) {
+			continue
+		}
+		if err = s.db.Delete(ctx, s.ns(namespace), key); err != nil {
+			return errors.Wrapf(err, "deleting %s<%s>", namespace, key)
+		}
+	}
+	return nil
+}
+
+// deleteBlocksAfter deletes all keys of a namespace that belong to blocks after the given number
+func (s *Storage) deleteBlocksAfter(ctx context.Context, namespace string, number uint64) error {
+	return s.deleteKeys(ctx, namespace, func(key string) bool {
+		block, err := blockFromKey(key)
+		return err == nil && block <= number
+	})
+}
+
+func (s *Storage) GetCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	var checkpoint Checkpoint
+	found, err := s.read(ctx, checkpointNamespace, checkpointKey, &checkpoint)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (s *Storage) SetCheckpoint(ctx context.Context, checkpoint Checkpoint) error {
+	return s.write(ctx, checkpointNamespace, checkpointKey, checkpoint)
+}
+
+func (s *Storage) DeleteCheckpoint(ctx context.Context) error {
+	return s.delete(ctx, checkpointNamespace, checkpointKey)
+}
+
+// GetSnapshotCheckpoint returns the last block whose logs are part of the snapshot of the read model
+func (s *Storage) GetSnapshotCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	var checkpoint Checkpoint
+	found, err := s.read(ctx, checkpointNamespace, snapshotKey, &checkpoint)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (s *Storage) SetSnapshotCheckpoint(ctx context.Context, checkpoint Checkpoint) error {
+	return s.write(ctx, checkpointNamespace, snapshotKey, checkpoint)
+}
+
+// InsertBlock remembers the hash of a processed block to detect reorgs
+func (s *Storage) InsertBlock(ctx context.Context, block Checkpoint) error {
+	return s.write(ctx, blockNamespace, blockKey(block.Number), block)
+}
+
+// ListBlocks returns all remembered blocks, latest first
+func (s *Storage) ListBlocks(ctx context.Context) ([]Checkpoint, error) {
+	gotBlocks, err := s.db.ReadAll(ctx, s.ns(blockNamespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading blocks")
+	}
+	blocks := make([]Checkpoint, 0, len(gotBlocks))
+	for _, blockBytes := range gotBlocks {
+		var block Checkpoint
+		if err = json.Unmarshal(blockBytes, &block); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored block: %s", string(blockBytes))
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Number > blocks[j].Number })
+	return blocks, nil
+}
+
+// PruneBlocks forgets all remembered blocks before the given number
+func (s *Storage) PruneBlocks(ctx context.Context, before uint64) error {
+	return s.deleteKeys(ctx, blockNamespace, func(key string) bool {
+		block, err := blockFromKey(key)
+		return err == nil && block >= before
+	})
+}
+
+func (s *Storage) InsertLog(ctx context.Context, log types.Log) error {
+	return s.write(ctx, logNamespace, logKey(log), log)
+}
+
+// ListLogs returns all stored logs in the order they were emitted
+func (s *Storage) ListLogs(ctx context.Context) ([]types.Log, error) {
+	gotLogs, err := s.db.ReadAll(ctx, s.ns(logNamespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading logs")
+	}
+	keys := make([]string, 0, len(gotLogs))
+	for key := range gotLogs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	logs := make([]types.Log, 0, len(keys))
+	for _, key := range keys {
+		var log types.Log
+		if err = json.Unmarshal(gotLogs[key], &log); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling stored log<%s>", key)
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// PruneLogs deletes all logs up to and including the given block number
+func (s *Storage) PruneLogs(ctx context.Context, number uint64) error {
+	return s.deleteKeys(ctx, logNamespace, func(key string) bool {
+		block, err := blockFromKey(key)
+		return err == nil && block > number
+	})
+}
+
+// Rollback deletes all logs and remembered blocks after the given block number and clears the read model
+func (s *Storage) Rollback(ctx context.Context, number uint64) error {
+	if err := s.deleteBlocksAfter(ctx, logNamespace, number); err != nil {
+		return err
+	}
+	if err := s.deleteBlocksAfter(ctx, blockNamespace, number); err != nil {
+		return err
+	}
+	return s.ClearReadModel(ctx)
+}
+
+// Reset deletes all indexed data
+func (s *Storage) Reset(ctx context.Context) error {
+	for _, ns := range []string{logNamespace, blockNamespace} {
+		if err := s.deleteKeys(ctx, ns, nil); err != nil {
+			return err
+		}
+	}
+	if err := s.DeleteCheckpoint(ctx); err != nil {
+		return err
+	}
+	if err := s.delete(ctx, checkpointNamespace, snapshotKey); err != nil {
+		return err
+	}
+	if err := s.snapshot().ClearReadModel(ctx); err != nil {
+		return err
+	}
+	return s.ClearReadModel(ctx)
+}
+
+func (s *Storage) ClearReadModel(ctx context.Context) error {
+	for _, ns := range readModelNamespaces {
+		if err := s.deleteKeys(ctx, ns, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreSnapshot copies the snapshot onto the cleared read model
+func (s *Storage) RestoreSnapshot(ctx context.Context) error {
+	snapshot := s.snapshot()
+	for _, ns := range readModelNamespaces {
+		entries, err := s.db.ReadAll(ctx, snapshot.ns(ns))
+		if err != nil {
+			return errors.Wrapf(err, "reading snapshot of %s", ns)
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		namespaces := make([]string, 0, len(entries))
+		keys := make([]string, 0, len(entries))
+		values := make([][]byte, 0, len(entries))
+		for key, value := range entries {
+			namespaces = append(namespaces, s.ns(ns))
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+		if err = s.db.WriteMany(ctx, namespaces, keys, values); err != nil {
+			return errors.Wrapf(err, "restoring snapshot of %s", ns)
+		}
+	}
+	return nil
+}
+
+func (s *Storage) PutContext(ctx context.Context, context IndexedContext) error {
+	return s.write(ctx, contextNamespace, context.Address.Hex(), context)
+}
+
+func (s *Storage) GetContext(ctx context.Context, address ethcommon.Address) (*IndexedContext, error) {
+	var context IndexedContext
+	found, err := s.read(ctx, contextNamespace, address.Hex(), &context)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &context, nil
+}
+
+func (s *Storage) ListContexts(ctx context.Context, page common.Page) (*StoredContexts, error) {
+	token, size := page.ToStorageArgs()
+	gotContexts, nextPageToken, err := s.db.ReadPage(ctx, s.ns(contextNamespace), token, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading page of contexts")
+	}
+	contexts := make([]IndexedContext, 0, len(gotContexts))
+	for _, contextBytes := range gotContexts {
+		var context IndexedContext
+		if err = json.Unmarshal(contextBytes, &context); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored context: %s", string(contextBytes))
+			continue
+		}
+		contexts = append(contexts, context)
+	}
+	return &StoredContexts{Contexts: contexts, NextPageToken: nextPageToken}, nil
+}
+
+func (s *Storage) ListContextAddresses(ctx context.Context) ([]ethcommon.Address, error) {
+	keys, err := s.db.ReadAllKeys(ctx, s.ns(contextNamespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading context keys")
+	}
+	addresses := make([]ethcommon.Address, 0, len(keys))
+	for _, key := range keys {
+		addresses = append(addresses, ethcommon.HexToAddress(key))
+	}
+	return addresses, nil
+}
+
+func (s *Storage) PutRoleHolder(ctx context.Context, holder RoleHolder) error {
+	return s.write(ctx, roleNamespace, roleKey(holder.Context, holder.Role, holder.DID), holder)
+}
+
+func (s *Storage) DeleteRoleHolder(ctx context.Context, context ethcommon.Address, role, did ethcommon.Hash) error {
+	return s.delete(ctx, roleNamespace, roleKey(context, role, did))
+}
+
+// ListRoleHolders returns the role holders of a context, optionally filtered by a role
+func (s *Storage) ListRoleHolders(ctx context.Context, context ethcommon.Address, role *ethcommon.Hash) ([]RoleHolder, error) {
+	prefix := storage.Join(context.Hex(), "")
+	if role != nil {
+		prefix = storage.Join(context.Hex(), role.Hex(), "")
+	}
+	gotHolders, err := s.db.ReadPrefix(ctx, s.ns(roleNamespace), prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading role holders")
+	}
+	holders := make([]RoleHolder, 0, len(gotHolders))
+	for _, holderBytes := range gotHolders {
+		var holder RoleHolder
+		if err = json.Unmarshal(holderBytes, &holder); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored role holder: %s", string(holderBytes))
+			continue
+		}
+		holders = append(holders, holder)
+	}
+	return holders, nil
+}
+
+func (s *Storage) PutSession(ctx context.Context, session IndexedSession) error {
+	return s.write(ctx, sessionNamespace, session.ID.Hex(), session)
+}
+
+func (s *Storage) DeleteSession(ctx context.Context, id ethcommon.Hash) error {
+	return s.delete(ctx, sessionNamespace, id.Hex())
+}
+
+func (s *Storage) ListSessions(ctx context.Context) ([]IndexedSession, error) {
+	gotSessions, err := s.db.ReadAll(ctx, s.ns(sessionNamespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading sessions")
+	}
+	sessions := make([]IndexedSession, 0, len(gotSessions))
+	for _, sessionBytes := range gotSessions {
+		var session IndexedSession
+		if err = json.Unmarshal(sessionBytes, &session); err != nil {
+			logrus.WithError(err).Errorf("could not unmarshal stored session: %s", string(sessionBytes))
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (s *Storage) PutDIDController(ctx context.Context, controller DIDController) error {
+	return s.write(ctx, didNamespace, controller.Identity.Hex(), controller)
+}
+
+func (s *Storage) GetDIDController(ctx context.Context, identity ethcommon.Hash) (*DIDController, error) {
+	var controller DIDController
+	found, err := s.read(ctx, didNamespace, identity.Hex(), &controller)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &controller, nil
+}
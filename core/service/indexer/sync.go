@@ -0,0 +1,278 @@
+package indexer
+
+import (
+	"context"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Run periodically indexes new blocks until the context is done. It returns immediately when no poll interval is
+// configured.
+func (s Service) Run(ctx context.Context) {
+	interval := s.config.PollInterval
+	if interval <= 0 {
+		logrus.Info("indexer disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx); err != nil {
+			logrus.WithError(err).WithField("chain_id", s.chainID).Error("could not index blocks")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync indexes all blocks from the last checkpoint up to the confirmed chain head. A reorg of already indexed blocks
+// is rolled back to the latest block that is still part of the canonical chain before indexing continues.
+func (s Service) Sync(ctx context.Context) error {
+	head, err := s.chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "getting chain head")
+	}
+	if head.Number.Uint64() < s.config.Confirmations {
+		return nil
+	}
+	target := head.Number.Uint64() - s.config.Confirmations
+
+	checkpoint, err := s.storage.GetCheckpoint(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting checkpoint")
+	}
+	if checkpoint != nil {
+		canonical, err := s.isCanonical(ctx, *checkpoint)
+		if err != nil {
+			return err
+		}
+		if !canonical {
+			if checkpoint, err = s.rollback(ctx, *checkpoint); err != nil {
+				return errors.Wrap(err, "rolling back reorg")
+			}
+		}
+	}
+
+	from := s.config.StartBlock
+	if checkpoint != nil {
+		from = checkpoint.Number + 1
+	}
+	batchSize := s.config.BatchSize
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	for from <= target {
+		to := from + batchSize - 1
+		if to > target {
+			to = target
+		}
+		if err = s.indexRange(ctx, from, to); err != nil {
+			return errors.Wrapf(err, "indexing blocks %d to %d", from, to)
+		}
+		from = to + 1
+	}
+	return nil
+}
+
+func (s Service) isCanonical(ctx context.Context, block Checkpoint) (bool, error) {
+	header, err := s.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+	if err != nil {
+		return false, errors.Wrapf(err, "getting header of block %d", block.Number)
+	}
+	return header.Hash() == block.Hash, nil
+}
+
+// rollback walks back the remembered blocks until it finds one that is still canonical, drops everything indexed after
+// it and rebuilds the read model from the snapshot and the remaining logs. If no remembered block after the snapshot is
+// canonical, the whole index is dropped and indexing starts over.
+func (s Service) rollback(ctx context.Context, checkpoint Checkpoint) (*Checkpoint, error) {
+	blocks, err := s.storage.ListBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.storage.GetSnapshotCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		// the logs up to the snapshot are pruned, so the read model cannot be rebuilt as of an earlier block
+		if block.Number >= checkpoint.Number || (snapshot != nil && block.Number < snapshot.Number) {
+			continue
+		}
+		canonical, err := s.isCanonical(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		if !canonical {
+			continue
+		}
+
+		logrus.WithField("chain_id", s.chainID).Warnf("reorg detected at block %d, rolling back to block %d", checkpoint.Number, block.Number)
+		if err = s.storage.Rollback(ctx, block.Number); err != nil {
+			return nil, err
+		}
+		if err = s.rebuild(ctx); err != nil {
+			return nil, err
+		}
+		if err = s.storage.SetCheckpoint(ctx, block); err != nil {
+			return nil, err
+		}
+		return &block, nil
+	}
+
+	logrus.WithField("chain_id", s.chainID).Warnf("reorg detected at block %d beyond the remembered blocks, reindexing from block %d", checkpoint.Number, s.config.StartBlock)
+	if err = s.storage.Reset(ctx); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// rebuild restores the snapshot onto the empty read model and replays the stored logs after it
+func (s Service) rebuild(ctx context.Context) error {
+	if err := s.storage.RestoreSnapshot(ctx); err != nil {
+		return err
+	}
+	snapshot, err := s.storage.GetSnapshotCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	logs, err := s.storage.ListLogs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, log := range logs {
+		if snapshot != nil && log.BlockNumber <= snapshot.Number {
+			continue
+		}
+		if err = s.apply(ctx, log); err != nil {
+			return errors.Wrapf(err, "replaying log %d of tx<%s>", log.Index, log.TxHash)
+		}
+	}
+	return nil
+}
+
+func (s Service) indexRange(ctx context.Context, from, to uint64) error {
+	// the header is fetched before the logs, so that a reorg while fetching the logs is detected by the next sync
+	header, err := s.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return errors.Wrapf(err, "getting header of block %d", to)
+	}
+
+	handlerLogs, err := s.filterLogs(ctx, from, to, []ethcommon.Address{s.contracts.ContextHandler})
+	if err != nil {
+		return err
+	}
+
+	addresses, err := s.storage.ListContextAddresses(ctx)
+	if err != nil {
+		return err
+	}
+	addresses = append(addresses, s.events.newContexts(handlerLogs)...)
+	addresses = append(addresses, s.contracts.SessionRegistry, s.contracts.DIDRegistry)
+	otherLogs, err := s.filterLogs(ctx, from, to, addresses)
+	if err != nil {
+		return err
+	}
+
+	logs := append(handlerLogs, otherLogs...)
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	for _, log := range logs {
+		if err = s.storage.InsertLog(ctx, log); err != nil {
+			return err
+		}
+		if err = s.storage.InsertBlock(ctx, Checkpoint{Number: log.BlockNumber, Hash: log.BlockHash}); err != nil {
+			return err
+		}
+		if err = s.apply(ctx, log); err != nil {
+			return errors.Wrapf(err, "applying log %d of tx<%s>", log.Index, log.TxHash)
+		}
+	}
+
+	checkpoint := Checkpoint{Number: to, Hash: header.Hash()}
+	if err = s.storage.InsertBlock(ctx, checkpoint); err != nil {
+		return err
+	}
+	if err = s.storage.SetCheckpoint(ctx, checkpoint); err != nil {
+		return err
+	}
+	if to <= s.config.ReorgDepth {
+		return nil
+	}
+	final := to - s.config.ReorgDepth
+	if err = s.storage.PruneBlocks(ctx, final); err != nil {
+		return err
+	}
+	return s.advanceSnapshot(ctx, final)
+}
+
+// advanceSnapshot applies the logs up to a block beyond the reorg depth onto the snapshot of the read model and prunes
+// them, so that the stored logs stay within the reorg depth and a rollback replays only the logs after the snapshot
+func (s Service) advanceSnapshot(ctx context.Context, number uint64) error {
+	snapshot, err := s.storage.GetSnapshotCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if snapshot != nil && snapshot.Number >= number {
+		return nil
+	}
+	header, err := s.chain.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return errors.Wrapf(err, "getting header of block %d", number)
+	}
+
+	logs, err := s.storage.ListLogs(ctx)
+	if err != nil {
+		return err
+	}
+	snapshotter := s
+	snapshotter.storage = s.storage.snapshot()
+	for _, log := range logs {
+		if log.BlockNumber > number {
+			break
+		}
+		if snapshot != nil && log.BlockNumber <= snapshot.Number {
+			continue
+		}
+		if err = snapshotter.apply(ctx, log); err != nil {
+			return errors.Wrapf(err, "applying log %d of tx<%s> to the snapshot", log.Index, log.TxHash)
+		}
+	}
+
+	if err = s.storage.SetSnapshotCheckpoint(ctx, Checkpoint{Number: number, Hash: header.Hash()}); err != nil {
+		return err
+	}
+	return s.storage.PruneLogs(ctx, number)
+}
+
+// filterLogs returns the logs of the given contracts, skipping unconfigured ones
+func (s Service) filterLogs(ctx context.Context, from, to uint64, addresses []ethcommon.Address) ([]types.Log, error) {
+	configured := make([]ethcommon.Address, 0, len(addresses))
+	for _, address := range addresses {
+		if address != (ethcommon.Address{}) {
+			configured = append(configured, address)
+		}
+	}
+	logs, err := s.chain.FilterLogs(ctx, rpc.FilterLogsParams{FromBlock: from, ToBlock: to, Addresses: configured})
+	if err != nil {
+		return nil, errors.Wrap(err, "filtering logs")
+	}
+	return logs, nil
+}
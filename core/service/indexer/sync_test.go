@@ -0,0 +1,234 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/storage"
+)
+
+var (
+	testContracts = Contracts{
+		ContextHandler:  ethcommon.HexToAddress("0x1000000000000000000000000000000000000001"),
+		SessionRegistry: ethcommon.HexToAddress("0x1000000000000000000000000000000000000002"),
+		DIDRegistry:     ethcommon.HexToAddress("0x1000000000000000000000000000000000000003"),
+	}
+	testContext = ethcommon.HexToAddress("0x2000000000000000000000000000000000000001")
+	testOwner   = crypto.Keccak256Hash([]byte("did:key:owner"))
+	testRole    = crypto.Keccak256Hash([]byte("VERIFICATION_BODY"))
+	testUserA   = crypto.Keccak256Hash([]byte("did:key:a"))
+	testUserB   = crypto.Keccak256Hash([]byte("did:key:b"))
+	testSession = crypto.Keccak256Hash([]byte("session"))
+)
+
+// fakeChain serves headers and logs of blocks, where each block belongs to a fork
+type fakeChain struct {
+	head  uint64
+	forks map[uint64]byte
+	logs  []types.Log
+}
+
+func (c *fakeChain) header(number uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte{c.forks[number]}}
+}
+
+func (c *fakeChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return c.header(c.head), nil
+	}
+	return c.header(number.Uint64()), nil
+}
+
+func (c *fakeChain) FilterLogs(_ context.Context, params rpc.FilterLogsParams) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber < params.FromBlock || log.BlockNumber > params.ToBlock {
+			continue
+		}
+		if c.header(log.BlockNumber).Hash() != log.BlockHash {
+			continue
+		}
+		for _, address := range params.Addresses {
+			if address == log.Address {
+				logs = append(logs, log)
+				break
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeChain) emit(t *testing.T, block uint64, index uint, address ethcommon.Address, event string, topics []ethcommon.Hash, data []byte) {
+	events, err := newEventParser()
+	require.NoError(t, err)
+	ids := map[string]ethcommon.Hash{
+		"CreateContextInstance": events.createContextInstance,
+		"OwnershipTransferred":  events.ownershipTransferred,
+		"RoleGranted":           events.roleGranted,
+		"RoleRevoked":           events.roleRevoked,
+		"SessionStarted":        events.sessionStarted,
+	}
+	c.logs = append(c.logs, types.Log{
+		Address:     address,
+		Topics:      append([]ethcommon.Hash{ids[event]}, topics...),
+		Data:        data,
+		BlockNumber: block,
+		BlockHash:   c.header(block).Hash(),
+		Index:       index,
+	})
+}
+
+func newTestIndexer(t *testing.T, chain Chain) *Service {
+	file, err := os.CreateTemp("", "bolt")
+	require.NoError(t, err)
+	name := file.Name()
+	assert.NoError(t, file.Close())
+	s, err := storage.NewStorage(storage.Bolt, storage.Option{
+		ID:     storage.BoltDBFilePathOption,
+		Option: name,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+		_ = os.Remove(s.URI())
+	})
+
+	indexerStorage, err := NewIndexerStorage(s)
+	require.NoError(t, err)
+	service, err := newIndexerService(config.IndexerServiceConfig{BatchSize: 4, ReorgDepth: 128}, indexerStorage, chain, testContracts)
+	require.NoError(t, err)
+	return service
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	chain := &fakeChain{head: 10, forks: map[uint64]byte{}}
+
+	chain.emit(t, 2, 0, testContext, "OwnershipTransferred", []ethcommon.Hash{{}, testOwner}, nil)
+	chain.emit(t, 2, 1, testContracts.ContextHandler, "CreateContextInstance", []ethcommon.Hash{ethcommon.BytesToHash(testContext.Bytes())}, nil)
+	chain.emit(t, 3, 0, testContext, "RoleGranted", []ethcommon.Hash{testRole, testUserA}, nil)
+	sessionABI, err := contracts.SessionRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+	sessionData, err := sessionABI.Events["SessionStarted"].Inputs.NonIndexed().Pack(big.NewInt(1<<40), []byte("jwe"))
+	require.NoError(t, err)
+	chain.emit(t, 5, 0, testContracts.SessionRegistry, "SessionStarted", []ethcommon.Hash{testSession, testUserA}, sessionData)
+	chain.emit(t, 8, 0, testContext, "RoleGranted", []ethcommon.Hash{testRole, testUserB}, nil)
+
+	indexer := newTestIndexer(t, chain)
+	require.NoError(t, indexer.Sync(ctx))
+
+	checkpoint, err := indexer.GetCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), checkpoint.Number)
+
+	got, err := indexer.GetContext(ctx, GetContextRequest{Address: testContext.Hex()})
+	require.NoError(t, err)
+	assert.Equal(t, testOwner, got.Context.Owner)
+
+	holders, err := indexer.ListRoleHolders(ctx, ListRoleHoldersRequest{Context: testContext.Hex(), Role: "VERIFICATION_BODY"})
+	require.NoError(t, err)
+	assert.Len(t, holders.RoleHolders, 2)
+
+	sessions, err := indexer.ListSessions(ctx, ListSessionsRequest{User: "did:key:a"})
+	require.NoError(t, err)
+	require.Len(t, sessions.Sessions, 1)
+	assert.Equal(t, testSession, sessions.Sessions[0].ID)
+	assert.Equal(t, []byte("jwe"), sessions.Sessions[0].Token)
+
+	t.Run("reorg", func(t *testing.T) {
+		// blocks from 7 on are replaced, dropping the second grant and revoking the first one
+		for number := uint64(7); number <= 12; number++ {
+			chain.forks[number] = 1
+		}
+		chain.head = 12
+		chain.emit(t, 9, 0, testContext, "RoleRevoked", []ethcommon.Hash{testRole, testUserA}, nil)
+
+		require.NoError(t, indexer.Sync(ctx))
+
+		checkpoint, err := indexer.GetCheckpoint(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), checkpoint.Number)
+		assert.Equal(t, chain.header(12).Hash(), checkpoint.Hash)
+
+		holders, err := indexer.ListRoleHolders(ctx, ListRoleHoldersRequest{Context: testContext.Hex()})
+		require.NoError(t, err)
+		assert.Empty(t, holders.RoleHolders)
+
+		sessions, err := indexer.ListSessions(ctx, ListSessionsRequest{})
+		require.NoError(t, err)
+		assert.Len(t, sessions.Sessions, 1)
+	})
+}
+
+func TestSyncPrunesLogs(t *testing.T) {
+	ctx := context.Background()
+	chain := &fakeChain{head: 10, forks: map[uint64]byte{}}
+
+	chain.emit(t, 2, 0, testContext, "OwnershipTransferred", []ethcommon.Hash{{}, testOwner}, nil)
+	chain.emit(t, 2, 1, testContracts.ContextHandler, "CreateContextInstance", []ethcommon.Hash{ethcommon.BytesToHash(testContext.Bytes())}, nil)
+	chain.emit(t, 3, 0, testContext, "RoleGranted", []ethcommon.Hash{testRole, testUserA}, nil)
+	chain.emit(t, 8, 0, testContext, "RoleGranted", []ethcommon.Hash{testRole, testUserB}, nil)
+
+	indexer := newTestIndexer(t, chain)
+	indexer.config.ReorgDepth = 3
+	require.NoError(t, indexer.Sync(ctx))
+
+	// the logs up to block 7 are folded into the snapshot
+	snapshot, err := indexer.storage.GetSnapshotCheckpoint(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, uint64(7), snapshot.Number)
+	logs, err := indexer.storage.ListLogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, uint64(8), logs[0].BlockNumber)
+
+	holders, err := indexer.ListRoleHolders(ctx, ListRoleHoldersRequest{Context: testContext.Hex()})
+	require.NoError(t, err)
+	assert.Len(t, holders.RoleHolders, 2)
+
+	t.Run("reorg after the snapshot", func(t *testing.T) {
+		// blocks from 9 on are replaced, revoking the first grant
+		for number := uint64(9); number <= 12; number++ {
+			chain.forks[number] = 1
+		}
+		chain.head = 12
+		chain.emit(t, 9, 0, testContext, "RoleRevoked", []ethcommon.Hash{testRole, testUserA}, nil)
+		// the logs folded into the snapshot are not fetched again, so the context is lost if indexing starts over
+		chain.logs = chain.logs[3:]
+
+		require.NoError(t, indexer.Sync(ctx))
+
+		// the read model is rebuilt from the snapshot and the log of block 8
+		holders, err := indexer.ListRoleHolders(ctx, ListRoleHoldersRequest{Context: testContext.Hex()})
+		require.NoError(t, err)
+		require.Len(t, holders.RoleHolders, 1)
+		assert.Equal(t, testUserB, holders.RoleHolders[0].DID)
+		got, err := indexer.GetContext(ctx, GetContextRequest{Address: testContext.Hex()})
+		require.NoError(t, err)
+		assert.Equal(t, testOwner, got.Context.Owner)
+
+		snapshot, err := indexer.storage.GetSnapshotCheckpoint(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9), snapshot.Number)
+		logs, err := indexer.storage.ListLogs(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+	})
+}
+
+func TestToHash(t *testing.T) {
+	assert.Equal(t, testRole, ToHash("VERIFICATION_BODY"))
+	assert.Equal(t, testRole, ToHash(testRole.Hex()))
+}
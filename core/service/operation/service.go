@@ -5,6 +5,7 @@ import (
 	"fmt"
 	opstorage "github.com/fapiper/onchain-access-control/core/service/operation/storage"
 	"github.com/fapiper/onchain-access-control/core/service/operation/submission"
+	"github.com/fapiper/onchain-access-control/core/service/operation/transaction"
 	"github.com/fapiper/onchain-access-control/core/service/presentation/model"
 	prestorage "github.com/fapiper/onchain-access-control/core/service/presentation/storage"
 	"strings"
@@ -96,6 +97,12 @@ func ServiceModel(op opstorage.StoredOperation) (*Operation, error) {
 				return nil, errors.Wrap(err, "unmarshalling cred response")
 			}
 			newOp.Result.Response = manifestmodel.ServiceModel(&s)
+		case strings.HasPrefix(op.ID, transaction.ParentResource):
+			var r transaction.Result
+			if err := json.Unmarshal(op.Response, &r); err != nil {
+				return nil, errors.Wrap(err, "unmarshalling transaction result")
+			}
+			newOp.Result.Response = r
 		default:
 			return nil, errors.New("unknown response type")
 		}
@@ -132,6 +132,27 @@ func (s Storage) ListOperations(ctx context.Context, parent string, filter filte
 	}, nil
 }
 
+// ListPendingOperations returns all operations of a parent resource that are not done yet
+func (s Storage) ListPendingOperations(ctx context.Context, parent string) ([]opstorage.StoredOperation, error) {
+	operations, err := s.db.ReadAll(ctx, namespace.FromParent(parent))
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "could not get all operations")
+	}
+
+	pending := make([]opstorage.StoredOperation, 0, len(operations))
+	for id, opBytes := range operations {
+		var op opstorage.StoredOperation
+		if err = json.Unmarshal(opBytes, &op); err != nil {
+			logrus.WithError(err).WithField("operation_id", id).Warnf("Skipping operation")
+			continue
+		}
+		if !op.Done {
+			pending = append(pending, op)
+		}
+	}
+	return pending, nil
+}
+
 func (s Storage) DeleteOperation(ctx context.Context, id string) error {
 	if err := s.db.Delete(ctx, namespace.FromID(id), id); err != nil {
 		return sdkutil.LoggingErrorMsgf(err, "deleting operation: %s", id)
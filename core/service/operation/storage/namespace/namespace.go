@@ -5,11 +5,13 @@ import (
 
 	"github.com/fapiper/onchain-access-control/core/service/operation/credential"
 	"github.com/fapiper/onchain-access-control/core/service/operation/submission"
+	"github.com/fapiper/onchain-access-control/core/service/operation/transaction"
 )
 
 const (
 	namespace                   = "operation_submission"
 	credentialResponseNamespace = "operation_credential_response"
+	transactionNamespace        = "operation_transaction"
 )
 
 // FromID returns a namespace from a given operation ID. An empty string is returned when the namespace cannot
@@ -30,6 +32,8 @@ func FromParent(parent string) string {
 		return namespace
 	case credential.ParentResource:
 		return credentialResponseNamespace
+	case transaction.ParentResource:
+		return transactionNamespace
 	default:
 		return ""
 	}
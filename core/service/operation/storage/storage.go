@@ -3,6 +3,7 @@ package storage
 import (
 	"strings"
 
+	"github.com/goccy/go-json"
 	"go.einride.tech/aip/filtering"
 )
 
@@ -22,6 +23,22 @@ type StoredOperation struct {
 
 	// Populated only when Done == true and Error == ""
 	Response []byte `json:"response,omitempty"`
+
+	// ChainID is the chain of the transaction of a pending transaction operation.
+	ChainID uint64 `json:"chainId,omitempty"`
+
+	// Hashes are the hashes of the transaction of a pending transaction operation and of its replacements, any of
+	// which may get mined.
+	Hashes []string `json:"hashes,omitempty"`
+
+	// FollowUp is run once the transaction of a pending transaction operation is confirmed.
+	FollowUp *FollowUp `json:"followUp,omitempty"`
+}
+
+// FollowUp is an action that completes an operation, identified by its kind and carrying its parameters.
+type FollowUp struct {
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params,omitempty"`
 }
 
 func (s StoredOperation) FilterVariablesMap() map[string]any {
@@ -0,0 +1,432 @@
+package operation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	opstorage "github.com/fapiper/onchain-access-control/core/service/operation/storage"
+	"github.com/fapiper/onchain-access-control/core/service/operation/transaction"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/storage"
+)
+
+const (
+	defaultConfirmations = 1
+	defaultPollInterval  = 2 * time.Second
+	// trackerQueueSize is the number of tracked transactions that are buffered until the tracker picks them up
+	trackerQueueSize = 64
+)
+
+// Chain is the part of the rpc service transactions are followed with
+type Chain interface {
+	ChainID() uint64
+	WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error)
+	TransactionReceipt(ctx context.Context, hash ethcommon.Hash) (*types.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	RevertReason(ctx context.Context, receipt *types.Receipt) (string, error)
+	TransactionHashes(tx *types.Transaction) []ethcommon.Hash
+}
+
+// FollowUp is the action that completes an operation once its transaction is confirmed, e.g. storing what the
+// transaction registered on-chain. It is stored along with the operation, so that it also runs for operations resumed
+// after a restart.
+type FollowUp = opstorage.FollowUp
+
+// NewFollowUp returns a follow-up of a kind with its parameters encoded as JSON
+func NewFollowUp(kind string, params any) (*FollowUp, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling params of follow-up<%s>", kind)
+	}
+	return &FollowUp{Kind: kind, Params: data}, nil
+}
+
+// FollowUpHandler runs the follow-ups of a kind with the receipt of the confirmed transaction and the parameters of
+// the follow-up. Its output becomes part of the result of the operation.
+type FollowUpHandler func(ctx context.Context, receipt *types.Receipt, params json.RawMessage) (any, error)
+
+// NewFollowUpHandler adapts a function taking the decoded parameters of a follow-up to a follow-up handler
+func NewFollowUpHandler[P any, O any](f func(ctx context.Context, params P) (O, error)) FollowUpHandler {
+	return func(ctx context.Context, _ *types.Receipt, data json.RawMessage) (any, error) {
+		var params P
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &params); err != nil {
+				return nil, errors.Wrap(err, "unmarshalling follow-up params")
+			}
+		}
+		return f(ctx, params)
+	}
+}
+
+// Tracker completes the operations of chain writes. Each operation is done once its transaction reached the
+// configured number of confirmations, or failed with the revert reason.
+type Tracker struct {
+	config  config.OperationServiceConfig
+	storage *Storage
+	chain   Chain
+	jobs    chan trackedTx
+	// resumed is closed once Run picked up the operations left pending by a previous run
+	resumed chan struct{}
+	// resumesUnbound is set for the tracker that resumes the pending operations stored without a chain
+	resumesUnbound bool
+
+	mu       sync.RWMutex
+	handlers map[string]FollowUpHandler
+}
+
+type trackedTx struct {
+	op opstorage.StoredOperation
+	// tx is unset for transactions resumed after a restart, which are only followed by their hashes
+	tx *types.Transaction
+}
+
+func NewTracker(config config.OperationServiceConfig, s storage.ServiceStorage, chain Chain) (*Tracker, error) {
+	if chain == nil {
+		return nil, errors.New("chain cannot be nil")
+	}
+	opStorage, err := NewOperationStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "creating operation storage")
+	}
+	if config.Confirmations == 0 {
+		config.Confirmations = defaultConfirmations
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	return &Tracker{
+		config:  config,
+		storage: opStorage,
+		chain:   chain,
+		jobs:    make(chan trackedTx, trackerQueueSize),
+		resumed: make(chan struct{}),
+
+		resumesUnbound: true,
+		handlers:       make(map[string]FollowUpHandler),
+	}, nil
+}
+
+// Handle registers the handler of a kind of follow-up. Handlers must be registered before Run, which runs the
+// follow-ups of resumed operations.
+func (t *Tracker) Handle(kind string, handler FollowUpHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers[kind] = handler
+}
+
+func (t *Tracker) handler(kind string) (FollowUpHandler, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	handler, ok := t.handlers[kind]
+	return handler, ok
+}
+
+// Track stores a pending operation for a sent transaction along with its follow-up and hands the transaction to Run.
+// The follow-up may be nil.
+func (t *Tracker) Track(ctx context.Context, tx *types.Transaction, followUp *FollowUp) (*Operation, error) {
+	// operations stored before Run resumed the pending ones would be followed twice
+	select {
+	case <-t.resumed:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	op := opstorage.StoredOperation{
+		ID:       transaction.NewID(),
+		ChainID:  t.chain.ChainID(),
+		Hashes:   []string{tx.Hash().Hex()},
+		FollowUp: followUp,
+	}
+	if err := t.storage.StoreOperation(ctx, op); err != nil {
+		return nil, errors.Wrap(err, "storing transaction operation")
+	}
+
+	select {
+	case t.jobs <- trackedTx{op: op, tx: tx}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &Operation{ID: op.ID}, nil
+}
+
+// Run follows tracked transactions until the context is done. Operations of its chain left pending by a previous run
+// are resumed by the hashes of their transaction and its replacements, and run their stored follow-up once confirmed.
+func (t *Tracker) Run(ctx context.Context) {
+	pending, err := t.storage.ListPendingOperations(ctx, transaction.ParentResource)
+	if err != nil {
+		logrus.WithError(err).Error("could not resume pending transaction operations")
+	}
+	for _, op := range pending {
+		if op.ChainID != t.chain.ChainID() && (op.ChainID != 0 || !t.resumesUnbound) {
+			continue
+		}
+		if len(op.Hashes) == 0 {
+			// operations stored before their hashes were recorded carry the hash of their transaction in the id
+			op.Hashes = []string{transaction.HashFromID(op.ID).Hex()}
+		}
+		go t.follow(ctx, trackedTx{op: op})
+	}
+	close(t.resumed)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-t.jobs:
+			go t.follow(ctx, job)
+		}
+	}
+}
+
+// Trackers run one tracker per chain of the rpc service. Transactions are tracked by the tracker of their chain.
+type Trackers struct {
+	trackers map[uint64]*Tracker
+}
+
+// NewTrackers returns a tracker for every chain of the rpc service. Pending operations stored without a chain are
+// resumed by the tracker of the default chain, on which all transactions were sent before.
+func NewTrackers(config config.OperationServiceConfig, s storage.ServiceStorage, rpcService *rpc.Service) (*Trackers, error) {
+	if rpcService == nil {
+		return nil, errors.New("rpc service cannot be nil")
+	}
+	chains := rpcService.Chains()
+	trackers := make(map[uint64]*Tracker, len(chains))
+	for i, chain := range chains {
+		tracker, err := NewTracker(config, s, chain)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating tracker of chain %d", chain.ChainID())
+		}
+		tracker.resumesUnbound = i == 0
+		trackers[chain.ChainID()] = tracker
+	}
+	return &Trackers{trackers: trackers}, nil
+}
+
+// Handle registers the handler of a kind of follow-up with the trackers of all chains
+func (t *Trackers) Handle(kind string, handler FollowUpHandler) {
+	for _, tracker := range t.trackers {
+		tracker.Handle(kind, handler)
+	}
+}
+
+// Track stores a pending operation for a sent transaction and hands it to the tracker of the chain of the transaction
+func (t *Trackers) Track(ctx context.Context, tx *types.Transaction, followUp *FollowUp) (*Operation, error) {
+	tracker, ok := t.trackers[tx.ChainId().Uint64()]
+	if !ok {
+		return nil, errors.Errorf("no tracker for chain %d of transaction %s", tx.ChainId(), tx.Hash().Hex())
+	}
+	return tracker.Track(ctx, tx, followUp)
+}
+
+// Run runs the trackers of all chains until the context is done
+func (t *Trackers) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, tracker := range t.trackers {
+		wg.Add(1)
+		go func(tracker *Tracker) {
+			defer wg.Done()
+			tracker.Run(ctx)
+		}(tracker)
+	}
+	wg.Wait()
+}
+
+// follow waits for the transaction of an operation and stores the operation as done
+func (t *Tracker) follow(ctx context.Context, job trackedTx) {
+	result, err := t.confirm(ctx, job)
+	if ctx.Err() != nil {
+		// the operation stays pending and is resumed by the next run
+		return
+	}
+
+	op := opstorage.StoredOperation{ID: job.op.ID, Done: true}
+	if err == nil {
+		op.Response, err = json.Marshal(result)
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("operation_id", job.op.ID).Warn("transaction operation failed")
+		op.Error = err.Error()
+	}
+	if err = t.storage.StoreOperation(ctx, op); err != nil {
+		logrus.WithError(err).WithField("operation_id", job.op.ID).Error("could not store transaction operation")
+	}
+}
+
+// confirm waits until the transaction reached the configured number of confirmations and runs the follow-up of the
+// operation. The receipt is checked again before, in case the block it was mined in got reorganised meanwhile.
+func (t *Tracker) confirm(ctx context.Context, job trackedTx) (*transaction.Result, error) {
+	receipt, err := t.mined(ctx, job)
+	if err != nil {
+		return nil, errors.Wrapf(err, "waiting for transaction of operation<%s>", job.op.ID)
+	}
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if receipt.Status == types.ReceiptStatusFailed {
+			return nil, t.reverted(ctx, receipt)
+		}
+
+		head, err := t.chain.BlockNumber(ctx)
+		if err != nil {
+			logrus.WithError(err).Warn("could not get block number")
+		} else if confirmations := confirmationsAt(head, receipt); confirmations >= t.config.Confirmations {
+			current, err := t.chain.TransactionReceipt(ctx, receipt.TxHash)
+			switch {
+			case err == nil && current.BlockHash == receipt.BlockHash:
+				return t.result(ctx, job, receipt, confirmations)
+			case err == nil:
+				receipt = current
+				continue
+			case errors.Is(err, ethereum.NotFound):
+				// the transaction was reorganised out of the chain and is waited for again
+				hash := receipt.TxHash.Hex()
+				reorged := trackedTx{op: opstorage.StoredOperation{ID: job.op.ID, Hashes: []string{hash}}}
+				if receipt, err = t.mined(ctx, reorged); err != nil {
+					return nil, errors.Wrapf(err, "waiting for transaction %s", hash)
+				}
+				continue
+			default:
+				logrus.WithError(err).WithField("tx", receipt.TxHash.Hex()).Warn("could not get transaction receipt")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// mined waits for the receipt of a transaction. Transactions sent by this instance are waited for through the wallet,
+// which replaces them if they get stuck. Resumed transactions are waited for by the hashes recorded for them.
+func (t *Tracker) mined(ctx context.Context, job trackedTx) (*types.Receipt, error) {
+	if job.tx != nil {
+		stop := t.recordReplacements(ctx, job)
+		defer stop()
+		return t.chain.WaitMined(ctx, job.tx)
+	}
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, hash := range job.op.Hashes {
+			receipt, err := t.chain.TransactionReceipt(ctx, ethcommon.HexToHash(hash))
+			if err == nil {
+				return receipt, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				logrus.WithError(err).WithField("tx", hash).Warn("could not get transaction receipt")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordReplacements stores the hashes of the replacements the wallet sends for the transaction of a pending
+// operation, so that the operation is resumed with whichever of them gets mined. The returned function stops
+// recording.
+func (t *Tracker) recordReplacements(ctx context.Context, job trackedTx) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(t.config.PollInterval)
+		defer ticker.Stop()
+
+		op := job.op
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			hashes := t.chain.TransactionHashes(job.tx)
+			if len(hashes) <= len(op.Hashes) {
+				continue
+			}
+			op.Hashes = make([]string, 0, len(hashes))
+			for _, hash := range hashes {
+				op.Hashes = append(op.Hashes, hash.Hex())
+			}
+			if err := t.storage.StoreOperation(ctx, op); err != nil {
+				logrus.WithError(err).WithField("operation_id", op.ID).Warn("could not store transaction replacements")
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (t *Tracker) reverted(ctx context.Context, receipt *types.Receipt) error {
+	reason, err := t.chain.RevertReason(ctx, receipt)
+	if err != nil {
+		logrus.WithError(err).WithField("tx", receipt.TxHash.Hex()).Warn("could not get revert reason")
+		reason = "unknown reason"
+	}
+	return errors.Errorf("transaction %s reverted: %s", receipt.TxHash.Hex(), reason)
+}
+
+func (t *Tracker) result(ctx context.Context, job trackedTx, receipt *types.Receipt, confirmations uint64) (*transaction.Result, error) {
+	result := transaction.Result{
+		Transaction: transaction.Receipt{
+			Hash:          receipt.TxHash.Hex(),
+			BlockNumber:   receipt.BlockNumber.Uint64(),
+			BlockHash:     receipt.BlockHash.Hex(),
+			GasUsed:       receipt.GasUsed,
+			Confirmations: confirmations,
+		},
+	}
+	followUp := job.op.FollowUp
+	if followUp == nil {
+		return &result, nil
+	}
+
+	handler, ok := t.handler(followUp.Kind)
+	if !ok {
+		return nil, errors.Errorf("no handler for follow-up<%s> of transaction %s", followUp.Kind, receipt.TxHash.Hex())
+	}
+	output, err := handler(ctx, receipt, followUp.Params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "completing transaction %s", receipt.TxHash.Hex())
+	}
+	if output == nil {
+		return &result, nil
+	}
+	if result.Output, err = json.Marshal(output); err != nil {
+		return nil, errors.Wrap(err, "marshalling transaction output")
+	}
+	return &result, nil
+}
+
+// confirmationsAt returns the number of blocks, including its own, on top of which a receipt was mined
+func confirmationsAt(head uint64, receipt *types.Receipt) uint64 {
+	block := receipt.BlockNumber.Uint64()
+	if head < block {
+		return 0
+	}
+	return head - block + 1
+}
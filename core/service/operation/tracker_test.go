@@ -0,0 +1,310 @@
+package operation
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	opstorage "github.com/fapiper/onchain-access-control/core/service/operation/storage"
+	"github.com/fapiper/onchain-access-control/core/service/operation/transaction"
+	"github.com/fapiper/onchain-access-control/core/testutil"
+)
+
+// fakeChain mines transactions on demand
+type fakeChain struct {
+	mu           sync.Mutex
+	head         uint64
+	receipts     map[ethcommon.Hash]*types.Receipt
+	replacements map[ethcommon.Hash][]ethcommon.Hash
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		head:         10,
+		receipts:     make(map[ethcommon.Hash]*types.Receipt),
+		replacements: make(map[ethcommon.Hash][]ethcommon.Hash),
+	}
+}
+
+func (c *fakeChain) ChainID() uint64 {
+	return 1337
+}
+
+func (c *fakeChain) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for {
+		for _, hash := range c.TransactionHashes(tx) {
+			if receipt, err := c.TransactionReceipt(ctx, hash); err == nil {
+				return receipt, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *fakeChain) TransactionReceipt(_ context.Context, hash ethcommon.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if receipt, ok := c.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *fakeChain) BlockNumber(_ context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+func (c *fakeChain) RevertReason(_ context.Context, _ *types.Receipt) (string, error) {
+	return "role already granted", nil
+}
+
+func (c *fakeChain) TransactionHashes(tx *types.Transaction) []ethcommon.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ethcommon.Hash{tx.Hash()}, c.replacements[tx.Hash()]...)
+}
+
+// replace records a replacement of a transaction, as the wallet does when it bumps the gas price
+func (c *fakeChain) replace(tx *types.Transaction, replacement ethcommon.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replacements[tx.Hash()] = append(c.replacements[tx.Hash()], replacement)
+}
+
+// mine includes a transaction in the next block
+func (c *fakeChain) mine(hash ethcommon.Hash, status uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head++
+	c.receipts[hash] = &types.Receipt{
+		Status:      status,
+		TxHash:      hash,
+		BlockNumber: new(big.Int).SetUint64(c.head),
+		BlockHash:   ethcommon.BigToHash(new(big.Int).SetUint64(c.head)),
+	}
+}
+
+func (c *fakeChain) advance(blocks uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head += blocks
+}
+
+func newTestTracker(t *testing.T, chain Chain) *Tracker {
+	tracker, err := NewTracker(config.OperationServiceConfig{
+		Confirmations: 3,
+		PollInterval:  time.Millisecond,
+	}, testutil.TestDatabases[0].ServiceStorage(t), chain)
+	require.NoError(t, err)
+	tracker.Handle("block", func(_ context.Context, receipt *types.Receipt, params json.RawMessage) (any, error) {
+		var label string
+		if err := json.Unmarshal(params, &label); err != nil {
+			return nil, err
+		}
+		return map[string]any{"label": label, "block": receipt.BlockNumber.Uint64()}, nil
+	})
+	return tracker
+}
+
+func newBlockFollowUp(t *testing.T, label string) *FollowUp {
+	followUp, err := NewFollowUp("block", label)
+	require.NoError(t, err)
+	return followUp
+}
+
+func waitDone(t *testing.T, tracker *Tracker, id string) opstorage.StoredOperation {
+	var op opstorage.StoredOperation
+	require.Eventually(t, func() bool {
+		var err error
+		op, err = tracker.storage.GetOperation(context.Background(), id)
+		return err == nil && op.Done
+	}, time.Second, time.Millisecond)
+	return op
+}
+
+func TestTracker(t *testing.T) {
+	t.Run("done once the transaction is confirmed", func(tt *testing.T) {
+		chain := newFakeChain()
+		tracker := newTestTracker(tt, chain)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+		op, err := tracker.Track(ctx, tx, newBlockFollowUp(tt, "granted"))
+		require.NoError(tt, err)
+		assert.True(tt, strings.HasPrefix(op.ID, transaction.ParentResource+"/"))
+		assert.False(tt, op.Done)
+
+		chain.mine(tx.Hash(), types.ReceiptStatusSuccessful)
+		time.Sleep(20 * time.Millisecond)
+		pending, err := tracker.storage.GetOperation(ctx, op.ID)
+		require.NoError(tt, err)
+		assert.False(tt, pending.Done)
+
+		chain.advance(2)
+		stored := waitDone(tt, tracker, op.ID)
+		assert.Empty(tt, stored.Error)
+
+		var result transaction.Result
+		require.NoError(tt, json.Unmarshal(stored.Response, &result))
+		assert.Equal(tt, tx.Hash().Hex(), result.Transaction.Hash)
+		assert.Equal(tt, uint64(11), result.Transaction.BlockNumber)
+		assert.Equal(tt, uint64(3), result.Transaction.Confirmations)
+		assert.JSONEq(tt, `{"label":"granted","block":11}`, string(result.Output))
+	})
+
+	t.Run("fails with the revert reason", func(tt *testing.T) {
+		chain := newFakeChain()
+		tracker := newTestTracker(tt, chain)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		tx := types.NewTx(&types.LegacyTx{Nonce: 2})
+		tracker.Handle("reverted", func(_ context.Context, _ *types.Receipt, _ json.RawMessage) (any, error) {
+			tt.Error("follow-up of a reverted transaction")
+			return nil, nil
+		})
+		op, err := tracker.Track(ctx, tx, &FollowUp{Kind: "reverted"})
+		require.NoError(tt, err)
+
+		chain.mine(tx.Hash(), types.ReceiptStatusFailed)
+		stored := waitDone(tt, tracker, op.ID)
+		assert.Contains(tt, stored.Error, "reverted: role already granted")
+		assert.Empty(tt, stored.Response)
+	})
+
+	t.Run("records replacements and resumes with the mined one", func(tt *testing.T) {
+		chain := newFakeChain()
+		tracker := newTestTracker(tt, chain)
+		ctx, cancel := context.WithCancel(context.Background())
+		go tracker.Run(ctx)
+
+		tx := types.NewTx(&types.LegacyTx{Nonce: 7})
+		op, err := tracker.Track(ctx, tx, newBlockFollowUp(tt, "replaced"))
+		require.NoError(tt, err)
+
+		replacement := types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(2)})
+		chain.replace(tx, replacement.Hash())
+		require.Eventually(tt, func() bool {
+			stored, err := tracker.storage.GetOperation(context.Background(), op.ID)
+			return err == nil && len(stored.Hashes) == 2
+		}, time.Second, time.Millisecond)
+
+		// the instance stops before the replacement is mined
+		cancel()
+		chain.mine(replacement.Hash(), types.ReceiptStatusSuccessful)
+		chain.advance(5)
+
+		restarted := newTestTracker(tt, chain)
+		restarted.storage = tracker.storage
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		go restarted.Run(ctx)
+
+		stored := waitDone(tt, restarted, op.ID)
+		assert.Empty(tt, stored.Error)
+
+		var result transaction.Result
+		require.NoError(tt, json.Unmarshal(stored.Response, &result))
+		assert.Equal(tt, replacement.Hash().Hex(), result.Transaction.Hash)
+		assert.JSONEq(tt, `{"label":"replaced","block":11}`, string(result.Output))
+	})
+
+	t.Run("fails resumed operations without a handler for their follow-up", func(tt *testing.T) {
+		chain := newFakeChain()
+		tracker := newTestTracker(tt, chain)
+
+		tx := types.NewTx(&types.LegacyTx{Nonce: 8})
+		op := opstorage.StoredOperation{
+			ID:       transaction.NewID(),
+			ChainID:  chain.ChainID(),
+			Hashes:   []string{tx.Hash().Hex()},
+			FollowUp: &FollowUp{Kind: "unknown"},
+		}
+		require.NoError(tt, tracker.storage.StoreOperation(context.Background(), op))
+		chain.mine(tx.Hash(), types.ReceiptStatusSuccessful)
+		chain.advance(5)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		stored := waitDone(tt, tracker, op.ID)
+		assert.Contains(tt, stored.Error, "no handler for follow-up<unknown>")
+	})
+
+	t.Run("resumes pending operations identified by the hash of their transaction", func(tt *testing.T) {
+		chain := newFakeChain()
+		tracker := newTestTracker(tt, chain)
+
+		tx := types.NewTx(&types.LegacyTx{Nonce: 3})
+		id := transaction.IDFromHash(tx.Hash())
+		require.NoError(tt, tracker.storage.StoreOperation(context.Background(), opstorage.StoredOperation{ID: id}))
+		chain.mine(tx.Hash(), types.ReceiptStatusSuccessful)
+		chain.advance(5)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		stored := waitDone(tt, tracker, id)
+		assert.Empty(tt, stored.Error)
+
+		var result transaction.Result
+		require.NoError(tt, json.Unmarshal(stored.Response, &result))
+		assert.Equal(tt, uint64(6), result.Transaction.Confirmations)
+		assert.Empty(tt, result.Output)
+	})
+
+	t.Run("resumes only the pending operations of its chain", func(tt *testing.T) {
+		chain := newFakeChain()
+		tracker := newTestTracker(tt, chain)
+		tracker.resumesUnbound = false
+
+		own := types.NewTx(&types.LegacyTx{Nonce: 4})
+		other := types.NewTx(&types.LegacyTx{Nonce: 5})
+		unbound := types.NewTx(&types.LegacyTx{Nonce: 6})
+		ops := map[*types.Transaction]opstorage.StoredOperation{
+			own:     {ID: transaction.NewID(), ChainID: chain.ChainID(), Hashes: []string{own.Hash().Hex()}},
+			other:   {ID: transaction.NewID(), ChainID: 10, Hashes: []string{other.Hash().Hex()}},
+			unbound: {ID: transaction.IDFromHash(unbound.Hash())},
+		}
+		for tx, op := range ops {
+			require.NoError(tt, tracker.storage.StoreOperation(context.Background(), op))
+			chain.mine(tx.Hash(), types.ReceiptStatusSuccessful)
+		}
+		chain.advance(5)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go tracker.Run(ctx)
+
+		waitDone(tt, tracker, ops[own].ID)
+		for _, tx := range []*types.Transaction{other, unbound} {
+			stored, err := tracker.storage.GetOperation(context.Background(), ops[tx].ID)
+			require.NoError(tt, err)
+			assert.False(tt, stored.Done)
+		}
+	})
+}
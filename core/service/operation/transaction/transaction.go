@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	opstorage "github.com/fapiper/onchain-access-control/core/service/operation/storage"
+)
+
+const (
+	// ParentResource is the prefix of the transaction parent resource.
+	ParentResource = "transactions"
+)
+
+// NewID returns a new transaction operation ID. The ID stays the same when the transaction is replaced with a higher
+// gas price.
+func NewID() string {
+	return fmt.Sprintf("%s/%s", ParentResource, uuid.NewString())
+}
+
+// IDFromHash returns a transaction operation ID from the hash of the transaction, the format operations were stored
+// with before they recorded the hashes of their transaction.
+func IDFromHash(hash common.Hash) string {
+	return fmt.Sprintf("%s/%s", ParentResource, hash.Hex())
+}
+
+// HashFromID returns the hash of the transaction an operation ID was created from by IDFromHash.
+func HashFromID(id string) common.Hash {
+	return common.HexToHash(opstorage.StatusObjectID(id))
+}
+
+// Receipt describes a transaction once it reached the configured number of confirmations. The hash is the one of the
+// mined version of the transaction, which differs from the first one when it was replaced with a higher gas price.
+type Receipt struct {
+	Hash          string `json:"hash"`
+	BlockNumber   uint64 `json:"blockNumber"`
+	BlockHash     string `json:"blockHash"`
+	GasUsed       uint64 `json:"gasUsed"`
+	Confirmations uint64 `json:"confirmations"`
+}
+
+// Result is the response of a transaction operation.
+type Result struct {
+	Transaction Receipt `json:"transaction"`
+	// Output is the result of the request that sent the transaction, e.g. the registered resource.
+	Output json.RawMessage `json:"output,omitempty"`
+}
@@ -57,6 +57,33 @@ func (p PolicyIdentifier) String() string {
 	return fmt.Sprintf("%s+%s", p.ContextID, p.PolicyID)
 }
 
+// ParsePolicyIdentifier parses a policy identifier of two hex encoded 32 byte hashes, e.g. `0x<context>+0x<policy>`
+func ParsePolicyIdentifier(data string) (*PolicyIdentifier, error) {
+	res := strings.Split(data, "+")
+	if len(res) != 2 {
+		return nil, fmt.Errorf("invalid policy identifier format")
+	}
+	for _, hash := range res {
+		if h := strings.TrimPrefix(hash, "0x"); len(h) != 2*common.HashLength || !isHex(h) {
+			return nil, fmt.Errorf("invalid policy identifier hash: %q", hash)
+		}
+	}
+	identifier := PolicyIdentifier{
+		ContextID: common.HexToHash(res[0]),
+		PolicyID:  common.HexToHash(res[1]),
+	}
+	return &identifier, nil
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
 // Value implements the driver.Valuer interface
 func (p PolicyIdentifier) Value() (driver.Value, error) {
 	return p.String(), nil
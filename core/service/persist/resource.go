@@ -0,0 +1,47 @@
+package persist
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// SessionResourceClaim is the session token claim carrying the DID URL of the requested resource
+	SessionResourceClaim = "resource"
+
+	resourceRefParam = "ref"
+)
+
+// Resource represents a resource of an access context, addressed by a DID URL like `did:pkh:...?ref=static/x.csv`
+type Resource struct {
+	DID string `json:"did"`
+	Ref string `json:"ref"`
+}
+
+// String returns the DID URL of the resource
+func (r Resource) String() string {
+	return fmt.Sprintf("%s?%s=%s", r.DID, resourceRefParam, r.Ref)
+}
+
+// Identifier returns the identifier of the resource as registered on-chain
+func (r Resource) Identifier() string {
+	return fmt.Sprintf("%s;%s", r.DID, r.Ref)
+}
+
+// ParseResourceFromDIDURL Parses a DID URL to Resource type
+func ParseResourceFromDIDURL(data string) (*Resource, error) {
+	did, query, found := strings.Cut(data, "?")
+	if !found || did == "" {
+		return nil, fmt.Errorf("invalid resource did url format")
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource did url query: %w", err)
+	}
+	ref := values.Get(resourceRefParam)
+	if ref == "" {
+		return nil, fmt.Errorf("resource did url is missing the %q parameter", resourceRefParam)
+	}
+	return &Resource{DID: did, Ref: ref}, nil
+}
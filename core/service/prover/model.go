@@ -0,0 +1,18 @@
+package prover
+
+import (
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+)
+
+type ProvePolicyRequest struct {
+	// Context is the did of the access context at AccessContext, whose chain the policy verifier is read from
+	Context          string
+	AccessContext    persist.Address
+	PolicyIdentifier persist.PolicyIdentifier
+}
+
+type ProvePolicyResponse struct {
+	Proof
+	// Credentials are the ids of the credentials the proof was generated from
+	Credentials []string
+}
@@ -0,0 +1,162 @@
+package prover
+
+import (
+	"context"
+	"github.com/fapiper/onchain-access-control/core/internal/groth16"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// NativeProgram is the policy program of the native backend. Instead of code, it names a built-in circuit together
+// with its parameters.
+type NativeProgram struct {
+	Circuit string `json:"circuit"`
+	// Arguments is the number of witness arguments the program is defined over
+	Arguments int             `json:"arguments"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// ParseNativeProgram parses a native program, returning false for programs of other backends
+func ParseNativeProgram(data []byte) (*NativeProgram, bool) {
+	var program NativeProgram
+	if err := json.Unmarshal(data, &program); err != nil || program.Circuit == "" {
+		return nil, false
+	}
+	return &program, true
+}
+
+// Circuit defines the constraints of a native program over the witness arguments. The constraints must only depend
+// on the parameters and the number of arguments, never on their values.
+type Circuit func(b *groth16.Builder, params json.RawMessage, arguments []*big.Int) error
+
+// NativeProver generates proofs in pure Go for native programs, with proving keys written by Setup
+type NativeProver struct {
+	Circuits map[string]Circuit
+}
+
+// NewNativeProver returns a native prover with the built-in circuits
+func NewNativeProver() NativeProver {
+	return NativeProver{
+		Circuits: map[string]Circuit{
+			RangeCircuit: rangeCircuit,
+		},
+	}
+}
+
+func (p NativeProver) Prove(_ context.Context, request ProveRequest) (*Proof, error) {
+	program, ok := ParseNativeProgram(request.Program)
+	if !ok {
+		return nil, errors.New("program is not a native program")
+	}
+	if len(request.Witness.Arguments) != program.Arguments {
+		return nil, errors.Errorf("program<%s> expects %d arguments, got %d", program.Circuit, program.Arguments, len(request.Witness.Arguments))
+	}
+	pk, err := groth16.ParseProvingKey(request.ProvingKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing proving key")
+	}
+
+	r1cs, witness, err := p.build(*program, request.Witness.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	proof, inputs, err := groth16.Prove(*pk, r1cs, witness, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "proving program<%s>", program.Circuit)
+	}
+	return &Proof{Proof: *proof, Inputs: inputs}, nil
+}
+
+// Setup runs the trusted setup of a native program and returns its proving and verification key in the JSON formats
+// expected by Prove and the policy verifiers. The setup is run by a single party, who must be trusted to discard the
+// toxic waste, so it suits policies whose verifier is deployed by the owner of the access context itself.
+func (p NativeProver) Setup(data []byte) (provingKey []byte, verificationKey []byte, err error) {
+	program, ok := ParseNativeProgram(data)
+	if !ok {
+		return nil, nil, errors.New("program is not a native program")
+	}
+	arguments := make([]*big.Int, program.Arguments)
+	for i := range arguments {
+		arguments[i] = new(big.Int)
+	}
+	r1cs, _, err := p.build(*program, arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, vk, err := groth16.Setup(r1cs, nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "setting up program<%s>", program.Circuit)
+	}
+	if provingKey, err = json.Marshal(pk); err != nil {
+		return nil, nil, errors.Wrap(err, "marshalling proving key")
+	}
+	if verificationKey, err = json.Marshal(vk); err != nil {
+		return nil, nil, errors.Wrap(err, "marshalling verification key")
+	}
+	return provingKey, verificationKey, nil
+}
+
+func (p NativeProver) build(program NativeProgram, arguments []*big.Int) (*groth16.R1CS, []*big.Int, error) {
+	circuit, ok := p.Circuits[program.Circuit]
+	if !ok {
+		return nil, nil, errors.Errorf("unknown circuit<%s>", program.Circuit)
+	}
+	b := groth16.NewBuilder()
+	if err := circuit(b, program.Params, arguments); err != nil {
+		return nil, nil, errors.Wrapf(err, "defining circuit<%s>", program.Circuit)
+	}
+	r1cs, witness := b.Build()
+	return r1cs, witness, nil
+}
+
+// RangeCircuit proves each argument to lie within the bounds of the program, e.g. a birthdate before a given day. The
+// bounds are constants of the constraint system, so they are fixed by the verification key and cannot be chosen by the
+// prover.
+const RangeCircuit = "range"
+
+type rangeParams struct {
+	// Bounds holds the inclusive bounds of each argument
+	Bounds []rangeBound `json:"bounds"`
+	// Bits is the bit size of the arguments and bounds, 64 by default
+	Bits int `json:"bits,omitempty"`
+}
+
+type rangeBound struct {
+	Min string `json:"min"`
+	Max string `json:"max"`
+}
+
+// rangeCircuit compares each argument to its minimum and maximum as constants and has no public inputs
+func rangeCircuit(b *groth16.Builder, raw json.RawMessage, arguments []*big.Int) error {
+	var params rangeParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return errors.Wrap(err, "unmarshalling params")
+	}
+	if len(params.Bounds) != len(arguments) {
+		return errors.Errorf("expected bounds for %d arguments, got %d", len(arguments), len(params.Bounds))
+	}
+	bits := params.Bits
+	if bits == 0 {
+		bits = 64
+	}
+	if bits < 1 || bits > 248 {
+		return errors.Errorf("bit size %d out of range", bits)
+	}
+
+	for i, bound := range params.Bounds {
+		minimum, ok := new(big.Int).SetString(bound.Min, 10)
+		if !ok {
+			return errors.Errorf("invalid minimum<%s> of argument %d", bound.Min, i)
+		}
+		maximum, ok := new(big.Int).SetString(bound.Max, 10)
+		if !ok {
+			return errors.Errorf("invalid maximum<%s> of argument %d", bound.Max, i)
+		}
+		lower, upper := groth16.Constant(minimum), groth16.Constant(maximum)
+		value := b.PrivateInput(arguments[i])
+		b.AssertLessOrEqual(lower, value, bits)
+		b.AssertLessOrEqual(value, upper, bits)
+	}
+	return nil
+}
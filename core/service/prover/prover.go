@@ -0,0 +1,45 @@
+package prover
+
+import (
+	"context"
+	"github.com/fapiper/onchain-access-control/core/config"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// Backends a prover service can be configured with
+const (
+	ZoKratesBackend = "zokrates"
+	NativeBackend   = "native"
+)
+
+// Prover generates a Groth16 proof that a witness satisfies a policy program
+type Prover interface {
+	Prove(ctx context.Context, request ProveRequest) (*Proof, error)
+}
+
+// NewProver returns the prover of the configured backend, ZoKrates unless configured otherwise
+func NewProver(config config.ProverServiceConfig) (Prover, error) {
+	switch config.Backend {
+	case "", ZoKratesBackend:
+		return ZoKratesProver{Binary: config.ZoKratesBinary}, nil
+	case NativeBackend:
+		return NewNativeProver(), nil
+	default:
+		return nil, errors.Errorf("unsupported prover backend: %s", config.Backend)
+	}
+}
+
+type ProveRequest struct {
+	// Program is the policy program as pinned to the policy verifier
+	Program []byte
+	// ProvingKey is the proving key of the program as pinned to the policy verifier
+	ProvingKey []byte
+	Witness    Witness
+}
+
+type Proof struct {
+	Proof  contracts.IPolicyVerifierProof
+	Inputs []*big.Int
+}
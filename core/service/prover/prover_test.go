@@ -0,0 +1,227 @@
+package prover
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	credint "github.com/fapiper/onchain-access-control/core/internal/credential"
+	"github.com/fapiper/onchain-access-control/core/internal/groth16"
+)
+
+const holder = "did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2"
+
+func newContainer(id, subject string, claims map[string]any) credint.Container {
+	credentialSubject := credential.CredentialSubject{"id": subject}
+	for k, v := range claims {
+		credentialSubject[k] = v
+	}
+	return credint.Container{
+		ID: id,
+		Credential: &credential.VerifiableCredential{
+			Context:           []any{"https://www.w3.org/2018/credentials/v1"},
+			ID:                id,
+			Type:              []any{"VerifiableCredential"},
+			Issuer:            "did:example:issuer",
+			IssuanceDate:      "2023-01-01T00:00:00Z",
+			CredentialSubject: credentialSubject,
+		},
+	}
+}
+
+func newDefinition(fields ...exchange.Field) exchange.PresentationDefinition {
+	return exchange.PresentationDefinition{
+		ID: "definition",
+		InputDescriptors: []exchange.InputDescriptor{
+			{ID: "descriptor", Constraints: &exchange.Constraints{Fields: fields}},
+		},
+	}
+}
+
+func TestBuildWitness(t *testing.T) {
+	revoked := newContainer("revoked", holder, map[string]any{"birthdate": "1990-01-01"})
+	revoked.Revoked = true
+	credentials := []credint.Container{
+		newContainer("other", "did:example:other", map[string]any{"birthdate": "1990-01-01"}),
+		revoked,
+		newContainer("membership", strings.ToLower(holder), map[string]any{"member": true}),
+		newContainer("identity", holder, map[string]any{
+			"birthdate": "1990-01-01",
+			"name":      "Alice",
+			"score":     42,
+			"balance":   "0x10",
+			"tags":      []any{1, "7"},
+		}),
+	}
+
+	t.Run("encodes the fields of the first matching credential", func(tt *testing.T) {
+		definition := newDefinition(
+			exchange.Field{Path: []string{"$.credentialSubject.dateOfBirth", "$.credentialSubject.birthdate"}},
+			exchange.Field{Path: []string{"$.credentialSubject.name"}},
+			exchange.Field{Path: []string{"$.credentialSubject.score"}},
+			exchange.Field{Path: []string{"$.credentialSubject.balance"}},
+			exchange.Field{Path: []string{"$.credentialSubject.tags"}},
+			exchange.Field{Path: []string{"$.credentialSubject.nickname"}, Optional: true},
+		)
+		witness, err := BuildWitness(definition, holder, credentials)
+		require.NoError(tt, err)
+		assert.Equal(tt, []string{"identity"}, witness.Credentials)
+
+		birthdate := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+		name := new(big.Int).SetBytes(crypto.Keccak256([]byte("Alice")))
+		name.Mod(name, groth16.ScalarField)
+		assert.Equal(tt, []string{
+			big.NewInt(birthdate).String(), name.String(), "42", "16", "1", "7", "0",
+		}, witness.Strings())
+	})
+
+	t.Run("matches the holder case insensitively", func(tt *testing.T) {
+		witness, err := BuildWitness(newDefinition(exchange.Field{Path: []string{"$.credentialSubject.member"}}), holder, credentials)
+		require.NoError(tt, err)
+		assert.Equal(tt, []string{"membership"}, witness.Credentials)
+		assert.Equal(tt, []string{"1"}, witness.Strings())
+	})
+
+	t.Run("no matching credential", func(tt *testing.T) {
+		_, err := BuildWitness(newDefinition(exchange.Field{Path: []string{"$.credentialSubject.unknown"}}), holder, credentials)
+		assert.ErrorContains(tt, err, "no credential of holder")
+	})
+
+	t.Run("objects are not encoded", func(tt *testing.T) {
+		_, err := BuildWitness(newDefinition(exchange.Field{Path: []string{"$.credentialSubject"}}), holder, credentials)
+		assert.ErrorContains(tt, err, "cannot encode value")
+	})
+}
+
+func TestNewProver(t *testing.T) {
+	p, err := NewProver(config.ProverServiceConfig{ZoKratesBinary: "zokrates"})
+	require.NoError(t, err)
+	assert.Equal(t, ZoKratesProver{Binary: "zokrates"}, p)
+
+	p, err = NewProver(config.ProverServiceConfig{Backend: NativeBackend})
+	require.NoError(t, err)
+	assert.IsType(t, NativeProver{}, p)
+
+	_, err = NewProver(config.ProverServiceConfig{Backend: "snarkjs"})
+	assert.ErrorContains(t, err, "unsupported prover backend: snarkjs")
+}
+
+func TestNativeProver(t *testing.T) {
+	p := NewNativeProver()
+	program, err := json.Marshal(NativeProgram{
+		Circuit:   RangeCircuit,
+		Arguments: 1,
+		Params:    json.RawMessage(`{"bounds": [{"min": "18", "max": "130"}], "bits": 16}`),
+	})
+	require.NoError(t, err)
+
+	provingKey, verificationKey, err := p.Setup(program)
+	require.NoError(t, err)
+	vk, err := groth16.ParseVerificationKey(verificationKey)
+	require.NoError(t, err)
+
+	t.Run("valid witness", func(tt *testing.T) {
+		proof, err := p.Prove(context.Background(), ProveRequest{
+			Program:    program,
+			ProvingKey: provingKey,
+			Witness:    Witness{Arguments: []*big.Int{big.NewInt(42)}},
+		})
+		require.NoError(tt, err)
+		assert.Empty(tt, proof.Inputs)
+		assert.NoError(tt, groth16.Verify(*vk, proof.Proof, proof.Inputs))
+	})
+
+	t.Run("bounds are fixed by the verification key", func(tt *testing.T) {
+		proof, err := p.Prove(context.Background(), ProveRequest{
+			Program:    program,
+			ProvingKey: provingKey,
+			Witness:    Witness{Arguments: []*big.Int{big.NewInt(42)}},
+		})
+		require.NoError(tt, err)
+
+		other, err := json.Marshal(NativeProgram{
+			Circuit:   RangeCircuit,
+			Arguments: 1,
+			Params:    json.RawMessage(`{"bounds": [{"min": "0", "max": "130"}], "bits": 16}`),
+		})
+		require.NoError(tt, err)
+		_, otherKey, err := p.Setup(other)
+		require.NoError(tt, err)
+		otherVK, err := groth16.ParseVerificationKey(otherKey)
+		require.NoError(tt, err)
+		assert.ErrorIs(tt, groth16.Verify(*otherVK, proof.Proof, proof.Inputs), groth16.ErrPairingCheckFailed)
+	})
+
+	t.Run("argument out of range", func(tt *testing.T) {
+		_, err := p.Prove(context.Background(), ProveRequest{
+			Program:    program,
+			ProvingKey: provingKey,
+			Witness:    Witness{Arguments: []*big.Int{big.NewInt(17)}},
+		})
+		assert.ErrorIs(tt, err, groth16.ErrUnsatisfied)
+	})
+
+	t.Run("wrong number of arguments", func(tt *testing.T) {
+		_, err := p.Prove(context.Background(), ProveRequest{Program: program, ProvingKey: provingKey})
+		assert.ErrorContains(tt, err, "expects 1 arguments, got 0")
+	})
+}
+
+func TestZoKratesProver(t *testing.T) {
+	binary, err := filepath.Abs("testdata/zokrates")
+	require.NoError(t, err)
+	proofPath, err := filepath.Abs("testdata/proof.json")
+	require.NoError(t, err)
+	log := filepath.Join(t.TempDir(), "zokrates.log")
+	t.Setenv("ZOKRATES_LOG", log)
+	t.Setenv("ZOKRATES_PROOF", proofPath)
+
+	p := ZoKratesProver{Binary: binary}
+	witness := Witness{Arguments: []*big.Int{big.NewInt(20030101), big.NewInt(1)}}
+
+	t.Run("source program", func(tt *testing.T) {
+		require.NoError(tt, os.WriteFile(log, nil, 0600))
+		proof, err := p.Prove(context.Background(), ProveRequest{
+			Program:    []byte("def main(private field a, field b) -> bool { return a > b; }"),
+			ProvingKey: []byte("key"),
+			Witness:    witness,
+		})
+		require.NoError(tt, err)
+		assert.NotEmpty(tt, proof.Inputs)
+
+		calls, err := os.ReadFile(log)
+		require.NoError(tt, err)
+		assert.Equal(tt, []string{
+			"compile -i program.zok -o out",
+			"compute-witness -i out -o witness -a 20030101 1",
+			"generate-proof -i out -w witness -p proving.key -j proof.json",
+		}, strings.Split(strings.TrimSpace(string(calls)), "\n"))
+	})
+
+	t.Run("compiled program", func(tt *testing.T) {
+		require.NoError(tt, os.WriteFile(log, nil, 0600))
+		_, err := p.Prove(context.Background(), ProveRequest{Program: []byte("ZOK\x00compiled"), Witness: witness})
+		require.NoError(tt, err)
+
+		calls, err := os.ReadFile(log)
+		require.NoError(tt, err)
+		assert.NotContains(tt, string(calls), "compile -i")
+	})
+
+	t.Run("failing binary", func(tt *testing.T) {
+		_, err := ZoKratesProver{Binary: "false"}.Prove(context.Background(), ProveRequest{Witness: witness})
+		assert.ErrorContains(tt, err, "zokrates compile")
+	})
+}
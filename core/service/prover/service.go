@@ -0,0 +1,158 @@
+package prover
+
+import (
+	"context"
+	"fmt"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/fapiper/onchain-access-control/core/config"
+	credint "github.com/fapiper/onchain-access-control/core/internal/credential"
+	"github.com/fapiper/onchain-access-control/core/server/pagination"
+	"github.com/fapiper/onchain-access-control/core/service/credential"
+	"github.com/fapiper/onchain-access-control/core/service/framework"
+	"github.com/fapiper/onchain-access-control/core/service/rpc"
+	"github.com/fapiper/onchain-access-control/core/service/rpc/ipfs"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.einride.tech/aip/filtering"
+)
+
+// Service proves policies of access contexts with the credentials held by the wallet of this instance
+type Service struct {
+	config     config.ProverServiceConfig
+	credential *credential.Service
+	rpcService *rpc.Service
+	artifacts  ipfs.Store
+	prover     Prover
+}
+
+func (s Service) Type() framework.Type {
+	return framework.Prover
+}
+
+func (s Service) Status() framework.Status {
+	ae := sdkutil.NewAppendError()
+	if s.credential == nil {
+		ae.AppendString("no credential service configured")
+	}
+	if s.rpcService == nil {
+		ae.AppendString("no rpc service configured")
+	}
+	if s.artifacts == nil {
+		ae.AppendString("no artifact store configured")
+	}
+	if !ae.IsEmpty() {
+		return framework.Status{
+			Status:  framework.StatusNotReady,
+			Message: fmt.Sprintf("prover service is not ready: %s", ae.Error().Error()),
+		}
+	}
+	return framework.Status{Status: framework.StatusReady}
+}
+
+func NewProverService(config config.ProverServiceConfig, c *credential.Service, rpcService *rpc.Service, artifacts ipfs.Store) (*Service, error) {
+	prover, err := NewProver(config)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "instantiating prover for the prover service")
+	}
+	service := Service{
+		config:     config,
+		credential: c,
+		rpcService: rpcService,
+		artifacts:  artifacts,
+		prover:     prover,
+	}
+	if !service.Status().IsReady() {
+		return nil, errors.New(service.Status().Message)
+	}
+	return &service, nil
+}
+
+// ProvePolicy fetches the artifacts of a policy from its verifier, builds the witness from the credentials of the
+// holder and proves the policy program with the configured backend. The program and its bounds are the ones pinned to
+// the verifier on-chain, and only credentials whose issuer signature verifies are used for the witness.
+func (s Service) ProvePolicy(ctx context.Context, request ProvePolicyRequest) (*ProvePolicyResponse, error) {
+	verifier, err := s.rpcService.GetPolicyVerifier(ctx, rpc.GetPolicyVerifierParams{
+		Context:          request.Context,
+		AccessContext:    request.AccessContext,
+		PolicyIdentifier: request.PolicyIdentifier,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting policy verifier")
+	}
+
+	uris, err := s.rpcService.GetPolicyVerifierURIs(ctx, rpc.PolicyVerifierParams{Context: request.Context, Verifier: verifier})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting artifact uris of policy verifier<%s>", verifier)
+	}
+
+	definitionBytes, err := ipfs.ReadURI(ctx, s.artifacts, uris.PresentationDefinition)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching presentation definition")
+	}
+	var definition exchange.PresentationDefinition
+	if err = json.Unmarshal(definitionBytes, &definition); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling presentation definition")
+	}
+
+	program, err := ipfs.ReadURI(ctx, s.artifacts, uris.ProofProgram)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching proof program")
+	}
+	provingKey, err := ipfs.ReadURI(ctx, s.artifacts, uris.ProvingKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching proving key")
+	}
+
+	credentials, err := s.credential.ListCredentials(ctx, filtering.Filter{}, pagination.PageRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing credentials")
+	}
+	verified, err := s.verifiedCredentials(ctx, credentials.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	holder := s.rpcService.OwnerDID()
+	witness, err := BuildWitness(definition, holder, verified)
+	if err != nil {
+		return nil, errors.Wrap(err, "building witness")
+	}
+
+	logrus.Debugf("proving policy<%s> with credentials %v", request.PolicyIdentifier, witness.Credentials)
+
+	proof, err := s.prover.Prove(ctx, ProveRequest{
+		Program:    program,
+		ProvingKey: provingKey,
+		Witness:    *witness,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "proving policy<%s>", request.PolicyIdentifier)
+	}
+	return &ProvePolicyResponse{Proof: *proof, Credentials: witness.Credentials}, nil
+}
+
+// verifiedCredentials drops the credentials whose signature does not verify against their issuer, so that no
+// claim enters the witness that the issuer did not sign
+func (s Service) verifiedCredentials(ctx context.Context, containers []credint.Container) ([]credint.Container, error) {
+	verified := make([]credint.Container, 0, len(containers))
+	for _, container := range containers {
+		if !container.HasSignedCredential() {
+			continue
+		}
+		request := credential.VerifyCredentialRequest{CredentialJWT: container.CredentialJWT}
+		if container.HasDataIntegrityCredential() {
+			request = credential.VerifyCredentialRequest{DataIntegrityCredential: container.Credential}
+		}
+		response, err := s.credential.VerifyCredential(ctx, request)
+		if err != nil {
+			return nil, errors.Wrapf(err, "verifying credential<%s>", container.ID)
+		}
+		if !response.Verified {
+			logrus.Debugf("skipping credential<%s>: %s", container.ID, response.Reason)
+			continue
+		}
+		verified = append(verified, container)
+	}
+	return verified, nil
+}
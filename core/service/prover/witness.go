@@ -0,0 +1,169 @@
+package prover
+
+import (
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/ethereum/go-ethereum/crypto"
+	credint "github.com/fapiper/onchain-access-control/core/internal/credential"
+	"github.com/fapiper/onchain-access-control/core/internal/groth16"
+	"github.com/oliveagle/jsonpath"
+	"github.com/pkg/errors"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Witness holds the arguments of a policy program, derived from the credentials of the holder
+type Witness struct {
+	// Arguments are the encoded values of the fields requested by the presentation definition, in the order of its
+	// input descriptors and their fields
+	Arguments []*big.Int
+	// Credentials are the ids of the credentials the arguments are taken from, one per input descriptor
+	Credentials []string
+}
+
+// Strings returns the arguments as decimal strings, as expected by `zokrates compute-witness -a`
+func (w Witness) Strings() []string {
+	out := make([]string, 0, len(w.Arguments))
+	for _, argument := range w.Arguments {
+		out = append(out, argument.String())
+	}
+	return out
+}
+
+// BuildWitness selects a credential of the holder for each input descriptor of the presentation definition and
+// encodes the values of the requested fields into field elements. Like the presentation exchange of the ssi-sdk, a
+// credential matches an input descriptor if all of its non-optional field paths resolve, filters are left to the
+// policy program.
+func BuildWitness(definition exchange.PresentationDefinition, holder string, credentials []credint.Container) (*Witness, error) {
+	claims := make([]map[string]any, 0, len(credentials))
+	ids := make([]string, 0, len(credentials))
+	for _, container := range credentials {
+		if !isHeldBy(container, holder) {
+			continue
+		}
+		claim, err := sdkutil.ToJSONMap(container.Credential)
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting credential<%s> to json", container.ID)
+		}
+		claims = append(claims, claim)
+		ids = append(ids, container.ID)
+	}
+
+	var witness Witness
+	for _, descriptor := range definition.InputDescriptors {
+		var fields []exchange.Field
+		if descriptor.Constraints != nil {
+			fields = descriptor.Constraints.Fields
+		}
+
+		matched := false
+		for i, claim := range claims {
+			values, ok := selectFields(fields, claim)
+			if !ok {
+				continue
+			}
+			for j, value := range values {
+				arguments, err := encodeValue(value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "encoding field %d of input descriptor<%s>", j, descriptor.ID)
+				}
+				witness.Arguments = append(witness.Arguments, arguments...)
+			}
+			witness.Credentials = append(witness.Credentials, ids[i])
+			matched = true
+			break
+		}
+		if !matched {
+			return nil, errors.Errorf("no credential of holder<%s> matches input descriptor<%s>", holder, descriptor.ID)
+		}
+	}
+	return &witness, nil
+}
+
+// isHeldBy checks the credential to be usable and issued to the holder
+func isHeldBy(container credint.Container, holder string) bool {
+	if container.Credential == nil || container.Revoked || container.Suspended {
+		return false
+	}
+	return strings.EqualFold(container.Credential.CredentialSubject.GetID(), holder)
+}
+
+// selectFields resolves the value of each field in the claim. Missing optional fields resolve to nil.
+func selectFields(fields []exchange.Field, claim map[string]any) ([]any, bool) {
+	values := make([]any, 0, len(fields))
+	for _, field := range fields {
+		value, ok := lookup(field.Path, claim)
+		if !ok && !field.Optional {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+func lookup(paths []string, claim map[string]any) (any, bool) {
+	for _, path := range paths {
+		if value, err := jsonpath.JsonPathLookup(claim, path); err == nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// encodeValue encodes a JSON value into field elements: numbers as integers, booleans as 0 or 1, strings holding an
+// integer or a date as the integer or unix time, other strings by their keccak256 hash reduced into the scalar field
+// and arrays element by element. Missing values encode to 0.
+func encodeValue(value any) ([]*big.Int, error) {
+	switch v := value.(type) {
+	case nil:
+		return []*big.Int{new(big.Int)}, nil
+	case bool:
+		if v {
+			return []*big.Int{big.NewInt(1)}, nil
+		}
+		return []*big.Int{new(big.Int)}, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, errors.Errorf("number<%v> is not an integer", v)
+		}
+		n, _ := big.NewFloat(v).Int(nil)
+		return []*big.Int{toField(n)}, nil
+	case string:
+		return []*big.Int{encodeString(v)}, nil
+	case []any:
+		out := make([]*big.Int, 0, len(v))
+		for _, element := range v {
+			encoded, err := encodeValue(element)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, encoded...)
+		}
+		return out, nil
+	default:
+		return nil, errors.Errorf("cannot encode value of type %T", value)
+	}
+}
+
+func encodeString(s string) *big.Int {
+	if hex, ok := strings.CutPrefix(s, "0x"); ok {
+		if n, ok := new(big.Int).SetString(hex, 16); ok {
+			return toField(n)
+		}
+	}
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return toField(n)
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return toField(big.NewInt(t.Unix()))
+		}
+	}
+	return toField(new(big.Int).SetBytes(crypto.Keccak256([]byte(s))))
+}
+
+func toField(n *big.Int) *big.Int {
+	return n.Mod(n, groth16.ScalarField)
+}
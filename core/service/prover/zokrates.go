@@ -0,0 +1,73 @@
+package prover
+
+import (
+	"bytes"
+	"context"
+	"github.com/fapiper/onchain-access-control/core/internal/groth16"
+	"github.com/pkg/errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// zokratesMagic prefixes programs compiled by `zokrates compile`
+var zokratesMagic = []byte("ZOK")
+
+// ZoKratesProver generates proofs with the ZoKrates command line interface. Programs are accepted both as source code
+// and compiled, the witness arguments are passed to the main function of the program in order.
+type ZoKratesProver struct {
+	// Binary is the path of the zokrates executable
+	Binary string
+}
+
+func (p ZoKratesProver) Prove(ctx context.Context, request ProveRequest) (*Proof, error) {
+	dir, err := os.MkdirTemp("", "zokrates")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating working directory")
+	}
+	defer os.RemoveAll(dir)
+
+	if bytes.HasPrefix(request.Program, zokratesMagic) {
+		if err = os.WriteFile(filepath.Join(dir, "out"), request.Program, 0600); err != nil {
+			return nil, errors.Wrap(err, "writing program")
+		}
+	} else {
+		if err = os.WriteFile(filepath.Join(dir, "program.zok"), request.Program, 0600); err != nil {
+			return nil, errors.Wrap(err, "writing program")
+		}
+		if err = p.run(ctx, dir, "compile", "-i", "program.zok", "-o", "out"); err != nil {
+			return nil, errors.Wrap(err, "compiling program")
+		}
+	}
+	if err = os.WriteFile(filepath.Join(dir, "proving.key"), request.ProvingKey, 0600); err != nil {
+		return nil, errors.Wrap(err, "writing proving key")
+	}
+
+	args := append([]string{"compute-witness", "-i", "out", "-o", "witness", "-a"}, request.Witness.Strings()...)
+	if err = p.run(ctx, dir, args...); err != nil {
+		return nil, errors.Wrap(err, "computing witness")
+	}
+	if err = p.run(ctx, dir, "generate-proof", "-i", "out", "-w", "witness", "-p", "proving.key", "-j", "proof.json"); err != nil {
+		return nil, errors.Wrap(err, "generating proof")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "proof.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading proof")
+	}
+	proof, inputs, err := groth16.ParseProof(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Proof: *proof, Inputs: inputs}, nil
+}
+
+func (p ZoKratesProver) run(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, p.Binary, args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "zokrates %s: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return nil
+}
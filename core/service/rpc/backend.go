@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/fapiper/onchain-access-control/core/tx"
+	"math/big"
+	"time"
+)
+
+// SimulatedChainID is the chain id of the chain simulated by backends.SimulatedBackend
+const SimulatedChainID = 1337
+
+// Backend is the chain the rpc service reads contracts from and sends transactions to
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	TxBackend
+	tx.FeeBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+var (
+	_ Backend = (*ethclient.Client)(nil)
+	_ Backend = (*SimulatedBackend)(nil)
+)
+
+// SimulatedBackend is an in-memory chain to run the rpc service against without a node. Each transaction sent is
+// mined in a block of its own.
+type SimulatedBackend struct {
+	*backends.SimulatedBackend
+}
+
+func NewSimulatedBackend(backend *backends.SimulatedBackend) *SimulatedBackend {
+	return &SimulatedBackend{SimulatedBackend: backend}
+}
+
+// SimulatedConfig returns the config of a service on a simulated chain, whose wallet signs with key. Receipts are
+// polled often, as the simulated chain mines every transaction right away.
+func SimulatedConfig(key *ecdsa.PrivateKey) Config {
+	return Config{
+		Signer:  SignerConfig{PrivateKey: hex.EncodeToString(crypto.FromECDSA(key))},
+		ChainID: SimulatedChainID,
+		Wallet: WalletConfig{
+			Queue: TxQueueConfig{PollInterval: 10 * time.Millisecond},
+		},
+	}
+}
+
+// SendTransaction sends a transaction and mines it
+func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+// BlockNumber returns the number of the latest block
+func (b *SimulatedBackend) BlockNumber(_ context.Context) (uint64, error) {
+	return b.Blockchain().CurrentBlock().Number.Uint64(), nil
+}
+
+// FeeHistory returns the base fee of the latest block, the simulated chain keeps no history of fees
+func (b *SimulatedBackend) FeeHistory(_ context.Context, _ uint64, _ *big.Int, _ []float64) (*ethereum.FeeHistory, error) {
+	head := b.Blockchain().CurrentBlock()
+	return &ethereum.FeeHistory{
+		OldestBlock: new(big.Int).Set(head.Number),
+		BaseFee:     []*big.Int{head.BaseFee},
+	}, nil
+}
@@ -0,0 +1,327 @@
+package rpc
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCachePollInterval = 2 * time.Second
+
+	// maxCacheSyncBlocks is the most blocks the cache scans for invalidating events at once. A cache further behind
+	// the chain head is flushed instead.
+	maxCacheSyncBlocks = 1000
+)
+
+// CacheConfig is how long reads of access context addresses, roles and sessions are served from the cache of the rpc
+// service instead of the chain
+type CacheConfig struct {
+	// MaxAge bounds the staleness of a cached read, which is read from the chain again after MaxAge even if no event
+	// invalidated it, e.g. once its session expired. Reads are not cached if zero.
+	MaxAge time.Duration
+	// PollInterval in which the cache follows the chain head and the events invalidating cached reads
+	PollInterval time.Duration
+	// StatsInterval in which the hits and misses of the cache are logged, they are not logged if zero
+	StatsInterval time.Duration
+}
+
+func cacheConfigFromEnv() CacheConfig {
+	return CacheConfig{
+		MaxAge:        env.GetDuration("RPC_CACHE_MAX_AGE"),
+		PollInterval:  env.GetDuration("RPC_CACHE_POLL_INTERVAL"),
+		StatsInterval: env.GetDuration("RPC_CACHE_STATS_INTERVAL"),
+	}
+}
+
+// CacheStats counts the reads served by the cache of a chain
+type CacheStats struct {
+	ChainID       uint64
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	Entries       int
+	// Block the cached reads are read at, zero until the cache first followed the chain head
+	Block uint64
+}
+
+type cacheKind uint8
+
+const (
+	contextAddressKind cacheKind = iota
+	roleKind
+	sessionKind
+)
+
+// cacheKey identifies a cached read, the access context of a did, a role of a did in the access context at address, or
+// a session of a did
+type cacheKey struct {
+	kind    cacheKind
+	address common.Address
+	id      common.Hash
+	did     common.Hash
+}
+
+type cacheEntry struct {
+	value  any
+	readAt time.Time
+}
+
+// readCache caches reads at the block it followed the events of the chain up to. A cached read stays valid as long as
+// no event of a later block touches it, so moving on to a new block keeps the cached reads unless they are invalidated.
+type readCache struct {
+	config  CacheConfig
+	backend Backend
+	chainID uint64
+	events  cacheEvents
+
+	mu      sync.Mutex
+	block   uint64
+	hash    common.Hash
+	entries map[cacheKey]cacheEntry
+	stats   CacheStats
+}
+
+// cacheEvents are the signatures of the events invalidating cached reads
+type cacheEvents struct {
+	createContextInstance common.Hash
+	roleGranted           common.Hash
+	roleRevoked           common.Hash
+	sessionStarted        common.Hash
+	sessionRevoked        common.Hash
+}
+
+// newReadCache returns the cache of a chain, or nil if reads are not cached
+func newReadCache(backend Backend, chainID uint64, config CacheConfig) (*readCache, error) {
+	if config.MaxAge <= 0 {
+		return nil, nil
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultCachePollInterval
+	}
+
+	handlerABI, err := contracts.AccessContextHandlerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contextABI, err := contracts.AccessContextMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	sessionABI, err := contracts.SessionRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return &readCache{
+		config:  config,
+		backend: backend,
+		chainID: chainID,
+		events: cacheEvents{
+			createContextInstance: handlerABI.Events["CreateContextInstance"].ID,
+			roleGranted:           contextABI.Events["RoleGranted"].ID,
+			roleRevoked:           contextABI.Events["RoleRevoked"].ID,
+			sessionStarted:        sessionABI.Events["SessionStarted"].ID,
+			sessionRevoked:        sessionABI.Events["SessionRevoked"].ID,
+		},
+		entries: make(map[cacheKey]cacheEntry),
+	}, nil
+}
+
+// cachedRead returns the cached read of key, or reads it at the block the cache followed the chain to and caches it.
+// Without a cache, or before the cache first followed the chain head, it reads the latest block.
+func cachedRead[T any](c *readCache, key cacheKey, opts *bind.CallOpts, read func(*bind.CallOpts) (T, error)) (T, error) {
+	if c == nil {
+		return read(opts)
+	}
+
+	c.mu.Lock()
+	block := c.block
+	entry, ok := c.entries[key]
+	if ok && time.Since(entry.readAt) < c.config.MaxAge {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return entry.value.(T), nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	if block == 0 {
+		return read(opts)
+	}
+	pinned := *opts
+	pinned.BlockNumber = new(big.Int).SetUint64(block)
+	value, err := read(&pinned)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the events of the blocks the cache moved on to meanwhile may already have invalidated the read
+	if c.block == block {
+		c.entries[key] = cacheEntry{value: value, readAt: time.Now()}
+	}
+	return value, nil
+}
+
+// run follows the chain head until the context is done
+func (c *readCache) run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	var stats <-chan time.Time
+	if c.config.StatsInterval > 0 {
+		statsTicker := time.NewTicker(c.config.StatsInterval)
+		defer statsTicker.Stop()
+		stats = statsTicker.C
+	}
+
+	for {
+		if err := c.sync(ctx); err != nil {
+			logrus.WithError(err).Warnf("could not follow chain %d for the read cache", c.chainID)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-stats:
+			s := c.Stats()
+			logrus.Infof("read cache of chain %d at block %d: %d hits, %d misses, %d invalidations, %d entries",
+				s.ChainID, s.Block, s.Hits, s.Misses, s.Invalidations, s.Entries)
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync moves the cache on to the chain head, invalidating the reads touched by the events of the blocks in between. A
+// reorg of the block the cache was at flushes it.
+func (c *readCache) sync(ctx context.Context) error {
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "getting chain head")
+	}
+
+	c.mu.Lock()
+	block, hash := c.block, c.hash
+	c.mu.Unlock()
+	if head.Number.Uint64() <= block {
+		return nil
+	}
+
+	flush := block == 0 || head.Number.Uint64()-block > maxCacheSyncBlocks
+	var logs []types.Log
+	if !flush {
+		header, err := c.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+		if err != nil {
+			return errors.Wrapf(err, "getting header of block %d", block)
+		}
+		if flush = header.Hash() != hash; !flush {
+			logs, err = c.backend.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(block + 1),
+				ToBlock:   head.Number,
+				Topics: [][]common.Hash{{
+					c.events.createContextInstance,
+					c.events.roleGranted,
+					c.events.roleRevoked,
+					c.events.sessionStarted,
+					c.events.sessionRevoked,
+				}},
+			})
+			if err != nil {
+				return errors.Wrap(err, "filtering logs")
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if flush {
+		c.stats.Invalidations += uint64(len(c.entries))
+		c.entries = make(map[cacheKey]cacheEntry)
+	}
+	for _, log := range logs {
+		c.invalidate(log)
+	}
+	for key, entry := range c.entries {
+		if time.Since(entry.readAt) >= c.config.MaxAge {
+			delete(c.entries, key)
+		}
+	}
+	c.block, c.hash = head.Number.Uint64(), head.Hash()
+	return nil
+}
+
+// invalidate drops the cached reads touched by an event. Only the signature and indexed arguments of the event are
+// relied on, so that an event of an unrelated contract at worst drops reads needlessly.
+func (c *readCache) invalidate(log types.Log) {
+	if len(log.Topics) == 0 {
+		return
+	}
+	var touches func(cacheKey) bool
+	switch {
+	case log.Topics[0] == c.events.createContextInstance:
+		touches = func(key cacheKey) bool { return key.kind == contextAddressKind }
+	case (log.Topics[0] == c.events.roleGranted || log.Topics[0] == c.events.roleRevoked) && len(log.Topics) == 3:
+		touches = func(key cacheKey) bool {
+			return key.kind == roleKind && key.address == log.Address && key.id == log.Topics[1] && key.did == log.Topics[2]
+		}
+	case (log.Topics[0] == c.events.sessionStarted || log.Topics[0] == c.events.sessionRevoked) && len(log.Topics) >= 2:
+		touches = func(key cacheKey) bool { return key.kind == sessionKind && key.id == log.Topics[1] }
+	default:
+		return
+	}
+	for key := range c.entries {
+		if touches(key) {
+			delete(c.entries, key)
+			c.stats.Invalidations++
+		}
+	}
+}
+
+// Stats returns the hits and misses of the cache
+func (c *readCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.ChainID = c.chainID
+	stats.Entries = len(c.entries)
+	stats.Block = c.block
+	return stats
+}
+
+// RunCache follows the chain heads for the read caches of all chains until the context is done. It returns
+// immediately when reads are not cached.
+func (s Service) RunCache(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, chain := range s.Chains() {
+		if chain.cache == nil {
+			logrus.Infof("read cache of chain %d disabled", chain.ChainID())
+			continue
+		}
+		wg.Add(1)
+		go func(cache *readCache) {
+			defer wg.Done()
+			cache.run(ctx)
+		}(chain.cache)
+	}
+	wg.Wait()
+}
+
+// CacheStats returns the hits and misses of the read caches of all chains that cache reads
+func (s Service) CacheStats() []CacheStats {
+	var stats []CacheStats
+	for _, chain := range s.Chains() {
+		if chain.cache != nil {
+			stats = append(stats, chain.cache.Stats())
+		}
+	}
+	return stats
+}
@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheChain is a chain the cache follows, whose head and logs are set by the test
+type cacheChain struct {
+	Backend
+	mu   sync.Mutex
+	head uint64
+	// fork changes the hashes of all blocks, as a reorg would
+	fork uint64
+	logs []types.Log
+}
+
+func (c *cacheChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number == nil {
+		number = new(big.Int).SetUint64(c.head)
+	}
+	return &types.Header{Number: number, Extra: new(big.Int).SetUint64(c.fork).Bytes()}, nil
+}
+
+func (c *cacheChain) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber >= q.FromBlock.Uint64() && log.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// mine moves the head on by a block holding the given logs
+func (c *cacheChain) mine(logs ...types.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head++
+	for _, log := range logs {
+		log.BlockNumber = c.head
+		c.logs = append(c.logs, log)
+	}
+}
+
+func TestReadCache(t *testing.T) {
+	ctx := context.Background()
+	contextAddress := common.HexToAddress("0xd231120Eea6201B142b4048cf6C86BaC2A0655D2")
+	role, did := common.HexToHash("0x01"), common.HexToHash("0x02")
+	roleKey := cacheKey{kind: roleKind, address: contextAddress, id: role, did: did}
+	sessionKey := cacheKey{kind: sessionKind, id: common.HexToHash("0x03"), did: did}
+
+	newCache := func(tt *testing.T, maxAge time.Duration) (*readCache, *cacheChain) {
+		chain := cacheChain{head: 10}
+		cache, err := newReadCache(&chain, SimulatedChainID, CacheConfig{MaxAge: maxAge})
+		require.NoError(tt, err)
+		return cache, &chain
+	}
+	// read returns the block a read was served at, counting the reads that reached the chain
+	var reads int
+	read := func(cache *readCache, key cacheKey) uint64 {
+		block, err := cachedRead(cache, key, &bind.CallOpts{}, func(opts *bind.CallOpts) (uint64, error) {
+			reads++
+			if opts.BlockNumber == nil {
+				return 0, nil
+			}
+			return opts.BlockNumber.Uint64(), nil
+		})
+		require.NoError(t, err)
+		return block
+	}
+
+	t.Run("reads the latest block until it follows the chain", func(tt *testing.T) {
+		cache, _ := newCache(tt, time.Minute)
+		reads = 0
+		assert.Equal(tt, uint64(0), read(cache, roleKey))
+		assert.Equal(tt, uint64(0), read(cache, roleKey))
+		assert.Equal(tt, 2, reads)
+	})
+
+	t.Run("serves reads at the block it followed the chain to", func(tt *testing.T) {
+		cache, chain := newCache(tt, time.Minute)
+		require.NoError(tt, cache.sync(ctx))
+		reads = 0
+		assert.Equal(tt, uint64(10), read(cache, roleKey))
+
+		// blocks without invalidating events keep the read
+		chain.mine()
+		require.NoError(tt, cache.sync(ctx))
+		assert.Equal(tt, uint64(10), read(cache, roleKey))
+		assert.Equal(tt, 1, reads)
+
+		stats := cache.Stats()
+		assert.Equal(tt, CacheStats{ChainID: SimulatedChainID, Hits: 1, Misses: 1, Entries: 1, Block: 11}, stats)
+	})
+
+	t.Run("invalidates reads touched by events", func(tt *testing.T) {
+		cache, chain := newCache(tt, time.Minute)
+		require.NoError(tt, cache.sync(ctx))
+		read(cache, roleKey)
+		read(cache, sessionKey)
+		otherRole := roleKey
+		otherRole.id = common.HexToHash("0x04")
+		read(cache, otherRole)
+
+		chain.mine(types.Log{Address: contextAddress, Topics: []common.Hash{cache.events.roleRevoked, role, did}})
+		require.NoError(tt, cache.sync(ctx))
+		assert.Equal(tt, uint64(11), read(cache, roleKey))
+		assert.Equal(tt, uint64(10), read(cache, otherRole), "the revocation of another role keeps the read")
+		assert.Equal(tt, uint64(10), read(cache, sessionKey))
+
+		chain.mine(types.Log{Topics: []common.Hash{cache.events.sessionRevoked, sessionKey.id}})
+		require.NoError(tt, cache.sync(ctx))
+		assert.Equal(tt, uint64(12), read(cache, sessionKey))
+		assert.Equal(tt, uint64(2), cache.Stats().Invalidations)
+	})
+
+	t.Run("invalidates context addresses on new contexts", func(tt *testing.T) {
+		cache, chain := newCache(tt, time.Minute)
+		require.NoError(tt, cache.sync(ctx))
+		contextKey := cacheKey{kind: contextAddressKind, did: did}
+		read(cache, contextKey)
+		read(cache, roleKey)
+
+		chain.mine(types.Log{Topics: []common.Hash{cache.events.createContextInstance, common.BytesToHash(contextAddress.Bytes())}})
+		require.NoError(tt, cache.sync(ctx))
+		assert.Equal(tt, uint64(11), read(cache, contextKey))
+		assert.Equal(tt, uint64(10), read(cache, roleKey))
+	})
+
+	t.Run("flushes on a reorg", func(tt *testing.T) {
+		cache, chain := newCache(tt, time.Minute)
+		require.NoError(tt, cache.sync(ctx))
+		read(cache, roleKey)
+
+		chain.fork++
+		chain.mine()
+		require.NoError(tt, cache.sync(ctx))
+		assert.Equal(tt, uint64(11), read(cache, roleKey))
+	})
+
+	t.Run("bounds the staleness of reads", func(tt *testing.T) {
+		cache, _ := newCache(tt, 20*time.Millisecond)
+		require.NoError(tt, cache.sync(ctx))
+		reads = 0
+		read(cache, roleKey)
+		time.Sleep(30 * time.Millisecond)
+		read(cache, roleKey)
+		assert.Equal(tt, 2, reads)
+	})
+
+	t.Run("is disabled without a max age", func(tt *testing.T) {
+		cache, _ := newCache(tt, 0)
+		assert.Nil(tt, cache)
+		reads = 0
+		read(cache, roleKey)
+		read(cache, roleKey)
+		assert.Equal(tt, 2, reads)
+	})
+}
@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const eip155Prefix = "did:pkh:eip155:"
+
+// ErrUnknownChain is returned for a did:pkh of a chain the rpc service is not configured for
+var ErrUnknownChain = errors.New("unknown chain")
+
+// ChainConfig is a chain served in addition to the default chain of the rpc service, with its own node, contracts and
+// wallet
+type ChainConfig struct {
+	ChainID uint64
+	// RPCURL is a comma separated list of the endpoints of the chain, in order of preference
+	RPCURL string
+	// Backend of the chain, a client of the endpoints at RPCURL is used if nil
+	Backend Backend
+	// Signer of the wallet on the chain, the signer of the default chain is used if nil
+	Signer          *SignerConfig
+	ContextHandler  persist.Address
+	SessionRegistry persist.Address
+	DIDRegistry     persist.Address
+}
+
+// chainConfigsFromEnv reads the chains listed in CHAINS, each configured by the variables prefixed with
+// CHAIN_<id>_, e.g. CHAIN_11155111_RPC_URL. A chain without a WALLET_SIGNER of its own shares the default signer.
+func chainConfigsFromEnv() ([]ChainConfig, error) {
+	var chains []ChainConfig
+	for _, id := range strings.Split(env.GetString("CHAINS"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		chainID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid chain id<%s> in CHAINS", id)
+		}
+
+		prefix := fmt.Sprintf("CHAIN_%d_", chainID)
+		chain := ChainConfig{
+			ChainID:         chainID,
+			RPCURL:          env.GetString(prefix + "RPC_URL"),
+			ContextHandler:  persist.Address(env.GetString(prefix + "CONTEXT_HANDLER_CONTRACT")),
+			SessionRegistry: persist.Address(env.GetString(prefix + "SESSION_REGISTRY_CONTRACT")),
+			DIDRegistry:     persist.Address(env.GetString(prefix + "DID_REGISTRY_CONTRACT")),
+		}
+		if chain.RPCURL == "" {
+			return nil, errors.Errorf("no %sRPC_URL configured", prefix)
+		}
+		if signer := signerConfigFromEnv(prefix); signer.Type != "" {
+			chain.Signer = &signer
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+// newChainService returns the service of a chain other than the default chain, whose signer, retry and cache config it
+// shares unless the chain configures its own signer
+func newChainService(ctx context.Context, chain ChainConfig, signer Signer, config Config, keyStore *keystore.Service) (*Service, error) {
+	backend := chain.Backend
+	if backend == nil {
+		client, err := NewClient(ParseEndpoints(chain.RPCURL), config.Retry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create client of chain %d", chain.ChainID)
+		}
+		backend = client
+	}
+
+	if chain.Signer != nil {
+		var err error
+		if signer, err = NewSigner(ctx, *chain.Signer, keyStore); err != nil {
+			return nil, errors.Wrapf(err, "could not instantiate signer of chain %d", chain.ChainID)
+		}
+	}
+
+	wallet, err := NewWallet(backend, signer, chain.ChainID, config.Wallet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not instantiate wallet of chain %d", chain.ChainID)
+	}
+	cache, err := newReadCache(backend, chain.ChainID, config.Cache)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not instantiate read cache of chain %d", chain.ChainID)
+	}
+	return &Service{
+		Wallet:          wallet,
+		ContextHandler:  chain.ContextHandler,
+		SessionRegistry: chain.SessionRegistry,
+		DIDRegistry:     chain.DIDRegistry,
+		cache:           cache,
+	}, nil
+}
+
+// ChainIDOf returns the chain id of a did:pkh of an eip155 chain. Other dids are not bound to a chain.
+func ChainIDOf(did string) (uint64, bool, error) {
+	if !strings.HasPrefix(did, eip155Prefix) {
+		return 0, false, nil
+	}
+	reference, _, found := strings.Cut(strings.TrimPrefix(did, eip155Prefix), ":")
+	if !found {
+		return 0, false, errors.Errorf("did<%s> has no account", did)
+	}
+	chainID, err := strconv.ParseUint(reference, 10, 64)
+	if err != nil {
+		return 0, false, errors.Errorf("did<%s> has an invalid chain id", did)
+	}
+	return chainID, true, nil
+}
+
+// ChainID returns the id of the chain of the service
+func (s Service) ChainID() uint64 {
+	return s.Wallet.ChainID.Uint64()
+}
+
+// Chain returns the service of a chain
+func (s Service) Chain(chainID uint64) (*Service, error) {
+	if chainID == s.ChainID() {
+		return &s, nil
+	}
+	if chain, ok := s.chains[chainID]; ok {
+		return chain, nil
+	}
+	return nil, errors.Wrapf(ErrUnknownChain, "chain %d", chainID)
+}
+
+// ChainOf returns the service of the chain of a did:pkh. Dids not bound to a chain are served by the default chain.
+func (s Service) ChainOf(did string) (*Service, error) {
+	chainID, ok, err := ChainIDOf(did)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &s, nil
+	}
+	return s.Chain(chainID)
+}
+
+// Chains returns the services of all chains, starting with the default chain followed by the others by chain id
+func (s Service) Chains() []*Service {
+	chains := make([]*Service, 0, len(s.chains)+1)
+	for _, chain := range s.chains {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].ChainID() < chains[j].ChainID()
+	})
+	return append([]*Service{&s}, chains...)
+}
@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/service/framework"
+)
+
+func TestChainIDOf(t *testing.T) {
+	tests := []struct {
+		did     string
+		chainID uint64
+		bound   bool
+		err     bool
+	}{
+		{"did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2", 1337, true, false},
+		{"did:pkh:eip155:11155111:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2#blockchainAccountId", 11155111, true, false},
+		{"did:pkh:eip155:sepolia:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2", 0, false, true},
+		{"did:pkh:eip155:1337", 0, false, true},
+		{"did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:CKg5d12Jhpej1JqtmxLJgaFqqeYjxgPqToJ4LBdvG9Ev", 0, false, false},
+		{"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", 0, false, false},
+		{"", 0, false, false},
+	}
+	for _, test := range tests {
+		chainID, bound, err := ChainIDOf(test.did)
+		if test.err {
+			assert.Error(t, err, test.did)
+			continue
+		}
+		require.NoError(t, err, test.did)
+		assert.Equal(t, test.chainID, chainID, test.did)
+		assert.Equal(t, test.bound, bound, test.did)
+	}
+}
+
+func TestChains(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// a node that is gone, which the chain is dialed at but cannot be read from
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+
+	s, err := NewRPCServiceWithConfig(newFundedBackend(t, address), Config{
+		Signer:  SignerConfig{PrivateKey: hex.EncodeToString(crypto.FromECDSA(key))},
+		ChainID: SimulatedChainID,
+		Chains: []ChainConfig{
+			{ChainID: 10, RPCURL: gone.URL},
+			{ChainID: 5, Backend: newFundedBackend(t, address), Signer: &SignerConfig{PrivateKey: hex.EncodeToString(crypto.FromECDSA(other))}},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	t.Run("routes dids to the service of their chain", func(tt *testing.T) {
+		chain, err := s.ChainOf("did:pkh:eip155:5:" + address.Hex())
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(5), chain.ChainID())
+		assert.Equal(tt, crypto.PubkeyToAddress(other.PublicKey), chain.Wallet.Address)
+		assert.Equal(tt, "did:pkh:eip155:5:"+crypto.PubkeyToAddress(other.PublicKey).Hex(), chain.Wallet.GetDID())
+
+		chain, err = s.ChainOf("did:pkh:eip155:10:" + address.Hex())
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(10), chain.ChainID())
+		assert.Equal(tt, address, chain.Wallet.Address, "chains without a signer share the default signer")
+
+		chain, err = s.ChainOf("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(SimulatedChainID), chain.ChainID())
+	})
+
+	t.Run("rejects dids of unknown chains", func(tt *testing.T) {
+		did := "did:pkh:eip155:1:" + address.Hex()
+		_, err := s.GetAccessContextAddress(did)
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.HasRole(context.Background(), HasRoleParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.CheckSession(context.Background(), CheckSessionParams{Subject: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.HasPermission(context.Background(), HasPermissionParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.GetRolePolicyCount(context.Background(), GetRolePolicyCountParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.GetPolicyVerifier(context.Background(), GetPolicyVerifierParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.GetVerificationKeyURI(context.Background(), PolicyVerifierParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.GetPolicyInputCount(context.Background(), PolicyVerifierParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+		_, err = s.GetPolicyVerifierURIs(context.Background(), PolicyVerifierParams{Context: did})
+		assert.ErrorIs(tt, err, ErrUnknownChain)
+	})
+
+	t.Run("reports readiness per chain", func(tt *testing.T) {
+		chains := s.Chains()
+		require.Len(tt, chains, 3)
+
+		var chainTypes []framework.Type
+		ready := make(map[framework.Type]bool)
+		for _, chain := range chains {
+			chainTypes = append(chainTypes, chain.Type())
+			ready[chain.Type()] = chain.Status().IsReady()
+		}
+		assert.Equal(tt, []framework.Type{"rpc:eip155:1337", "rpc:eip155:5", "rpc:eip155:10"}, chainTypes)
+		assert.Equal(tt, map[framework.Type]bool{"rpc:eip155:1337": true, "rpc:eip155:5": true, "rpc:eip155:10": false}, ready)
+	})
+
+	t.Run("acts for the configured owner did", func(tt *testing.T) {
+		assert.Equal(tt, s.Wallet.GetDID(), s.OwnerDID())
+
+		owner := "did:pkh:eip155:1337:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2"
+		rotated, err := NewRPCServiceWithConfig(newFundedBackend(tt, address), Config{
+			Signer:   SignerConfig{PrivateKey: hex.EncodeToString(crypto.FromECDSA(other))},
+			ChainID:  SimulatedChainID,
+			OwnerDID: owner,
+		}, nil)
+		require.NoError(tt, err)
+		assert.Equal(tt, owner, rotated.OwnerDID())
+		assert.Equal(tt, crypto.Keccak256Hash([]byte(owner)), rotated.OwnerDIDHash())
+		assert.NotEqual(tt, owner, rotated.Wallet.GetDID())
+	})
+
+	t.Run("rejects a chain configured twice", func(tt *testing.T) {
+		_, err := NewRPCServiceWithConfig(newFundedBackend(tt, address), Config{
+			Signer:  SignerConfig{PrivateKey: hex.EncodeToString(crypto.FromECDSA(key))},
+			ChainID: SimulatedChainID,
+			Chains:  []ChainConfig{{ChainID: SimulatedChainID, Backend: newFundedBackend(tt, common.Address{})}},
+		}, nil)
+		assert.Error(tt, err)
+	})
+}
@@ -0,0 +1,427 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 4
+	defaultInitialBackoff = 250 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+
+	// limitExceededCode is the json-rpc error code some providers answer rate limited calls with
+	limitExceededCode = -32005
+)
+
+var _ Backend = (*Client)(nil)
+
+// RetryConfig is how a client retries calls and backs off from failed endpoints
+type RetryConfig struct {
+	// MaxAttempts of an idempotent call, across all endpoints. Transactions are sent once.
+	MaxAttempts int
+	// InitialBackoff is how long an endpoint is skipped after it failed, doubling with every further failure up to
+	// MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func retryConfigFromEnv() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    env.GetInt("RPC_MAX_ATTEMPTS"),
+		InitialBackoff: env.GetDuration("RPC_INITIAL_BACKOFF"),
+		MaxBackoff:     env.GetDuration("RPC_MAX_BACKOFF"),
+	}
+}
+
+// WithDefaults returns the config with defaults for the unset values
+func (c RetryConfig) WithDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = defaultInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+	return c
+}
+
+// ParseEndpoints parses a comma separated list of endpoints
+func ParseEndpoints(endpoints string) []string {
+	var parsed []string
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			parsed = append(parsed, endpoint)
+		}
+	}
+	return parsed
+}
+
+// Client is a chain client over an ordered list of endpoints. Calls go to the first healthy endpoint. An endpoint that
+// cannot be reached, answers with an http error or rate limits the client is skipped for a backoff that doubles with
+// every failure in a row, and the call fails over to the next endpoint. Idempotent calls are retried, waiting for the
+// first endpoint to come out of its backoff if none is healthy.
+type Client struct {
+	config    RetryConfig
+	endpoints []*endpoint
+	mu        sync.Mutex
+}
+
+type endpoint struct {
+	url    string
+	client *ethclient.Client
+	// failures in a row, the endpoint is skipped until retryAt after a failure
+	failures int
+	retryAt  time.Time
+	lastErr  error
+}
+
+// EndpointStatus is the health of an endpoint of a client
+type EndpointStatus struct {
+	// Endpoint is the scheme and host of the endpoint, leaving out api keys in its path or query
+	Endpoint string
+	Healthy  bool
+	Error    string
+	RetryAt  time.Time
+}
+
+// NewClient returns a client of endpoints in order of preference. The endpoints are dialed on first use, so the
+// client is returned even if none is reachable yet.
+func NewClient(endpoints []string, config RetryConfig) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrEthClient{Err: errors.New("no rpc endpoint configured")}
+	}
+	client := Client{config: config.WithDefaults()}
+	for _, e := range endpoints {
+		client.endpoints = append(client.endpoints, &endpoint{url: e})
+	}
+	return &client, nil
+}
+
+// Endpoints returns the health of the endpoints of the client
+func (c *Client) Endpoints() []EndpointStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	statuses := make([]EndpointStatus, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		status := EndpointStatus{Endpoint: redact(e.url), Healthy: e.failures == 0}
+		if e.failures > 0 {
+			status.Error = e.lastErr.Error()
+			status.RetryAt = e.retryAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Healthy returns whether any endpoint of the client is healthy
+func (c *Client) Healthy() bool {
+	for _, status := range c.Endpoints() {
+		if status.Healthy {
+			return true
+		}
+	}
+	return false
+}
+
+// call runs fn on the first healthy endpoint, retrying idempotent calls on failures of the endpoint
+func (c *Client) call(ctx context.Context, idempotent bool, fn func(*ethclient.Client) error) error {
+	attempts := 1
+	if idempotent {
+		attempts = c.config.MaxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		e, wait := c.next()
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ErrEthClient{Err: errors.Wrapf(ctx.Err(), "waiting for an endpoint after: %s", err)}
+			case <-time.After(wait):
+			}
+		}
+
+		var client *ethclient.Client
+		if client, err = c.client(ctx, e); err == nil {
+			err = fn(client)
+		}
+		if !endpointFailed(err) {
+			c.succeeded(e)
+			return err
+		}
+		c.failed(e, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return ErrEthClient{Err: err}
+}
+
+// next returns the first endpoint that is not backing off, or the endpoint coming out of its backoff first along with
+// how long it still backs off
+func (c *Client) next() (*endpoint, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	next := c.endpoints[0]
+	for _, e := range c.endpoints {
+		if !now.Before(e.retryAt) {
+			return e, 0
+		}
+		if e.retryAt.Before(next.retryAt) {
+			next = e
+		}
+	}
+	return next, next.retryAt.Sub(now)
+}
+
+// client returns the client of an endpoint, which is dialed on first use
+func (c *Client) client(ctx context.Context, e *endpoint) (*ethclient.Client, error) {
+	c.mu.Lock()
+	client := e.client
+	c.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+
+	client, err := dial(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.client == nil {
+		e.client = client
+	} else {
+		client.Close()
+	}
+	return e.client, nil
+}
+
+func (c *Client) succeeded(e *endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.failures > 0 {
+		logrus.Infof("rpc endpoint %s recovered", redact(e.url))
+	}
+	e.failures = 0
+	e.retryAt = time.Time{}
+	e.lastErr = nil
+}
+
+func (c *Client) failed(e *endpoint, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	backoff := c.config.InitialBackoff
+	for i := 0; i < e.failures && backoff < c.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > c.config.MaxBackoff {
+		backoff = c.config.MaxBackoff
+	}
+	e.failures++
+	e.retryAt = time.Now().Add(backoff)
+	e.lastErr = err
+
+	if isRateLimited(err) {
+		logrus.Warnf("rpc endpoint %s is rate limited, backing off for %s", redact(e.url), backoff)
+	} else {
+		logrus.WithError(err).Warnf("rpc endpoint %s failed, backing off for %s", redact(e.url), backoff)
+	}
+}
+
+// endpointFailed returns whether a call failed because of its endpoint rather than the call itself. Errors answered
+// by the node, like a reverted call or a missing receipt, are no failures of the endpoint.
+func endpointFailed(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if isRateLimited(err) {
+		return true
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// isRateLimited returns whether an endpoint answered a call with a rate limit
+func isRateLimited(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == limitExceededCode {
+		return true
+	}
+	return strings.Contains(err.Error(), rateLimited)
+}
+
+// redact returns the scheme and host of an endpoint, which may carry an api key in its path or query
+func redact(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return fmt.Sprintf("endpoint<%d chars>", len(endpoint))
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (number uint64, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		number, err = client.BlockNumber(ctx)
+		return err
+	})
+	return number, err
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		tx, isPending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) (value []byte, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		value, err = client.StorageAt(ctx, account, key, blockNumber)
+		return err
+	})
+	return value, err
+}
+
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		code, err = client.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (nonce uint64, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		nonce, err = client.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return nonce, err
+}
+
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		logs, err = client.FilterLogs(ctx, q)
+		return err
+	})
+	return logs, err
+}
+
+// SubscribeFilterLogs subscribes to logs at the first healthy endpoint, the subscription does not fail over
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = c.call(ctx, false, func(client *ethclient.Client) (err error) {
+		sub, err = client.SubscribeFilterLogs(ctx, q, ch)
+		return err
+	})
+	return sub, err
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		code, err = client.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		result, err = client.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		price, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		tip, err = client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (history *ethereum.FeeHistory, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		history, err = client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+		return err
+	})
+	return history, err
+}
+
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = c.call(ctx, true, func(client *ethclient.Client) (err error) {
+		gas, err = client.EstimateGas(ctx, msg)
+		return err
+	})
+	return gas, err
+}
+
+// SendTransaction sends a transaction to the first healthy endpoint without retrying, the transaction queue of the
+// wallet resubmits transactions that do not get mined
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.call(ctx, false, func(client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
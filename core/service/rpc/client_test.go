@@ -0,0 +1,191 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/service/framework"
+)
+
+// ethAPI is a stand-in for the eth_ api of a node at a fixed block
+type ethAPI struct {
+	block uint64
+}
+
+func (a *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(a.block)
+}
+
+func (a *ethAPI) GetTransactionReceipt(common.Hash) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (a *ethAPI) SendRawTransaction(hexutil.Bytes) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+
+// testNode is a node serving ethAPI, which answers with status instead while status is set
+type testNode struct {
+	url      string
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func newTestNode(t *testing.T, block uint64) *testNode {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &ethAPI{block: block}))
+
+	node := testNode{}
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.requests.Add(1)
+		if status := node.status.Load(); status != 0 {
+			http.Error(w, http.StatusText(int(status)), int(status))
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	node.url = httpServer.URL + "/v2/secret-api-key"
+	return &node
+}
+
+func newTestClient(t *testing.T, nodes ...*testNode) *Client {
+	var endpoints []string
+	for _, node := range nodes {
+		endpoints = append(endpoints, node.url)
+	}
+	client, err := NewClient(endpoints, RetryConfig{MaxAttempts: 3, InitialBackoff: 50 * time.Millisecond, MaxBackoff: 200 * time.Millisecond})
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fails over from a rate limited endpoint and returns after its backoff", func(tt *testing.T) {
+		primary, secondary := newTestNode(tt, 1), newTestNode(tt, 2)
+		client := newTestClient(tt, primary, secondary)
+
+		primary.status.Store(http.StatusTooManyRequests)
+		block, err := client.BlockNumber(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(2), block)
+
+		endpoints := client.Endpoints()
+		require.Len(tt, endpoints, 2)
+		assert.False(tt, endpoints[0].Healthy)
+		assert.Contains(tt, endpoints[0].Error, rateLimited)
+		assert.True(tt, endpoints[1].Healthy)
+		assert.True(tt, client.Healthy())
+
+		// the primary is skipped while it backs off
+		requests := primary.requests.Load()
+		_, err = client.BlockNumber(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, requests, primary.requests.Load())
+
+		primary.status.Store(0)
+		assert.Eventually(tt, func() bool {
+			block, err := client.BlockNumber(ctx)
+			return err == nil && block == 1
+		}, time.Second, 10*time.Millisecond)
+		assert.True(tt, client.Endpoints()[0].Healthy)
+	})
+
+	t.Run("retries idempotent calls until an endpoint recovers", func(tt *testing.T) {
+		node := newTestNode(tt, 1)
+		client := newTestClient(tt, node)
+
+		node.status.Store(http.StatusInternalServerError)
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			node.status.Store(0)
+		}()
+		block, err := client.BlockNumber(ctx)
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(1), block)
+		assert.Equal(tt, int32(2), node.requests.Load())
+	})
+
+	t.Run("gives up after the max attempts", func(tt *testing.T) {
+		node := newTestNode(tt, 1)
+		client := newTestClient(tt, node)
+
+		node.status.Store(http.StatusBadGateway)
+		_, err := client.BlockNumber(ctx)
+		assert.ErrorAs(tt, err, &ErrEthClient{})
+		assert.Equal(tt, int32(3), node.requests.Load())
+		assert.False(tt, client.Healthy())
+	})
+
+	t.Run("sends transactions once", func(tt *testing.T) {
+		node := newTestNode(tt, 1)
+		client := newTestClient(tt, node)
+		tx := types.NewTx(&types.LegacyTx{})
+
+		require.NoError(tt, client.SendTransaction(ctx, tx))
+		node.status.Store(http.StatusInternalServerError)
+		assert.Error(tt, client.SendTransaction(ctx, tx))
+		assert.Equal(tt, int32(2), node.requests.Load())
+	})
+
+	t.Run("does not count errors of the call against the endpoint", func(tt *testing.T) {
+		node := newTestNode(tt, 1)
+		client := newTestClient(tt, node)
+
+		_, err := client.TransactionReceipt(ctx, common.Hash{})
+		assert.ErrorIs(tt, err, ethereum.NotFound)
+		_, err = client.NonceAt(ctx, common.Address{}, nil)
+		var rpcErr rpc.Error
+		assert.ErrorAs(tt, err, &rpcErr, "the node has no eth_getTransactionCount")
+		assert.Equal(tt, int32(2), node.requests.Load())
+		assert.True(tt, client.Healthy())
+	})
+
+	t.Run("leaves api keys out of the endpoint health", func(tt *testing.T) {
+		node := newTestNode(tt, 1)
+		client := newTestClient(tt, node)
+		endpoint := client.Endpoints()[0].Endpoint
+		assert.NotContains(tt, endpoint, "secret-api-key")
+		assert.Contains(tt, node.url, endpoint)
+	})
+
+	t.Run("is created without a reachable endpoint", func(tt *testing.T) {
+		gone := httptest.NewServer(http.NotFoundHandler())
+		gone.Close()
+		client, err := NewClient([]string{gone.URL}, RetryConfig{MaxAttempts: 1})
+		require.NoError(tt, err)
+		assert.True(tt, client.Healthy(), "endpoints are healthy until they fail")
+
+		s := Service{Wallet: &Wallet{ChainID: big.NewInt(1), Client: client}}
+		status := s.Status()
+		assert.Equal(tt, framework.StatusNotReady, status.Status)
+		assert.Contains(tt, status.Message, "is failing until")
+	})
+
+	t.Run("rejects no endpoints", func(tt *testing.T) {
+		_, err := NewClient(ParseEndpoints(" , "), RetryConfig{})
+		assert.Error(tt, err)
+	})
+}
+
+func TestParseEndpoints(t *testing.T) {
+	assert.Equal(t, []string{"https://a.example", "wss://b.example/key"}, ParseEndpoints(" https://a.example,,wss://b.example/key "))
+	assert.Empty(t, ParseEndpoints(""))
+}
@@ -0,0 +1,269 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/contracts"
+	"github.com/fapiper/onchain-access-control/core/internal/groth16"
+	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/testutil"
+)
+
+const proofFile = "../../internal/groth16/testdata/proof.json"
+
+// newFundedBackend returns a simulated chain on which address holds 1000 ether
+func newFundedBackend(t *testing.T, address common.Address) *SimulatedBackend {
+	return NewSimulatedBackend(testutil.NewFundedChain(t, address))
+}
+
+// newSimulatedService returns an rpc service with a funded wallet on a simulated chain
+func newSimulatedService(t *testing.T) (*Service, *SimulatedBackend) {
+	key, sim := testutil.NewFundedKey(t)
+	backend := NewSimulatedBackend(sim)
+
+	service, err := NewRPCServiceWithConfig(backend, SimulatedConfig(key), nil)
+	require.NoError(t, err)
+	return service, backend
+}
+
+// bytecodeOf returns the bytecode of a contract binding, skipping the test for bindings generated without bytecode
+func bytecodeOf(t *testing.T, name string, metadata *bind.MetaData) []byte {
+	bytecode := common.FromHex(metadata.Bin)
+	if len(bytecode) == 0 {
+		t.Skipf("the %s binding carries no bytecode, compile the contracts and run make abi", name)
+	}
+	return bytecode
+}
+
+func deploy(t *testing.T, s *Service, name string, metadata *bind.MetaData, args ...any) common.Address {
+	bytecode := bytecodeOf(t, name, metadata)
+	parsed, err := metadata.GetAbi()
+	require.NoError(t, err)
+
+	address, tx, err := s.DeployContract(context.Background(), DeployContractParams{
+		ABI:      *parsed,
+		Bytecode: bytecode,
+		Args:     args,
+	})
+	require.NoError(t, err, "deploying %s", name)
+	requireMined(t, s, tx, err)
+	return address
+}
+
+// deployContracts deploys and links the did registry, the access context handler and the session registry, with the
+// wallet as the controller of its did
+func deployContracts(t *testing.T, s *Service) {
+	didRegistry := deploy(t, s, "SimpleDIDRegistry", contracts.SimpleDIDRegistryMetaData, [][32]byte{s.Wallet.GetDIDHash()}, []common.Address{s.Wallet.Address})
+	contextHandler := deploy(t, s, "AccessContextHandler", contracts.AccessContextHandlerMetaData, didRegistry)
+	sessionRegistry := deploy(t, s, "SessionRegistry", contracts.SessionRegistryMetaData, contextHandler, didRegistry)
+
+	s.DIDRegistry = persist.Address(didRegistry.Hex())
+	s.ContextHandler = persist.Address(contextHandler.Hex())
+	s.SessionRegistry = persist.Address(sessionRegistry.Hex())
+
+	_, err := s.SetSessionRegistry(context.Background(), sessionRegistry)
+	require.NoError(t, err)
+}
+
+func requireMined(t *testing.T, s *Service, tx *types.Transaction, err error) {
+	receipt, err := s.waitMined(context.Background(), tx, err)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func TestSimulatedBackend(t *testing.T) {
+	s, backend := newSimulatedService(t)
+	recipient := common.HexToAddress("0xd231120Eea6201B142b4048cf6C86BaC2A0655D2")
+	transfer := bind.NewBoundContract(recipient, abi.ABI{}, backend, backend, backend)
+
+	start, err := s.BlockNumber(context.Background())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	txs := make(chan *types.Transaction, 5)
+	for i := 0; i < cap(txs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := s.Wallet.Transact(context.Background(), func(opts *bind.TransactOpts) (*types.Transaction, error) {
+				// plain transfers are not estimated, the binding would expect contract code at the recipient
+				opts.Value = big.NewInt(1)
+				opts.GasLimit = 21_000
+				return transfer.Transfer(opts)
+			})
+			assert.NoError(t, err)
+			txs <- tx
+		}()
+	}
+	wg.Wait()
+	close(txs)
+
+	for tx := range txs {
+		require.NotNil(t, tx)
+		assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+		receipt, err := s.WaitMined(context.Background(), tx)
+		require.NoError(t, err)
+		assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+		stored, err := s.TransactionReceipt(context.Background(), tx.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, receipt.BlockHash, stored.BlockHash)
+	}
+
+	head, err := s.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, start+5, head)
+
+	balance, err := backend.BalanceAt(context.Background(), recipient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), balance)
+}
+
+func TestAccessControlFlow(t *testing.T) {
+	s, _ := newSimulatedService(t)
+	deployContracts(t, s)
+	ctx := context.Background()
+	did := s.Wallet.GetDIDHash()
+
+	// the context is not a did:pkh, it is served by the default chain
+	contextDID := "did:example:context"
+	contextID := crypto.Keccak256Hash([]byte(contextDID))
+	roleID := crypto.Keccak256Hash([]byte("role"))
+	policyID := crypto.Keccak256Hash([]byte("policy"))
+
+	var accessContext persist.Address
+	t.Run("creates an access context", func(tt *testing.T) {
+		tx, err := s.CreateAccessContext(ctx, CreateAccessContextParams{ID: contextID, DID: did})
+		requireMined(tt, s, tx, err)
+
+		accessContext, err = s.GetAccessContextAddress(contextDID)
+		require.NoError(tt, err)
+		assert.NotEqual(tt, common.Address{}, accessContext.Address())
+	})
+
+	t.Run("registers a resource with a policy", func(tt *testing.T) {
+		verifier, tx, err := s.DeployPolicyVerifier(ctx, DeployPolicyVerifierParams{
+			Bytecode:               bytecodeOf(tt, "PolicyVerifier", contracts.PolicyVerifierMetaData),
+			PresentationDefinition: "ipfs://definition",
+			ProofProgram:           "ipfs://program",
+			ProvingKey:             "ipfs://proving.key",
+			VerificationKey:        "ipfs://verification.key",
+		})
+		requireMined(tt, s, tx, err)
+
+		uris, err := s.GetPolicyVerifierURIs(ctx, PolicyVerifierParams{Context: contextDID, Verifier: verifier})
+		require.NoError(tt, err)
+		assert.Equal(tt, "ipfs://verification.key", uris.VerificationKey)
+
+		tx, err = s.RegisterResource(ctx, RegisterResourceParams{
+			AccessContext: accessContext,
+			Role:          roleID,
+			Policy:        policyID,
+			Permission:    crypto.Keccak256Hash([]byte("permission")),
+			Resource:      crypto.Keccak256Hash([]byte("resource")),
+			Operations:    []uint8{0},
+			Verifier:      &verifier,
+			DID:           did,
+		})
+		requireMined(tt, s, tx, err)
+
+		count, err := s.GetRolePolicyCount(ctx, GetRolePolicyCountParams{Context: contextDID, Address: accessContext, RoleID: roleID})
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(1), count)
+	})
+
+	t.Run("grants a role for a valid proof", func(tt *testing.T) {
+		data, err := os.ReadFile(proofFile)
+		require.NoError(tt, err)
+		proof, inputs, err := groth16.ParseProof(data)
+		require.NoError(tt, err)
+
+		has, err := s.HasRole(ctx, HasRoleParams{Context: contextDID, Address: accessContext, RoleID: roleID, DID: did})
+		require.NoError(tt, err)
+		require.False(tt, has)
+
+		policy := GrantRolePolicy{
+			PolicyIdentifier: persist.PolicyIdentifier{ContextID: contextID, PolicyID: policyID},
+			Proof:            *proof,
+			Inputs:           inputs,
+		}
+		// a proof of a policy given twice does not stand in for the other policies of the role
+		_, err = s.GrantRole(ctx, GrantRoleParams{
+			RoleIdentifier: persist.RoleIdentifier{ContextID: contextID, RoleID: roleID},
+			DID:            did,
+			Policies:       []GrantRolePolicy{policy, policy},
+		})
+		require.Error(tt, err)
+
+		tx, err := s.GrantRole(ctx, GrantRoleParams{
+			RoleIdentifier: persist.RoleIdentifier{ContextID: contextID, RoleID: roleID},
+			DID:            did,
+			Policies:       []GrantRolePolicy{policy},
+		})
+		requireMined(tt, s, tx, err)
+
+		has, err = s.HasRole(ctx, HasRoleParams{Context: contextDID, Address: accessContext, RoleID: roleID, DID: did})
+		require.NoError(tt, err)
+		assert.True(tt, has)
+	})
+
+	t.Run("starts a session", func(tt *testing.T) {
+		tokenID := crypto.Keccak256Hash([]byte("token"))
+		params := CheckSessionParams{TokenID: tokenID, Subject: s.Wallet.GetDID()}
+
+		valid, err := s.CheckSession(ctx, params)
+		require.NoError(tt, err)
+		require.False(tt, valid)
+
+		_, err = s.StartSession(ctx, StartSessionParams{DID: did, Context: contextID, TokenID: tokenID, SessionJWE: []byte("session")})
+		require.ErrorContains(tt, err, "shorter than a second")
+
+		_, err = s.StartSession(ctx, StartSessionParams{DID: did, Context: contextID, TokenID: tokenID, SessionJWE: []byte("session"), Duration: MaxSessionDuration + time.Second})
+		require.ErrorContains(tt, err, "exceeds")
+
+		tx, err := s.StartSession(ctx, StartSessionParams{DID: did, Context: contextID, TokenID: tokenID, SessionJWE: []byte("session"), Duration: time.Hour})
+		requireMined(tt, s, tx, err)
+
+		valid, err = s.CheckSession(ctx, params)
+		require.NoError(tt, err)
+		assert.True(tt, valid)
+	})
+
+	t.Run("revokes a session only from its context", func(tt *testing.T) {
+		tokenID := crypto.Keccak256Hash([]byte("revoked token"))
+		params := CheckSessionParams{TokenID: tokenID, Subject: s.Wallet.GetDID()}
+		tx, err := s.StartSession(ctx, StartSessionParams{DID: did, Context: contextID, TokenID: tokenID, SessionJWE: []byte("session"), Duration: time.Hour})
+		requireMined(tt, s, tx, err)
+
+		// the wallet administers another context too, which must not reach the sessions of the first one
+		otherContextID := crypto.Keccak256Hash([]byte("did:example:other"))
+		tx, err = s.CreateAccessContext(ctx, CreateAccessContextParams{ID: otherContextID, DID: did})
+		requireMined(tt, s, tx, err)
+
+		tx, err = s.RevokeContextSession(ctx, RevokeContextSessionParams{TokenID: tokenID, Context: otherContextID, DID: did})
+		_, err = s.waitMined(ctx, tx, err)
+		assert.Error(tt, err)
+		valid, err := s.CheckSession(ctx, params)
+		require.NoError(tt, err)
+		require.True(tt, valid)
+
+		tx, err = s.RevokeContextSession(ctx, RevokeContextSessionParams{TokenID: tokenID, Context: contextID, DID: did})
+		requireMined(tt, s, tx, err)
+		valid, err = s.CheckSession(ctx, params)
+		require.NoError(tt, err)
+		assert.False(tt, valid)
+	})
+}
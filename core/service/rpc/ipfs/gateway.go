@@ -0,0 +1,387 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	goerrors "errors"
+	"fmt"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/fapiper/onchain-access-control/core/internal/util"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/conc/pool"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgeDelay     = 500 * time.Millisecond
+	defaultGatewayTimeout = time.Minute
+
+	// rawBlockType is the media type of a single block in the trustless gateway api
+	rawBlockType = "application/vnd.ipld.raw"
+	// maxBlockSize is the largest block a gateway may return, which is the largest block ipfs nodes exchange
+	maxBlockSize = 4 << 20
+	// maxLinkedBlocks is the most blocks of a file fetched at once
+	maxLinkedBlocks = 8
+	// healthWeight is the weight of the last fetch from a gateway in its success rate and latency
+	healthWeight = 0.2
+	// minSuccessRate keeps gateways that keep failing ranked by their latency among each other
+	minSuccessRate = 0.05
+)
+
+// ErrCIDMismatch is returned for content a gateway returned for a cid that does not hash to the cid
+var ErrCIDMismatch = errors.New("content does not match cid")
+
+// GatewayConfig is the gateways content is fetched from and how they are raced
+type GatewayConfig struct {
+	// Gateways are the hosts of http gateways, e.g. https://ipfs.io
+	Gateways []string
+	// HedgeDelay after which the next gateway is asked for a block the gateways asked so far did not return yet
+	HedgeDelay time.Duration
+	// Timeout of a request to a gateway
+	Timeout time.Duration
+}
+
+// gatewayConfigFromEnv returns the gateways configured by the environment, which are the gateway at IPFS_URL, those
+// listed in IPFS_GATEWAYS and the one at FALLBACK_IPFS_URL
+func gatewayConfigFromEnv() GatewayConfig {
+	gateways := strings.Join([]string{
+		env.GetString("IPFS_URL"),
+		env.GetString("IPFS_GATEWAYS"),
+		env.GetString("FALLBACK_IPFS_URL"),
+	}, ",")
+	return GatewayConfig{
+		Gateways:   ParseGateways(gateways),
+		HedgeDelay: env.GetDuration("IPFS_GATEWAY_HEDGE_DELAY"),
+		Timeout:    env.GetDuration("IPFS_GATEWAY_TIMEOUT"),
+	}
+}
+
+// ParseGateways parses a comma separated list of gateway hosts. The path prefix of gateway urls is dropped, so that
+// both https://ipfs.io and https://ipfs.io/ipfs refer to the same gateway, which is listed once.
+func ParseGateways(gateways string) []string {
+	var parsed []string
+	seen := make(map[string]bool)
+	for _, gateway := range strings.Split(gateways, ",") {
+		gateway = strings.TrimSuffix(strings.TrimSpace(gateway), "/")
+		if gateway = strings.TrimSuffix(gateway, "/ipfs"); gateway != "" && !seen[gateway] {
+			seen[gateway] = true
+			parsed = append(parsed, gateway)
+		}
+	}
+	return parsed
+}
+
+// GatewayHealth is how reliably and fast a gateway returned content
+type GatewayHealth struct {
+	Gateway string
+	// SuccessRate is the moving average of fetches returning content matching its cid
+	SuccessRate float64
+	// Latency is the moving average of the time to fetch a block
+	Latency time.Duration
+	Fetches uint64
+	Error   string
+}
+
+// Gateway fetches content from http gateways and verifies it against its cid, so that no gateway has to be trusted.
+// Blocks are fetched from the gateways ranked by their health. The best gateway is asked first, and the next one is
+// asked in addition whenever the hedge delay passes or a gateway fails, until one of them returns the block.
+type Gateway struct {
+	config GatewayConfig
+	client *http.Client
+
+	mu     sync.Mutex
+	health []*gatewayHealth
+}
+
+type gatewayHealth struct {
+	host        string
+	successRate float64
+	latency     time.Duration
+	fetches     uint64
+	err         error
+}
+
+// rank is the expected time to fetch a block from the gateway. Gateways not asked yet rank first to learn their health.
+func (h *gatewayHealth) rank() float64 {
+	return float64(h.latency) / math.Max(h.successRate, minSuccessRate)
+}
+
+// NewGateway returns a gateway fetching from the configured gateways, which are ranked in their order until their
+// health is known
+func NewGateway(config GatewayConfig, client *http.Client) (*Gateway, error) {
+	if len(config.Gateways) == 0 {
+		return nil, errors.New("no ipfs gateway configured")
+	}
+	if config.HedgeDelay <= 0 {
+		config.HedgeDelay = defaultHedgeDelay
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultGatewayTimeout
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	g := Gateway{config: config, client: client}
+	for _, host := range config.Gateways {
+		g.health = append(g.health, &gatewayHealth{host: host, successRate: 1})
+	}
+	return &g, nil
+}
+
+// GatewayStore is a store that reads artifacts it does not hold itself from http gateways. Artifacts are still added
+// to and pinned in the underlying store only.
+type GatewayStore struct {
+	Store
+	gateway *Gateway
+}
+
+var _ Store = (*GatewayStore)(nil)
+
+// NewGatewayStore returns a store reading from gateway whatever store fails to return
+func NewGatewayStore(store Store, gateway *Gateway) *GatewayStore {
+	return &GatewayStore{Store: store, gateway: gateway}
+}
+
+// Get returns the content of an artifact from the underlying store, or from the gateways if the store fails to return
+// it. Content from the gateways is verified against its cid.
+func (s GatewayStore) Get(ctx context.Context, c string) (io.ReadCloser, error) {
+	reader, err := s.Store.Get(ctx, c)
+	if err == nil {
+		return reader, nil
+	}
+	data, gatewayErr := s.gateway.Get(ctx, c)
+	if gatewayErr != nil {
+		return nil, goerrors.Join(err, gatewayErr)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Get returns the content of a cid. Content added as a unixfs file, as ipfs nodes add it, is assembled from its
+// blocks, each of which is verified against its cid.
+func (g *Gateway) Get(ctx context.Context, c string) ([]byte, error) {
+	decoded, err := cid.Decode(strings.TrimPrefix(c, "ipfs://"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid cid: %s", c)
+	}
+	return g.getFile(ctx, decoded)
+}
+
+func (g *Gateway) getFile(ctx context.Context, c cid.Cid) ([]byte, error) {
+	block, err := g.getBlock(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	switch c.Type() {
+	case cid.Raw:
+		return block, nil
+	case cid.DagProtobuf:
+	default:
+		return nil, errors.Errorf("cid<%s> of codec %d is not a file", c, c.Type())
+	}
+
+	node, err := decodeFileNode(block)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cid<%s>", c)
+	}
+	if len(node.links) == 0 {
+		return node.data, nil
+	}
+
+	parts := make([][]byte, len(node.links))
+	p := pool.New().WithContext(ctx).WithCancelOnError().WithMaxGoroutines(maxLinkedBlocks)
+	for i, link := range node.links {
+		i, link := i, link
+		p.Go(func(ctx context.Context) error {
+			part, err := g.getFile(ctx, link)
+			parts[i] = part
+			return err
+		})
+	}
+	if err = p.Wait(); err != nil {
+		return nil, err
+	}
+
+	data := append([]byte(nil), node.data...)
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+	return data, nil
+}
+
+type blockAttempt struct {
+	data []byte
+	err  error
+}
+
+// getBlock races the gateways for a block, asking the next one after the hedge delay or a failure
+func (g *Gateway) getBlock(ctx context.Context, c cid.Cid) ([]byte, error) {
+	race, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	gateways := g.ranked()
+	attempts := make(chan blockAttempt, len(gateways))
+	next, running := 0, 0
+	ask := func() {
+		gateway := gateways[next]
+		next++
+		running++
+		go func() {
+			start := time.Now()
+			data, err := g.fetchBlock(race, gateway.host, c)
+			switch {
+			case race.Err() == nil:
+				g.record(gateway, time.Since(start), err)
+			case ctx.Err() == nil:
+				// a gateway losing the race is not to blame, but took longer than the winner
+				g.recordLost(gateway, time.Since(start))
+			}
+			attempts <- blockAttempt{data: data, err: err}
+		}()
+	}
+
+	ask()
+	hedge := time.NewTimer(g.config.HedgeDelay)
+	defer hedge.Stop()
+
+	var errs []error
+	for running > 0 {
+		select {
+		case <-hedge.C:
+			if next < len(gateways) {
+				ask()
+				hedge.Reset(g.config.HedgeDelay)
+			}
+		case attempt := <-attempts:
+			running--
+			if attempt.err == nil {
+				return attempt.data, nil
+			}
+			errs = append(errs, attempt.err)
+			if next < len(gateways) {
+				ask()
+			}
+		}
+	}
+	return nil, errors.Wrapf(goerrors.Join(errs...), "fetching cid<%s> from %d gateways", c, len(gateways))
+}
+
+// fetchBlock fetches a block from a gateway and verifies it against its cid
+func (g *Gateway) fetchBlock(ctx context.Context, host string, c cid.Cid) ([]byte, error) {
+	data, err := g.requestBlock(ctx, host, c)
+	if err == nil {
+		var sum cid.Cid
+		if sum, err = c.Prefix().Sum(data); err == nil && !sum.Equals(c) {
+			err = ErrCIDMismatch
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "gateway<%s>", host)
+	}
+	return data, nil
+}
+
+func (g *Gateway) requestBlock(ctx context.Context, host string, c cid.Cid) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.config.Timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/ipfs/%s?format=raw", host, c)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", rawBlockType)
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &util.ErrHTTP{StatusCode: resp.StatusCode, URL: url}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBlockSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBlockSize {
+		return nil, errors.Errorf("block exceeds %d bytes", maxBlockSize)
+	}
+	return data, nil
+}
+
+// record updates the health of a gateway after a fetch
+func (g *Gateway) record(gateway *gatewayHealth, latency time.Duration, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gateway.fetches++
+	gateway.err = err
+	success := 0.0
+	switch {
+	case err == nil:
+		success = 1
+		gateway.latency = average(gateway.latency, latency)
+	case gateway.latency == 0:
+		// a gateway failing before it ever returned a block ranks as if it timed out
+		gateway.latency = g.config.Timeout
+	}
+	gateway.successRate = (1-healthWeight)*gateway.successRate + healthWeight*success
+}
+
+// recordLost updates the latency of a gateway that did not return a block before another gateway did
+func (g *Gateway) recordLost(gateway *gatewayHealth, elapsed time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if elapsed > gateway.latency {
+		gateway.latency = average(gateway.latency, elapsed)
+	}
+}
+
+// average returns the moving average of a latency, which is the latency itself for the first one
+func average(average, latency time.Duration) time.Duration {
+	if average == 0 {
+		return latency
+	}
+	return time.Duration((1-healthWeight)*float64(average) + healthWeight*float64(latency))
+}
+
+// ranked returns the gateways, best first
+func (g *Gateway) ranked() []*gatewayHealth {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ranked := append([]*gatewayHealth(nil), g.health...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].rank() < ranked[j].rank() })
+	return ranked
+}
+
+// Health returns the health of the gateways, best first
+func (g *Gateway) Health() []GatewayHealth {
+	ranked := g.ranked()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	health := make([]GatewayHealth, 0, len(ranked))
+	for _, gateway := range ranked {
+		h := GatewayHealth{
+			Gateway:     gateway.host,
+			SuccessRate: gateway.successRate,
+			Latency:     gateway.latency,
+			Fetches:     gateway.fetches,
+		}
+		if gateway.err != nil {
+			h.Error = gateway.err.Error()
+		}
+		health = append(health, h)
+	}
+	return health
+}
@@ -0,0 +1,216 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// testGateway is a stand-in for an http gateway serving blocks, which answers after delay and corrupts blocks if set
+type testGateway struct {
+	url      string
+	delay    time.Duration
+	corrupt  bool
+	requests atomic.Int32
+}
+
+func newTestGateway(t *testing.T, blocks map[cid.Cid][]byte) *testGateway {
+	gateway := testGateway{}
+	byPath := make(map[string][]byte)
+	for c, block := range blocks {
+		byPath["/ipfs/"+c.String()] = block
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gateway.requests.Add(1)
+		select {
+		case <-time.After(gateway.delay):
+		case <-r.Context().Done():
+			return
+		}
+		block, ok := byPath[r.URL.Path]
+		if !ok || r.Header.Get("Accept") != rawBlockType {
+			http.NotFound(w, r)
+			return
+		}
+		if gateway.corrupt {
+			block = append([]byte("corrupt"), block...)
+		}
+		_, _ = w.Write(block)
+	}))
+	t.Cleanup(server.Close)
+	gateway.url = server.URL
+	return &gateway
+}
+
+func newTestGatewayClient(t *testing.T, hedgeDelay time.Duration, gateways ...*testGateway) *Gateway {
+	var hosts []string
+	for _, gateway := range gateways {
+		hosts = append(hosts, gateway.url)
+	}
+	g, err := NewGateway(GatewayConfig{Gateways: hosts, HedgeDelay: hedgeDelay, Timeout: 5 * time.Second}, nil)
+	require.NoError(t, err)
+	return g
+}
+
+// chunkedFile returns the blocks of content added as a unixfs file split into raw leaves of chunkSize, and its cid
+func chunkedFile(t *testing.T, content []byte, chunkSize int) (cid.Cid, map[cid.Cid][]byte) {
+	blocks := make(map[cid.Cid][]byte)
+	var node []byte
+	for len(content) > 0 {
+		chunk := content
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		content = content[len(chunk):]
+		leaf := cid.NewCidV1(cid.Raw, mustSum(t, chunk))
+		blocks[leaf] = chunk
+
+		var link []byte
+		link = protowire.AppendTag(link, 1, protowire.BytesType)
+		link = protowire.AppendBytes(link, leaf.Bytes())
+		link = protowire.AppendTag(link, 3, protowire.VarintType)
+		link = protowire.AppendVarint(link, uint64(len(chunk)))
+		node = protowire.AppendTag(node, 2, protowire.BytesType)
+		node = protowire.AppendBytes(node, link)
+	}
+
+	var unixFS []byte
+	unixFS = protowire.AppendTag(unixFS, 1, protowire.VarintType)
+	unixFS = protowire.AppendVarint(unixFS, unixFSFile)
+	node = protowire.AppendTag(node, 1, protowire.BytesType)
+	node = protowire.AppendBytes(node, unixFS)
+
+	root := cid.NewCidV1(cid.DagProtobuf, mustSum(t, node))
+	blocks[root] = node
+	return root, blocks
+}
+
+func TestGateway(t *testing.T) {
+	ctx := context.Background()
+	content := []byte(strings.Repeat("proving key ", 100))
+	raw := cid.NewCidV1(cid.Raw, mustSum(t, content))
+	root, blocks := chunkedFile(t, content, 256)
+	blocks[raw] = content
+
+	t.Run("fetches raw content and chunked files", func(tt *testing.T) {
+		g := newTestGatewayClient(tt, time.Second, newTestGateway(tt, blocks))
+
+		data, err := g.Get(ctx, raw.String())
+		require.NoError(tt, err)
+		assert.Equal(tt, content, data)
+
+		data, err = g.Get(ctx, URI(root.String()))
+		require.NoError(tt, err)
+		assert.Equal(tt, content, data)
+	})
+
+	t.Run("rejects content not matching the cid", func(tt *testing.T) {
+		corrupt := newTestGateway(tt, blocks)
+		corrupt.corrupt = true
+		g := newTestGatewayClient(tt, time.Second, corrupt)
+
+		_, err := g.Get(ctx, raw.String())
+		assert.ErrorIs(tt, err, ErrCIDMismatch)
+		health := g.Health()
+		assert.Less(tt, health[0].SuccessRate, 1.0)
+		assert.Contains(tt, health[0].Error, ErrCIDMismatch.Error())
+	})
+
+	t.Run("asks the next gateway when one fails", func(tt *testing.T) {
+		corrupt, good := newTestGateway(tt, blocks), newTestGateway(tt, blocks)
+		corrupt.corrupt = true
+		g := newTestGatewayClient(tt, time.Minute, corrupt, good)
+
+		data, err := g.Get(ctx, raw.String())
+		require.NoError(tt, err)
+		assert.Equal(tt, content, data)
+
+		// the failing gateway ranks last from then on
+		health := g.Health()
+		assert.Equal(tt, good.url, health[0].Gateway)
+		assert.Equal(tt, corrupt.url, health[1].Gateway)
+		requests := corrupt.requests.Load()
+		_, err = g.Get(ctx, raw.String())
+		require.NoError(tt, err)
+		assert.Equal(tt, requests, corrupt.requests.Load())
+	})
+
+	t.Run("hedges a slow gateway", func(tt *testing.T) {
+		slow, fast := newTestGateway(tt, blocks), newTestGateway(tt, blocks)
+		slow.delay = time.Minute
+		g := newTestGatewayClient(tt, 20*time.Millisecond, slow, fast)
+
+		start := time.Now()
+		data, err := g.Get(ctx, raw.String())
+		require.NoError(tt, err)
+		assert.Equal(tt, content, data)
+		assert.Less(tt, time.Since(start), time.Second)
+
+		// the slow gateway is not charged a failure for losing the race, but ranks after the fast one
+		assert.Eventually(tt, func() bool { return g.Health()[0].Gateway == fast.url }, time.Second, 10*time.Millisecond)
+		health := g.Health()
+		require.Len(tt, health, 2)
+		assert.Equal(tt, slow.url, health[1].Gateway)
+		assert.Equal(tt, 1.0, health[1].SuccessRate)
+		assert.Zero(tt, health[1].Fetches)
+	})
+
+	t.Run("fails when no gateway has the content", func(tt *testing.T) {
+		g := newTestGatewayClient(tt, time.Second, newTestGateway(tt, nil), newTestGateway(tt, nil))
+		_, err := g.Get(ctx, raw.String())
+		assert.ErrorContains(tt, err, "from 2 gateways")
+		_, err = g.Get(ctx, "not-a-cid")
+		assert.ErrorContains(tt, err, "invalid cid")
+	})
+
+	t.Run("reads what the store does not hold from the gateways", func(tt *testing.T) {
+		local, err := NewLocalStore(tt.TempDir())
+		require.NoError(tt, err)
+		held, err := local.Put(ctx, bytes.NewReader([]byte("held")))
+		require.NoError(tt, err)
+
+		gateway := newTestGateway(tt, blocks)
+		store := NewGatewayStore(local, newTestGatewayClient(tt, time.Second, gateway))
+
+		data, err := ReadURI(ctx, store, URI(held))
+		require.NoError(tt, err)
+		assert.Equal(tt, []byte("held"), data)
+		assert.Zero(tt, gateway.requests.Load())
+
+		data, err = ReadURI(ctx, store, URI(root.String()))
+		require.NoError(tt, err)
+		assert.Equal(tt, content, data)
+	})
+
+	t.Run("rejects gateway content not matching the cid", func(tt *testing.T) {
+		local, err := NewLocalStore(tt.TempDir())
+		require.NoError(tt, err)
+		corrupt := newTestGateway(tt, blocks)
+		corrupt.corrupt = true
+		store := NewGatewayStore(local, newTestGatewayClient(tt, time.Second, corrupt))
+
+		_, err = ReadURI(ctx, store, URI(raw.String()))
+		assert.ErrorIs(tt, err, ErrNotFound)
+		assert.ErrorIs(tt, err, ErrCIDMismatch)
+	})
+
+	t.Run("requires a gateway", func(tt *testing.T) {
+		_, err := NewGateway(GatewayConfig{Gateways: ParseGateways(" , ")}, nil)
+		assert.Error(tt, err)
+	})
+}
+
+func TestParseGateways(t *testing.T) {
+	assert.Equal(t, []string{"https://ipfs.io", "https://cloudflare-ipfs.com"}, ParseGateways(" https://ipfs.io/ipfs/,,https://cloudflare-ipfs.com ,https://ipfs.io"))
+	assert.Empty(t, ParseGateways(""))
+}
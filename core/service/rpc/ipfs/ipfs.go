@@ -1,12 +1,9 @@
 package ipfs
 
 import (
-	"context"
 	"fmt"
 	"github.com/fapiper/onchain-access-control/core/env"
-	"github.com/fapiper/onchain-access-control/core/internal/util"
 	shell "github.com/ipfs/go-ipfs-api"
-	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -15,9 +12,13 @@ import (
 
 func init() {
 	env.RegisterValidation("IPFS_URL", "required")
-	env.RegisterValidation("FALLBACK_IPFS_URL", "required")
 }
 
+const (
+	defaultGatewayHost = "https://ipfs.io"
+	fxHashGatewayHost  = "https://gateway.fxhash.xyz"
+)
+
 type ErrInfuraQuotaExceeded struct {
 	Err error
 }
@@ -27,61 +28,6 @@ func (r ErrInfuraQuotaExceeded) Error() string {
 	return fmt.Sprintf("quota exceeded: %s", r.Err.Error())
 }
 
-// HTTPReader is a reader that uses a HTTP gateway to read from
-type HTTPReader struct {
-	Host   string
-	Client *http.Client
-}
-
-func (r HTTPReader) Do(ctx context.Context, path string) (io.ReadCloser, error) {
-	path = pathURL(r.Host, path)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := r.Client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	if isInfura(path) && resp.StatusCode == http.StatusTooManyRequests {
-		return nil, ErrInfuraQuotaExceeded{Err: err}
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &util.ErrHTTP{StatusCode: resp.StatusCode, URL: path}
-	}
-	return resp.Body, nil
-}
-
-func (r HTTPReader) Head(ctx context.Context, path string) (http.Header, error) {
-	path = pathURL(r.Host, path)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := r.Client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, &util.ErrHTTP{StatusCode: resp.StatusCode, URL: path}
-	}
-	return resp.Header, nil
-}
-
-// IPFSReader is a reader that uses an IPFS shell to read from IPFS
-type IPFSReader struct {
-	Client *shell.Shell
-}
-
-func (r IPFSReader) Do(ctx context.Context, path string) (io.ReadCloser, error) {
-	reader, err := r.Client.Cat(path)
-	if err != nil && isInfura(path) && strings.Contains(err.Error(), "transfer quota reached") {
-		return nil, ErrInfuraQuotaExceeded{Err: err}
-	}
-	return reader, err
-}
-
 // NewShell returns an IPFS shell with default configuration
 func NewShell() *shell.Shell {
 	sh := shell.NewShellWithClient(env.GetString("IPFS_API_URL"), defaultHTTPClient())
@@ -89,77 +35,6 @@ func NewShell() *shell.Shell {
 	return sh
 }
 
-var (
-	nodeCustom = func(h *http.Client, s *shell.Shell) HTTPReader {
-		return HTTPReader{Host: env.GetString("FALLBACK_IPFS_URL"), Client: h}
-	}
-	nodeIPFS = func(h *http.Client, s *shell.Shell) IPFSReader {
-		return IPFSReader{Client: s}
-	}
-	nodeIpfsIO = func(h *http.Client, s *shell.Shell) HTTPReader {
-		return HTTPReader{Host: "https://ipfs.io", Client: h}
-	}
-	nodePinata = func(h *http.Client, s *shell.Shell) HTTPReader {
-		return HTTPReader{Host: "https://gateway.pinata.cloud", Client: h}
-	}
-	nodeNftStorage = func(h *http.Client, s *shell.Shell) HTTPReader {
-		return HTTPReader{Host: "https://nftstorage.link", Client: h}
-	}
-	nodeCloudFlare = func(h *http.Client, s *shell.Shell) HTTPReader {
-		return HTTPReader{Host: "https://cloudflare-ipfs.com", Client: h}
-	}
-	nodeFxHash = func(h *http.Client, s *shell.Shell) HTTPReader {
-		return HTTPReader{Host: "https://gateway.fxhash.xyz", Client: h}
-	}
-)
-
-func GetResponse(ctx context.Context, path string) (io.ReadCloser, error) {
-	httpClient := defaultHTTPClient()
-	ipfsClient := NewShell()
-	return util.FirstNonErrorWithValue(ctx, false, nil,
-		func(ctx context.Context) (io.ReadCloser, error) {
-			return nodeCustom(httpClient, ipfsClient).Do(ctx, path)
-		},
-		func(ctx context.Context) (io.ReadCloser, error) {
-			return nodeIPFS(httpClient, ipfsClient).Do(ctx, path)
-		},
-		func(ctx context.Context) (io.ReadCloser, error) {
-			return nodeIpfsIO(httpClient, ipfsClient).Do(ctx, path)
-		},
-		func(ctx context.Context) (io.ReadCloser, error) {
-			return nodePinata(httpClient, ipfsClient).Do(ctx, path)
-		},
-		func(ctx context.Context) (io.ReadCloser, error) {
-			return nodeNftStorage(httpClient, ipfsClient).Do(ctx, path)
-		},
-		func(ctx context.Context) (io.ReadCloser, error) {
-			return nodeCloudFlare(httpClient, ipfsClient).Do(ctx, path)
-		},
-	)
-}
-
-func GetHeader(ctx context.Context, path string) (http.Header, error) {
-	httpClient := defaultHTTPClient()
-	ipfsClient := NewShell()
-	return util.FirstNonErrorWithValue(ctx, true, nil,
-		func(ctx context.Context) (http.Header, error) {
-			return nodeCustom(httpClient, ipfsClient).Head(ctx, path)
-		},
-		func(ctx context.Context) (http.Header, error) {
-			return nodeIpfsIO(httpClient, ipfsClient).Head(ctx, path)
-		},
-		func(ctx context.Context) (http.Header, error) {
-			return nodePinata(httpClient, ipfsClient).Head(ctx, path)
-		},
-		func(ctx context.Context) (http.Header, error) {
-			return nodeNftStorage(httpClient, ipfsClient).Head(ctx, path)
-		},
-		func(ctx context.Context) (http.Header, error) {
-			return nodeCloudFlare(httpClient, ipfsClient).Head(ctx, path)
-		},
-	)
-}
-
 // defaultHTTPClient returns an http.Client configured with default settings intended for IPFS calls.
 func defaultHTTPClient() *http.Client {
 	return &http.Client{
@@ -178,7 +53,7 @@ func BestGatewayNodeFrom(ipfsURL string, isFxHash bool) string {
 	}
 	// Use fxhash's specific node
 	if isFxHash {
-		return PathGatewayFrom(nodeFxHash(nil, nil).Host, ipfsURL)
+		return PathGatewayFrom(fxHashGatewayHost, ipfsURL)
 	}
 	// Re-write to a more reliable one while Infura node is down
 	if strings.HasPrefix(ipfsURL, "https://gallery.infura-ipfs.io") {
@@ -194,7 +69,7 @@ func BestGatewayNodeFrom(ipfsURL string, isFxHash bool) string {
 
 // DefaultGatewayFrom rewrites an IPFS URL to a gateway URL using the default gateway
 func DefaultGatewayFrom(ipfsURL string) string {
-	return PathGatewayFrom(nodeIpfsIO(nil, nil).Host, ipfsURL)
+	return PathGatewayFrom(defaultGatewayHost, ipfsURL)
 }
 
 // PathGatewayFrom is a helper function that rewrites an IPFS URI to an IPFS gateway URL
@@ -230,6 +105,9 @@ type authTransport struct {
 
 func (t authTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	r.SetBasicAuth(t.ProjectID, t.ProjectSecret)
+	if t.RoundTripper == nil {
+		return http.DefaultTransport.RoundTrip(r)
+	}
 	return t.RoundTripper.RoundTrip(r)
 }
 
@@ -260,7 +138,3 @@ func standardizeQueryParams(uri string) string {
 	}
 	return uri
 }
-
-func isInfura(gateway string) bool {
-	return strings.Contains(gateway, "infura")
-}
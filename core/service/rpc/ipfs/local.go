@@ -0,0 +1,138 @@
+package ipfs
+
+import (
+	"context"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	localBlocksDir = "blocks"
+	localPinsDir   = "pins"
+)
+
+// LocalStore is a store keeping artifacts in a local directory, for tests and demos without an ipfs node. Artifacts
+// are addressed by the cid an ipfs node adds them with, so that they resolve on ipfs once added there, but are kept
+// whole rather than as blocks. Unpinned artifacts are kept, as there is no garbage collection.
+type LocalStore struct {
+	dir string
+}
+
+var _ Store = (*LocalStore)(nil)
+
+// NewLocalStore returns a store keeping artifacts in dir, which is created if it does not exist
+func NewLocalStore(dir string) (*LocalStore, error) {
+	for _, sub := range []string{localBlocksDir, localPinsDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, errors.Wrapf(err, "creating local store directory<%s>", dir)
+		}
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put stores and pins an artifact under the CIDv1 an ipfs node adds it with
+func (s LocalStore) Put(_ context.Context, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "reading artifact")
+	}
+	root, err := fileCID(data)
+	if err != nil {
+		return "", errors.Wrap(err, "hashing artifact")
+	}
+	c := root.String()
+
+	if err = s.write(filepath.Join(s.dir, localBlocksDir, c), data); err != nil {
+		return "", errors.Wrapf(err, "storing cid<%s>", c)
+	}
+	if err = s.write(filepath.Join(s.dir, localPinsDir, c), nil); err != nil {
+		return "", errors.Wrapf(err, "pinning cid<%s>", c)
+	}
+	return c, nil
+}
+
+func (s LocalStore) Get(_ context.Context, c string) (io.ReadCloser, error) {
+	path, err := s.path(localBlocksDir, c)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Wrapf(ErrNotFound, "cid<%s>", c)
+	}
+	return file, err
+}
+
+func (s LocalStore) Pin(_ context.Context, c string) error {
+	if _, err := s.stat(c); err != nil {
+		return err
+	}
+	path, _ := s.path(localPinsDir, c)
+	return s.write(path, nil)
+}
+
+func (s LocalStore) Unpin(_ context.Context, c string) error {
+	path, err := s.path(localPinsDir, c)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		return errors.Errorf("cid<%s> is not pinned", c)
+	}
+	return err
+}
+
+func (s LocalStore) Stat(_ context.Context, c string) (*Stat, error) {
+	return s.stat(c)
+}
+
+func (s LocalStore) stat(c string) (*Stat, error) {
+	path, err := s.path(localBlocksDir, c)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Wrapf(ErrNotFound, "cid<%s>", c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pin, _ := s.path(localPinsDir, c)
+	_, err = os.Stat(pin)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return &Stat{CID: filepath.Base(path), Size: uint64(info.Size()), Pinned: err == nil}, nil
+}
+
+// path returns the path of a cid in a sub directory of the store, named after the canonical form of the cid so that
+// different encodings of it refer to the same file
+func (s LocalStore) path(sub, c string) (string, error) {
+	decoded, err := cid.Decode(c)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid cid: %s", c)
+	}
+	return filepath.Join(s.dir, sub, decoded.String()), nil
+}
+
+// write replaces the file at path atomically
+func (s LocalStore) write(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,67 @@
+package ipfs
+
+import (
+	"context"
+	"github.com/ipfs/go-cid"
+	shell "github.com/ipfs/go-ipfs-api"
+	"github.com/pkg/errors"
+	"io"
+	"strings"
+)
+
+// NodeStore is a store backed by the http api of an ipfs node
+type NodeStore struct {
+	shell *shell.Shell
+}
+
+var _ Store = (*NodeStore)(nil)
+
+// NewNodeStore returns a store adding artifacts to the ipfs node of a shell
+func NewNodeStore(sh *shell.Shell) *NodeStore {
+	return &NodeStore{shell: sh}
+}
+
+// Put adds and pins an artifact as CIDv1
+func (s NodeStore) Put(_ context.Context, r io.Reader) (string, error) {
+	return s.shell.Add(r, shell.Pin(true), shell.CidVersion(1))
+}
+
+func (s NodeStore) Get(ctx context.Context, c string) (io.ReadCloser, error) {
+	if _, err := cid.Decode(c); err != nil {
+		return nil, errors.Wrapf(err, "invalid cid: %s", c)
+	}
+	resp, err := s.shell.Request("cat", c).Send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		resp.Close()
+		if strings.Contains(resp.Error.Message, "transfer quota reached") {
+			return nil, ErrInfuraQuotaExceeded{Err: resp.Error}
+		}
+		return nil, resp.Error
+	}
+	return resp.Output, nil
+}
+
+func (s NodeStore) Pin(ctx context.Context, c string) error {
+	return s.shell.Request("pin/add", c).Option("recursive", true).Exec(ctx, nil)
+}
+
+func (s NodeStore) Unpin(ctx context.Context, c string) error {
+	return s.shell.Request("pin/rm", c).Option("recursive", true).Exec(ctx, nil)
+}
+
+func (s NodeStore) Stat(ctx context.Context, c string) (*Stat, error) {
+	stat, err := s.shell.FilesStat(ctx, "/ipfs/"+c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting stat of cid<%s>", c)
+	}
+
+	var pins struct{ Keys map[string]shell.PinInfo }
+	err = s.shell.Request("pin/ls", c).Exec(ctx, &pins)
+	if err != nil && !strings.Contains(err.Error(), "is not pinned") {
+		return nil, errors.Wrapf(err, "getting pins of cid<%s>", c)
+	}
+	return &Stat{CID: stat.Hash, Size: stat.Size, Pinned: err == nil && len(pins.Keys) > 0}, nil
+}
@@ -0,0 +1,92 @@
+package ipfs
+
+import (
+	"context"
+	"fmt"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned by a store for a cid it does not hold
+var ErrNotFound = errors.New("artifact not found")
+
+// Store keeps the artifacts of policies, e.g. presentation definitions, proof programs and keys, addressed by their cid
+type Store interface {
+	// Put adds and pins an artifact, returning its cid
+	Put(ctx context.Context, r io.Reader) (string, error)
+	// Get returns the content of an artifact. Callers need to close the returned reader.
+	Get(ctx context.Context, cid string) (io.ReadCloser, error)
+	// Pin keeps an artifact held by the store
+	Pin(ctx context.Context, cid string) error
+	// Unpin releases an artifact, which the store may drop afterwards
+	Unpin(ctx context.Context, cid string) error
+	// Stat returns the size and pin state of an artifact
+	Stat(ctx context.Context, cid string) (*Stat, error)
+}
+
+// Stat describes an artifact held by a store
+type Stat struct {
+	CID    string
+	Size   uint64
+	Pinned bool
+}
+
+// NewStore returns the store configured by the environment, a local directory if IPFS_STORE_DIR is set, and the ipfs
+// node at IPFS_API_URL otherwise. Artifacts the store fails to return are read from the configured gateways.
+func NewStore() (Store, error) {
+	var store Store = NewNodeStore(NewShell())
+	if dir := env.GetString("IPFS_STORE_DIR"); dir != "" {
+		local, err := NewLocalStore(dir)
+		if err != nil {
+			return nil, err
+		}
+		store = local
+	}
+
+	gateway, err := NewGateway(gatewayConfigFromEnv(), nil)
+	if err != nil {
+		logrus.WithError(err).Warn("artifacts are read from the artifact store only")
+		return store, nil
+	}
+	return NewGatewayStore(store, gateway), nil
+}
+
+// URI returns the ipfs uri of a cid
+func URI(cid string) string {
+	return fmt.Sprintf("ipfs://%s", cid)
+}
+
+// CIDFromURI returns the cid of an ipfs uri
+func CIDFromURI(uri string) (string, error) {
+	if !IsIpfsProtoURL(uri) {
+		return "", errors.Errorf("not an ipfs uri: %q", uri)
+	}
+	c, err := cid.Decode(strings.TrimPrefix(uri, "ipfs://"))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid cid of ipfs uri: %q", uri)
+	}
+	return c.String(), nil
+}
+
+// ReadURI returns the content of the artifact an ipfs uri points to
+func ReadURI(ctx context.Context, store Store, uri string) ([]byte, error) {
+	c, err := CIDFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := store.Get(ctx, c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching<%s>", uri)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading<%s>", uri)
+	}
+	return data, nil
+}
@@ -0,0 +1,223 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	shell "github.com/ipfs/go-ipfs-api"
+	"github.com/multiformats/go-multihash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestNode returns a shell of a stand-in for the http api of an ipfs node, which keeps artifacts in a local store
+func newTestNode(t *testing.T) *shell.Shell {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	respond := func(w http.ResponseWriter, v any, err error) {
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			v = shell.Error{Message: err.Error()}
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, arg := r.Context(), r.URL.Query().Get("arg")
+		switch strings.TrimPrefix(r.URL.Path, "/api/v0/") {
+		case "version":
+			respond(w, map[string]string{"Version": "0.20.0"}, nil)
+		case "add":
+			parts, err := r.MultipartReader()
+			if err != nil {
+				respond(w, nil, err)
+				return
+			}
+			file, err := parts.NextPart()
+			if err != nil {
+				respond(w, nil, err)
+				return
+			}
+			c, err := store.Put(ctx, file)
+			respond(w, map[string]string{"Hash": c}, err)
+		case "cat":
+			reader, err := store.Get(ctx, arg)
+			if err != nil {
+				respond(w, nil, err)
+				return
+			}
+			defer reader.Close()
+			_, _ = io.Copy(w, reader)
+		case "pin/add":
+			respond(w, struct{}{}, store.Pin(ctx, arg))
+		case "pin/rm":
+			respond(w, struct{}{}, store.Unpin(ctx, arg))
+		case "pin/ls":
+			stat, err := store.Stat(ctx, arg)
+			if err == nil && !stat.Pinned {
+				err = errors.Errorf("path '%s' is not pinned", arg)
+			}
+			respond(w, map[string]any{"Keys": map[string]shell.PinInfo{arg: {Type: "recursive"}}}, err)
+		case "files/stat":
+			stat, err := store.Stat(ctx, strings.TrimPrefix(arg, "/ipfs/"))
+			if err != nil {
+				respond(w, nil, err)
+				return
+			}
+			respond(w, shell.FilesStatObject{Hash: stat.CID, Size: stat.Size}, nil)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return shell.NewShell(server.URL)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	artifact := []byte(`{"id":"presentation-definition"}`)
+
+	stores := map[string]func(*testing.T) Store{
+		"local": func(tt *testing.T) Store {
+			store, err := NewLocalStore(tt.TempDir())
+			require.NoError(tt, err)
+			return store
+		},
+		"node": func(tt *testing.T) Store {
+			return NewNodeStore(newTestNode(tt))
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Run("puts and gets an artifact by its cid", func(tt *testing.T) {
+				store := newStore(tt)
+				c, err := store.Put(ctx, bytes.NewReader(artifact))
+				require.NoError(tt, err)
+
+				data, err := ReadURI(ctx, store, URI(c))
+				require.NoError(tt, err)
+				assert.Equal(tt, artifact, data)
+
+				stat, err := store.Stat(ctx, c)
+				require.NoError(tt, err)
+				assert.Equal(tt, &Stat{CID: c, Size: uint64(len(artifact)), Pinned: true}, stat)
+			})
+
+			t.Run("pins and unpins an artifact", func(tt *testing.T) {
+				store := newStore(tt)
+				c, err := store.Put(ctx, bytes.NewReader(artifact))
+				require.NoError(tt, err)
+
+				require.NoError(tt, store.Unpin(ctx, c))
+				stat, err := store.Stat(ctx, c)
+				require.NoError(tt, err)
+				assert.False(tt, stat.Pinned)
+				assert.Error(tt, store.Unpin(ctx, c))
+
+				require.NoError(tt, store.Pin(ctx, c))
+				stat, err = store.Stat(ctx, c)
+				require.NoError(tt, err)
+				assert.True(tt, stat.Pinned)
+			})
+
+			t.Run("fails for an unknown cid", func(tt *testing.T) {
+				store := newStore(tt)
+				unknown := cid.NewCidV1(cid.Raw, mustSum(tt, []byte("unknown"))).String()
+				_, err := store.Get(ctx, unknown)
+				assert.Error(tt, err)
+				_, err = store.Stat(ctx, unknown)
+				assert.Error(tt, err)
+				assert.Error(tt, store.Pin(ctx, unknown))
+			})
+		})
+	}
+
+	t.Run("addresses local artifacts by the CIDv1 of their content", func(tt *testing.T) {
+		store, err := NewLocalStore(tt.TempDir())
+		require.NoError(tt, err)
+		c, err := store.Put(ctx, bytes.NewReader(artifact))
+		require.NoError(tt, err)
+
+		decoded, err := cid.Decode(c)
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(1), decoded.Version())
+		assert.Equal(tt, uint64(cid.Raw), decoded.Type())
+		digest, err := multihash.Decode(decoded.Hash())
+		require.NoError(tt, err)
+		sum := sha256.Sum256(artifact)
+		assert.Equal(tt, sum[:], digest.Digest)
+
+		// other encodings of the cid refer to the same artifact
+		base58, err := decoded.StringOfBase('z')
+		require.NoError(tt, err)
+		_, err = store.Stat(ctx, base58)
+		assert.NoError(tt, err)
+		_, err = store.Get(ctx, "not-a-cid")
+		assert.ErrorContains(tt, err, "invalid cid")
+		_, err = store.Get(ctx, cid.NewCidV1(cid.Raw, mustSum(tt, []byte("unknown"))).String())
+		assert.ErrorIs(tt, err, ErrNotFound)
+	})
+}
+
+func TestFileCID(t *testing.T) {
+	// cids of `ipfs add --cid-version=1` for content of the given size, whose byte i is i % 251
+	tests := map[int]string{
+		0:        "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku",
+		1:        "bafkreidogqfzz75tpkmjzjke425xqcrmpcib2p5tg44hnbirumdbpl5adu",
+		262144:   "bafkreibruh455iawsviqslif5c7uurdcfdemh22mtnytyzvnzn75kpejxy",
+		262145:   "bafybeiexg2oqkfnj56l7fcmawswqbijt5shq4b5rg6a546uwpkqqzwjioi",
+		1048576:  "bafybeiedpcapwld4tkgtzwahfofgn4wex5ryysf4se6hwpmlrsh4ntnrau",
+		45613056: "bafybeihpe5snhzneq7xs53nivmsopto5lrogo3wjynauqylqeym5a3irbm",
+		// one chunk more than a node links, so the chunks are linked by two levels of nodes
+		45613057: "bafybeib4y7ghw2rq7bracc4xwtxrbzo7cfvagdpte2tmrkgwl6dyard3cm",
+	}
+	content := make([]byte, 45613057)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	for size, expected := range tests {
+		c, err := fileCID(content[:size])
+		require.NoError(t, err)
+		assert.Equal(t, expected, c.String(), "content of %d bytes", size)
+	}
+
+	t.Run("local artifacts are addressed by the cid of an ipfs node", func(tt *testing.T) {
+		store, err := NewLocalStore(tt.TempDir())
+		require.NoError(tt, err)
+		c, err := store.Put(context.Background(), bytes.NewReader(content[:1048576]))
+		require.NoError(tt, err)
+		assert.Equal(tt, tests[1048576], c)
+
+		data, err := ReadURI(context.Background(), store, URI(c))
+		require.NoError(tt, err)
+		assert.Equal(tt, content[:1048576], data)
+	})
+}
+
+func TestCIDFromURI(t *testing.T) {
+	c := "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"
+	got, err := CIDFromURI("ipfs://" + c)
+	require.NoError(t, err)
+	assert.Equal(t, c, got)
+
+	_, err = CIDFromURI("https://ipfs.io/ipfs/" + c)
+	assert.ErrorContains(t, err, "not an ipfs uri")
+	_, err = CIDFromURI("ipfs://not-a-cid")
+	assert.ErrorContains(t, err, "invalid cid")
+}
+
+func mustSum(t *testing.T, data []byte) multihash.Multihash {
+	hash, err := multihash.Sum(data, multihash.SHA2_256, -1)
+	require.NoError(t, err)
+	return hash
+}
@@ -0,0 +1,213 @@
+package ipfs
+
+import (
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// unixfs data types of the files ipfs nodes add
+const (
+	unixFSRaw  = 0
+	unixFSFile = 2
+)
+
+// Defaults ipfs nodes add files with: chunks of 256KiB as raw blocks, linked by a balanced tree of dag-pb nodes with
+// at most 174 links each
+const (
+	fileChunkSize = 256 << 10
+	fileMaxLinks  = 174
+)
+
+// fileLink is a block of a unixfs file together with the sizes a link to it records
+type fileLink struct {
+	cid cid.Cid
+	// fileSize is the size of the content of the block and the blocks it links
+	fileSize uint64
+	// dagSize is the size of the block and the blocks it links
+	dagSize uint64
+}
+
+// fileCID returns the cid an ipfs node adds content with as a CIDv1 file, i.e. `ipfs add --cid-version=1`. Content
+// that fits into a single chunk is addressed as a raw block, larger content by the root of the tree linking its chunks.
+func fileCID(content []byte) (cid.Cid, error) {
+	links := make([]fileLink, 0, len(content)/fileChunkSize+1)
+	for offset := 0; offset == 0 || offset < len(content); offset += fileChunkSize {
+		end := offset + fileChunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		chunk := content[offset:end]
+		c, err := blockCID(cid.Raw, chunk)
+		if err != nil {
+			return cid.Undef, err
+		}
+		links = append(links, fileLink{cid: c, fileSize: uint64(len(chunk)), dagSize: uint64(len(chunk))})
+	}
+
+	for len(links) > 1 {
+		parents := make([]fileLink, 0, (len(links)+fileMaxLinks-1)/fileMaxLinks)
+		for i := 0; i < len(links); i += fileMaxLinks {
+			end := i + fileMaxLinks
+			if end > len(links) {
+				end = len(links)
+			}
+			parent, err := encodeFileNode(links[i:end])
+			if err != nil {
+				return cid.Undef, err
+			}
+			parents = append(parents, *parent)
+		}
+		links = parents
+	}
+	return links[0].cid, nil
+}
+
+// encodeFileNode encodes the dag-pb block of a unixfs file linking the given blocks, in the canonical form of dag-pb
+// with the links before the data
+func encodeFileNode(links []fileLink) (*fileLink, error) {
+	node := fileLink{}
+	var block []byte
+	var blockSizes []byte
+	for _, link := range links {
+		var encoded []byte
+		encoded = protowire.AppendTag(encoded, 1, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, link.cid.Bytes())
+		encoded = protowire.AppendTag(encoded, 2, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, nil)
+		encoded = protowire.AppendTag(encoded, 3, protowire.VarintType)
+		encoded = protowire.AppendVarint(encoded, link.dagSize)
+		block = protowire.AppendTag(block, 2, protowire.BytesType)
+		block = protowire.AppendBytes(block, encoded)
+
+		blockSizes = protowire.AppendTag(blockSizes, 4, protowire.VarintType)
+		blockSizes = protowire.AppendVarint(blockSizes, link.fileSize)
+		node.fileSize += link.fileSize
+		node.dagSize += link.dagSize
+	}
+
+	var unixFS []byte
+	unixFS = protowire.AppendTag(unixFS, 1, protowire.VarintType)
+	unixFS = protowire.AppendVarint(unixFS, unixFSFile)
+	unixFS = protowire.AppendTag(unixFS, 3, protowire.VarintType)
+	unixFS = protowire.AppendVarint(unixFS, node.fileSize)
+	unixFS = append(unixFS, blockSizes...)
+	block = protowire.AppendTag(block, 1, protowire.BytesType)
+	block = protowire.AppendBytes(block, unixFS)
+
+	c, err := blockCID(cid.DagProtobuf, block)
+	if err != nil {
+		return nil, err
+	}
+	node.cid = c
+	node.dagSize += uint64(len(block))
+	return &node, nil
+}
+
+// blockCID returns the CIDv1 of a block of the given codec
+func blockCID(codec uint64, block []byte) (cid.Cid, error) {
+	hash, err := multihash.Sum(block, multihash.SHA2_256, -1)
+	if err != nil {
+		return cid.Undef, errors.Wrap(err, "hashing block")
+	}
+	return cid.NewCidV1(codec, hash), nil
+}
+
+// fileNode is a dag-pb block of a unixfs file. The content of the file is the data of the block followed by the
+// content of the linked blocks in order.
+type fileNode struct {
+	data  []byte
+	links []cid.Cid
+}
+
+// decodeFileNode decodes a dag-pb block holding a unixfs file
+func decodeFileNode(block []byte) (*fileNode, error) {
+	var node fileNode
+	var unixFS []byte
+	err := forEachField(block, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			unixFS = value
+		case 2:
+			link, err := decodeLink(value)
+			if err != nil {
+				return err
+			}
+			node.links = append(node.links, link)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding dag-pb node")
+	}
+
+	dataType := -1
+	err = forEachField(unixFS, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			v, n := protowire.ConsumeVarint(value)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			dataType = int(v)
+		case 2:
+			node.data = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding unixfs data")
+	}
+	if dataType != unixFSFile && dataType != unixFSRaw {
+		return nil, errors.Errorf("unixfs node of type %d is not a file", dataType)
+	}
+	return &node, nil
+}
+
+// decodeLink returns the cid of a dag-pb link
+func decodeLink(link []byte) (cid.Cid, error) {
+	var hash []byte
+	err := forEachField(link, func(num protowire.Number, value []byte) error {
+		if num == 1 {
+			hash = value
+		}
+		return nil
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	_, c, err := cid.CidFromBytes(hash)
+	return c, err
+}
+
+// forEachField calls fn with the number and the raw value of each field of a protobuf message. The value of a varint
+// field is passed encoded, the value of a length delimited field without its length.
+func forEachField(message []byte, fn func(protowire.Number, []byte) error) error {
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		message = message[n:]
+
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(message)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, message)
+			if n >= 0 {
+				value = message[:n]
+			}
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		message = message[n:]
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/sirupsen/logrus"
 	"net"
 	"net/http"
 	"strings"
@@ -30,6 +31,7 @@ var (
 // rateLimited is the content returned from an RPC call when rate limited.
 var rateLimited = "429 Too Many Requests"
 
+// ErrEthClient is returned by a client when no endpoint could serve a call
 type ErrEthClient struct {
 	Err error
 }
@@ -38,27 +40,45 @@ func (e ErrEthClient) Error() string {
 	return e.Err.Error()
 }
 
-// NewEthClient returns an ethclient.Client
-func NewEthClient() *ethclient.Client {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (e ErrEthClient) Unwrap() error {
+	return e.Err
+}
+
+// NewEthClient returns a client of the endpoints listed in RPC_URL, in order of preference, which are dialed on first
+// use
+func NewEthClient() (*Client, error) {
+	return NewClient(ParseEndpoints(env.GetString("RPC_URL")), retryConfigFromEnv())
+}
+
+// NewBackend returns the client of NewEthClient, or nil if it cannot be created. Services depending on the chain
+// report themselves as not ready without a backend, so that a server still starts and reports why.
+func NewBackend() Backend {
+	client, err := NewEthClient()
+	if err != nil {
+		logrus.WithError(err).Error("could not create the chain client")
+		return nil
+	}
+	return client
+}
+
+// dial dials the node at endpoint
+func dial(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var client *rpc.Client
 	var err error
 
-	if endpoint := env.GetString("RPC_URL"); strings.HasPrefix(endpoint, "https://") {
+	if strings.HasPrefix(endpoint, "https://") {
 		client, err = rpc.DialHTTPWithClient(endpoint, defaultHTTPClient)
-		if err != nil {
-			panic(err)
-		}
 	} else {
 		client, err = rpc.DialContext(ctx, endpoint)
-		if err != nil {
-			panic(err)
-		}
+	}
+	if err != nil {
+		return nil, ErrEthClient{Err: err}
 	}
 
-	return ethclient.NewClient(client)
+	return ethclient.NewClient(client), nil
 }
 
 // newHTTPClientForRPC returns a http.Client configured with default settings intended for RPC calls.
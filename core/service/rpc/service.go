@@ -4,95 +4,274 @@ import (
 	"context"
 	"fmt"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/fapiper/onchain-access-control/core/contracts"
 	"github.com/fapiper/onchain-access-control/core/env"
 	"github.com/fapiper/onchain-access-control/core/service/framework"
+	"github.com/fapiper/onchain-access-control/core/service/keystore"
 	"github.com/fapiper/onchain-access-control/core/service/persist"
+	"github.com/fapiper/onchain-access-control/core/tx"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"math/big"
+	"strings"
+	"time"
 )
 
+// statusTimeout bounds the request checking that the chain of a service is reachable
+const statusTimeout = 5 * time.Second
+
+// Service reads from and writes to the contracts of its default chain, on which its wallet identifies this instance.
+// Reads of a did:pkh of another configured chain are routed to the service of that chain.
 type Service struct {
 	Wallet          *Wallet
 	ContextHandler  persist.Address
 	SessionRegistry persist.Address
+	DIDRegistry     persist.Address
+	chains          map[uint64]*Service
+	// ownerDID is the DID this instance acts for, empty if it is the DID of the wallet
+	ownerDID string
+	// cache of the reads of access context addresses, roles and sessions, nil if they are not cached
+	cache *readCache
 }
 
+// Type is the type of the service of a chain, so that readiness is reported per chain
 func (s Service) Type() framework.Type {
-	return framework.Schema
+	if s.Wallet == nil {
+		return framework.RPC
+	}
+	return framework.Type(fmt.Sprintf("%s:eip155:%d", framework.RPC, s.ChainID()))
 }
 
+// Status reports whether the chain of the service is reachable, along with the endpoints of its client that are
+// failing
 func (s Service) Status() framework.Status {
 	ae := sdkutil.NewAppendError()
 	if s.Wallet == nil {
 		ae.AppendString("no wallet configured")
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+		defer cancel()
+		if _, err := s.Wallet.Client.BlockNumber(ctx); err != nil {
+			ae.AppendString(fmt.Sprintf("chain %d is not reachable: %s", s.ChainID(), err))
+		}
 	}
 	if !ae.IsEmpty() {
 		return framework.Status{
 			Status:  framework.StatusNotReady,
-			Message: fmt.Sprintf("rpc service is not ready: %s", ae.Error().Error()),
+			Message: fmt.Sprintf("rpc service is not ready: %s", strings.Join(append([]string{ae.Error().Error()}, s.failingEndpoints()...), "; ")),
+		}
+	}
+	return framework.Status{Status: framework.StatusReady, Message: strings.Join(s.failingEndpoints(), "; ")}
+}
+
+// failingEndpoints describes the endpoints of the client of the service that are backing off
+func (s Service) failingEndpoints() []string {
+	if s.Wallet == nil {
+		return nil
+	}
+	client, ok := s.Wallet.Client.(*Client)
+	if !ok {
+		return nil
+	}
+	var failing []string
+	for _, endpoint := range client.Endpoints() {
+		if !endpoint.Healthy {
+			failing = append(failing, fmt.Sprintf("endpoint %s is failing until %s: %s", endpoint.Endpoint, endpoint.RetryAt.Format(time.RFC3339), endpoint.Error))
 		}
 	}
-	return framework.Status{Status: framework.StatusReady}
+	return failing
 }
 
-func NewRPCService() (*Service, error) {
-	wallet, err := NewWallet(env.GetString("PRIVATE_KEY"), uint64(env.GetInt("CHAIN_ID")))
+// OwnerDID returns the DID this instance acts for, which is the DID of the wallet unless another one is configured
+func (s Service) OwnerDID() string {
+	if s.ownerDID != "" {
+		return s.ownerDID
+	}
+	return s.Wallet.GetDID()
+}
+
+// OwnerDIDHash returns the hash of the owner DID, which identifies it on-chain
+func (s Service) OwnerDIDHash() common.Hash {
+	return crypto.Keccak256Hash([]byte(s.OwnerDID()))
+}
+
+// Config is the wallet and the contracts of the rpc service on its default chain, and the further chains it serves
+type Config struct {
+	Signer          SignerConfig
+	ChainID         uint64
+	Wallet          WalletConfig
+	ContextHandler  persist.Address
+	SessionRegistry persist.Address
+	DIDRegistry     persist.Address
+	// OwnerDID is the DID the access contexts, roles and sessions of this instance belong to. It stays the same when
+	// the wallet acting for it is rotated through the did registry, and defaults to the DID of the wallet.
+	OwnerDID string
+	Chains   []ChainConfig
+	// Retry is how the clients of the chains dialed by the service retry calls
+	Retry RetryConfig
+	Cache CacheConfig
+}
+
+// ConfigFromEnv reads the config of the rpc service from the environment
+func ConfigFromEnv() (*Config, error) {
+	fees, err := feeConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	chains, err := chainConfigsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &Config{
+		Signer:  signerConfigFromEnv(""),
+		ChainID: uint64(env.GetInt("CHAIN_ID")),
+		Wallet: WalletConfig{
+			Fees: *fees,
+			Queue: TxQueueConfig{
+				ResubmitAfter:  env.GetDuration("TX_RESUBMIT_AFTER"),
+				GasBumpPercent: env.GetInt64("TX_GAS_BUMP_PERCENT"),
+			},
+		},
+		ContextHandler:  persist.Address(env.GetString("CONTEXT_HANDLER_CONTRACT")),
+		SessionRegistry: persist.Address(env.GetString("SESSION_REGISTRY_CONTRACT")),
+		DIDRegistry:     persist.Address(env.GetString("DID_REGISTRY_CONTRACT")),
+		OwnerDID:        env.GetString("OWNER_DID"),
+		Chains:          chains,
+		Retry:           retryConfigFromEnv(),
+		Cache:           cacheConfigFromEnv(),
+	}, nil
+}
+
+// NewRPCService returns an rpc service on backend configured from the environment. The key store service holds the
+// key of the wallet if it is configured with the keystore signer.
+func NewRPCService(backend Backend, keyStore *keystore.Service) (*Service, error) {
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid config for the rpc service")
+	}
+	return NewRPCServiceWithConfig(backend, *config, keyStore)
+}
+
+// NewRPCServiceWithConfig returns an rpc service on backend, which may be a node client or a simulated chain, as its
+// default chain
+func NewRPCServiceWithConfig(backend Backend, config Config, keyStore *keystore.Service) (*Service, error) {
+	ctx := context.Background()
+	signer, err := NewSigner(ctx, config.Signer, keyStore)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate signer for the rpc service")
+	}
+
+	wallet, err := NewWallet(backend, signer, config.ChainID, config.Wallet)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate wallet for the rpc service")
 	}
+	cache, err := newReadCache(backend, config.ChainID, config.Cache)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate read cache for the rpc service")
+	}
 
 	service := Service{
 		Wallet:          wallet,
-		ContextHandler:  persist.Address(env.GetString("CONTEXT_HANDLER_CONTRACT")),
-		SessionRegistry: persist.Address(env.GetString("SESSION_REGISTRY_CONTRACT")),
+		ContextHandler:  config.ContextHandler,
+		SessionRegistry: config.SessionRegistry,
+		DIDRegistry:     config.DIDRegistry,
+		chains:          make(map[uint64]*Service, len(config.Chains)),
+		ownerDID:        config.OwnerDID,
+		cache:           cache,
 	}
-
-	if !service.Status().IsReady() {
-		return nil, errors.New(service.Status().Message)
+	for _, chain := range config.Chains {
+		if _, err = service.Chain(chain.ChainID); err == nil {
+			return nil, sdkutil.LoggingNewErrorf("chain %d is configured more than once", chain.ChainID)
+		}
+		if service.chains[chain.ChainID], err = newChainService(ctx, chain, signer, config, keyStore); err != nil {
+			return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate chain for the rpc service")
+		}
 	}
 	return &service, nil
 }
 
+// feeConfigFromEnv reads the fee config of the wallet, with fee caps given in wei
+func feeConfigFromEnv() (*tx.FeeConfig, error) {
+	config := tx.FeeConfig{
+		Mode:             tx.FeeMode(env.GetString("TX_FEE_MODE")),
+		GasLimitMargin:   env.GetInt64("TX_GAS_LIMIT_MARGIN"),
+		HistoryBlocks:    uint64(env.GetInt64("TX_FEE_HISTORY_BLOCKS")),
+		RewardPercentile: env.GetFloat64("TX_PRIORITY_FEE_PERCENTILE"),
+	}
+	switch config.Mode {
+	case "", tx.FeeModeDynamic, tx.FeeModeLegacy:
+	default:
+		return nil, errors.Wrapf(tx.ErrUnknownFeeMode, "%q", config.Mode)
+	}
+
+	var err error
+	if config.MaxFeePerGas, err = weiFromEnv("TX_MAX_FEE_PER_GAS"); err != nil {
+		return nil, err
+	}
+	if config.MaxPriorityFeePerGas, err = weiFromEnv("TX_MAX_PRIORITY_FEE_PER_GAS"); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func weiFromEnv(name string) (*big.Int, error) {
+	value := env.GetString(name)
+	if value == "" {
+		return nil, nil
+	}
+	wei, ok := new(big.Int).SetString(value, 10)
+	if !ok || wei.Sign() < 0 {
+		return nil, errors.Errorf("invalid %s<%s>, expected an amount in wei", name, value)
+	}
+	return wei, nil
+}
+
 type CreateAccessContextParams struct {
 	ID   common.Hash
 	Salt [20]byte
 	DID  common.Hash
 }
 
-// CreateAccessContext creates a new access context
-func (s Service) CreateAccessContext(ctx context.Context, params CreateAccessContextParams) (*types.Receipt, error) {
+// CreateAccessContext sends the transaction creating a new access context, without waiting for it to be mined
+func (s Service) CreateAccessContext(ctx context.Context, params CreateAccessContextParams) (*types.Transaction, error) {
 	instance, err := contracts.NewAccessContextHandler(s.ContextHandler.Address(), s.Wallet.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	txOpts, err := s.Wallet.ToTransactOpts()
-	if err != nil {
-		return nil, err
-	}
-
-	return s.waitMined(instance.CreateContextInstance(
-		txOpts,
-		params.ID,
-		params.Salt,
-		params.DID,
-	))
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.CreateContextInstance(
+			txOpts,
+			params.ID,
+			params.Salt,
+			params.DID,
+		)
+	})
 }
 
-// GetAccessContextAddress creates a new access context
-func (s Service) GetAccessContextAddress(id common.Hash) (persist.Address, error) {
-	instance, err := contracts.NewAccessContextHandlerCaller(s.ContextHandler.Address(), s.Wallet.Client)
+// GetAccessContextAddress returns the address of the access context of a did on the chain of the did, which is the
+// zero address if there is none
+func (s Service) GetAccessContextAddress(did string) (persist.Address, error) {
+	chain, err := s.ChainOf(did)
+	if err != nil {
+		return "", err
+	}
+	instance, err := contracts.NewAccessContextHandlerCaller(chain.ContextHandler.Address(), chain.Wallet.Client)
 	if err != nil {
 		return "", err
 	}
 
-	txOpts := s.Wallet.ToCallOpts()
-	address, err := instance.GetContextInstance(txOpts, id)
+	key := cacheKey{kind: contextAddressKind, did: crypto.Keccak256Hash([]byte(did))}
+	address, err := cachedRead(chain.cache, key, chain.Wallet.ToCallOpts(), func(opts *bind.CallOpts) (common.Address, error) {
+		return instance.GetContextInstance(opts, key.did)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -103,111 +282,591 @@ func (s Service) GetAccessContextAddress(id common.Hash) (persist.Address, error
 type RegisterResourceParams struct {
 	AccessContext persist.Address
 	Role          common.Hash
+	PolicyContext common.Hash
 	Policy        common.Hash
 	Permission    common.Hash
 	Resource      common.Hash
 	Operations    []uint8
+	// Verifier registers a new policy with the given verifier when set, otherwise the existing policy of
+	// PolicyContext is assigned
+	Verifier *common.Address
+	DID      common.Hash
+}
+
+// RegisterResource sends the transaction setting up a role with a policy and a permission for a resource, without
+// waiting for it to be mined
+func (s Service) RegisterResource(ctx context.Context, params RegisterResourceParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		if params.Verifier != nil {
+			return instance.SetupRole(txOpts, params.Role, params.Policy, params.Permission, params.Resource, params.Operations, *params.Verifier, params.DID)
+		}
+		return instance.SetupRole0(txOpts, params.Role, params.PolicyContext, params.Policy, params.Permission, params.Resource, params.Operations, params.DID)
+	})
+}
+
+type DeployPolicyVerifierParams struct {
+	Bytecode               []byte
+	PresentationDefinition string
+	ProofProgram           string
+	ProvingKey             string
+	VerificationKey        string
+}
+
+// DeployPolicyVerifier sends the transaction deploying a policy verifier contract that carries the uris of its policy
+// artifacts, without waiting for it to be mined
+func (s Service) DeployPolicyVerifier(ctx context.Context, params DeployPolicyVerifierParams) (common.Address, *types.Transaction, error) {
+	parsed, err := contracts.PolicyVerifierMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	return s.DeployContract(ctx, DeployContractParams{
+		ABI:      *parsed,
+		Bytecode: params.Bytecode,
+		Args: []any{
+			params.PresentationDefinition,
+			params.ProofProgram,
+			params.ProvingKey,
+			params.VerificationKey,
+		},
+	})
+}
+
+type DeployContractParams struct {
+	ABI      abi.ABI
+	Bytecode []byte
+	Args     []any
+}
+
+// DeployContract sends the transaction deploying a contract with the given constructor arguments, without waiting for
+// it to be mined. The address of the contract follows from the nonce of the transaction and is known right away.
+func (s Service) DeployContract(ctx context.Context, params DeployContractParams) (common.Address, *types.Transaction, error) {
+	var address common.Address
+	tx, err := s.submit(ctx, func(txOpts *bind.TransactOpts) (tx *types.Transaction, err error) {
+		address, tx, _, err = bind.DeployContract(txOpts, params.ABI, params.Bytecode, s.Wallet.Client, params.Args...)
+		return tx, err
+	})
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	return address, tx, nil
+}
+
+type RegisterPolicyParams struct {
+	AccessContext persist.Address
+	Policy        common.Hash
 	Verifier      common.Address
+	// Role the policy is assigned to, if set
+	Role *common.Hash
+	DID  common.Hash
+}
+
+// RegisterPolicy sends the transaction registering a policy verifier in an access context, without waiting for it to
+// be mined
+func (s Service) RegisterPolicy(ctx context.Context, params RegisterPolicyParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		if params.Role != nil {
+			return instance.RegisterPolicy0(txOpts, params.Policy, params.Verifier, *params.Role, params.DID)
+		}
+		return instance.RegisterPolicy(txOpts, params.Policy, params.Verifier, params.DID)
+	})
+}
+
+type AssignPolicyParams struct {
+	AccessContext persist.Address
+	PolicyContext common.Hash
+	Policy        common.Hash
+	Role          common.Hash
 	DID           common.Hash
 }
 
-// RegisterResource creates a new access context
-func (s Service) RegisterResource(ctx context.Context, params RegisterResourceParams) (*types.Receipt, error) {
+// AssignPolicy sends the transaction assigning a policy of any access context to a role of an access context, without
+// waiting for it to be mined
+func (s Service) AssignPolicy(ctx context.Context, params AssignPolicyParams) (*types.Transaction, error) {
 	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	txOpts, err := s.Wallet.ToTransactOpts()
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.AssignPolicy(txOpts, params.PolicyContext, params.Policy, params.Role, params.DID)
+	})
+}
+
+type RegisterPermissionParams struct {
+	AccessContext persist.Address
+	Permission    common.Hash
+	Resource      common.Hash
+	Operations    []uint8
+	// RoleContext and Role the permission is assigned to, if Role is set
+	RoleContext common.Hash
+	Role        *common.Hash
+	DID         common.Hash
+}
+
+// RegisterPermission sends the transaction registering a permission for operations on a resource in an access context,
+// without waiting for it to be mined
+func (s Service) RegisterPermission(ctx context.Context, params RegisterPermissionParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	v := persist.Address("0x04756f72242049Eb05A0BAADa41E0F46828122cD")
-	logrus.Infof("RegisterResource TxParams: %s", params)
-	return s.waitMined(instance.SetupRole(txOpts, params.Role, params.Policy, params.Permission, params.Resource, params.Operations, v.Address(), params.DID))
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		if params.Role != nil {
+			return instance.RegisterPermission0(txOpts, params.Permission, params.Resource, params.Operations, params.RoleContext, *params.Role, params.DID)
+		}
+		return instance.RegisterPermission(txOpts, params.Permission, params.Resource, params.Operations)
+	})
 }
 
-type GrantRoleParams struct {
-	RoleIdentifier   persist.RoleIdentifier
-	DID              common.Hash
+type PermissionAssignmentParams struct {
+	AccessContext persist.Address
+	Permission    common.Hash
+	RoleContext   common.Hash
+	Role          common.Hash
+	DID           common.Hash
+}
+
+// AssignPermission sends the transaction assigning a permission to a role, without waiting for it to be mined
+func (s Service) AssignPermission(ctx context.Context, params PermissionAssignmentParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.AssignPermission(txOpts, params.Permission, params.RoleContext, params.Role, params.DID)
+	})
+}
+
+// UnassignPermission sends the transaction removing the assignment of a permission to a role, without waiting for it
+// to be mined
+func (s Service) UnassignPermission(ctx context.Context, params PermissionAssignmentParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.UnassignPermission(txOpts, params.Permission, params.RoleContext, params.Role, params.DID)
+	})
+}
+
+// GrantRolePolicy is a policy of a role along with the proof and public inputs that satisfy it. The number of inputs
+// is declared by the policy verifier.
+type GrantRolePolicy struct {
 	PolicyIdentifier persist.PolicyIdentifier
 	Proof            contracts.IPolicyVerifierProof
-	Inputs           [20]*big.Int
+	Inputs           []*big.Int
+}
+
+type GrantRoleParams struct {
+	RoleIdentifier persist.RoleIdentifier
+	DID            common.Hash
+	Policies       []GrantRolePolicy
 }
 
-// GrantRole grants a role to a user
-func (s Service) GrantRole(ctx context.Context, params GrantRoleParams) (*types.Receipt, error) {
+// GrantRole sends the transaction granting a role to a user, without waiting for it to be mined
+func (s Service) GrantRole(ctx context.Context, params GrantRoleParams) (*types.Transaction, error) {
 	instance, err := contracts.NewAccessContextHandler(s.ContextHandler.Address(), s.Wallet.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	txOpts, err := s.Wallet.ToTransactOpts()
+	policyContexts := make([][32]byte, 0, len(params.Policies))
+	policies := make([][32]byte, 0, len(params.Policies))
+	proofs := make([]contracts.IPolicyVerifierProof, 0, len(params.Policies))
+	inputs := make([][]*big.Int, 0, len(params.Policies))
+	for _, policy := range params.Policies {
+		policyContexts = append(policyContexts, policy.PolicyIdentifier.ContextID)
+		policies = append(policies, policy.PolicyIdentifier.PolicyID)
+		proofs = append(proofs, policy.Proof)
+		inputs = append(inputs, policy.Inputs)
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.GrantRole(
+			txOpts,
+			params.RoleIdentifier.ContextID,
+			params.RoleIdentifier.RoleID,
+			params.DID,
+			policyContexts,
+			policies,
+			proofs,
+			inputs,
+		)
+	})
+}
+
+type GetRolePolicyCountParams struct {
+	// Context is the did of the access context at Address, whose chain the count is read from
+	Context string
+	Address persist.Address
+	RoleID  common.Hash
+}
+
+// GetRolePolicyCount returns the number of policies assigned to a role, all of which must be satisfied to be granted
+func (s Service) GetRolePolicyCount(ctx context.Context, params GetRolePolicyCountParams) (uint64, error) {
+	chain, err := s.ChainOf(params.Context)
+	if err != nil {
+		return 0, err
+	}
+	instance, err := contracts.NewAccessContextCaller(params.Address.Address(), chain.Wallet.Client)
+	if err != nil {
+		return 0, err
+	}
+
+	txOpts := chain.Wallet.ToCallOpts()
+
+	count, err := instance.GetRolePolicyCount(txOpts, params.RoleID)
+	if err != nil {
+		return 0, err
+	}
+	return count.Uint64(), nil
+}
+
+type GetPolicyVerifierParams struct {
+	// Context is the did of the access context at AccessContext, whose chain the policy is read from
+	Context string
+	// AccessContext the policy is looked up from, which may reference a policy of another context
+	AccessContext    persist.Address
+	PolicyIdentifier persist.PolicyIdentifier
+}
+
+// GetPolicyVerifier returns the address of the verifier contract of a policy
+func (s Service) GetPolicyVerifier(ctx context.Context, params GetPolicyVerifierParams) (common.Address, error) {
+	chain, err := s.ChainOf(params.Context)
+	if err != nil {
+		return common.Address{}, err
+	}
+	instance, err := contracts.NewAccessContextCaller(params.AccessContext.Address(), chain.Wallet.Client)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	txOpts := chain.Wallet.ToCallOpts()
+
+	policy, err := instance.GetPolicy(txOpts, params.PolicyIdentifier.ContextID, params.PolicyIdentifier.PolicyID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !policy.Exists {
+		return common.Address{}, errors.Errorf("policy<%s> does not exist", params.PolicyIdentifier)
+	}
+	return policy.Verifier, nil
+}
+
+type PolicyVerifierParams struct {
+	// Context is the did of the access context the policy verifier is registered in, whose chain it is read from
+	Context  string
+	Verifier common.Address
+}
+
+// policyVerifier returns the chain and the binding of a policy verifier contract
+func (s Service) policyVerifier(params PolicyVerifierParams) (*Service, *contracts.PolicyVerifierCaller, error) {
+	chain, err := s.ChainOf(params.Context)
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, err := contracts.NewPolicyVerifierCaller(params.Verifier, chain.Wallet.Client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chain, instance, nil
+}
+
+// GetVerificationKeyURI returns the uri of the verification key carried by a policy verifier contract
+func (s Service) GetVerificationKeyURI(ctx context.Context, params PolicyVerifierParams) (string, error) {
+	chain, instance, err := s.policyVerifier(params)
+	if err != nil {
+		return "", err
+	}
+
+	txOpts := chain.Wallet.ToCallOpts()
+
+	return instance.VerificationKey(txOpts)
+}
+
+// GetPolicyInputCount returns the number of public inputs a policy verifier contract declares for its proofs
+func (s Service) GetPolicyInputCount(ctx context.Context, params PolicyVerifierParams) (uint64, error) {
+	chain, instance, err := s.policyVerifier(params)
+	if err != nil {
+		return 0, err
+	}
+
+	txOpts := chain.Wallet.ToCallOpts()
+
+	count, err := instance.InputCount(txOpts)
+	if err != nil {
+		return 0, err
+	}
+	return count.Uint64(), nil
+}
+
+type PolicyVerifierURIs struct {
+	PresentationDefinition string
+	ProofProgram           string
+	ProvingKey             string
+	VerificationKey        string
+}
+
+// GetPolicyVerifierURIs returns the uris of all policy artifacts carried by a policy verifier contract
+func (s Service) GetPolicyVerifierURIs(ctx context.Context, params PolicyVerifierParams) (*PolicyVerifierURIs, error) {
+	chain, instance, err := s.policyVerifier(params)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.waitMined(instance.GrantRole(
-		txOpts,
-		params.RoleIdentifier.ContextID,
-		params.RoleIdentifier.RoleID,
-		params.DID,
-		[][32]byte{params.PolicyIdentifier.ContextID},
-		[][32]byte{params.PolicyIdentifier.PolicyID},
-		[]contracts.IPolicyVerifierProof{params.Proof},
-		[][20]*big.Int{params.Inputs},
-	))
+	txOpts := chain.Wallet.ToCallOpts()
+
+	var uris PolicyVerifierURIs
+	if uris.PresentationDefinition, err = instance.PresentationDefinition(txOpts); err != nil {
+		return nil, errors.Wrap(err, "getting presentation definition uri")
+	}
+	if uris.ProofProgram, err = instance.ProofProgram(txOpts); err != nil {
+		return nil, errors.Wrap(err, "getting proof program uri")
+	}
+	if uris.ProvingKey, err = instance.ProvingKey(txOpts); err != nil {
+		return nil, errors.Wrap(err, "getting proving key uri")
+	}
+	if uris.VerificationKey, err = instance.VerificationKey(txOpts); err != nil {
+		return nil, errors.Wrap(err, "getting verification key uri")
+	}
+	return &uris, nil
 }
 
+type RevokeRoleParams struct {
+	AccessContext persist.Address
+	Role          common.Hash
+	DID           common.Hash
+}
+
+// RevokeRole sends the transaction revoking a role of a user within an access context, without waiting for it to be
+// mined
+func (s Service) RevokeRole(ctx context.Context, params RevokeRoleParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContext(params.AccessContext.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.RevokeRole(txOpts, params.Role, params.DID)
+	})
+}
+
+// SetSessionRegistry links the access context handler to the session registry sessions are forwarded to
+func (s Service) SetSessionRegistry(ctx context.Context, sessionRegistry common.Address) (*types.Receipt, error) {
+	instance, err := contracts.NewAccessContextHandler(s.ContextHandler.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transact(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.SetSessionRegistry(txOpts, sessionRegistry)
+	})
+}
+
+// ErrNoDIDRegistry is returned for did controller calls on a chain without a configured did registry
+var ErrNoDIDRegistry = errors.New("no did registry configured")
+
+// GetControllers returns the controllers the did registry of the chain of a did records for it, along with the current
+// one authorized to act for the did. A did unknown to the registry has no controllers.
+func (s Service) GetControllers(ctx context.Context, did string) ([]common.Address, common.Address, error) {
+	chain, instance, err := s.didRegistry(did)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	txOpts := chain.Wallet.ToCallOpts()
+	txOpts.Context = ctx
+	identity := crypto.Keccak256Hash([]byte(did))
+	controllers, err := instance.GetControllers(txOpts, identity)
+	if err != nil || len(controllers) == 0 {
+		return nil, common.Address{}, err
+	}
+	current, err := instance.GetController(txOpts, identity)
+	if err != nil {
+		return nil, common.Address{}, errors.Wrap(err, "getting current controller")
+	}
+	return controllers, current, nil
+}
+
+type DIDControllerParams struct {
+	// DID whose controllers are changed on its chain, by the wallet as its current controller
+	DID        string
+	Controller common.Address
+}
+
+// AddController sends the transaction adding a controller of a did, without waiting for it to be mined. The first
+// controller of a did unknown to the registry may be added by anyone.
+func (s Service) AddController(ctx context.Context, params DIDControllerParams) (*types.Transaction, error) {
+	chain, instance, err := s.didRegistry(params.DID)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.AddController(txOpts, crypto.Keccak256Hash([]byte(params.DID)), params.Controller)
+	})
+}
+
+// RemoveController sends the transaction removing a controller of a did other than its current one, without waiting
+// for it to be mined
+func (s Service) RemoveController(ctx context.Context, params DIDControllerParams) (*types.Transaction, error) {
+	chain, instance, err := s.didRegistry(params.DID)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.RemoveController(txOpts, crypto.Keccak256Hash([]byte(params.DID)), params.Controller)
+	})
+}
+
+// ChangeController sends the transaction making a controller of a did its current one, which is authorized to act for
+// the did from then on, without waiting for it to be mined
+func (s Service) ChangeController(ctx context.Context, params DIDControllerParams) (*types.Transaction, error) {
+	chain, instance, err := s.didRegistry(params.DID)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.ChangeController(txOpts, crypto.Keccak256Hash([]byte(params.DID)), params.Controller)
+	})
+}
+
+// didRegistry returns the service of the chain of a did along with its did registry
+func (s Service) didRegistry(did string) (*Service, *contracts.SimpleDIDRegistry, error) {
+	chain, err := s.ChainOf(did)
+	if err != nil {
+		return nil, nil, err
+	}
+	if chain.DIDRegistry == "" {
+		return nil, nil, errors.Wrapf(ErrNoDIDRegistry, "chain %d", chain.ChainID())
+	}
+	instance, err := contracts.NewSimpleDIDRegistry(chain.DIDRegistry.Address(), chain.Wallet.Client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chain, instance, nil
+}
+
+// MaxSessionDuration is the longest session the session registry accepts
+const MaxSessionDuration = 30 * 24 * time.Hour
+
 type StartSessionParams struct {
-	DID        common.Hash
+	DID common.Hash
+	// Context is the access context the session is started in, whose admin may revoke it
+	Context    common.Hash
 	TokenID    common.Hash
 	SessionJWE []byte
+	// Duration is the lifetime of the session, after which the session registry no longer accepts it. It is counted
+	// in whole seconds from the block the session is started in and must not exceed MaxSessionDuration.
+	Duration time.Duration
 }
 
-// StartSession starts a session
-func (s Service) StartSession(ctx context.Context, params StartSessionParams) (*types.Receipt, error) {
+// StartSession sends the transaction starting a session, without waiting for it to be mined
+func (s Service) StartSession(ctx context.Context, params StartSessionParams) (*types.Transaction, error) {
+	seconds := int64(params.Duration / time.Second)
+	if seconds <= 0 {
+		return nil, errors.Errorf("session duration %s is shorter than a second", params.Duration)
+	}
+	if params.Duration > MaxSessionDuration {
+		return nil, errors.Errorf("session duration %s exceeds %s", params.Duration, MaxSessionDuration)
+	}
+
 	instance, err := contracts.NewAccessContextHandler(s.ContextHandler.Address(), s.Wallet.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	txOpts, err := s.Wallet.ToTransactOpts()
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.StartSession(
+			txOpts,
+			params.DID,
+			params.Context,
+			params.TokenID,
+			params.SessionJWE,
+			big.NewInt(seconds),
+		)
+	})
+}
+
+type RevokeSessionParams struct {
+	TokenID common.Hash
+}
+
+// RevokeSession sends the transaction revoking a session of the wallet's did in the session registry, without waiting
+// for it to be mined
+func (s Service) RevokeSession(ctx context.Context, params RevokeSessionParams) (*types.Transaction, error) {
+	instance, err := contracts.NewSessionRegistry(s.SessionRegistry.Address(), s.Wallet.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.waitMined(instance.StartSession(
-		txOpts,
-		params.DID,
-		params.TokenID,
-		params.SessionJWE,
-	))
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.RevokeSession(txOpts, params.TokenID)
+	})
 }
 
-type CheckSessionParams struct {
+type RevokeContextSessionParams struct {
 	TokenID common.Hash
+	Context common.Hash
 	DID     common.Hash
 }
 
+// RevokeContextSession revokes a session started in an access context on behalf of the admin of the context, without
+// waiting for it to be mined
+func (s Service) RevokeContextSession(ctx context.Context, params RevokeContextSessionParams) (*types.Transaction, error) {
+	instance, err := contracts.NewAccessContextHandler(s.ContextHandler.Address(), s.Wallet.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(ctx, func(txOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return instance.RevokeSession(txOpts, params.TokenID, params.Context, params.DID)
+	})
+}
+
+type CheckSessionParams struct {
+	TokenID common.Hash
+	// Subject is the did holding the session, which is checked on the chain of the did
+	Subject string
+}
+
 // CheckSession verifies a session
 func (s Service) CheckSession(ctx context.Context, params CheckSessionParams) (bool, error) {
-	instance, err := contracts.NewSessionRegistryCaller(s.ContextHandler.Address(), s.Wallet.Client)
+	chain, err := s.ChainOf(params.Subject)
+	if err != nil {
+		return false, err
+	}
+	instance, err := contracts.NewSessionRegistryCaller(chain.ContextHandler.Address(), chain.Wallet.Client)
 	if err != nil {
 		return false, err
 	}
 
-	txOpts := s.Wallet.ToCallOpts()
-
-	return instance.IsSession(
-		txOpts,
-		params.TokenID,
-		params.DID,
-	)
+	key := cacheKey{kind: sessionKind, id: params.TokenID, did: crypto.Keccak256Hash([]byte(params.Subject))}
+	return cachedRead(chain.cache, key, chain.Wallet.ToCallOpts(), func(opts *bind.CallOpts) (bool, error) {
+		return instance.IsSession(opts, key.id, key.did)
+	})
 }
 
 type HasRoleParams struct {
+	// Context is the did of the access context at Address, whose chain the role is read from
+	Context string
 	Address persist.Address
 	RoleID  common.Hash
 	DID     common.Hash
@@ -215,35 +874,161 @@ type HasRoleParams struct {
 
 // HasRole verifies a role
 func (s Service) HasRole(ctx context.Context, params HasRoleParams) (bool, error) {
-	instance, err := contracts.NewAccessContextCaller(params.Address.Address(), s.Wallet.Client)
+	chain, err := s.ChainOf(params.Context)
+	if err != nil {
+		return false, err
+	}
+	instance, err := contracts.NewAccessContextCaller(params.Address.Address(), chain.Wallet.Client)
+	if err != nil {
+		return false, err
+	}
+
+	key := cacheKey{kind: roleKind, address: params.Address.Address(), id: params.RoleID, did: params.DID}
+	return cachedRead(chain.cache, key, chain.Wallet.ToCallOpts(), func(opts *bind.CallOpts) (bool, error) {
+		return instance.HasRole(opts, key.id, key.did)
+	})
+}
+
+type HasPermissionParams struct {
+	// Context is the did of the access context at Address, whose chain the permission is read from
+	Context    string
+	Address    persist.Address
+	Role       common.Hash
+	Permission common.Hash
+	Resource   common.Hash
+	Operation  uint8
+}
+
+// HasPermission verifies that a role holds a permission for an operation on a resource
+func (s Service) HasPermission(ctx context.Context, params HasPermissionParams) (bool, error) {
+	chain, err := s.ChainOf(params.Context)
+	if err != nil {
+		return false, err
+	}
+	instance, err := contracts.NewAccessContextCaller(params.Address.Address(), chain.Wallet.Client)
 	if err != nil {
 		return false, err
 	}
 
-	txOpts := s.Wallet.ToCallOpts()
+	txOpts := chain.Wallet.ToCallOpts()
 
-	return instance.HasRole(
+	return instance.HasPermission(
 		txOpts,
-		params.RoleID,
-		params.DID,
+		params.Role,
+		params.Permission,
+		params.Resource,
+		params.Operation,
 	)
 }
 
-func (s Service) waitMined(tx *types.Transaction, err error) (*types.Receipt, error) {
+// HeaderByNumber returns the header of a block, or of the latest block if number is nil
+func (s Service) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return s.Wallet.Client.HeaderByNumber(ctx, number)
+}
+
+type FilterLogsParams struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Addresses []common.Address
+}
+
+// FilterLogs returns all logs emitted by the given contracts within a block range
+func (s Service) FilterLogs(ctx context.Context, params FilterLogsParams) ([]types.Log, error) {
+	if len(params.Addresses) == 0 {
+		return nil, nil
+	}
+	return s.Wallet.Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(params.FromBlock),
+		ToBlock:   new(big.Int).SetUint64(params.ToBlock),
+		Addresses: params.Addresses,
+	})
+}
+
+// BlockNumber returns the number of the latest block
+func (s Service) BlockNumber(ctx context.Context) (uint64, error) {
+	return s.Wallet.Client.BlockNumber(ctx)
+}
+
+// TransactionReceipt returns the receipt of a mined transaction, or ethereum.NotFound if it is not mined
+func (s Service) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return s.Wallet.Client.TransactionReceipt(ctx, hash)
+}
+
+// WaitMined waits until a transaction sent by the wallet, or its gas-bumped replacement, is mined
+func (s Service) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	return s.Wallet.WaitMined(ctx, tx)
+}
+
+// TransactionHashes returns the hashes of a transaction sent by the wallet and of its gas-bumped replacements so far
+func (s Service) TransactionHashes(tx *types.Transaction) []common.Hash {
+	return s.Wallet.TransactionHashes(tx)
+}
+
+// RevertReason replays a failed transaction on the state of the block before it was mined and returns the error the
+// call reverts with
+func (s Service) RevertReason(ctx context.Context, receipt *types.Receipt) (string, error) {
+	tx, _, err := s.Wallet.Client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return "", errors.Wrap(err, "could not get transaction")
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return "", errors.Wrap(err, "could not get transaction sender")
+	}
+
+	block := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	_, err = s.Wallet.Client.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, block)
+	if err == nil {
+		return "transaction failed without revert reason", nil
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if reason, unpackErr := abi.UnpackRevert(common.FromHex(data)); unpackErr == nil {
+				return reason, nil
+			}
+		}
+	}
+	return err.Error(), nil
+}
+
+// submit submits a transaction through the queue of the wallet without waiting for it to be mined
+func (s Service) submit(ctx context.Context, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	tx, err := s.Wallet.Transact(ctx, send)
 	if err != nil {
 		return nil, err
 	}
+	logrus.Infof("Sent transaction %s", tx.Hash())
+	return tx, nil
+}
 
-	logrus.Infof("Sent transaction %s. Waiting for confirmation...", tx.Hash())
+// transact submits a transaction through the queue of the wallet and waits for it to be mined
+func (s Service) transact(ctx context.Context, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	tx, err := s.Wallet.Transact(ctx, send)
+	return s.waitMined(ctx, tx, err)
+}
 
-	receipt, err := bind.WaitMined(context.Background(), s.Wallet.Client, tx)
-	if receipt.Status == types.ReceiptStatusFailed {
-		return nil, fmt.Errorf("transaction status failed for transaction %s", receipt.TxHash)
+func (s Service) waitMined(ctx context.Context, tx *types.Transaction, err error) (*types.Receipt, error) {
+	if err != nil {
+		return nil, err
 	}
+
+	logrus.Infof("Sent transaction %s. Waiting for confirmation...", tx.Hash())
+
+	receipt, err := s.Wallet.WaitMined(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return nil, fmt.Errorf("transaction status failed for transaction %s", receipt.TxHash)
+	}
 
 	return receipt, nil
-
 }
@@ -0,0 +1,283 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	sdkcrypto "github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/fapiper/onchain-access-control/core/env"
+	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+	keystoreservice "github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/pkg/errors"
+	"math/big"
+	"os"
+	"strings"
+)
+
+type SignerType string
+
+const (
+	// SignerTypeKey signs with a hex encoded private key, which is meant for development only
+	SignerTypeKey SignerType = "key"
+	// SignerTypeKeyStore signs with a secp256k1 key stored encrypted in the key store service. Like ES256K session
+	// tokens, storing secp256k1 keys needs the jwx_es256k build tag.
+	SignerTypeKeyStore SignerType = "keystore"
+	// SignerTypeKeyFile signs with the key of an Ethereum V3 keystore file
+	SignerTypeKeyFile SignerType = "keyfile"
+	// SignerTypeRemote sends transactions and session tokens to a remote signer speaking the account_ api of clef
+	SignerTypeRemote SignerType = "remote"
+)
+
+// ErrNoTokenKey is returned when the signer of a wallet cannot sign session tokens
+var ErrNoTokenKey = errors.New("signer cannot sign session tokens")
+
+var (
+	_ TokenSigner = (*KeySigner)(nil)
+	_ TokenSigner = (*RemoteSigner)(nil)
+)
+
+// Signer signs the transactions of a wallet
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// TokenSigner is implemented by signers that also sign session tokens. Signers holding their key sign with ES256K over
+// the SHA-256 digest of the token. Remote signers only sign transactions and prefixed messages, so they sign the token
+// as an EIP-191 personal message instead, which is verified against the account of the wallet DID all the same.
+type TokenSigner interface {
+	// TokenKey returns the algorithm and the key session tokens are signed with, as passed to jwt.WithKey
+	TokenKey(ctx context.Context) (jwa.SignatureAlgorithm, any)
+}
+
+type SignerConfig struct {
+	Type SignerType
+	// PrivateKey is the hex encoded key of SignerTypeKey
+	PrivateKey string
+	// KeyID is the id of the key of SignerTypeKeyStore in the key store service
+	KeyID string
+	// KeyFile and the file holding its password are the keystore file of SignerTypeKeyFile
+	KeyFile         string
+	KeyPasswordFile string
+	// URL and Address are the endpoint of SignerTypeRemote and the account it signs for
+	URL     string
+	Address string
+}
+
+// signerConfigFromEnv reads the config of a signer from the environment variables with the given prefix
+func signerConfigFromEnv(prefix string) SignerConfig {
+	return SignerConfig{
+		Type:            SignerType(env.GetString(prefix + "WALLET_SIGNER")),
+		PrivateKey:      env.GetString(prefix + "PRIVATE_KEY"),
+		KeyID:           env.GetString(prefix + "WALLET_KEY_ID"),
+		KeyFile:         env.GetString(prefix + "WALLET_KEY_FILE"),
+		KeyPasswordFile: env.GetString(prefix + "WALLET_KEY_PASSWORD_FILE"),
+		URL:             env.GetString(prefix + "WALLET_SIGNER_URL"),
+		Address:         env.GetString(prefix + "WALLET_ADDRESS"),
+	}
+}
+
+// NewSigner returns the signer of a config. The key store service is only needed for SignerTypeKeyStore.
+func NewSigner(ctx context.Context, config SignerConfig, keyStore *keystoreservice.Service) (Signer, error) {
+	switch config.Type {
+	case "", SignerTypeKey:
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse private key")
+		}
+		return NewKeySigner(key), nil
+	case SignerTypeKeyStore:
+		return NewKeyStoreSigner(ctx, keyStore, config.KeyID)
+	case SignerTypeKeyFile:
+		password, err := os.ReadFile(config.KeyPasswordFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read key file password")
+		}
+		return NewKeyFileSigner(config.KeyFile, strings.TrimRight(string(password), "\r\n"))
+	case SignerTypeRemote:
+		if !common.IsHexAddress(config.Address) {
+			return nil, errors.Errorf("invalid address<%s> of remote signer", config.Address)
+		}
+		return NewRemoteSigner(ctx, config.URL, common.HexToAddress(config.Address))
+	default:
+		return nil, errors.Errorf("unknown signer type: %s", config.Type)
+	}
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// NewKeyStoreSigner returns a signer of a secp256k1 key of the key store service, which keeps it encrypted at rest
+func NewKeyStoreSigner(ctx context.Context, keyStore *keystoreservice.Service, keyID string) (*KeySigner, error) {
+	if keyStore == nil {
+		return nil, errors.New("no key store service configured")
+	}
+	if keyID == "" {
+		return nil, errors.New("no key id configured")
+	}
+	stored, err := keyStore.GetKey(ctx, keystoreservice.GetKeyRequest{ID: keyID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get key<%s>", keyID)
+	}
+	if stored.Revoked {
+		return nil, errors.Errorf("key<%s> is revoked", keyID)
+	}
+
+	if stored.Type != sdkcrypto.SECP256k1 && stored.Type != sdkcrypto.SECP256k1ECDSA {
+		return nil, errors.Errorf("key<%s> of type %s is not a secp256k1 key", keyID, stored.Type)
+	}
+	raw, err := sdkcrypto.PrivKeyToBytes(stored.Key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not encode key<%s>", keyID)
+	}
+	key, err := crypto.ToECDSA(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "key<%s> is not a secp256k1 key", keyID)
+	}
+	return NewKeySigner(key), nil
+}
+
+// NewKeyFileSigner returns a signer of the key of an Ethereum V3 keystore file
+func NewKeyFileSigner(path, password string) (*KeySigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read key file")
+	}
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt key file")
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *KeySigner) TokenKey(context.Context) (jwa.SignatureAlgorithm, any) {
+	return jwa.ES256K, s.key
+}
+
+// RemoteSigner sends transactions and messages to be signed to a remote signer, such as clef, over the account_
+// json-rpc api
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewRemoteSigner connects to a remote signer and checks that it signs for address
+func NewRemoteSigner(ctx context.Context, url string, address common.Address) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to remote signer")
+	}
+
+	var accounts []common.Address
+	if err = client.CallContext(ctx, &accounts, "account_list"); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "could not list accounts of remote signer")
+	}
+	for _, account := range accounts {
+		if account == address {
+			return &RemoteSigner{client: client, address: address}, nil
+		}
+	}
+	client.Close()
+	return nil, errors.Errorf("remote signer does not sign for %s", address)
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx asks the remote signer to sign a transaction and checks that the signed transaction is the one requested
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, errors.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, errors.Wrap(err, "remote signer did not sign transaction")
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(result.Raw); err != nil {
+		return nil, errors.Wrap(err, "could not decode signed transaction")
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	from, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not recover signer of signed transaction")
+	}
+	if from != s.address {
+		return nil, errors.Errorf("remote signer signed for %s instead of %s", from, s.address)
+	}
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, errors.New("remote signer signed another transaction")
+	}
+	return signed, nil
+}
+
+// TokenKey signs session tokens with the remote signer as EIP-191 personal messages, bound to the context of the request
+func (s *RemoteSigner) TokenKey(ctx context.Context) (jwa.SignatureAlgorithm, any) {
+	return keyaccess.EIP191, keyaccess.EIP191Key{Context: ctx, Signer: s}
+}
+
+// SignText asks the remote signer to sign text as an EIP-191 personal message and checks that it signed for its account
+func (s *RemoteSigner) SignText(ctx context.Context, text []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	err := s.client.CallContext(ctx, &signature, "account_signData", accounts.MimetypeTextPlain, common.NewMixedcaseAddress(s.address), hexutil.Encode(text))
+	if err != nil {
+		return nil, errors.Wrap(err, "remote signer did not sign message")
+	}
+
+	signer, err := keyaccess.RecoverEIP191(text, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not recover signer of signed message")
+	}
+	if signer != s.address {
+		return nil, errors.Errorf("remote signer signed for %s instead of %s", signer, s.address)
+	}
+	return signature, nil
+}
@@ -0,0 +1,72 @@
+//go:build jwx_es256k
+
+// The key store service stores secp256k1 keys only when built with the jwx_es256k tag, so these tests run with
+// go test -tags jwx_es256k.
+
+package rpc
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	sdkcrypto "github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fapiper/onchain-access-control/core/config"
+	keystoreservice "github.com/fapiper/onchain-access-control/core/service/keystore"
+	"github.com/fapiper/onchain-access-control/core/storage"
+)
+
+func newTestKeyStoreService(t *testing.T) *keystoreservice.Service {
+	s, err := storage.NewStorage(storage.Bolt, storage.Option{
+		ID:     storage.BoltDBFilePathOption,
+		Option: filepath.Join(t.TempDir(), "bolt"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	keyStore, err := keystoreservice.NewKeyStoreService(config.KeyStoreServiceConfig{}, s)
+	require.NoError(t, err)
+	return keyStore
+}
+
+func TestKeyStoreSigner(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	controller := "did:pkh:eip155:1337:" + crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	keyStore := newTestKeyStoreService(t)
+	err = keyStore.StoreKey(ctx, keystoreservice.StoreKeyRequest{
+		ID:               "wallet",
+		Type:             sdkcrypto.SECP256k1,
+		Controller:       controller,
+		PrivateKeyBase58: base58.Encode(crypto.FromECDSA(key)),
+	})
+	require.NoError(t, err)
+
+	signer, err := NewSigner(ctx, SignerConfig{Type: SignerTypeKeyStore, KeyID: "wallet"}, keyStore)
+	require.NoError(t, err)
+	requireWalletSigns(t, signer, key)
+
+	t.Run("rejects a revoked key", func(tt *testing.T) {
+		require.NoError(tt, keyStore.StoreKey(ctx, keystoreservice.StoreKeyRequest{
+			ID:               "revoked",
+			Type:             sdkcrypto.SECP256k1,
+			Controller:       controller,
+			PrivateKeyBase58: base58.Encode(crypto.FromECDSA(key)),
+		}))
+		require.NoError(tt, keyStore.RevokeKey(ctx, keystoreservice.RevokeKeyRequest{ID: "revoked"}))
+		_, err := NewSigner(ctx, SignerConfig{Type: SignerTypeKeyStore, KeyID: "revoked"}, keyStore)
+		assert.ErrorContains(tt, err, "revoked")
+	})
+
+	t.Run("rejects a missing key store service", func(tt *testing.T) {
+		_, err := NewSigner(ctx, SignerConfig{Type: SignerTypeKeyStore, KeyID: "wallet"}, nil)
+		assert.Error(tt, err)
+	})
+}
@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	didint "github.com/fapiper/onchain-access-control/core/internal/did"
+	"github.com/fapiper/onchain-access-control/core/internal/keyaccess"
+)
+
+// accountAPI is a stand-in for the account_ api of a remote signer such as clef
+type accountAPI struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+func (a *accountAPI) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(a.key.PublicKey)}
+}
+
+func (a *accountAPI) SignTransaction(args apitypes.SendTxArgs) (map[string]hexutil.Bytes, error) {
+	tx, err := types.SignTx(args.ToTransaction(), types.LatestSignerForChainID(a.chainID), a.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]hexutil.Bytes{"raw": raw}, nil
+}
+
+// SignData signs text/plain data as an EIP-191 personal message, with a recovery id of 27 or 28 like clef
+func (a *accountAPI) SignData(contentType string, _ common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != accounts.MimetypeTextPlain {
+		return nil, fmt.Errorf("unsupported content type %s", contentType)
+	}
+	signature, err := crypto.Sign(accounts.TextHash(data), a.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+func newRemoteSignerServer(t *testing.T, key *ecdsa.PrivateKey) string {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", &accountAPI{key: key, chainID: big.NewInt(SimulatedChainID)}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+// requireWalletSigns checks that a wallet with signer sends transactions on a simulated chain, and signs session tokens
+// that verify against the account of the wallet DID
+func requireWalletSigns(t *testing.T, signer Signer, key *ecdsa.PrivateKey) {
+	ctx := context.Background()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	assert.Equal(t, address, signer.Address())
+
+	backend := newFundedBackend(t, address)
+	wallet, err := NewWallet(backend, signer, SimulatedChainID, WalletConfig{
+		Queue: TxQueueConfig{PollInterval: 10 * time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	recipient := common.HexToAddress("0xd231120Eea6201B142b4048cf6C86BaC2A0655D2")
+	transfer := bind.NewBoundContract(recipient, abi.ABI{}, backend, backend, backend)
+	tx, err := wallet.Transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		opts.Value = big.NewInt(1)
+		opts.GasLimit = 21_000
+		return transfer.Transfer(opts)
+	})
+	require.NoError(t, err)
+	receipt, err := wallet.WaitMined(ctx, tx)
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	alg, tokenKey, err := wallet.TokenKey(ctx)
+	require.NoError(t, err)
+	token, err := jwt.NewBuilder().Issuer(wallet.GetDID()).JwtID("session").Build()
+	require.NoError(t, err)
+	signed, err := jwt.Sign(token, jwt.WithKey(alg, tokenKey))
+	require.NoError(t, err)
+	assert.NoError(t, didint.VerifyTokenFromPKH(wallet.GetDID(), keyaccess.JWT(signed)))
+}
+
+func TestSigners(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	dir := t.TempDir()
+	keyFile, err := keystore.EncryptKey(&keystore.Key{Address: address, PrivateKey: key}, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	keyFilePath := filepath.Join(dir, "key.json")
+	require.NoError(t, os.WriteFile(keyFilePath, keyFile, 0o600))
+	passwordPath := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordPath, []byte("passphrase\n"), 0o600))
+
+	tests := []struct {
+		name   string
+		config SignerConfig
+	}{
+		{"key", SignerConfig{Type: SignerTypeKey, PrivateKey: "0x" + hex.EncodeToString(crypto.FromECDSA(key))}},
+		{"keyfile", SignerConfig{Type: SignerTypeKeyFile, KeyFile: keyFilePath, KeyPasswordFile: passwordPath}},
+		{"remote", SignerConfig{Type: SignerTypeRemote, URL: newRemoteSignerServer(t, key), Address: address.Hex()}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			signer, err := NewSigner(ctx, test.config, nil)
+			require.NoError(tt, err)
+			requireWalletSigns(tt, signer, key)
+		})
+	}
+
+	t.Run("rejects a wrong passphrase", func(tt *testing.T) {
+		wrong := filepath.Join(tt.TempDir(), "password")
+		require.NoError(tt, os.WriteFile(wrong, []byte("wrong"), 0o600))
+		_, err := NewSigner(ctx, SignerConfig{Type: SignerTypeKeyFile, KeyFile: keyFilePath, KeyPasswordFile: wrong}, nil)
+		assert.Error(tt, err)
+	})
+
+	t.Run("rejects a remote signer of another account", func(tt *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(tt, err)
+		_, err = NewSigner(ctx, SignerConfig{Type: SignerTypeRemote, URL: newRemoteSignerServer(tt, other), Address: address.Hex()}, nil)
+		assert.ErrorContains(tt, err, "does not sign for")
+	})
+}
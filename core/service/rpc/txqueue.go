@@ -0,0 +1,402 @@
+package rpc
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultResubmitAfter  = time.Minute
+	defaultGasBumpPercent = 15
+	defaultPollInterval   = time.Second
+	// minGasBumpPercent is the price increase nodes require to accept a replacement transaction
+	minGasBumpPercent = 10
+	// gapGasLimit is the gas of the self transfers that fill nonce gaps
+	gapGasLimit = 21_000
+	// maxReceiptMisses is the number of polls a consumed nonce may lack a receipt of a known transaction, to allow
+	// for nodes that index receipts after the block
+	maxReceiptMisses = 3
+)
+
+// ErrTxReplaced is returned when the nonce of a transaction was consumed by a transaction unknown to the queue
+var ErrTxReplaced = errors.New("nonce of transaction was consumed by another transaction")
+
+// errFeeCapReached is returned when a transaction cannot be replaced without exceeding the fee cap
+var errFeeCapReached = errors.New("fee cap reached")
+
+// TxBackend is the part of a chain client the transaction queue submits and tracks transactions with
+type TxBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+type TxQueueConfig struct {
+	// ResubmitAfter is how long a transaction may stay pending before it is replaced with a higher gas price
+	ResubmitAfter time.Duration
+	// GasBumpPercent is the gas price increase of a replacement, at least 10 as required by the nodes
+	GasBumpPercent int64
+	// PollInterval is the interval receipts are polled with while waiting for a transaction
+	PollInterval time.Duration
+	// MaxFeePerGas caps the fee per gas, or gas price, replacements are bumped to, if set
+	MaxFeePerGas *big.Int
+}
+
+func (c TxQueueConfig) withDefaults() TxQueueConfig {
+	if c.ResubmitAfter <= 0 {
+		c.ResubmitAfter = defaultResubmitAfter
+	}
+	if c.GasBumpPercent == 0 {
+		c.GasBumpPercent = defaultGasBumpPercent
+	}
+	if c.GasBumpPercent < minGasBumpPercent {
+		c.GasBumpPercent = minGasBumpPercent
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	return c
+}
+
+// pendingTx is a transaction sent by the queue that is not yet known to be mined
+type pendingTx struct {
+	// tx is the latest version of the transaction
+	tx *types.Transaction
+	// hashes of all versions of the transaction, any of which may get mined
+	hashes []common.Hash
+	sentAt time.Time
+}
+
+// TxQueue hands out the nonces of an account and serialises the submission of its transactions, so that concurrent
+// callers never share a nonce. It keeps track of the transactions it sent and replaces those that get stuck with
+// gas-bumped resubmissions.
+type TxQueue struct {
+	backend TxBackend
+	from    common.Address
+	signer  bind.SignerFn
+	config  TxQueueConfig
+
+	mu sync.Mutex
+	// nonce is the next nonce to hand out, valid if synced
+	nonce   uint64
+	synced  bool
+	pending map[uint64]*pendingTx
+}
+
+func NewTxQueue(backend TxBackend, from common.Address, signer bind.SignerFn, config TxQueueConfig) *TxQueue {
+	return &TxQueue{
+		backend: backend,
+		from:    from,
+		signer:  signer,
+		config:  config.withDefaults(),
+		pending: make(map[uint64]*pendingTx),
+	}
+}
+
+// Submit sends the transaction built by send with the next nonce of the account. If the node rejects the nonce, the
+// queue resynchronises with the pending nonce of the chain and sends the transaction once more.
+func (q *TxQueue) Submit(ctx context.Context, opts *bind.TransactOpts, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if err := q.sync(ctx); err != nil {
+			return nil, err
+		}
+
+		opts.Nonce = new(big.Int).SetUint64(q.nonce)
+		tx, err := send(opts)
+		if err == nil {
+			q.pending[tx.Nonce()] = &pendingTx{tx: tx, hashes: []common.Hash{tx.Hash()}, sentAt: time.Now()}
+			q.nonce++
+			return tx, nil
+		}
+		if attempt > 0 || !isNonceError(err) {
+			return nil, err
+		}
+
+		logrus.WithError(err).Warnf("nonce %d of %s was rejected, resynchronising with the chain", q.nonce, q.from)
+		q.synced = false
+	}
+}
+
+// WaitMined waits until the transaction, or one of its replacements, is mined. Meanwhile, stuck transactions of the
+// account are resubmitted with a higher gas price, including those of lower nonces that hold the transaction back.
+func (q *TxQueue) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	hashes := []common.Hash{tx.Hash()}
+	misses := 0
+	for {
+		hashes = q.hashes(tx.Nonce(), hashes)
+
+		// the nonce is read before the receipts, so that a consumed nonce implies a receipt of a known transaction
+		confirmed, nonceErr := q.backend.NonceAt(ctx, q.from, nil)
+
+		receipt, err := q.receipt(ctx, hashes)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			q.forget(tx.Nonce())
+			return receipt, nil
+		}
+
+		switch {
+		case nonceErr != nil:
+			logrus.WithError(nonceErr).Debugf("could not get the confirmed nonce of %s", q.from)
+		case confirmed > tx.Nonce():
+			if misses++; misses >= maxReceiptMisses {
+				q.forget(tx.Nonce())
+				return nil, errors.Wrapf(ErrTxReplaced, "transaction %s", tx.Hash())
+			}
+		default:
+			if err = q.resubmit(ctx, confirmed); err != nil {
+				logrus.WithError(err).Warnf("could not resubmit stuck transactions of %s", q.from)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Hashes returns the hashes of a transaction sent by the queue and of the replacements sent for it so far, oldest first
+func (q *TxQueue) Hashes(tx *types.Transaction) []common.Hash {
+	return q.hashes(tx.Nonce(), []common.Hash{tx.Hash()})
+}
+
+// sync sets the next nonce from the pending nonce of the chain, unless it is already in sync
+func (q *TxQueue) sync(ctx context.Context) error {
+	if q.synced {
+		return nil
+	}
+	nonce, err := q.backend.PendingNonceAt(ctx, q.from)
+	if err != nil {
+		return errors.Wrap(err, "unable to derive nonce")
+	}
+	q.nonce = nonce
+	q.synced = true
+	return nil
+}
+
+// receipt returns the receipt of the first mined transaction, or nil if none is mined yet
+func (q *TxQueue) receipt(ctx context.Context, hashes []common.Hash) (*types.Receipt, error) {
+	for _, hash := range hashes {
+		receipt, err := q.backend.TransactionReceipt(ctx, hash)
+		if err == nil && receipt != nil {
+			return receipt, nil
+		}
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logrus.WithError(err).Debugf("could not get receipt of transaction %s", hash)
+		}
+	}
+	return nil, nil
+}
+
+// hashes adds the hashes of the resubmissions of a nonce to the known ones
+func (q *TxQueue) hashes(nonce uint64, known []common.Hash) []common.Hash {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// a resynchronised queue may have handed out the nonce again, to a transaction that is not a resubmission
+	p, ok := q.pending[nonce]
+	if !ok || p.hashes[0] != known[0] || len(p.hashes) <= len(known) {
+		return known
+	}
+	return append([]common.Hash(nil), p.hashes...)
+}
+
+func (q *TxQueue) forget(nonce uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, nonce)
+}
+
+// resubmit replaces the pending transactions from the confirmed nonce on once one of them got stuck. Nonces in
+// between that are unknown to the queue, e.g. of transactions sent before a restart, are filled with self transfers.
+func (q *TxQueue) resubmit(ctx context.Context, confirmed uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	nonces := make([]uint64, 0, len(q.pending))
+	stuck := false
+	for nonce, p := range q.pending {
+		if nonce < confirmed {
+			delete(q.pending, nonce)
+			continue
+		}
+		nonces = append(nonces, nonce)
+		stuck = stuck || time.Since(p.sentAt) >= q.config.ResubmitAfter
+	}
+	if !stuck {
+		return nil
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	for nonce := confirmed; nonce <= nonces[len(nonces)-1]; nonce++ {
+		p, ok := q.pending[nonce]
+		if ok && time.Since(p.sentAt) < q.config.ResubmitAfter {
+			continue
+		}
+
+		var replacement *types.Transaction
+		var err error
+		if ok {
+			replacement, err = q.bump(ctx, p.tx)
+		} else {
+			replacement, err = q.fill(ctx, nonce)
+		}
+		if errors.Is(err, errFeeCapReached) {
+			logrus.Debugf("transaction with nonce %d of %s is stuck at the fee cap", nonce, q.from)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "building replacement of nonce %d", nonce)
+		}
+		if err = q.backend.SendTransaction(ctx, replacement); err != nil && !isKnownError(err) {
+			return errors.Wrapf(err, "sending replacement of nonce %d", nonce)
+		}
+
+		logrus.Infof("Resubmitted transaction %s with nonce %d and gas price %s", replacement.Hash(), nonce, replacement.GasFeeCap())
+		if !ok {
+			p = &pendingTx{}
+			q.pending[nonce] = p
+		}
+		p.tx = replacement
+		p.hashes = append(p.hashes, replacement.Hash())
+		p.sentAt = time.Now()
+	}
+	return nil
+}
+
+// bump signs a copy of a transaction with its gas price raised by the configured percentage, or to the suggested
+// gas price if that is higher, without exceeding the fee cap
+func (q *TxQueue) bump(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	suggested, err := q.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get gas price")
+	}
+
+	var data types.TxData
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		feeCap, err := q.capped(maxBig(q.bumped(tx.GasFeeCap()), suggested), tx.GasFeeCap())
+		if err != nil {
+			return nil, err
+		}
+		tip, err := q.capped(minBig(q.bumped(tx.GasTipCap()), feeCap), tx.GasTipCap())
+		if err != nil {
+			return nil, err
+		}
+		data = &types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tip,
+			GasFeeCap:  feeCap,
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}
+	default:
+		gasPrice, err := q.capped(maxBig(q.bumped(tx.GasPrice()), suggested), tx.GasPrice())
+		if err != nil {
+			return nil, err
+		}
+		data = &types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}
+	}
+	return q.signer(q.from, types.NewTx(data))
+}
+
+// fill signs an empty self transfer for a nonce, priced above the suggested gas price to replace whatever holds it
+func (q *TxQueue) fill(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+	suggested, err := q.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get gas price")
+	}
+	return q.signer(q.from, types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: minBig(q.bumped(suggested), q.config.MaxFeePerGas),
+		Gas:      gapGasLimit,
+		To:       &q.from,
+		Value:    new(big.Int),
+	}))
+}
+
+func (q *TxQueue) bumped(price *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(price, big.NewInt(100+q.config.GasBumpPercent))
+	bumped.Div(bumped, big.NewInt(100))
+	// integer division must not swallow the increase of tiny prices
+	if bumped.Cmp(price) <= 0 {
+		bumped.Add(price, big.NewInt(1))
+	}
+	return bumped
+}
+
+// capped limits a bumped price to the fee cap. A price capped below the increase nodes require of a replacement
+// cannot replace the transaction.
+func (q *TxQueue) capped(price, current *big.Int) (*big.Int, error) {
+	price = minBig(price, q.config.MaxFeePerGas)
+	required := new(big.Int).Mul(current, big.NewInt(100+minGasBumpPercent))
+	if price.Cmp(required.Div(required, big.NewInt(100))) < 0 || price.Cmp(current) <= 0 {
+		return nil, errFeeCapReached
+	}
+	return price, nil
+}
+
+// minBig returns the lower value, ignoring an unset or zero limit b
+func minBig(a, b *big.Int) *big.Int {
+	if b != nil && b.Sign() > 0 && b.Cmp(a) < 0 {
+		return b
+	}
+	return a
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return b
+	}
+	return a
+}
+
+// isNonceError reports whether a node rejected a transaction because of its nonce
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// isKnownError reports whether a node rejected a transaction because it is already in its pool
+func isKnownError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
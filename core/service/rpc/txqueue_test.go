@@ -0,0 +1,306 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var chainID = big.NewInt(1337)
+
+// fakeBackend is a chain with a transaction pool that is mined on demand
+type fakeBackend struct {
+	mu        sync.Mutex
+	gasPrice  *big.Int
+	confirmed uint64
+	pool      map[uint64]*types.Transaction
+	mined     map[common.Hash]*types.Transaction
+	receipts  map[common.Hash]*types.Receipt
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		gasPrice: big.NewInt(100),
+		pool:     make(map[uint64]*types.Transaction),
+		mined:    make(map[common.Hash]*types.Transaction),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (b *fakeBackend) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	nonce := b.confirmed
+	for b.pool[nonce] != nil {
+		nonce++
+	}
+	return nonce, nil
+}
+
+func (b *fakeBackend) NonceAt(_ context.Context, _ common.Address, _ *big.Int) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.confirmed, nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.gasPrice, nil
+}
+
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if tx.Nonce() < b.confirmed {
+		return errors.New("nonce too low")
+	}
+	if existing := b.pool[tx.Nonce()]; existing != nil {
+		if existing.Hash() == tx.Hash() {
+			return errors.New("already known")
+		}
+		if tx.GasPrice().Cmp(existing.GasPrice()) <= 0 {
+			return errors.New("replacement transaction underpriced")
+		}
+	}
+	b.pool[tx.Nonce()] = tx
+	return nil
+}
+
+func (b *fakeBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if receipt, ok := b.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// mine includes all transactions of consecutive nonces in a block
+func (b *fakeBackend) mine() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for tx := b.pool[b.confirmed]; tx != nil; tx = b.pool[b.confirmed] {
+		b.mined[tx.Hash()] = tx
+		b.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}
+		delete(b.pool, b.confirmed)
+		b.confirmed++
+	}
+}
+
+func (b *fakeBackend) pooled(nonce uint64) *types.Transaction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pool[nonce]
+}
+
+func (b *fakeBackend) minedTx(hash common.Hash) *types.Transaction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.mined[hash]
+}
+
+func newTestQueue(t *testing.T, backend *fakeBackend) (*TxQueue, *bind.TransactOpts) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	require.NoError(t, err)
+	queue := NewTxQueue(backend, auth.From, auth.Signer, TxQueueConfig{
+		ResubmitAfter: 10 * time.Millisecond,
+		PollInterval:  time.Millisecond,
+	})
+	return queue, auth
+}
+
+// sendTo returns a send function that transfers to an address with the nonce of the options
+func sendTo(backend *fakeBackend, to common.Address) func(*bind.TransactOpts) (*types.Transaction, error) {
+	return func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		tx, err := opts.Signer(opts.From, types.NewTx(&types.LegacyTx{
+			Nonce:    opts.Nonce.Uint64(),
+			GasPrice: big.NewInt(100),
+			Gas:      21_000,
+			To:       &to,
+			Value:    new(big.Int),
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return tx, backend.SendTransaction(context.Background(), tx)
+	}
+}
+
+func TestTxQueue(t *testing.T) {
+	recipient := common.HexToAddress("0xd231120Eea6201B142b4048cf6C86BaC2A0655D2")
+
+	t.Run("concurrent submissions get consecutive nonces", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+
+		var mu sync.Mutex
+		var nonces []uint64
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				opts := *auth
+				tx, err := queue.Submit(context.Background(), &opts, sendTo(backend, recipient))
+				assert.NoError(tt, err)
+				mu.Lock()
+				nonces = append(nonces, tx.Nonce())
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+		for i, nonce := range nonces {
+			assert.Equal(tt, uint64(i), nonce)
+		}
+	})
+
+	t.Run("resynchronises after a rejected nonce", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+
+		_, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+
+		// another process of the same account takes the next nonce
+		_, err = sendTo(backend, common.Address{})(&bind.TransactOpts{From: auth.From, Signer: auth.Signer, Nonce: big.NewInt(1)})
+		require.NoError(tt, err)
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(2), tx.Nonce())
+	})
+
+	t.Run("waits for the receipt", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+		backend.mine()
+
+		receipt, err := queue.WaitMined(context.Background(), tx)
+		require.NoError(tt, err)
+		assert.Equal(tt, tx.Hash(), receipt.TxHash)
+	})
+
+	t.Run("replaces a stuck transaction with a higher gas price", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+
+		receipts := make(chan *types.Receipt)
+		go func() {
+			receipt, err := queue.WaitMined(context.Background(), tx)
+			assert.NoError(tt, err)
+			receipts <- receipt
+		}()
+
+		require.Eventually(tt, func() bool { return backend.pooled(0).Hash() != tx.Hash() }, time.Second, time.Millisecond)
+		backend.mine()
+
+		receipt := <-receipts
+		replacement := backend.minedTx(receipt.TxHash)
+		require.NotNil(tt, replacement)
+		assert.NotEqual(tt, tx.Hash(), replacement.Hash())
+		assert.GreaterOrEqual(tt, replacement.GasPrice().Int64(), int64(115))
+		assert.Equal(tt, tx.Nonce(), replacement.Nonce())
+		assert.Equal(tt, tx.To(), replacement.To())
+	})
+
+	t.Run("does not bump beyond the fee cap", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+		queue.config.MaxFeePerGas = big.NewInt(105)
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = queue.WaitMined(ctx, tx)
+		assert.ErrorIs(tt, err, context.DeadlineExceeded)
+		assert.Equal(tt, tx.Hash(), backend.pooled(0).Hash())
+	})
+
+	t.Run("fills a nonce gap held by an unknown transaction", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+
+		// a transaction sent before a restart holds the first nonce
+		unknown, err := sendTo(backend, common.Address{})(&bind.TransactOpts{From: auth.From, Signer: auth.Signer, Nonce: big.NewInt(0)})
+		require.NoError(tt, err)
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(1), tx.Nonce())
+
+		receipts := make(chan *types.Receipt)
+		go func() {
+			receipt, err := queue.WaitMined(context.Background(), tx)
+			assert.NoError(tt, err)
+			receipts <- receipt
+		}()
+
+		require.Eventually(tt, func() bool { return backend.pooled(0).Hash() != unknown.Hash() }, time.Second, time.Millisecond)
+		filler := backend.pooled(0)
+		assert.Equal(tt, auth.From, *filler.To())
+		assert.Zero(tt, filler.Value().Sign())
+		backend.mine()
+
+		receipt := <-receipts
+		mined := backend.minedTx(receipt.TxHash)
+		require.NotNil(tt, mined)
+		assert.Equal(tt, tx.Nonce(), mined.Nonce())
+		assert.Equal(tt, recipient, *mined.To())
+	})
+
+	t.Run("nonce consumed by another transaction", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+		queue.config.ResubmitAfter = time.Hour
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+
+		// another process of the same account replaces the transaction
+		other, err := auth.Signer(auth.From, types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(200), Gas: 21_000}))
+		require.NoError(tt, err)
+		require.NoError(tt, backend.SendTransaction(context.Background(), other))
+		backend.mine()
+
+		_, err = queue.WaitMined(context.Background(), tx)
+		assert.ErrorIs(tt, err, ErrTxReplaced)
+	})
+
+	t.Run("stops waiting with the context", func(tt *testing.T) {
+		backend := newFakeBackend()
+		queue, auth := newTestQueue(tt, backend)
+
+		tx, err := queue.Submit(context.Background(), auth, sendTo(backend, recipient))
+		require.NoError(tt, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = queue.WaitMined(ctx, tx)
+		assert.ErrorIs(tt, err, context.DeadlineExceeded)
+	})
+}
@@ -2,47 +2,52 @@ package rpc
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/fapiper/onchain-access-control/core/tx"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/pkg/errors"
-	"log"
 	"math/big"
 )
 
 type Wallet struct {
-	ChainID    *big.Int
-	PrivateKey *ecdsa.PrivateKey
-	PublicKey  *ecdsa.PublicKey
-	Address    common.Address
-	Client     *ethclient.Client
+	ChainID *big.Int
+	Signer  Signer
+	Address common.Address
+	Client  Backend
+	fees    tx.FeeConfig
+	queue   *TxQueue
 }
 
-func NewWallet(privateKey string, chainID uint64) (*Wallet, error) {
-	ethClient := NewEthClient()
+type WalletConfig struct {
+	Fees  tx.FeeConfig
+	Queue TxQueueConfig
+}
 
-	privateKeyECDSA, err := crypto.HexToECDSA(privateKey)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not generate private key")
+// NewWallet returns a wallet of the account of signer that sends its transactions to backend
+func NewWallet(backend Backend, signer Signer, chainID uint64, config WalletConfig) (*Wallet, error) {
+	if backend == nil {
+		return nil, errors.New("no chain backend configured")
 	}
-
-	publicKey := privateKeyECDSA.Public()
-
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, errors.Wrap(err, "unable to derive public key")
+	if signer == nil {
+		return nil, errors.New("no signer configured")
 	}
 
 	wallet := Wallet{
-		Client:     ethClient,
-		ChainID:    new(big.Int).SetUint64(chainID),
-		PrivateKey: privateKeyECDSA,
-		PublicKey:  publicKeyECDSA,
-		Address:    crypto.PubkeyToAddress(*publicKeyECDSA),
+		Client:  backend,
+		ChainID: new(big.Int).SetUint64(chainID),
+		Signer:  signer,
+		Address: signer.Address(),
+		fees:    config.Fees.WithDefaults(),
+	}
+
+	if config.Queue.MaxFeePerGas == nil {
+		config.Queue.MaxFeePerGas = config.Fees.MaxFeePerGas
 	}
+	wallet.queue = NewTxQueue(backend, wallet.Address, wallet.signerFn(nil), config.Queue)
 	return &wallet, nil
 }
 
@@ -52,29 +57,66 @@ func (w Wallet) ToCallOpts() *bind.CallOpts {
 	}
 }
 
-func (w Wallet) ToTransactOpts() (*bind.TransactOpts, error) {
-
-	nonce, err := w.Client.PendingNonceAt(context.Background(), w.Address)
+// ToTransactOpts returns the options of a transaction of the wallet, priced with the configured fee mode. The nonce
+// is left unset, transactions sent with Transact get theirs from the transaction queue. So is the gas limit, which
+// Transact estimates per transaction.
+func (w Wallet) ToTransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	fees, err := tx.SuggestFees(ctx, w.Client, w.fees)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to derive nonce")
+		return nil, errors.Wrap(err, "could not suggest fees, quitting")
 	}
 
-	gasPrice, err := w.Client.SuggestGasPrice(context.Background())
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get gas price, quitting")
-	}
+	auth := &bind.TransactOpts{From: w.Address}
+	auth.Signer = w.signerFn(auth)
+	auth.Value = big.NewInt(0) // in wei
+	fees.ApplyFees(auth)
+
+	return auth, nil
+}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(w.PrivateKey, w.ChainID)
+// Transact sends the transaction built by send through the transaction queue of the wallet, which assigns its nonce.
+// It is safe for concurrent use.
+func (w Wallet) Transact(ctx context.Context, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	opts, err := w.ToTransactOpts(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create authorized transactor: %v", err)
+		return nil, err
 	}
+	opts.Context = ctx
+	return w.queue.Submit(ctx, opts, tx.WithGasLimitMargin(w.Client, w.fees.GasLimitMargin, send))
+}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)        // in wei
-	auth.GasLimit = uint64(8_000_000) // in units
-	auth.GasPrice = gasPrice
+// WaitMined waits until a transaction sent with Transact, or its gas-bumped replacement, is mined
+func (w Wallet) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	return w.queue.WaitMined(ctx, tx)
+}
 
-	return auth, nil
+// TransactionHashes returns the hashes of a transaction sent with Transact and of its gas-bumped replacements so far
+func (w Wallet) TransactionHashes(tx *types.Transaction) []common.Hash {
+	return w.queue.Hashes(tx)
+}
+
+// TokenKey returns the algorithm and the key session tokens of the wallet are signed with, if its signer signs them
+func (w Wallet) TokenKey(ctx context.Context) (jwa.SignatureAlgorithm, any, error) {
+	signer, ok := w.Signer.(TokenSigner)
+	if !ok {
+		return "", nil, ErrNoTokenKey
+	}
+	alg, key := signer.TokenKey(ctx)
+	return alg, key, nil
+}
+
+// signerFn returns the signer function of transaction options, which signs with the context of opts if set
+func (w Wallet) signerFn(opts *bind.TransactOpts) bind.SignerFn {
+	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != w.Address {
+			return nil, bind.ErrNotAuthorized
+		}
+		ctx := context.Background()
+		if opts != nil && opts.Context != nil {
+			ctx = opts.Context
+		}
+		return w.Signer.SignTx(ctx, tx, w.ChainID)
+	}
 }
 
 func (w Wallet) GetDID() string {
@@ -84,3 +126,8 @@ func (w Wallet) GetDIDHash() common.Hash {
 	did := w.GetDID()
 	return crypto.Keccak256Hash([]byte(did))
 }
+
+// GetKID returns the id of the verification method of the wallet DID, which references the blockchain account
+func (w Wallet) GetKID() string {
+	return w.GetDID() + "#blockchainAccountId"
+}
@@ -291,13 +291,21 @@ func (b *BoltDB) ReadAllKeys(_ context.Context, namespace string) ([]string, err
 }
 
 func (b *BoltDB) Delete(_ context.Context, namespace, key string) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.db.Update(deleteFunc(namespace, key))
+}
+
+func (btx *boltTx) Delete(_ context.Context, namespace, key string) error {
+	return deleteFunc(namespace, key)(btx.tx)
+}
+
+func deleteFunc(namespace string, key string) func(tx *bolt.Tx) error {
+	return func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(namespace))
 		if bucket == nil {
 			return sdkutil.LoggingNewErrorf("namespace<%s> does not exist", namespace)
 		}
 		return bucket.Delete([]byte(key))
-	})
+	}
 }
 
 func (b *BoltDB) DeleteNamespace(_ context.Context, namespace string) error {
@@ -143,6 +143,10 @@ func (m encryptedTx) Write(ctx context.Context, namespace, key string, value []b
 	return m.tx.Write(ctx, namespace, key, encryptedData)
 }
 
+func (m encryptedTx) Delete(ctx context.Context, namespace, key string) error {
+	return m.tx.Delete(ctx, namespace, key)
+}
+
 func (e EncryptedWrapper) Execute(ctx context.Context, businessLogicFunc BusinessLogicFunc, watchKeys []WatchKey) (any, error) {
 	return e.s.Execute(ctx, func(ctx context.Context, tx Tx) (any, error) {
 		return businessLogicFunc(ctx, encryptedTx{tx: tx, encrypter: e.encrypter})
@@ -62,6 +62,10 @@ func (rtx *redisTx) Write(ctx context.Context, namespace, key string, value []by
 	return rtx.pipe.Set(ctx, nameSpaceKey, value, 0).Err()
 }
 
+func (rtx *redisTx) Delete(ctx context.Context, namespace, key string) error {
+	return rtx.pipe.Del(ctx, getRedisKey(namespace, key)).Err()
+}
+
 func (b *RedisDB) Init(opts ...Option) error {
 	address, password, err := processRedisOptions(opts...)
 	if err != nil {
@@ -443,6 +443,11 @@ func (s *sqlTx) Write(ctx context.Context, namespace, key string, value []byte)
 	return write(ctx, s.tx, namespace, key, value)
 }
 
+func (s *sqlTx) Delete(ctx context.Context, namespace, key string) error {
+	_, err := s.tx.ExecContext(ctx, "DELETE FROM key_values WHERE key = $1", Join(namespace, key))
+	return err
+}
+
 func (s *SQLDB) Execute(ctx context.Context, businessLogicFunc BusinessLogicFunc, _ []WatchKey) (any, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
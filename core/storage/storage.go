@@ -21,6 +21,7 @@ type WatchKey struct {
 
 type Tx interface {
 	Write(ctx context.Context, namespace, key string, value []byte) error
+	Delete(ctx context.Context, namespace, key string) error
 }
 
 const (
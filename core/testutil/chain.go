@@ -0,0 +1,32 @@
+package testutil
+
+import (
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+// SimulatedGasLimit is the block gas limit of the simulated chains, which fits the deployment of the contracts
+const SimulatedGasLimit = 30_000_000
+
+// NewFundedChain returns a simulated chain on which address holds 1000 ether. The chain is closed with the test.
+func NewFundedChain(t *testing.T, address common.Address) *backends.SimulatedBackend {
+	balance := new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		address: {Balance: balance},
+	}, SimulatedGasLimit)
+	t.Cleanup(func() { _ = sim.Close() })
+	return sim
+}
+
+// NewFundedKey returns a new key and a simulated chain on which the address of the key holds 1000 ether
+func NewFundedKey(t *testing.T) (*ecdsa.PrivateKey, *backends.SimulatedBackend) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return key, NewFundedChain(t, crypto.PubkeyToAddress(key.PublicKey))
+}
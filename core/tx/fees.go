@@ -0,0 +1,217 @@
+package tx
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// FeeMode is the type of transaction fees are priced for
+type FeeMode string
+
+const (
+	// FeeModeDynamic prices dynamic fee (EIP-1559) transactions, falling back to legacy on chains without a base fee
+	FeeModeDynamic FeeMode = "dynamic"
+	// FeeModeLegacy prices legacy transactions with a gas price
+	FeeModeLegacy FeeMode = "legacy"
+)
+
+const (
+	defaultGasLimitMargin   = 20
+	defaultHistoryBlocks    = 10
+	defaultRewardPercentile = 50
+	// baseFeeMultiplier leaves room for the base fee to rise over several full blocks before the fee cap is reached
+	baseFeeMultiplier = 2
+)
+
+// ErrUnknownFeeMode is returned for fee modes other than dynamic and legacy
+var ErrUnknownFeeMode = errors.New("unknown fee mode")
+
+// FeeConfig configures the gas limit and fees of transactions
+type FeeConfig struct {
+	Mode FeeMode
+	// GasLimitMargin is the percentage added to the estimated gas of a transaction
+	GasLimitMargin int64
+	// MaxFeePerGas caps the fee per gas of dynamic fee transactions and the gas price of legacy ones, if set
+	MaxFeePerGas *big.Int
+	// MaxPriorityFeePerGas caps the priority fee per gas, if set
+	MaxPriorityFeePerGas *big.Int
+	// HistoryBlocks is the number of recent blocks the priority fee is derived from
+	HistoryBlocks uint64
+	// RewardPercentile is the percentile of the priority fees paid within those blocks
+	RewardPercentile float64
+}
+
+// WithDefaults returns the config with defaults for the unset values
+func (c FeeConfig) WithDefaults() FeeConfig {
+	if c.Mode == "" {
+		c.Mode = FeeModeDynamic
+	}
+	if c.GasLimitMargin <= 0 {
+		c.GasLimitMargin = defaultGasLimitMargin
+	}
+	if c.HistoryBlocks == 0 {
+		c.HistoryBlocks = defaultHistoryBlocks
+	}
+	if c.RewardPercentile <= 0 || c.RewardPercentile > 100 {
+		c.RewardPercentile = defaultRewardPercentile
+	}
+	return c
+}
+
+// FeeBackend provides the chain data transaction fees are derived from
+type FeeBackend interface {
+	ethereum.GasPricer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// SuggestFees returns the transaction arguments with the fees to send a transaction with. These are MaxFeePerGas and
+// MaxPriorityFeePerGas for dynamic fee transactions, or GasPrice for legacy transactions.
+func SuggestFees(ctx context.Context, backend FeeBackend, config FeeConfig) (*SendTxArgs, error) {
+	config = config.WithDefaults()
+	switch config.Mode {
+	case FeeModeLegacy:
+		return suggestLegacyFees(ctx, backend, config)
+	case FeeModeDynamic:
+		head, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get latest header")
+		}
+		if head.BaseFee == nil {
+			return suggestLegacyFees(ctx, backend, config)
+		}
+		return suggestDynamicFees(ctx, backend, config, head.BaseFee)
+	default:
+		return nil, errors.Wrapf(ErrUnknownFeeMode, "%q", config.Mode)
+	}
+}
+
+func suggestLegacyFees(ctx context.Context, backend FeeBackend, config FeeConfig) (*SendTxArgs, error) {
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get gas price")
+	}
+	return &SendTxArgs{GasPrice: (*hexutil.Big)(capped(gasPrice, config.MaxFeePerGas))}, nil
+}
+
+// suggestDynamicFees derives the priority fee from the rewards paid in recent blocks and the fee cap from the base fee
+// of the next block
+func suggestDynamicFees(ctx context.Context, backend FeeBackend, config FeeConfig, baseFee *big.Int) (*SendTxArgs, error) {
+	history, err := backend.FeeHistory(ctx, config.HistoryBlocks, nil, []float64{config.RewardPercentile})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get fee history")
+	}
+
+	tip := averageReward(history)
+	if tip == nil {
+		if tip, err = backend.SuggestGasTipCap(ctx); err != nil {
+			return nil, errors.Wrap(err, "could not get gas tip cap")
+		}
+	}
+	tip = capped(tip, config.MaxPriorityFeePerGas)
+
+	// the fee history includes the base fee of the block after the newest one
+	if n := len(history.BaseFee); n > 0 && history.BaseFee[n-1] != nil {
+		baseFee = history.BaseFee[n-1]
+	}
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(baseFeeMultiplier))
+	feeCap = capped(feeCap.Add(feeCap, tip), config.MaxFeePerGas)
+	tip = capped(tip, feeCap)
+
+	return &SendTxArgs{
+		MaxFeePerGas:         (*hexutil.Big)(feeCap),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tip),
+	}, nil
+}
+
+// averageReward returns the average of the rewards within a fee history, or nil if it holds none
+func averageReward(history *ethereum.FeeHistory) *big.Int {
+	sum, count := new(big.Int), int64(0)
+	for _, rewards := range history.Reward {
+		if len(rewards) == 0 || rewards[0] == nil {
+			continue
+		}
+		sum.Add(sum, rewards[0])
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	return sum.Div(sum, big.NewInt(count))
+}
+
+// ApplyFees sets the fees of the arguments on transaction options
+func (args SendTxArgs) ApplyFees(opts *bind.TransactOpts) {
+	if args.IsDynamicFeeTx() {
+		opts.GasFeeCap = (*big.Int)(args.MaxFeePerGas)
+		opts.GasTipCap = (*big.Int)(args.MaxPriorityFeePerGas)
+		opts.GasPrice = nil
+		return
+	}
+	if args.GasPrice != nil {
+		opts.GasPrice = (*big.Int)(args.GasPrice)
+	}
+}
+
+// WithGasLimitMargin wraps a function sending a contract transaction. Unless the options set a gas limit, the call
+// data packed by the contract binding is captured without signing the transaction, its gas is estimated by the
+// backend, and the transaction is then signed and sent once with the estimate raised by a margin in percent.
+func WithGasLimitMargin(backend ethereum.GasEstimator, margin int64, send func(*bind.TransactOpts) (*types.Transaction, error)) func(*bind.TransactOpts) (*types.Transaction, error) {
+	return func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		if opts.GasLimit != 0 || opts.NoSend {
+			return send(opts)
+		}
+
+		call, err := packCall(opts, send)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not pack transaction")
+		}
+		ctx := opts.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		gas, err := backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:      opts.From,
+			To:        call.To(),
+			GasPrice:  opts.GasPrice,
+			GasFeeCap: opts.GasFeeCap,
+			GasTipCap: opts.GasTipCap,
+			Value:     call.Value(),
+			Data:      call.Data(),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not estimate gas")
+		}
+
+		limited := *opts
+		limited.GasLimit = gas + gas*uint64(margin)/100
+		return send(&limited)
+	}
+}
+
+// packCall returns the unsigned transaction a contract binding builds for the options. A placeholder gas limit keeps
+// the binding from estimating the gas itself.
+func packCall(opts *bind.TransactOpts, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	capture := *opts
+	capture.NoSend = true
+	capture.GasLimit = 1
+	capture.Signer = func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		return tx, nil
+	}
+	return send(&capture)
+}
+
+// capped returns the value, or the limit if it is set and lower
+func capped(value, limit *big.Int) *big.Int {
+	if limit != nil && limit.Sign() > 0 && value.Cmp(limit) > 0 {
+		return new(big.Int).Set(limit)
+	}
+	return value
+}
@@ -0,0 +1,174 @@
+package tx
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeeBackend struct {
+	baseFee  *big.Int
+	rewards  []*big.Int
+	gasPrice *big.Int
+	tipCap   *big.Int
+}
+
+func (b fakeFeeBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return b.gasPrice, nil
+}
+
+func (b fakeFeeBackend) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return b.tipCap, nil
+}
+
+func (b fakeFeeBackend) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: b.baseFee}, nil
+}
+
+func (b fakeFeeBackend) FeeHistory(_ context.Context, _ uint64, _ *big.Int, _ []float64) (*ethereum.FeeHistory, error) {
+	history := &ethereum.FeeHistory{BaseFee: []*big.Int{b.baseFee}}
+	for _, reward := range b.rewards {
+		history.Reward = append(history.Reward, []*big.Int{reward})
+	}
+	return history, nil
+}
+
+func TestSuggestFees(t *testing.T) {
+	backend := fakeFeeBackend{
+		baseFee:  big.NewInt(100),
+		rewards:  []*big.Int{big.NewInt(2), big.NewInt(4)},
+		gasPrice: big.NewInt(150),
+		tipCap:   big.NewInt(7),
+	}
+
+	t.Run("dynamic fees from the fee history", func(tt *testing.T) {
+		fees, err := SuggestFees(context.Background(), backend, FeeConfig{})
+		require.NoError(tt, err)
+		assert.True(tt, fees.IsDynamicFeeTx())
+		assert.Equal(tt, big.NewInt(3), (*big.Int)(fees.MaxPriorityFeePerGas))
+		assert.Equal(tt, big.NewInt(203), (*big.Int)(fees.MaxFeePerGas))
+	})
+
+	t.Run("suggested tip without rewards", func(tt *testing.T) {
+		b := backend
+		b.rewards = nil
+		fees, err := SuggestFees(context.Background(), b, FeeConfig{})
+		require.NoError(tt, err)
+		assert.Equal(tt, big.NewInt(7), (*big.Int)(fees.MaxPriorityFeePerGas))
+	})
+
+	t.Run("capped dynamic fees", func(tt *testing.T) {
+		fees, err := SuggestFees(context.Background(), backend, FeeConfig{
+			MaxFeePerGas:         big.NewInt(150),
+			MaxPriorityFeePerGas: big.NewInt(1),
+		})
+		require.NoError(tt, err)
+		assert.Equal(tt, big.NewInt(1), (*big.Int)(fees.MaxPriorityFeePerGas))
+		assert.Equal(tt, big.NewInt(150), (*big.Int)(fees.MaxFeePerGas))
+	})
+
+	t.Run("legacy mode", func(tt *testing.T) {
+		fees, err := SuggestFees(context.Background(), backend, FeeConfig{Mode: FeeModeLegacy, MaxFeePerGas: big.NewInt(120)})
+		require.NoError(tt, err)
+		assert.False(tt, fees.IsDynamicFeeTx())
+		assert.Equal(tt, big.NewInt(120), (*big.Int)(fees.GasPrice))
+	})
+
+	t.Run("legacy fallback without base fee", func(tt *testing.T) {
+		b := backend
+		b.baseFee = nil
+		fees, err := SuggestFees(context.Background(), b, FeeConfig{Mode: FeeModeDynamic})
+		require.NoError(tt, err)
+		assert.False(tt, fees.IsDynamicFeeTx())
+		assert.Equal(tt, big.NewInt(150), (*big.Int)(fees.GasPrice))
+	})
+
+	t.Run("unknown mode", func(tt *testing.T) {
+		_, err := SuggestFees(context.Background(), backend, FeeConfig{Mode: "priority"})
+		assert.ErrorIs(tt, err, ErrUnknownFeeMode)
+	})
+}
+
+func TestApplyFees(t *testing.T) {
+	fees, err := SuggestFees(context.Background(), fakeFeeBackend{baseFee: big.NewInt(100), tipCap: big.NewInt(7)}, FeeConfig{})
+	require.NoError(t, err)
+
+	opts := &bind.TransactOpts{GasPrice: big.NewInt(1)}
+	fees.ApplyFees(opts)
+	assert.Nil(t, opts.GasPrice)
+	assert.Equal(t, big.NewInt(207), opts.GasFeeCap)
+	assert.Equal(t, big.NewInt(7), opts.GasTipCap)
+}
+
+// fakeGasEstimator estimates the gas of a call by the length of its call data
+type fakeGasEstimator struct {
+	calls []ethereum.CallMsg
+}
+
+func (e *fakeGasEstimator) EstimateGas(_ context.Context, call ethereum.CallMsg) (uint64, error) {
+	e.calls = append(e.calls, call)
+	return 1000 * uint64(len(call.Data)), nil
+}
+
+func TestWithGasLimitMargin(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1")
+
+	// send stands in for a contract binding, which packs the call and signs the transaction
+	var sent []*types.Transaction
+	signatures := 0
+	send := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		require.NotZero(t, opts.GasLimit, "the binding must not estimate the gas itself")
+		signer := opts.Signer
+		tx, err := signer(opts.From, types.NewTx(&types.LegacyTx{To: &to, Gas: opts.GasLimit, GasPrice: big.NewInt(1), Data: make([]byte, 50)}))
+		if err == nil && !opts.NoSend {
+			sent = append(sent, tx)
+		}
+		return tx, err
+	}
+	counting := *auth
+	counting.Signer = func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		signatures++
+		return auth.Signer(address, tx)
+	}
+
+	t.Run("estimated gas with margin", func(tt *testing.T) {
+		sent, signatures = nil, 0
+		estimator := fakeGasEstimator{}
+		tx, err := WithGasLimitMargin(&estimator, 20, send)(&counting)
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(60_000), tx.Gas())
+		assert.Len(tt, sent, 1)
+		assert.Equal(tt, 1, signatures)
+		assert.Zero(tt, counting.GasLimit)
+
+		require.Len(tt, estimator.calls, 1)
+		assert.Equal(tt, &to, estimator.calls[0].To)
+		assert.Equal(tt, auth.From, estimator.calls[0].From)
+		assert.Len(tt, estimator.calls[0].Data, 50)
+	})
+
+	t.Run("explicit gas limit", func(tt *testing.T) {
+		sent, signatures = nil, 0
+		estimator := fakeGasEstimator{}
+		opts := counting
+		opts.GasLimit = 30_000
+		tx, err := WithGasLimitMargin(&estimator, 20, send)(&opts)
+		require.NoError(tt, err)
+		assert.Equal(tt, uint64(30_000), tx.Gas())
+		assert.Len(tt, sent, 1)
+		assert.Equal(tt, 1, signatures)
+		assert.Empty(tt, estimator.calls)
+	})
+}
@@ -1,47 +1,51 @@
 curl --location --silent --request PUT 'http://127.0.0.1:3000/v1/auth/role/did:pkh:eip155:11155111:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2+ROLE_VERIFICATION_BODY_10' \
 --header 'Content-Type: application/json' \
 --data '{
-    "policy": "'"$1"'",
-    "proof": {
-        "a": {
-            "X": "0x207e9b1f9a9633d0a9086638aabee33c172ba94c713b3e04b4530cafd5fd042f",
-            "Y": "0x17af83f93b31de19741bf4a376d1caae08d6e517b2d772156cbe350681cd2e82"
-        },
-        "b": {
-            "X": [
-                "0x1292827c3f09eea38778bd54da04415036b1e206eee4bd8877a8ab197ccfd5c4",
-                "0x25e3c901e4f17dee4a743efefa33983e07734ce1695f787c4f7f94d9cd94726f"
-            ],
-            "Y": [
-                "0x149aa0c72acda7ec484877b1582d74612def5025bbcd7fbfbfb80327ea8f4792",
-                "0x1382e3e087ab8b4297d3214f390e6099874e09db2aa15cf33b53afb6f63f2ce7"
+    "policies": [
+        {
+            "policy": "'"$1"'",
+            "proof": {
+                "a": {
+                    "X": "0x207e9b1f9a9633d0a9086638aabee33c172ba94c713b3e04b4530cafd5fd042f",
+                    "Y": "0x17af83f93b31de19741bf4a376d1caae08d6e517b2d772156cbe350681cd2e82"
+                },
+                "b": {
+                    "X": [
+                        "0x1292827c3f09eea38778bd54da04415036b1e206eee4bd8877a8ab197ccfd5c4",
+                        "0x25e3c901e4f17dee4a743efefa33983e07734ce1695f787c4f7f94d9cd94726f"
+                    ],
+                    "Y": [
+                        "0x149aa0c72acda7ec484877b1582d74612def5025bbcd7fbfbfb80327ea8f4792",
+                        "0x1382e3e087ab8b4297d3214f390e6099874e09db2aa15cf33b53afb6f63f2ce7"
+                    ]
+                },
+                "c": {
+                    "X": "0x0fa1fef03502c56dafd34c9e08e2dc089a622667d77133ba380cab0c98c20598",
+                    "Y": "0x044b495dafb8e35138cde24fa9749f4a91f99104f19770742e29b7dbf798382a"
+                }
+            },
+            "inputs": [
+                "0x000000000000000000000000000000000000000000000000000000000131c9a5",
+                "0x196ad888b3d5184eec5967943eeb4d211aa41c0e68f9634cf5a14a91f9df206d",
+                "0x24d0fafb29a809fa7a55a6aacc57f78d794721aca594d7a35089d0261f3d1572",
+                "0x00000000000000000000000000000000000000000000000000000000b722fe4f",
+                "0x00000000000000000000000000000000000000000000000000000000a415b2ba",
+                "0x00000000000000000000000000000000000000000000000000000000d444d8a9",
+                "0x0000000000000000000000000000000000000000000000000000000090958d4e",
+                "0x000000000000000000000000000000000000000000000000000000007a775c92",
+                "0x000000000000000000000000000000000000000000000000000000001fd2e756",
+                "0x000000000000000000000000000000000000000000000000000000000df8c0e5",
+                "0x00000000000000000000000000000000000000000000000000000000374e9b15",
+                "0x00000000000000000000000000000000000000000000000000000000b722fe4f",
+                "0x00000000000000000000000000000000000000000000000000000000a415b2ba",
+                "0x00000000000000000000000000000000000000000000000000000000d444d8a9",
+                "0x0000000000000000000000000000000000000000000000000000000090958d4e",
+                "0x000000000000000000000000000000000000000000000000000000007a775c92",
+                "0x000000000000000000000000000000000000000000000000000000001fd2e756",
+                "0x000000000000000000000000000000000000000000000000000000000df8c0e5",
+                "0x00000000000000000000000000000000000000000000000000000000374e9b15",
+                "0x0000000000000000000000000000000000000000000000000000000000000000"
             ]
-        },
-        "c": {
-            "X": "0x0fa1fef03502c56dafd34c9e08e2dc089a622667d77133ba380cab0c98c20598",
-            "Y": "0x044b495dafb8e35138cde24fa9749f4a91f99104f19770742e29b7dbf798382a"
         }
-    },
-    "inputs": [
-        "0x000000000000000000000000000000000000000000000000000000000131c9a5",
-        "0x196ad888b3d5184eec5967943eeb4d211aa41c0e68f9634cf5a14a91f9df206d",
-        "0x24d0fafb29a809fa7a55a6aacc57f78d794721aca594d7a35089d0261f3d1572",
-        "0x00000000000000000000000000000000000000000000000000000000b722fe4f",
-        "0x00000000000000000000000000000000000000000000000000000000a415b2ba",
-        "0x00000000000000000000000000000000000000000000000000000000d444d8a9",
-        "0x0000000000000000000000000000000000000000000000000000000090958d4e",
-        "0x000000000000000000000000000000000000000000000000000000007a775c92",
-        "0x000000000000000000000000000000000000000000000000000000001fd2e756",
-        "0x000000000000000000000000000000000000000000000000000000000df8c0e5",
-        "0x00000000000000000000000000000000000000000000000000000000374e9b15",
-        "0x00000000000000000000000000000000000000000000000000000000b722fe4f",
-        "0x00000000000000000000000000000000000000000000000000000000a415b2ba",
-        "0x00000000000000000000000000000000000000000000000000000000d444d8a9",
-        "0x0000000000000000000000000000000000000000000000000000000090958d4e",
-        "0x000000000000000000000000000000000000000000000000000000007a775c92",
-        "0x000000000000000000000000000000000000000000000000000000001fd2e756",
-        "0x000000000000000000000000000000000000000000000000000000000df8c0e5",
-        "0x00000000000000000000000000000000000000000000000000000000374e9b15",
-        "0x0000000000000000000000000000000000000000000000000000000000000000"
     ]
 }'
@@ -0,0 +1,7 @@
+#!/usr/bin/env bash
+
+curl --location --silent --request PUT 'http://127.0.0.1:4001/v1/dids/key' \
+--header 'Content-Type: application/json' \
+--data '{
+    "keyType": "P-256"
+}'
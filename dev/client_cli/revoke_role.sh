@@ -0,0 +1,3 @@
+#!/usr/bin/env bash
+
+curl --location --silent --request DELETE 'http://127.0.0.1:3000/v1/auth/role/did:pkh:eip155:11155111:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2+ROLE_VERIFICATION_BODY_10'
@@ -3,27 +3,33 @@
 set -e
 
 dir=$(dirname "$0")
+owner=http://127.0.0.1:4001
+user=http://127.0.0.1:3000
 
 # 1 - Create access context
 echo "Executing 1: Create access context..."
 res1=$("$dir"/create_access_context.sh)
+# an existing access context is returned right away, a new one once its transaction is confirmed
+if [ "$(jq -r '.id // empty' <<< "$res1")" != "" ]; then
+  res1=$("$dir"/wait_operation.sh "$owner" "$(jq -r '.id' <<< "$res1")")
+fi
 printf "Finished 1: Create access context - Result:\n%s\n" "$(jq . <<< "$res1")"
-sleep 5
 # 2 - Register resource
 echo "Executing 2: Register resource..."
 res2=$("$dir"/register_resource.sh)
+res2=$("$dir"/wait_operation.sh "$owner" "$(jq -r '.id' <<< "$res2")")
 printf "Finished 2: Register resource - Result:\n%s\n" "$(jq . <<< "$res2")"
-sleep 5
 # 3 - Assign role
 echo "Executing 3: Assign role..."
-res3=$("$dir"/assign_role.sh "$(jq -r '.policy' <<< "$res2")")
+res3=$("$dir"/assign_role.sh "$(jq -r '.result.response.output.policy' <<< "$res2")")
+res3=$("$dir"/wait_operation.sh "$user" "$(jq -r '.id' <<< "$res3")")
 printf "Finished 3: Assign role - Result:\n%s\n" "$(jq . <<< "$res3")"
-sleep 5
-# 4 - Start a session
+# 4 - Start a session, encrypted to a key agreement key of the resource owner
 echo "Executing 4: Start session..."
-res4=$("$dir"/start_session.sh)
+recipient=$("$dir"/create_key_agreement_did.sh | jq -r '.did.id')
+res4=$("$dir"/start_session.sh "$recipient")
+"$dir"/wait_operation.sh "$user" "$(jq -r '.operation.id' <<< "$res4")" > /dev/null
 printf "Finished 4: Start session - Result:\n%s\n" "$(jq . <<< "$res4")"
-sleep 5
 # 5 - Request the resource
 echo "Executing 5: Request resource..."
 res5=$("$dir"/request_resource.sh "$(jq -r '.signed_token' <<< "$res4")")
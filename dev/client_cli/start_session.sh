@@ -1,3 +1,8 @@
 #!/usr/bin/env bash
 
-curl --location --silent --request PUT 'http://127.0.0.1:3000/v1/auth/session'
+curl --location --silent --request PUT 'http://127.0.0.1:3000/v1/auth/session' \
+--header 'Content-Type: application/json' \
+--data '{
+    "resource": "did:pkh:eip155:11155111:0xd231120Eea6201B142b4048cf6C86BaC2A0655D2?ref=static/data/emission_report.csv",
+    "recipient": "'"$1"'"
+}'
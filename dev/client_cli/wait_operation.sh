@@ -0,0 +1,17 @@
+#!/usr/bin/env bash
+
+# Polls an operation of a server until it is done and prints it. Fails if the operation finished with an error.
+# Usage: wait_operation.sh <server url> <operation id>
+
+for _ in $(seq 1 60); do
+  op=$(curl --location --silent "$1/v1/operations/$2")
+  if [ "$(jq -r '.done' <<< "$op")" = "true" ]; then
+    echo "$op"
+    [ "$(jq -r '.result.error // empty' <<< "$op")" = "" ]
+    exit
+  fi
+  sleep 5
+done
+
+echo "operation $2 not done in time" >&2
+exit 1